@@ -0,0 +1,195 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// anomalyDetection is 1 if the anomaly detection mode is enabled, 0 otherwise.
+var anomalyDetection int32
+
+// errNonFiniteGrad is the panic value of AccGrad, when it receives
+// non-finite gradients in anomaly detection mode. The backward pass
+// replaces it with an AnomalyError describing the operator which
+// back-propagated the gradients.
+var errNonFiniteGrad = errors.New("ag: non-finite gradients")
+
+// SetAnomalyDetection enables or disables the anomaly detection mode, and
+// returns the previous setting, so that it can be restored later.
+//
+// When enabled, the value of each operator, and each gradient accumulated
+// during the backward pass, is checked for non-finite values (NaN or ±Inf).
+// The first operator producing such a value fails with an *AnomalyError,
+// which is reported as any other operator failure (see OperatorError).
+//
+// The mode is meant for debugging, since the checks have a cost
+// proportional to the size of the values. It's disabled by default, in
+// which case it has no cost.
+func SetAnomalyDetection(enabled bool) bool {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	return atomic.SwapInt32(&anomalyDetection, v) != 0
+}
+
+// detectAnomalies reports whether the anomaly detection mode is enabled.
+func detectAnomalies() bool {
+	return atomic.LoadInt32(&anomalyDetection) != 0
+}
+
+// AnomalyError is the error reported, in anomaly detection mode, by the
+// first operator producing non-finite values.
+type AnomalyError struct {
+	// Operator is the name of the operator.
+	Operator string
+	// Backward reports whether the non-finite values are the gradients
+	// back-propagated by the operator, rather than its output.
+	Backward bool
+	// Shapes are the shapes of the operands, formatted as "rows×columns".
+	Shapes []string
+	// Path is the longest path from an input node to the operator, made
+	// up of the names of the nodes.
+	Path []string
+}
+
+func newAnomalyError(op *Operator, backward bool) *AnomalyError {
+	operands := op.Operands()
+	shapes := make([]string, len(operands))
+	for i, x := range operands {
+		shapes[i] = "nil"
+		if x == nil {
+			continue
+		}
+		if v := x.Value(); v != nil {
+			shapes[i] = fmt.Sprintf("%d×%d", v.Rows(), v.Columns())
+		}
+	}
+	return &AnomalyError{
+		Operator: op.Name(),
+		Backward: backward,
+		Shapes:   shapes,
+		Path:     graphPath(op),
+	}
+}
+
+// Error returns a description of the error.
+func (e *AnomalyError) Error() string {
+	what := "output"
+	if e.Backward {
+		what = "gradients"
+	}
+	return fmt.Sprintf("ag: non-finite %s of %s operator (operands %s; path %s)",
+		what, e.Operator, strings.Join(e.Shapes, ", "), strings.Join(e.Path, " → "))
+}
+
+// CheckGrad must be called by the AccGrad method of any Node, before
+// accumulating the given gradients. In anomaly detection mode (see
+// SetAnomalyDetection), it panics if the gradients are not finite, so that
+// the backward pass reports an *AnomalyError for the operator which
+// back-propagated them. Otherwise, it has no effect.
+//
+// The nodes of this package call it already; it's meant for the ones
+// implemented elsewhere, such as nn.BaseParam.
+func CheckGrad(grad mat.Matrix) {
+	if detectAnomalies() && !isFinite(grad) {
+		panic(errNonFiniteGrad)
+	}
+}
+
+// checkFinite panics with an AnomalyError if the value of the operator
+// is not finite.
+func checkFinite(op *Operator, value mat.Matrix) {
+	if !isFinite(value) {
+		panic(newAnomalyError(op, false))
+	}
+}
+
+// isFinite reports whether all the values of the matrix are finite.
+func isFinite(m mat.Matrix) bool {
+	data := m.Data()
+	if data.BitSize() == 32 {
+		for _, v := range data.F32() {
+			if v-v != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	for _, v := range data.F64() {
+		if v-v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// graphPath returns the names of the nodes along the longest path from
+// an input node to the given node.
+func graphPath(node Node) []string {
+	depths := make(map[Node]int)
+	var depth func(n Node) int
+	depth = func(n Node) int {
+		op, ok := unwrapNode(n).(*Operator)
+		if !ok {
+			return 0
+		}
+		if d, ok := depths[op]; ok {
+			return d
+		}
+		d := 0
+		for _, x := range op.Operands() {
+			if x != nil {
+				if dx := depth(x) + 1; dx > d {
+					d = dx
+				}
+			}
+		}
+		depths[op] = d
+		return d
+	}
+
+	var path []string
+	n := node
+	for {
+		op, ok := unwrapNode(n).(*Operator)
+		if !ok {
+			path = append(path, leafName(n))
+			break
+		}
+		path = append(path, op.Name())
+		var next Node
+		d := -1
+		for _, x := range op.Operands() {
+			if x != nil && depth(x) > d {
+				next, d = x, depth(x)
+			}
+		}
+		if next == nil {
+			break
+		}
+		n = next
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// leafName returns the name of a node other than an operator.
+func leafName(n Node) string {
+	if name := unwrapNode(n).Name(); name != "" {
+		return name
+	}
+	return reflect.Indirect(reflect.ValueOf(unwrapNode(n))).Type().Name()
+}
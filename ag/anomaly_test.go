@@ -0,0 +1,77 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnomalyDetection(t *testing.T) {
+	t.Run("float32", testAnomalyDetection[float32])
+	t.Run("float64", testAnomalyDetection[float64])
+}
+
+func testAnomalyDetection[T float.DType](t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		x := Var(mat.NewVecDense[T]([]T{1, -1})).WithName("x")
+		y := Log(Tanh(x))
+		assert.NotPanics(t, func() { y.Value() })
+		ReleaseGraph(y)
+	})
+
+	defer SetAnomalyDetection(SetAnomalyDetection(true))
+
+	t.Run("forward", func(t *testing.T) {
+		x := Var(mat.NewVecDense[T]([]T{1, -1})).WithName("x")
+		y := Add(Log(Tanh(x)), x)
+
+		_, err := ValueContext(context.Background(), y)
+		var ae *AnomalyError
+		require.True(t, errors.As(err, &ae))
+		assert.Equal(t, &AnomalyError{
+			Operator: "Log",
+			Backward: false,
+			Shapes:   []string{"2×1"},
+			Path:     []string{"x", "Tanh", "Log"},
+		}, ae)
+		assert.Equal(t, "ag: non-finite output of Log operator (operands 2×1; path x → Tanh → Log)", ae.Error())
+		assert.Panics(t, func() { y.Value() })
+		ReleaseGraph(y)
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		x := Var(mat.NewVecDense[T]([]T{4, 0})).WithGrad(true)
+		w := Var(mat.NewVecDense[T]([]T{1, 2})).WithName("w").WithGrad(true)
+		y := ReduceSum(Prod(Sqrt(x), w))
+		require.True(t, isFinite(y.Value()))
+
+		err := BackwardContext(context.Background(), y)
+		var ae *AnomalyError
+		require.True(t, errors.As(err, &ae))
+		assert.Equal(t, &AnomalyError{
+			Operator: "Sqrt",
+			Backward: true,
+			Shapes:   []string{"2×1"},
+			Path:     []string{"Variable", "Sqrt"},
+		}, ae)
+		ReleaseGraph(y)
+	})
+
+	t.Run("plan", func(t *testing.T) {
+		p := TracePlan(func(xs ...Node) []Node {
+			return []Node{Log(xs[0])}
+		}, mat.NewScalar[T](1))
+		assert.NotPanics(t, func() { p.Run(mat.NewScalar[T](2)) })
+		assert.Panics(t, func() { p.Run(mat.NewScalar[T](-2)) })
+		p.Release()
+	})
+}
@@ -5,7 +5,9 @@
 package ag
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
 
 	"github.com/nlpodyssey/spago/mat"
 )
@@ -86,14 +88,61 @@ func BackwardMany(xs ...Node) ReleaseGraphFunc {
 // of backward steps compared against the nodes' time step.
 //
 // If backSteps is a negative value, no truncation is applied.
+//
+// If the forward or backward function of any operator fails, it panics with
+// an *OperatorError; see BackwardContext for a non-panicking alternative.
 func BackwardT(tsh *TimeStepHandler, backSteps int, x Node, grad ...mat.Matrix) {
+	if err := backwardT(context.Background(), tsh, backSteps, x, grad...); err != nil {
+		panic(err)
+	}
+}
+
+// BackwardManyT starts a truncated backpropagation from a list of nodes.
+// It works like BackwardMany, but allows to specify a maximum
+// number of backward steps compared against the nodes' time step.
+//
+// If backSteps is a negative value, no truncation is applied.
+//
+// If the forward or backward function of any operator fails, it panics with
+// an *OperatorError; see BackwardManyContext for a non-panicking alternative.
+func BackwardManyT(tsh *TimeStepHandler, backSteps int, xs ...Node) {
+	if err := backwardManyT(context.Background(), tsh, backSteps, xs...); err != nil {
+		panic(err)
+	}
+}
+
+// BackwardContext works like Backward, but it returns an error, instead of
+// panicking, if the forward or backward function of any operator fails.
+//
+// The backpropagation is aborted as soon as the context is done, returning
+// ctx.Err(); the backward functions already running are completed anyway.
+//
+// After a failure, the gradients of the graph are incomplete: they should be
+// discarded, or the graph released altogether.
+func BackwardContext(ctx context.Context, x Node, grad ...mat.Matrix) error {
+	return backwardT(ctx, nil, -1, x, grad...)
+}
+
+// BackwardManyContext works like BackwardMany, but it returns an error,
+// instead of panicking, as described for BackwardContext.
+func BackwardManyContext(ctx context.Context, xs ...Node) error {
+	return backwardManyT(ctx, nil, -1, xs...)
+}
+
+func backwardT(ctx context.Context, tsh *TimeStepHandler, backSteps int, x Node, grad ...mat.Matrix) error {
 	if len(grad) > 1 {
 		panic("ag: only none or one gradients matrix must be passed to Backward")
 	}
 
 	op, ok := x.(*Operator)
 	if !ok {
-		return
+		return nil
+	}
+	if _, err := op.wait(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	stopAtTimeStep := -1
@@ -109,23 +158,24 @@ func BackwardT(tsh *TimeStepHandler, backSteps int, x Node, grad ...mat.Matrix)
 	}
 	op.initOutputGrad(outputGrad)
 
-	wg := new(sync.WaitGroup)
-	backward(tsh, wg, op, stopAtTimeStep)
-	wg.Wait()
+	r := newBackwardRun(ctx, tsh, stopAtTimeStep)
+	r.start(op)
+	return r.wait()
 }
 
-// BackwardManyT starts a truncated backpropagation from a list of nodes.
-// It works like BackwardMany, but allows to specify a maximum
-// number of backward steps compared against the nodes' time step.
-//
-// If backSteps is a negative value, no truncation is applied.
-func BackwardManyT(tsh *TimeStepHandler, backSteps int, xs ...Node) {
+func backwardManyT(ctx context.Context, tsh *TimeStepHandler, backSteps int, xs ...Node) error {
 	ops := make([]*Operator, 0, len(xs))
 	for _, x := range xs {
 		if op, ok := x.(*Operator); ok {
+			if _, err := op.wait(); err != nil {
+				return err
+			}
 			ops = append(ops, op)
 		}
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	stopAtTimeStep := -1
 	if tsh != nil {
@@ -140,11 +190,9 @@ func BackwardManyT(tsh *TimeStepHandler, backSteps int, xs ...Node) {
 		op.initOutputGrad(nil)
 	}
 
-	wg := new(sync.WaitGroup)
-	for _, op := range ops {
-		backward(tsh, wg, op, stopAtTimeStep)
-	}
-	wg.Wait()
+	r := newBackwardRun(ctx, tsh, stopAtTimeStep)
+	r.start(ops...)
+	return r.wait()
 }
 
 func setupOperatorForBackward(tsh *TimeStepHandler, op *Operator, stopAtTimeStep int) {
@@ -166,24 +214,197 @@ func setupOperatorForBackward(tsh *TimeStepHandler, op *Operator, stopAtTimeStep
 	}
 }
 
-func backward(tsh *TimeStepHandler, wg *sync.WaitGroup, op *Operator, stopAtTimeStep int) {
-	if !op.RequiresGrad() || op.backwardState != pending || timeStepTruncation(tsh, op, stopAtTimeStep) {
+// backwardRun keeps track of the operators involved in a single backward
+// step, allowing it to be aborted on the first failure or when the context
+// is done.
+type backwardRun struct {
+	ctx            context.Context
+	tsh            *TimeStepHandler
+	stopAtTimeStep int
+	// sequential reports whether the backward functions are executed one
+	// at a time, in a fixed order (see SetDeterministic).
+	sequential bool
+	wg         sync.WaitGroup
+	// mu protects ops, which is appended while the backward functions are
+	// already running.
+	mu      sync.Mutex
+	ops     []*Operator
+	aborted int32
+	errOnce sync.Once
+	err     error
+	// done is closed once all backward functions are completed, stopping
+	// the goroutine watching the context, which then closes watcherDone.
+	done        chan struct{}
+	watcherDone chan struct{}
+}
+
+func newBackwardRun(ctx context.Context, tsh *TimeStepHandler, stopAtTimeStep int) *backwardRun {
+	r := &backwardRun{
+		ctx:            ctx,
+		tsh:            tsh,
+		stopAtTimeStep: stopAtTimeStep,
+		sequential:     IsDeterministic(),
+		done:           make(chan struct{}),
+		watcherDone:    make(chan struct{}),
+	}
+	if ctx.Done() == nil {
+		close(r.watcherDone)
+		return r
+	}
+	go func() {
+		defer close(r.watcherDone)
+		select {
+		case <-ctx.Done():
+			r.abort(ctx.Err())
+		case <-r.done:
+		}
+	}()
+	return r
+}
+
+// start executes the backward functions of the operators reachable from
+// the given ones.
+func (r *backwardRun) start(ops ...*Operator) {
+	if r.sequential {
+		r.backwardSequential(ops)
+		return
+	}
+	for _, op := range ops {
+		r.backward(op)
+	}
+}
+
+func (r *backwardRun) backward(op *Operator) {
+	if !op.RequiresGrad() || op.backwardState != pending || timeStepTruncation(r.tsh, op, r.stopAtTimeStep) {
 		return
 	}
 	op.backwardState = ongoing
 
-	wg.Add(1)
-	go func() {
-		op.backward()
-		op.backwardState = idle
-		wg.Done()
-	}()
+	r.mu.Lock()
+	r.ops = append(r.ops, op)
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go r.run(op)
 
 	for _, operand := range op.Operands() {
 		if oo, ok := operand.(*Operator); ok {
-			backward(tsh, wg, oo, stopAtTimeStep)
+			r.backward(oo)
+		}
+	}
+}
+
+// backwardSequential executes the backward functions of the operators
+// reachable from the given ones in the calling goroutine, in reverse
+// topological order, so that each operator runs once all its gradients have
+// been accumulated, always in the same order.
+func (r *backwardRun) backwardSequential(ops []*Operator) {
+	var order []*Operator
+	var visit func(op *Operator)
+	visit = func(op *Operator) {
+		if !op.RequiresGrad() || op.backwardState != pending || timeStepTruncation(r.tsh, op, r.stopAtTimeStep) {
+			return
+		}
+		op.backwardState = ongoing
+		for _, operand := range op.Operands() {
+			if oo, ok := operand.(*Operator); ok {
+				visit(oo)
+			}
 		}
+		order = append(order, op) // operands come first
+	}
+	for _, op := range ops {
+		visit(op)
+	}
+
+	r.mu.Lock()
+	r.ops = order
+	r.mu.Unlock()
+
+	for i := len(order) - 1; i >= 0; i-- {
+		r.wg.Add(1)
+		r.run(order[i])
+	}
+}
+
+// run executes the backward function of the operator, as soon as all its
+// gradients have been accumulated.
+func (r *backwardRun) run(op *Operator) {
+	defer r.wg.Done()
+	defer func() {
+		if p := recover(); p != nil {
+			if p == any(errNonFiniteGrad) {
+				p = newAnomalyError(op, true)
+			}
+			r.abort(newOperatorError(op, true, p))
+		}
+		op.backwardState = idle
+	}()
+
+	grad := r.waitGrad(op)
+	if grad == nil {
+		return
+	}
+	if err := r.ctx.Err(); err != nil {
+		r.abort(err)
+		return
+	}
+	if p := runningProfiler(); p != nil {
+		p.backward(op, grad)
+		return
+	}
+	op.function.Backward(grad)
+}
+
+// waitGrad waits for all the gradients of the operator to be accumulated.
+// It returns nil if the backward step has been aborted meanwhile.
+func (r *backwardRun) waitGrad(op *Operator) mat.Matrix {
+	op.cond.L.Lock()
+	defer op.cond.L.Unlock()
+	for atomic.LoadInt64(&op.pendingGrads) != 0 && atomic.LoadInt32(&r.aborted) == 0 {
+		op.cond.Wait()
+	}
+	if atomic.LoadInt32(&r.aborted) != 0 {
+		return nil
+	}
+	return op.grad
+}
+
+// abort records the error, if it's the first one, and wakes up all the
+// goroutines waiting for gradients.
+func (r *backwardRun) abort(err error) {
+	r.errOnce.Do(func() {
+		r.err = err
+	})
+	if !atomic.CompareAndSwapInt32(&r.aborted, 0, 1) {
+		return
+	}
+	r.mu.Lock()
+	ops := r.ops
+	r.mu.Unlock()
+	for _, op := range ops {
+		op.cond.L.Lock()
+		op.cond.Broadcast()
+		op.cond.L.Unlock()
+	}
+}
+
+// wait waits for the backward step to be completed or aborted, returning
+// the first error, if any. After a failure, the state of all operators is
+// reset, so that their (incomplete) gradients can be read or cleared.
+func (r *backwardRun) wait() error {
+	r.wg.Wait()
+	close(r.done)
+	<-r.watcherDone
+
+	if r.err == nil {
+		return nil
+	}
+	for _, op := range r.ops {
+		atomic.StoreInt64(&op.pendingGrads, 0)
+		op.backwardState = idle
 	}
+	return r.err
 }
 
 func timeStepTruncation(tsh *TimeStepHandler, op *Operator, stopAtTimeStep int) bool {
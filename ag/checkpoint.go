@@ -0,0 +1,171 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"sync"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+var (
+	_ fn.Function[Node] = &checkpoint{}
+	_ fn.Function[Node] = &checkpointOutput{}
+)
+
+// Checkpoint evaluates f on the inputs, trading compute for memory.
+//
+// The values of the nodes created by f are released as soon as its outputs
+// are computed, so only the inputs and the returned outputs are kept alive
+// in the graph. During the backward pass, f is evaluated once again, on new
+// Variables holding the values of the inputs, and the gradients of the
+// outputs are back-propagated through the recomputed sub-graph, which is
+// released afterwards.
+//
+// The resulting gradients are the same that would be obtained by calling f
+// directly, provided that f is deterministic: any randomness, such as
+// dropout, must not change between the two evaluations.
+//
+// Unlike other operators, f is evaluated synchronously. It panics if no
+// inputs are given.
+func Checkpoint(f func(xs ...Node) []Node, inputs ...Node) []Node {
+	if len(inputs) == 0 {
+		panic("ag: Checkpoint requires at least one input")
+	}
+
+	xs := make([]Node, len(inputs))
+	for i, x := range inputs {
+		xs[i] = Var(x.Value())
+	}
+	ys := f(xs...)
+	values := make([]mat.Matrix, len(ys))
+	for i, y := range ys {
+		values[i] = y.Value().Clone()
+	}
+	ReleaseGraph(ys...)
+
+	c := &checkpoint{
+		f:      f,
+		inputs: inputs,
+		n:      len(ys),
+	}
+	segment := NewOperator(c)
+
+	outputs := make([]Node, len(ys))
+	for i, v := range values {
+		outputs[i] = NewOperator(&checkpointOutput{
+			segment: segment,
+			c:       c,
+			i:       i,
+			value:   v,
+		})
+	}
+	return outputs
+}
+
+// checkpoint is the function of the node representing the whole segment
+// of graph created by a Checkpoint.
+//
+// Its value is just a placeholder scalar, the actual output values being
+// held by the checkpointOutput nodes. During the backward pass, it
+// receives the gradients of the outputs, which are accumulated separately.
+type checkpoint struct {
+	f      func(xs ...Node) []Node
+	inputs []Node
+	n      int
+
+	mu    sync.Mutex
+	grads []mat.Matrix
+}
+
+// Operands returns the list of operands.
+func (c *checkpoint) Operands() []Node {
+	return c.inputs
+}
+
+// Forward returns a placeholder scalar.
+func (c *checkpoint) Forward() mat.Matrix {
+	return c.inputs[0].Value().NewScalar(0)
+}
+
+// Backward recomputes the segment and back-propagates the gradients
+// accumulated by the outputs; gy is only a placeholder.
+func (c *checkpoint) Backward(_ mat.Matrix) {
+	c.mu.Lock()
+	grads := c.grads
+	c.grads = nil
+	c.mu.Unlock()
+	if grads == nil {
+		return
+	}
+
+	xs := make([]Node, len(c.inputs))
+	for i, x := range c.inputs {
+		xs[i] = Var(x.Value()).WithGrad(x.RequiresGrad())
+	}
+	ys := c.f(xs...)
+
+	roots := make([]Node, 0, len(ys))
+	for i, g := range grads {
+		if g == nil {
+			continue
+		}
+		ys[i].AccGrad(g)
+		mat.ReleaseMatrix(g)
+		roots = append(roots, ys[i])
+	}
+	BackwardMany(roots...)
+
+	for i, x := range xs {
+		if gx := x.Grad(); gx != nil {
+			c.inputs[i].AccGrad(gx)
+			x.ZeroGrad()
+		}
+	}
+	ReleaseGraph(ys...)
+}
+
+// composite marks the checkpoint as evaluating a sub-graph of operators
+// during the backward pass.
+func (c *checkpoint) composite() {}
+
+// accOutputGrad accumulates the gradients of the i-th output.
+func (c *checkpoint) accOutputGrad(i int, gy mat.Matrix) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.grads == nil {
+		c.grads = make([]mat.Matrix, c.n)
+	}
+	if c.grads[i] == nil {
+		c.grads[i] = gy.Clone()
+		return
+	}
+	c.grads[i].AddInPlace(gy)
+}
+
+// checkpointOutput is the function of an output node of a Checkpoint.
+type checkpointOutput struct {
+	segment Node
+	c       *checkpoint
+	i       int
+	value   mat.Matrix
+}
+
+// Operands returns the list of operands.
+func (r *checkpointOutput) Operands() []Node {
+	return []Node{r.segment}
+}
+
+// Forward returns the output value, computed by Checkpoint.
+func (r *checkpointOutput) Forward() mat.Matrix {
+	return r.value
+}
+
+// Backward accumulates the output gradients into the segment node.
+func (r *checkpointOutput) Backward(gy mat.Matrix) {
+	r.c.accOutputGrad(r.i, gy)
+	r.segment.AccGrad(r.segment.Value())
+}
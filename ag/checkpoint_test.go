@@ -0,0 +1,145 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	t.Run("float32", testCheckpoint[float32])
+	t.Run("float64", testCheckpoint[float64])
+}
+
+func testCheckpoint[T float.DType](t *testing.T) {
+	tol := 1.0e-12
+	if float.Interface(T(0)).BitSize() == 32 {
+		tol = 1.0e-6
+	}
+
+	newParams := func() []Node {
+		return []Node{
+			Var(mat.NewVecDense[T]([]T{0.7, -1.1, 0.4})).WithGrad(true),
+			Var(mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5})).WithGrad(true),
+			Var(mat.NewVecDense[T]([]T{0.1, -0.2})).WithGrad(true),
+			Var(mat.NewDense[T](2, 2, []T{0.5, -0.3, 0.8, 0.1})).WithGrad(true),
+			Var(mat.NewVecDense[T]([]T{1, 2})),
+		}
+	}
+
+	// A segment with two outputs, one depending on the other, and a
+	// constant input.
+	segment := func(xs ...Node) []Node {
+		h := Tanh(Affine(xs[2], xs[1], xs[0]))
+		y := Sigmoid(Add(Mul(xs[3], h), xs[4]))
+		return []Node{h, y}
+	}
+	loss := func(ys []Node) Node {
+		return Add(ReduceSum(Square(ys[1])), ReduceSum(Exp(ys[0])))
+	}
+
+	expectedParams := newParams()
+	expectedLoss := loss(segment(expectedParams...))
+	Backward(expectedLoss)
+
+	params := newParams()
+	ys := Checkpoint(segment, params...)
+	require.Len(t, ys, 2)
+	actualLoss := loss(ys)
+	Backward(actualLoss)
+
+	assert.Equal(t, expectedLoss.Value().Data(), actualLoss.Value().Data())
+	for i, p := range params {
+		if !p.RequiresGrad() {
+			assert.Nil(t, p.Grad())
+			continue
+		}
+		require.NotNil(t, p.Grad(), "param %d", i)
+		assert.InDeltaSlice(t, expectedParams[i].Grad().Data().F64(), p.Grad().Data().F64(), tol, "param %d", i)
+	}
+
+	// Only the gradients of the used outputs are propagated.
+	params = newParams()
+	ys = Checkpoint(segment, params...)
+	Backward(ReduceSum(ys[0]))
+	assert.Nil(t, params[3].Grad())
+	assert.NotNil(t, params[1].Grad())
+}
+
+func TestCheckpoint_Memory(t *testing.T) {
+	const depth, size = 32, 128
+
+	chain := func(xs ...Node) []Node {
+		h := xs[0]
+		for i := 0; i < depth; i++ {
+			h = Tanh(Prod(h, xs[1]))
+		}
+		return []Node{h}
+	}
+	newInputs := func() []Node {
+		return []Node{
+			Var(mat.NewInitDense[float64](size, size, 0.5)).WithGrad(true),
+			Var(mat.NewInitDense[float64](size, size, 1.1)).WithGrad(true),
+		}
+	}
+
+	// The values retained by the graph, which is also measured on the heap.
+	var plainY, checkpointY Node
+	plainHeap := heapGrowth(func() { plainY = chain(newInputs()...)[0]; plainY.Value() })
+	checkpointHeap := heapGrowth(func() { checkpointY = Checkpoint(chain, newInputs()...)[0] })
+
+	plainSize, checkpointSize := retainedValuesSize(plainY), retainedValuesSize(checkpointY)
+	assert.Equal(t, (2*depth+2)*size*size, plainSize)
+	assert.Equal(t, 3*size*size+1, checkpointSize)
+	assert.Less(t, checkpointHeap*4, plainHeap)
+
+	ReleaseGraph(plainY, checkpointY)
+}
+
+// heapGrowth returns the growth of the heap, after garbage collection,
+// caused by f.
+func heapGrowth(f func()) int64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.GC()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	return int64(after.HeapAlloc) - int64(before.HeapAlloc)
+}
+
+// retainedValuesSize returns the total size of the values of the nodes in
+// the graph.
+func retainedValuesSize(nodes ...Node) int {
+	visited := make(map[Node]struct{})
+	size := 0
+	var visit func(n Node)
+	visit = func(n Node) {
+		if _, ok := visited[n]; ok {
+			return
+		}
+		visited[n] = struct{}{}
+		if v := n.Value(); v != nil {
+			size += v.Size()
+		}
+		if op, ok := n.(*Operator); ok {
+			for _, operand := range op.Operands() {
+				visit(operand)
+			}
+		}
+	}
+	for _, n := range nodes {
+		visit(n)
+	}
+	return size
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// OperatorError is the error reported when the forward or the backward
+// function of an operator panics.
+//
+// Since each operator is evaluated in its own goroutine, such panics are
+// recovered, and reported to the goroutines waiting for the value of the
+// operator, or performing the backward step. An operator which fails
+// because one of its operands failed reports the original error.
+type OperatorError struct {
+	// Operator is the name of the failed operator.
+	Operator string
+	// Backward reports whether the failure occurred during the backward
+	// pass, rather than during the forward pass.
+	Backward bool
+	// Value is the value passed to panic.
+	Value any
+}
+
+func newOperatorError(op *Operator, backward bool, p any) *OperatorError {
+	if err, ok := p.(*OperatorError); ok {
+		return err
+	}
+	return &OperatorError{
+		Operator: op.Name(),
+		Backward: backward,
+		Value:    p,
+	}
+}
+
+// Error returns a description of the error.
+func (e *OperatorError) Error() string {
+	pass := "forward"
+	if e.Backward {
+		pass = "backward"
+	}
+	return fmt.Sprintf("ag: %s operator failed during the %s pass: %v", e.Operator, pass, e.Value)
+}
+
+// Unwrap returns the value passed to panic, if it's an error.
+func (e *OperatorError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// ContextScope calls f, binding the given context to every operator created
+// by the calling goroutine while f is running, as NoGradScope does for the
+// inference mode.
+//
+// Each of those operators checks the context right before executing its
+// forward function: once the context is done, the function is skipped and
+// the operator fails with an *OperatorError wrapping ctx.Err(), which is
+// reported by ValueContext and Node.Value, like any other forward failure,
+// and spreads to the operators depending on it. A forward function already
+// running is not interrupted.
+//
+// A nested ContextScope replaces the context of the enclosing one.
+func ContextScope(ctx context.Context, f func()) {
+	runInScope(func(s scope) scope {
+		s.ctx = ctx
+		return s
+	}, f)
+}
+
+// ValueContext waits for the value of the node, like Node.Value, but it
+// returns an error, instead of panicking, if the forward evaluation of the
+// node, or of any node it depends on, failed.
+//
+// If the context is done before the value is available, it returns
+// ctx.Err(). The operators already running keep going in background, so
+// the graph can still be released as usual; to skip the evaluation of the
+// remaining ones, create the graph within ContextScope.
+func ValueContext(ctx context.Context, node Node) (mat.Matrix, error) {
+	if w, ok := node.(*Wrapper); ok {
+		return ValueContext(ctx, w.Node)
+	}
+	op, ok := node.(*Operator)
+	if !ok {
+		return node.Value(), nil
+	}
+	if v := op.value.Load(); v != nil {
+		return v.(mat.Matrix), nil
+	}
+	if op.noGrad {
+		return nil, op.err // evaluated synchronously
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// The watcher wakes up the waiting goroutine when the context is done.
+	// It's stopped, and waited for, before returning, so that it never
+	// outlives the call.
+	stop, watcherDone := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(watcherDone)
+		select {
+		case <-ctx.Done():
+			op.cond.L.Lock()
+			op.cond.Broadcast()
+			op.cond.L.Unlock()
+		case <-stop:
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-watcherDone
+	}()
+
+	op.cond.L.Lock()
+	defer op.cond.L.Unlock()
+	for {
+		if v := op.value.Load(); v != nil {
+			return v.(mat.Matrix), nil
+		}
+		if op.err != nil {
+			return nil, op.err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		op.cond.Wait()
+	}
+}
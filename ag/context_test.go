@@ -0,0 +1,240 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueContext(t *testing.T) {
+	t.Run("forward failure", func(t *testing.T) {
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+		failing := NewOperator(&testFunction{x: x, forward: func(mat.Matrix) mat.Matrix {
+			panic("shape mismatch")
+		}})
+		y := Tanh(Add(failing, x))
+
+		value, err := ValueContext(context.Background(), y)
+		assert.Nil(t, value)
+		var opErr *OperatorError
+		require.ErrorAs(t, err, &opErr)
+		assert.Equal(t, "testFunction", opErr.Operator)
+		assert.False(t, opErr.Backward)
+		assert.Equal(t, "shape mismatch", opErr.Value)
+		assert.EqualError(t, err, "ag: testFunction operator failed during the forward pass: shape mismatch")
+
+		assert.PanicsWithValue(t, err, func() { y.Value() })
+		assert.PanicsWithValue(t, err, func() { Backward(y) })
+		assert.Equal(t, err, BackwardContext(context.Background(), y))
+		assert.Nil(t, x.Grad())
+
+		assert.NotPanics(t, func() { ReleaseGraph(y) })
+	})
+
+	t.Run("wrapped error", func(t *testing.T) {
+		cause := errors.New("broken")
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2}))
+		y := StopGrad(NewOperator(&testFunction{x: x, forward: func(mat.Matrix) mat.Matrix {
+			panic(cause)
+		}}))
+		_, err := ValueContext(context.Background(), y)
+		assert.ErrorIs(t, err, cause)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2}))
+		value, err := ValueContext(context.Background(), Add(x, x))
+		require.NoError(t, err)
+		assert.Equal(t, []float64{2, 4}, value.Data().F64())
+
+		value, err = ValueContext(context.Background(), x)
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 2}, value.Data().F64())
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		unblock := make(chan struct{})
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2}))
+		y := NewOperator(&testFunction{x: x, forward: func(x mat.Matrix) mat.Matrix {
+			<-unblock
+			return x.Clone()
+		}})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		value, err := ValueContext(ctx, y)
+		assert.Nil(t, value)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		value, err = ValueContext(ctx, y)
+		assert.Nil(t, value)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(unblock)
+		ReleaseGraph(y)
+	})
+}
+
+func TestContextScope(t *testing.T) {
+	t.Run("cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		started, unblock := make(chan struct{}), make(chan struct{})
+		var calls int32
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2}))
+		var a, b, c Node
+		ContextScope(ctx, func() {
+			a = NewOperator(&testFunction{x: x, forward: func(x mat.Matrix) mat.Matrix {
+				close(started)
+				<-unblock
+				return x.Clone()
+			}})
+			b = NewOperator(&testFunction{x: a, forward: func(x mat.Matrix) mat.Matrix {
+				atomic.AddInt32(&calls, 1)
+				return x.Clone()
+			}})
+			c = Tanh(b)
+		})
+		d := Tanh(a) // outside the scope
+
+		<-started
+		cancel()
+		close(unblock)
+
+		// The running forward function completes.
+		_, err := ValueContext(context.Background(), a)
+		require.NoError(t, err)
+		_, err = ValueContext(context.Background(), d)
+		require.NoError(t, err)
+
+		// The others are skipped.
+		_, err = ValueContext(context.Background(), c)
+		assert.ErrorIs(t, err, context.Canceled)
+		var opErr *OperatorError
+		require.ErrorAs(t, err, &opErr)
+		assert.Equal(t, "testFunction", opErr.Operator)
+		assert.Zero(t, atomic.LoadInt32(&calls))
+		assert.PanicsWithValue(t, err, func() { c.Value() })
+
+		ReleaseGraph(c, d)
+	})
+
+	t.Run("inference mode", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2}))
+		ContextScope(ctx, func() {
+			NoGradScope(func() {
+				y := Exp(x)
+				require.NoError(t, y.(*Operator).err)
+				cancel()
+				z := Exp(y)
+				assert.ErrorIs(t, z.(*Operator).err, context.Canceled)
+				ReleaseGraph(z)
+			})
+		})
+	})
+}
+
+func TestBackwardContext(t *testing.T) {
+	t.Run("backward failure", func(t *testing.T) {
+		x1 := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+		x2 := Var(mat.NewVecDense[float64]([]float64{3, 4})).WithGrad(true)
+		failing := NewOperator(&testFunction{x: Exp(x1), backward: func(mat.Matrix) {
+			panic("invalid gradients")
+		}})
+		y := Add(Tanh(failing), Square(x2))
+
+		err := BackwardContext(context.Background(), y)
+		var opErr *OperatorError
+		require.ErrorAs(t, err, &opErr)
+		assert.Equal(t, "testFunction", opErr.Operator)
+		assert.True(t, opErr.Backward)
+		assert.Nil(t, x1.Grad())
+
+		// The state of the graph is consistent anyway.
+		assert.NotNil(t, y.Grad())
+		y.ZeroGrad()
+		assert.Panics(t, func() { Backward(y) })
+		ReleaseGraph(y)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+		y := Square(x)
+		require.NoError(t, BackwardContext(context.Background(), y))
+		assert.Equal(t, []float64{2, 4}, x.Grad().Data().F64())
+
+		x.ZeroGrad()
+		require.NoError(t, BackwardManyContext(context.Background(), y, Add(x, x)))
+		assert.Equal(t, []float64{4, 6}, x.Grad().Data().F64())
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+		y := Square(x)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, BackwardContext(ctx, y), context.Canceled)
+		assert.ErrorIs(t, BackwardManyContext(ctx, y), context.Canceled)
+		assert.Nil(t, x.Grad())
+	})
+
+	t.Run("cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+		h := Tanh(x)
+		y := NewOperator(&testFunction{x: h, backward: func(gy mat.Matrix) {
+			<-ctx.Done()
+			h.AccGrad(gy)
+		}})
+
+		assert.ErrorIs(t, BackwardContext(ctx, y), context.DeadlineExceeded)
+		assert.Nil(t, x.Grad())
+		assert.NotNil(t, h.Grad())
+		ReleaseGraph(y)
+	})
+}
+
+// testFunction is an identity function, whose forward and backward passes
+// can be customized.
+type testFunction struct {
+	x        Node
+	forward  func(x mat.Matrix) mat.Matrix
+	backward func(gy mat.Matrix)
+}
+
+var _ fn.Function[Node] = &testFunction{}
+
+func (r *testFunction) Operands() []Node {
+	return []Node{r.x}
+}
+
+func (r *testFunction) Forward() mat.Matrix {
+	if r.forward != nil {
+		return r.forward(r.x.Value())
+	}
+	return r.x.Value().Clone()
+}
+
+func (r *testFunction) Backward(gy mat.Matrix) {
+	if r.backward != nil {
+		r.backward(gy)
+		return
+	}
+	r.x.AccGrad(gy)
+}
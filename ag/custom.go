@@ -0,0 +1,172 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+var _ fn.Function[Node] = &customFunction{}
+
+// CustomForwardFunc computes the output value of a custom operator, given
+// the values of its operands.
+type CustomForwardFunc func(xs []mat.Matrix) mat.Matrix
+
+// CustomBackwardFunc computes the gradients of the operands of a custom
+// operator, given the gradients of the output gy, the values of the
+// operands xs, and the output value y.
+//
+// It returns one gradient for each operand, or nil for the operands which
+// don't require gradients. The gradients are released after their
+// accumulation, unless they are gy, y or any of xs.
+type CustomBackwardFunc func(gy mat.Matrix, xs []mat.Matrix, y mat.Matrix) []mat.Matrix
+
+// Custom returns a new operator node computing a custom function on the
+// values of the operands, without implementing a new fn.Function.
+//
+// The operator is named after the forward function, as reported by the
+// runtime, such as "softsign" for a function softsign of any package, so
+// that it can be recognized, for example, in the DOT encoding or in the
+// measurements of the Profiler. See NamedCustom to give it a name
+// explicitly, which is advisable with function literals.
+func Custom(forward CustomForwardFunc, backward CustomBackwardFunc, operands ...Node) Node {
+	return NamedCustom(funcName(forward), forward, backward, operands...)
+}
+
+// NamedCustom works like Custom, giving the operator the given name.
+func NamedCustom(name string, forward CustomForwardFunc, backward CustomBackwardFunc, operands ...Node) Node {
+	if name == "" {
+		name = "Custom"
+	}
+	return NewOperator(&customFunction{
+		name:     name,
+		forward:  forward,
+		backward: backward,
+		operands: operands,
+	})
+}
+
+// customFunction is the fn.Function of the operators created with Custom.
+type customFunction struct {
+	name     string
+	forward  CustomForwardFunc
+	backward CustomBackwardFunc
+	operands []Node
+	// y is the output value of the last forward evaluation.
+	y mat.Matrix
+}
+
+// Name returns the name of the function.
+func (c *customFunction) Name() string {
+	return c.name
+}
+
+// Operands returns the list of operands.
+func (c *customFunction) Operands() []Node {
+	return c.operands
+}
+
+// Forward computes the output of the function.
+func (c *customFunction) Forward() mat.Matrix {
+	c.y = c.forward(c.values())
+	return c.y
+}
+
+// Backward computes the backward pass.
+func (c *customFunction) Backward(gy mat.Matrix) {
+	xs := c.values()
+	gxs := c.backward(gy, xs, c.y)
+	if len(gxs) != len(xs) {
+		panic(fmt.Sprintf("ag: %s backward returned %d gradients, expected %d", c.name, len(gxs), len(xs)))
+	}
+	for i, gx := range gxs {
+		if gx == nil {
+			continue
+		}
+		if x := c.operands[i]; x.RequiresGrad() {
+			if !mat.SameDims(gx, xs[i]) {
+				panic(fmt.Sprintf("ag: %s backward returned a %d×%d gradient for a %d×%d operand",
+					c.name, gx.Rows(), gx.Columns(), xs[i].Rows(), xs[i].Columns()))
+			}
+			x.AccGrad(gx)
+		}
+	}
+	c.releaseGrads(gxs, gy, xs)
+}
+
+// releaseGrads releases the gradients returned by the backward function
+// which are new matrices, once all of them have been accumulated. The same
+// matrix may be returned for more than one operand, and it's released once.
+func (c *customFunction) releaseGrads(gxs []mat.Matrix, gy mat.Matrix, xs []mat.Matrix) {
+	released := make([]mat.Matrix, 0, len(gxs))
+	for _, gx := range gxs {
+		if gx == nil || !c.isOwned(gx, gy, xs) || containsMatrix(released, gx) {
+			continue
+		}
+		mat.ReleaseMatrix(gx)
+		released = append(released, gx)
+	}
+}
+
+func containsMatrix(ms []mat.Matrix, m mat.Matrix) bool {
+	for _, v := range ms {
+		if v == m {
+			return true
+		}
+	}
+	return false
+}
+
+// isOwned reports whether the gradient returned by the backward function
+// is a new matrix, and not one of the matrices it was given.
+func (c *customFunction) isOwned(gx, gy mat.Matrix, xs []mat.Matrix) bool {
+	if gx == gy || gx == c.y {
+		return false
+	}
+	for _, x := range xs {
+		if gx == x {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *customFunction) values() []mat.Matrix {
+	xs := make([]mat.Matrix, len(c.operands))
+	for i, x := range c.operands {
+		xs[i] = x.Value()
+	}
+	return xs
+}
+
+// namedFunction is implemented by the functions providing their own name,
+// instead of the name of their type (see Operator.Name).
+type namedFunction interface {
+	Name() string
+}
+
+// funcName returns the name of the function f, without the package path
+// and the type parameters, if any.
+func funcName(f any) string {
+	fun := runtime.FuncForPC(reflect.ValueOf(f).Pointer())
+	if fun == nil {
+		return ""
+	}
+	name := fun.Name()
+	if i := strings.IndexByte(name, '['); i != -1 {
+		name = name[:i]
+	}
+	name = name[strings.LastIndexByte(name, '/')+1:]
+	if i := strings.IndexByte(name, '.'); i != -1 {
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,136 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/mattest"
+	"github.com/stretchr/testify/assert"
+)
+
+// softsign computes x / (1 + |x|).
+func softsign(xs []mat.Matrix) mat.Matrix {
+	return xs[0].Apply(func(_, _ int, v float64) float64 {
+		if v < 0 {
+			return v / (1 - v)
+		}
+		return v / (1 + v)
+	})
+}
+
+// softsignGrad computes the gradients of softsign, 1 / (1 + |x|)².
+func softsignGrad(gy mat.Matrix, xs []mat.Matrix, _ mat.Matrix) []mat.Matrix {
+	gx := xs[0].Apply(func(_, _ int, v float64) float64 {
+		if v < 0 {
+			v = -v
+		}
+		return 1 / ((1 + v) * (1 + v))
+	})
+	return []mat.Matrix{gx.ProdInPlace(gy)}
+}
+
+func TestCustom(t *testing.T) {
+	t.Run("float32", testCustom[float32])
+	t.Run("float64", testCustom[float64])
+}
+
+func testCustom[T float.DType](t *testing.T) {
+	t.Run("forward and backward", func(t *testing.T) {
+		x := Var(mat.NewVecDense[T]([]T{-1, 0, 3})).WithGrad(true)
+		y := Custom(softsign, softsignGrad, x)
+		assert.Equal(t, "softsign", y.Name())
+		assert.InDeltaSlice(t, []T{-0.5, 0, 0.75}, mat.Data[T](y.Value()), 1.0e-6)
+
+		Backward(y, mat.NewVecDense[T]([]T{1, 2, 4}))
+		assert.InDeltaSlice(t, []T{0.25, 2, 0.25}, mat.Data[T](x.Grad()), 1.0e-6)
+		ReleaseGraph(y)
+	})
+
+	t.Run("multiple operands and output value", func(t *testing.T) {
+		// y = x1 * exp(x2), element-wise.
+		x1 := Var(mat.NewVecDense[T]([]T{1, 2})).WithGrad(true)
+		x2 := Var(mat.NewVecDense[T]([]T{0, 1})).WithGrad(true)
+		c := Var(mat.NewVecDense[T]([]T{3, 4}))
+		y := NamedCustom("ScaledExp",
+			func(xs []mat.Matrix) mat.Matrix {
+				return xs[1].Exp().ProdInPlace(xs[0])
+			},
+			func(gy mat.Matrix, xs []mat.Matrix, y mat.Matrix) []mat.Matrix {
+				return []mat.Matrix{
+					xs[1].Exp().ProdInPlace(gy),
+					y.Prod(gy),
+					nil,
+				}
+			},
+			x1, x2, StopGrad(c))
+		assert.Equal(t, "ScaledExp", y.Name())
+
+		expected1 := Var(mat.NewVecDense[T]([]T{1, 2})).WithGrad(true)
+		expected2 := Var(mat.NewVecDense[T]([]T{0, 1})).WithGrad(true)
+		expected := Prod(expected1, Exp(expected2))
+		assert.InDeltaSlice(t, mat.Data[T](expected.Value()), mat.Data[T](y.Value()), 1.0e-6)
+
+		Backward(ReduceSum(y))
+		Backward(ReduceSum(expected))
+		assert.InDeltaSlice(t, mat.Data[T](expected1.Grad()), mat.Data[T](x1.Grad()), 1.0e-6)
+		assert.InDeltaSlice(t, mat.Data[T](expected2.Grad()), mat.Data[T](x2.Grad()), 1.0e-6)
+		ReleaseGraph(y, expected)
+	})
+
+	t.Run("identity gradients are not released", func(t *testing.T) {
+		x := Var(mat.NewVecDense[T]([]T{1, 2})).WithGrad(true)
+		y := NamedCustom("Identity",
+			func(xs []mat.Matrix) mat.Matrix { return xs[0].Clone() },
+			func(gy mat.Matrix, _ []mat.Matrix, _ mat.Matrix) []mat.Matrix { return []mat.Matrix{gy} },
+			x)
+		gy := mat.NewVecDense[T]([]T{5, 6})
+		Backward(y, gy)
+		assert.Equal(t, []T{5, 6}, mat.Data[T](x.Grad()))
+		ReleaseGraph(y)
+	})
+
+	t.Run("shared gradients are released once", func(t *testing.T) {
+		x1 := Var(mat.NewVecDense[T]([]T{1, 2})).WithGrad(true)
+		x2 := Var(mat.NewVecDense[T]([]T{3, 4})).WithGrad(true)
+		mattest.AssertNoLeaks(t, func() {
+			y := NamedCustom("Sum",
+				func(xs []mat.Matrix) mat.Matrix { return xs[0].Add(xs[1]) },
+				func(gy mat.Matrix, _ []mat.Matrix, _ mat.Matrix) []mat.Matrix {
+					g := gy.ProdScalar(2)
+					return []mat.Matrix{g, g}
+				},
+				x1, x2)
+			gy := mat.NewVecDense[T]([]T{1, 1})
+			Backward(y, gy)()
+			mat.ReleaseMatrix(gy)
+			assert.Equal(t, []T{2, 2}, mat.Data[T](x1.Grad()))
+			assert.Equal(t, []T{2, 2}, mat.Data[T](x2.Grad()))
+			x1.ZeroGrad()
+			x2.ZeroGrad()
+		})
+	})
+
+	t.Run("wrong gradients", func(t *testing.T) {
+		x := Var(mat.NewVecDense[T]([]T{1, 2})).WithGrad(true)
+		y := Custom(softsign, func(gy mat.Matrix, _ []mat.Matrix, _ mat.Matrix) []mat.Matrix {
+			return nil
+		}, x)
+		assert.Panics(t, func() { Backward(y) })
+
+		y = Custom(softsign, func(gy mat.Matrix, _ []mat.Matrix, _ mat.Matrix) []mat.Matrix {
+			return []mat.Matrix{mat.NewScalar[T](1)}
+		}, x)
+		assert.Panics(t, func() { Backward(y) })
+	})
+}
+
+func TestFuncName(t *testing.T) {
+	assert.Equal(t, "softsign", funcName(softsign))
+	assert.Equal(t, "TestFuncName.func1", funcName(func() {}))
+	assert.Equal(t, "ReduceSum", funcName(ReduceSum))
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"sync/atomic"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+)
+
+// deterministic is 1 if the deterministic mode is enabled, 0 otherwise.
+var deterministic int32
+
+// SetDeterministic enables or disables the deterministic mode, and returns
+// the previous setting, so that it can be restored later.
+//
+// By default, the operators are evaluated concurrently, and the gradients
+// are accumulated in the order in which the backward functions complete.
+// Since the floating point addition is not associative, the results may
+// differ in the last bits from run to run. When the deterministic mode is
+// enabled:
+//   - the operators are evaluated synchronously on creation, so that the
+//     random numbers (see ManualSeed) are drawn in the same order;
+//   - the backward functions are executed one at a time, in reverse
+//     topological order, and the functions propagating the gradients to
+//     their operands concurrently do it sequentially (see
+//     fn.SetSequentialBackward);
+//   - the optimizers of the gd package update the parameters one at a time.
+//
+// Together with ManualSeed, it makes training bit-for-bit reproducible, at
+// the cost of any parallelism between operators.
+func SetDeterministic(enabled bool) bool {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	fn.SetSequentialBackward(enabled)
+	return atomic.SwapInt32(&deterministic, v) != 0
+}
+
+// IsDeterministic reports whether the deterministic mode is enabled.
+func IsDeterministic() bool {
+	return atomic.LoadInt32(&deterministic) != 0
+}
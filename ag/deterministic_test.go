@@ -0,0 +1,99 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDeterministic(t *testing.T) {
+	assert.False(t, IsDeterministic())
+	assert.False(t, SetDeterministic(true))
+	assert.True(t, IsDeterministic())
+	assert.True(t, SetDeterministic(false))
+	assert.False(t, IsDeterministic())
+}
+
+func TestDeterministic(t *testing.T) {
+	defer SetDeterministic(SetDeterministic(true))
+
+	t.Run("execution order", func(t *testing.T) {
+		var forward, backward []string
+		traced := func(name string, x Node) Node {
+			return NewOperator(&testFunction{
+				x: x,
+				forward: func(x mat.Matrix) mat.Matrix {
+					forward = append(forward, name)
+					return x.Clone()
+				},
+				backward: func(gy mat.Matrix) {
+					backward = append(backward, name)
+					x.AccGrad(gy)
+				},
+			})
+		}
+
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+		a := traced("a", x)
+		b := traced("b", x)
+		y := traced("y", Add(traced("c", a), b))
+		assert.Equal(t, []string{"a", "b", "c", "y"}, forward)
+
+		Backward(y)
+		assert.Equal(t, []string{"y", "b", "c", "a"}, backward)
+		assert.Equal(t, []float64{2, 2}, x.Grad().Data().F64())
+	})
+
+	t.Run("gradients", func(t *testing.T) {
+		newInputs := func() []Node {
+			return []Node{
+				Var(mat.NewVecDense[float64]([]float64{0.1, -0.2})).WithGrad(true),
+				Var(mat.NewDense[float64](2, 3, []float64{0.1, -0.4, 0.3, 0.6, 0.2, -0.5})).WithGrad(true),
+				Var(mat.NewVecDense[float64]([]float64{0.7, -1.1, 0.4})).WithGrad(true),
+			}
+		}
+		f := func(xs []Node) Node {
+			h := Tanh(Affine(xs[0], xs[1], xs[2], xs[1], Sin(xs[2])))
+			return ReduceSum(Add(Square(h), Mul(xs[1], xs[2])))
+		}
+
+		SetDeterministic(false)
+		expected := newInputs()
+		Backward(f(expected))
+		SetDeterministic(true)
+
+		actual := newInputs()
+		Backward(f(actual))
+		for i, x := range actual {
+			assert.InDeltaSlice(t, expected[i].Grad().Data().F64(), x.Grad().Data().F64(), 1.0e-12, "input %d", i)
+		}
+
+		again := newInputs()
+		Backward(f(again))
+		for i, x := range again {
+			assert.Equal(t, actual[i].Grad().Data().F64(), x.Grad().Data().F64(), "input %d", i)
+		}
+	})
+
+	t.Run("backward failure", func(t *testing.T) {
+		x := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+		failing := NewOperator(&testFunction{x: Exp(x), backward: func(mat.Matrix) {
+			panic("invalid gradients")
+		}})
+		y := Add(Tanh(failing), Square(x))
+
+		err := BackwardContext(context.Background(), y)
+		var opErr *OperatorError
+		require.ErrorAs(t, err, &opErr)
+		assert.True(t, opErr.Backward)
+		assert.NotNil(t, y.Grad())
+		ReleaseGraph(y)
+	})
+}
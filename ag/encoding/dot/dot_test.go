@@ -110,4 +110,18 @@ func testEncode[T float.DType](t *testing.T) {
 `
 		assert.Equal(t, expectedOutput, out)
 	})
+
+	t.Run("custom operator", func(t *testing.T) {
+		a := ag.Var(mat.NewScalar[T](1)).WithGrad(false).WithName("a")
+		y := ag.NamedCustom("Double",
+			func(xs []mat.Matrix) mat.Matrix { return xs[0].ProdScalar(2) },
+			func(gy mat.Matrix, _ []mat.Matrix, _ mat.Matrix) []mat.Matrix { return []mat.Matrix{gy.ProdScalar(2)} },
+			a)
+
+		g := encoding.NewGraph(y)
+		buf := new(bytes.Buffer)
+		err := dot.Encode(g, buf)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "0 [label=<<sup>0</sup><br/><b>Double</b><br/><sub>1×1</sub>>,shape=oval]")
+	})
 }
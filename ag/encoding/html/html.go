@@ -0,0 +1,60 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package html allows exporting an encoding.Graph to a self-contained
+// interactive HTML page.
+//
+// The page embeds the JSON representation of the graph (see package
+// ag/encoding/json), along with a viewer which works offline, without any
+// external resource. The viewer can also load other JSON graphs from
+// local files.
+package html
+
+import (
+	"bytes"
+	_ "embed"
+	"io"
+	"strings"
+
+	"github.com/nlpodyssey/spago/ag/encoding"
+	"github.com/nlpodyssey/spago/ag/encoding/json"
+)
+
+// viewer is the HTML page, where the placeholder is replaced by the JSON
+// representation of the graph.
+//
+//go:embed viewer.html
+var viewer string
+
+const placeholder = "{{GRAPH}}"
+
+// Encode encodes the graph g as an HTML page, writing the output to w.
+// The options are applied to the embedded JSON representation.
+func Encode(g *encoding.Graph, w io.Writer, opts ...json.Option) error {
+	// The JSON encoder escapes the HTML characters, so the data can be
+	// safely embedded in a script element.
+	var data bytes.Buffer
+	if err := json.Encode(g, &data, opts...); err != nil {
+		return err
+	}
+	return writeViewer(w, strings.TrimSpace(data.String()))
+}
+
+// WriteViewer writes the HTML page without any embedded graph, which can
+// be used to load the JSON graphs from local files.
+func WriteViewer(w io.Writer) error {
+	return writeViewer(w, "null")
+}
+
+func writeViewer(w io.Writer, data string) error {
+	i := strings.Index(viewer, placeholder)
+	if _, err := io.WriteString(w, viewer[:i]); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, viewer[i+len(placeholder):])
+	return err
+}
@@ -0,0 +1,58 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package html_test
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/encoding"
+	"github.com/nlpodyssey/spago/ag/encoding/html"
+	"github.com/nlpodyssey/spago/ag/encoding/json"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var embeddedGraph = regexp.MustCompile(`(?s)<script type="application/json" id="graph">(.*?)</script>`)
+
+func TestEncode(t *testing.T) {
+	a := ag.Var(mat.NewScalar[float64](1)).WithName("</script><b>a")
+	b := ag.Var(mat.NewScalar[float64](3)).WithName("b")
+	y := ag.Sum(a, b)
+
+	buf := new(bytes.Buffer)
+	err := html.Encode(encoding.NewGraph(y), buf, json.WithValues())
+	require.NoError(t, err)
+	out := buf.String()
+
+	assert.True(t, strings.HasPrefix(out, "<!DOCTYPE html>"))
+	// The page is self-contained.
+	assert.NotContains(t, out, "<script src=")
+	assert.NotContains(t, out, "<link")
+	assert.NotContains(t, out, "{{GRAPH}}")
+
+	// The graph is embedded, with the HTML characters escaped.
+	match := embeddedGraph.FindStringSubmatch(out)
+	require.Len(t, match, 2)
+	var graph json.Graph
+	require.NoError(t, stdjson.Unmarshal([]byte(match[1]), &graph))
+	require.Len(t, graph.Nodes, 3)
+	assert.Equal(t, "Add", graph.Nodes[0].Name)
+	assert.Equal(t, "</script><b>a", graph.Nodes[1].Name)
+	assert.Equal(t, 3.0, graph.Nodes[2].Value.Mean)
+}
+
+func TestWriteViewer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	require.NoError(t, html.WriteViewer(buf))
+	match := embeddedGraph.FindStringSubmatch(buf.String())
+	require.Len(t, match, 2)
+	assert.Equal(t, "null", match[1])
+}
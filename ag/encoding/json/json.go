@@ -0,0 +1,208 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package json allows exporting an encoding.Graph to JSON format.
+//
+// The output is the JSON encoding of a Graph value, describing each node
+// with its operands, the shape of its value and its time step, along with
+// optional summaries of the values and the gradients.
+package json
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/encoding"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Graph is the JSON representation of an encoding.Graph.
+type Graph struct {
+	// Nodes are the nodes of the graph, indexed by their IDs.
+	Nodes []Node `json:"nodes"`
+	// TimeSteps are the IDs of the nodes belonging to each time step,
+	// sorted by time step (see encoding.Graph.NodesByTimeStep).
+	TimeSteps []TimeStep `json:"timeSteps"`
+}
+
+// Node is the JSON representation of a node.
+type Node struct {
+	// ID is the ID of the node within the encoding.Graph.
+	ID int `json:"id"`
+	// Name is the name of the node: the name of the function, for an
+	// operator, otherwise the name of the variable or parameter, if any.
+	Name string `json:"name"`
+	// Type is the name of the Go type of the node, such as "Operator" or
+	// "Variable".
+	Type string `json:"type"`
+	// Operands are the IDs of the operands of an operator, in order.
+	Operands []int `json:"operands,omitempty"`
+	// Rows is the number of rows of the value.
+	Rows int `json:"rows"`
+	// Columns is the number of columns of the value.
+	Columns int `json:"columns"`
+	// RequiresGrad reports whether the node requires gradients.
+	RequiresGrad bool `json:"requiresGrad"`
+	// TimeStep is the time step of the node, or -1 if the graph has no
+	// time step handler.
+	TimeStep int `json:"timeStep"`
+	// Value is the summary of the value, if requested (see WithValues).
+	Value *Summary `json:"value,omitempty"`
+	// Grad is the summary of the gradients, if requested and available
+	// (see WithGrads).
+	Grad *Summary `json:"grad,omitempty"`
+}
+
+// Summary describes the elements of a matrix. The statistics are computed
+// on the finite elements only.
+type Summary struct {
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Mean float64 `json:"mean"`
+	// NonFinite is the number of NaN or infinite elements.
+	NonFinite int `json:"nonFinite"`
+}
+
+// TimeStep lists the IDs of the nodes belonging to a time step.
+type TimeStep struct {
+	TimeStep int   `json:"timeStep"`
+	Nodes    []int `json:"nodes"`
+}
+
+// Config contains the parameters of the encoding.
+type Config struct {
+	// Values reports whether the values are summarized.
+	Values bool
+	// Grads reports whether the gradients are summarized.
+	Grads bool
+	// Indent is the indentation of the output; no indentation is applied
+	// if empty.
+	Indent string
+}
+
+// Option allows to configure the encoding with your specific needs.
+type Option func(*Config)
+
+// WithValues enables the summaries of the values.
+func WithValues() Option {
+	return func(c *Config) {
+		c.Values = true
+	}
+}
+
+// WithGrads enables the summaries of the gradients.
+func WithGrads() Option {
+	return func(c *Config) {
+		c.Grads = true
+	}
+}
+
+// WithIndent sets the indentation of the output.
+func WithIndent(indent string) Option {
+	return func(c *Config) {
+		c.Indent = indent
+	}
+}
+
+// Encode encodes the graph g in JSON format, writing the output to w.
+func Encode(g *encoding.Graph, w io.Writer, opts ...Option) error {
+	var c Config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", c.Indent)
+	return enc.Encode(NewGraph(g, opts...))
+}
+
+// NewGraph builds the JSON representation of the graph g.
+func NewGraph(g *encoding.Graph, opts ...Option) *Graph {
+	var c Config
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	out := &Graph{
+		Nodes: make([]Node, len(g.NodesList)),
+	}
+	timeSteps := g.NodesByTimeStep()
+	nodeTimeSteps := make([]int, len(g.NodesList))
+	for ts, ids := range timeSteps {
+		out.TimeSteps = append(out.TimeSteps, TimeStep{TimeStep: ts, Nodes: ids})
+		for _, id := range ids {
+			nodeTimeSteps[id] = ts
+		}
+	}
+	sort.Slice(out.TimeSteps, func(i, j int) bool {
+		return out.TimeSteps[i].TimeStep < out.TimeSteps[j].TimeStep
+	})
+
+	for id, n := range g.NodesList {
+		node := Node{
+			ID:           id,
+			Name:         n.Name(),
+			Type:         typeName(n),
+			RequiresGrad: n.RequiresGrad(),
+			TimeStep:     nodeTimeSteps[id],
+		}
+		if op, ok := n.(*ag.Operator); ok {
+			operands := op.Operands()
+			node.Operands = make([]int, len(operands))
+			for i, x := range operands {
+				node.Operands[i] = g.NodesMap[x]
+			}
+		}
+		if v := n.Value(); v != nil {
+			node.Rows, node.Columns = v.Rows(), v.Columns()
+			if c.Values {
+				node.Value = summarize(v)
+			}
+		}
+		if c.Grads {
+			if gr := n.Grad(); gr != nil {
+				node.Grad = summarize(gr)
+			}
+		}
+		out.Nodes[id] = node
+	}
+	return out
+}
+
+func typeName(n ag.Node) string {
+	name := reflect.Indirect(reflect.ValueOf(n)).Type().Name()
+	// Strip trailing generics, if any: "foo[bar]" becomes "foo".
+	if i := strings.IndexByte(name, '['); i != -1 {
+		return name[:i]
+	}
+	return name
+}
+
+func summarize(m mat.Matrix) *Summary {
+	s := &Summary{
+		Min: math.Inf(1),
+		Max: math.Inf(-1),
+	}
+	sum, count := 0.0, 0
+	for _, v := range m.Data().F64() {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			s.NonFinite++
+			continue
+		}
+		s.Min = math.Min(s.Min, v)
+		s.Max = math.Max(s.Max, v)
+		sum += v
+		count++
+	}
+	if count == 0 {
+		s.Min, s.Max = 0, 0
+		return s
+	}
+	s.Mean = sum / float64(count)
+	return s
+}
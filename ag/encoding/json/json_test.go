@@ -0,0 +1,107 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package json_test
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/encoding"
+	"github.com/nlpodyssey/spago/ag/encoding/json"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	t.Run("float32", testEncode[float32])
+	t.Run("float64", testEncode[float64])
+}
+
+func testEncode[T float.DType](t *testing.T) {
+	t.Run("without time steps", func(t *testing.T) {
+		a := ag.Scalar[T](1).WithName("a")
+		b := ag.Scalar[T](3).WithName("b")
+		y := ag.Sum(a, b)
+
+		g := encoding.NewGraph(y)
+		buf := new(bytes.Buffer)
+		err := json.Encode(g, buf)
+		require.NoError(t, err)
+
+		expectedOutput := `{"nodes":[` +
+			`{"id":0,"name":"Add","type":"Operator","operands":[1,2],"rows":1,"columns":1,"requiresGrad":false,"timeStep":-1},` +
+			`{"id":1,"name":"a","type":"Variable","rows":1,"columns":1,"requiresGrad":false,"timeStep":-1},` +
+			`{"id":2,"name":"b","type":"Variable","rows":1,"columns":1,"requiresGrad":false,"timeStep":-1}],` +
+			`"timeSteps":[{"timeStep":-1,"nodes":[0,1,2]}]}` + "\n"
+		assert.Equal(t, expectedOutput, buf.String())
+	})
+
+	t.Run("with time steps and summaries", func(t *testing.T) {
+		tsh := ag.NewTimeStepHandler()
+
+		a := ag.Var(mat.NewVecDense[T]([]T{1, 2, 6})).WithGrad(true).WithName("a")
+		b := ag.Var(mat.NewVecDense[T]([]T{3, 4, 5})).WithName("b")
+		x := ag.Prod(a, a)
+		tsh.IncTimeStep()
+		y := ag.ReduceSum(ag.Add(x, b))
+		ag.Backward(y)
+
+		g := encoding.NewGraph(y).WithTimeSteps(tsh)
+		graph := json.NewGraph(g, json.WithValues(), json.WithGrads())
+		m := g.NodesMap
+
+		add := graph.Nodes[m[y]].Operands[0]
+		require.Len(t, graph.TimeSteps, 2)
+		assert.Equal(t, 0, graph.TimeSteps[0].TimeStep)
+		assert.ElementsMatch(t, []int{m[x], m[a], m[b]}, graph.TimeSteps[0].Nodes)
+		assert.Equal(t, 1, graph.TimeSteps[1].TimeStep)
+		assert.ElementsMatch(t, []int{m[y], add}, graph.TimeSteps[1].Nodes)
+		assert.Equal(t, []int{m[x], m[b]}, graph.Nodes[add].Operands)
+
+		nx := graph.Nodes[m[x]]
+		assert.Equal(t, "Prod", nx.Name)
+		assert.Equal(t, []int{m[a], m[a]}, nx.Operands)
+		assert.Equal(t, 3, nx.Rows)
+		assert.Equal(t, 1, nx.Columns)
+		assert.True(t, nx.RequiresGrad)
+		assert.Equal(t, 0, nx.TimeStep)
+		assert.Equal(t, 1.0, nx.Value.Min)
+		assert.Equal(t, 36.0, nx.Value.Max)
+		assert.InDelta(t, 41.0/3, nx.Value.Mean, 1.0e-6)
+		assert.Equal(t, &json.Summary{Min: 1, Max: 1, Mean: 1}, nx.Grad)
+
+		na := graph.Nodes[m[a]]
+		assert.Equal(t, &json.Summary{Min: 2, Max: 12, Mean: 6}, na.Grad)
+		// b doesn't require gradients.
+		assert.Nil(t, graph.Nodes[m[b]].Grad)
+		assert.NotNil(t, graph.Nodes[m[b]].Value)
+
+		ny := graph.Nodes[m[y]]
+		assert.Equal(t, 1, ny.TimeStep)
+		assert.Equal(t, "ReduceSum", ny.Name)
+
+		// The encoded output can be decoded back.
+		buf := new(bytes.Buffer)
+		require.NoError(t, json.Encode(g, buf, json.WithValues(), json.WithGrads(), json.WithIndent("  ")))
+		var decoded json.Graph
+		require.NoError(t, stdjson.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, len(graph.Nodes), len(decoded.Nodes))
+		assert.Equal(t, graph.Nodes[m[a]], decoded.Nodes[m[a]])
+	})
+
+	t.Run("non-finite values", func(t *testing.T) {
+		x := ag.Var(mat.NewVecDense[T]([]T{1, T(math.NaN()), T(math.Inf(1)), 3}))
+		graph := json.NewGraph(encoding.NewGraph(x), json.WithValues())
+		assert.Equal(t, &json.Summary{Min: 1, Max: 3, Mean: 2, NonFinite: 2}, graph.Nodes[0].Value)
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, json.Encode(encoding.NewGraph(x), buf, json.WithValues()))
+	})
+}
@@ -8,6 +8,7 @@ import "github.com/nlpodyssey/spago/mat"
 
 // Add is an operator to perform element-wise sum over two values.
 // y = x1 + x2
+// The operands are broadcast together if their shapes differ.
 type Add[O Operand] struct {
 	x1 O
 	x2 O
@@ -40,17 +41,26 @@ func (r *Add[O]) Forward() mat.Matrix {
 // Backward computes the backward pass.
 func (r *Add[O]) Backward(gy mat.Matrix) {
 	if r.x1.RequiresGrad() {
-		x1v := r.x1.Value()
-		if !mat.SameDims(x1v, gy) {
-			panic("fn: matrices have incompatible dimensions")
-		}
-		r.x1.AccGrad(gy)
+		accReducedGrad(r.x1, gy)
 	}
 	if r.x2.RequiresGrad() {
-		x2v := r.x2.Value()
-		if !mat.SameDims(x2v, gy) {
-			panic("fn: matrices have incompatible dimensions")
-		}
-		r.x2.AccGrad(gy)
+		accReducedGrad(r.x2, gy)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Add[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = reduceToShape(g, r.x1, gy)
+	}
+	if required[1] {
+		gxs[1] = reduceToShape(g, r.x2, gy)
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Add[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].Add(tangents[1])
+}
@@ -41,3 +41,49 @@ func testAddForward[T float.DType](t *testing.T) {
 	assert.InDeltaSlice(t, []T{-1.0, 0.5, 0.8, 0.0}, x1.grad.Data(), 1.0e-6)
 	assert.InDeltaSlice(t, []T{-1.0, 0.5, 0.8, 0.0}, x2.grad.Data(), 1.0e-6)
 }
+
+func TestAdd_Broadcasting(t *testing.T) {
+	t.Run("float32", testAddBroadcasting[float32])
+	t.Run("float64", testAddBroadcasting[float64])
+}
+
+func testAddBroadcasting[T float.DType](t *testing.T) {
+	t.Run("dense", func(t *testing.T) {
+		x1 := newVarWithGrad(mat.NewDense[T](2, 3, []T{
+			1, 2, 3,
+			4, 5, 6,
+		}))
+		x2 := newVarWithGrad(mat.NewDense[T](1, 3, []T{10, 20, 30}))
+
+		f := NewAdd(x1, x2)
+		y := f.Forward()
+		assert.Equal(t, 2, y.Rows())
+		assert.Equal(t, 3, y.Columns())
+		assert.InDeltaSlice(t, []T{11, 22, 33, 14, 25, 36}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewDense[T](2, 3, []T{
+			1, 2, 3,
+			4, 5, 6,
+		}))
+		assert.InDeltaSlice(t, []T{1, 2, 3, 4, 5, 6}, x1.grad.Data(), 1.0e-6)
+		assert.Equal(t, 1, x2.grad.Rows())
+		assert.Equal(t, 3, x2.grad.Columns())
+		assert.InDeltaSlice(t, []T{5, 7, 9}, x2.grad.Data(), 1.0e-6)
+	})
+
+	t.Run("tensor", func(t *testing.T) {
+		x1 := newVarWithGrad(mat.NewDenseTensor[T]([]int{2, 1, 2}, []T{1, 2, 3, 4}))
+		x2 := newVarWithGrad(mat.NewDenseTensor[T]([]int{2, 1}, []T{10, 20}))
+
+		f := NewAdd(x1, x2)
+		y := f.Forward()
+		assert.Equal(t, []int{2, 2, 2}, mat.Shape(y))
+		assert.InDeltaSlice(t, []T{11, 12, 21, 22, 13, 14, 23, 24}, y.Data(), 1.0e-6)
+
+		f.Backward(mat.NewInitDenseTensor[T](1, 2, 2, 2))
+		assert.Equal(t, []int{2, 1, 2}, mat.Shape(x1.grad))
+		assert.InDeltaSlice(t, []T{2, 2, 2, 2}, x1.grad.Data(), 1.0e-6)
+		assert.Equal(t, []int{2, 1}, mat.Shape(x2.grad))
+		assert.InDeltaSlice(t, []T{4, 4}, x2.grad.Data(), 1.0e-6)
+	})
+}
@@ -47,3 +47,20 @@ func (r *AddScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *AddScalar[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = gy
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewReduceSum(gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *AddScalar[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].AddScalar(tangents[1].Scalar().F64())
+}
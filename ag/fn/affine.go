@@ -98,20 +98,17 @@ func (a *Affine[O]) Backward(gy mat.Matrix) {
 		}
 
 		if w.RequiresGrad() {
-			wg.Add(1)
-			go func() {
+			goBackward(&wg, func() {
 				xt := xv.T()
 				defer mat.ReleaseMatrix(xt)
 				gx := gy.Mul(xt)
 				defer mat.ReleaseMatrix(gx)
 				w.AccGrad(gx)
-				wg.Done()
-			}()
+			})
 		}
 
 		if x.RequiresGrad() {
-			wg.Add(1)
-			go func() {
+			goBackward(&wg, func() {
 				if gy.Columns() == 1 {
 					gx := wv.MulT(gy)
 					defer mat.ReleaseMatrix(gx)
@@ -123,8 +120,7 @@ func (a *Affine[O]) Backward(gy mat.Matrix) {
 					defer mat.ReleaseMatrix(gx)
 					x.AccGrad(gx)
 				}
-				wg.Done()
-			}()
+			})
 		}
 	}
 
@@ -136,3 +132,37 @@ func (a *Affine[O]) Backward(gy mat.Matrix) {
 
 	wg.Wait()
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (a *Affine[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		gxs[0] = gy
+	}
+	xs := a.Operands()
+	for i := 1; i < len(xs); i += 2 {
+		w, x := xs[i], xs[i+1]
+		if required[i] {
+			gxs[i] = g.Apply(NewMul(gy, g.Apply(NewTranspose(x))))
+		}
+		if required[i+1] {
+			gxs[i+1] = g.Apply(NewMul(g.Apply(NewTranspose(w)), gy))
+		}
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (a *Affine[O]) Tangent(_ mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	y := tangents[0].Clone()
+	xs := a.Operands()
+	for i := 1; i < len(xs); i += 2 {
+		wx := tangents[i].Mul(xs[i+1].Value())
+		y.AddInPlace(wx)
+		mat.ReleaseMatrix(wx)
+		wx = xs[i].Value().Mul(tangents[i+1])
+		y.AddInPlace(wx)
+		mat.ReleaseMatrix(wx)
+	}
+	return y
+}
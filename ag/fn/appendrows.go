@@ -61,3 +61,24 @@ func (a *AppendRows[O]) Backward(gy mat.Matrix) {
 		mat.ReleaseMatrix(vGrads)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (a *AppendRows[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(a.vs)+1)
+	xRows, xCols := a.x.Value().Dims()
+	if required[0] {
+		gxs[0] = g.Apply(NewSlice(gy, 0, 0, xRows, xCols))
+	}
+	for i, v := range a.vs {
+		if required[i+1] {
+			gv := g.Apply(NewRowView(gy, xRows+i))
+			gxs[i+1] = reshapeLike(g, v, gv)
+		}
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (a *AppendRows[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].AppendRows(tangents[1:]...)
+}
@@ -43,3 +43,18 @@ func (r *At[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(dx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *At[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		cols := r.x.Value().Columns()
+		gxs[0] = scatterLike(g, r.x, gy, func(int) int { return r.i*cols + r.j })
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *At[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].At(r.i, r.j)
+}
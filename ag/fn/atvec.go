@@ -41,3 +41,17 @@ func (r *AtVec[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(dx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *AtVec[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = scatterLike(g, r.x, gy, func(int) int { return r.i })
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *AtVec[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].AtVec(r.i)
+}
@@ -0,0 +1,33 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// accReducedGrad accumulates the gradients gx on the operand x.
+// If the operand was broadcast in the forward pass, the gradients are first
+// summed over the broadcast dimensions, reducing them to the operand shape.
+func accReducedGrad[O Operand](x O, gx mat.Matrix) {
+	xv := x.Value()
+	if !needsReduction(xv, gx) {
+		x.AccGrad(gx)
+		return
+	}
+	g := mat.SumToShape(gx, mat.Shape(xv)...)
+	defer mat.ReleaseMatrix(g)
+	x.AccGrad(g)
+}
+
+// needsReduction reports whether the gradients gx must be reduced to match
+// the value xv. Unless both are tensors, having the same dimensions is
+// enough, consistently with the broadcasting rules of mat.Matrix.
+func needsReduction(xv, gx mat.Matrix) bool {
+	_, xIsTensor := xv.(mat.Tensor)
+	_, gIsTensor := gx.(mat.Tensor)
+	if !xIsTensor || !gIsTensor {
+		return !mat.SameDims(xv, gx)
+	}
+	return !mat.SameShape(xv, gx)
+}
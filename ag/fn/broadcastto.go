@@ -0,0 +1,68 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// BroadcastTo is a Function which broadcasts an operand to the given shape,
+// following the same rules of mat.BroadcastShapes.
+//
+// If the value of the operand is not a Tensor and the shape has two
+// dimensions, the output is a Dense matrix; otherwise it is a Tensor.
+type BroadcastTo[O Operand] struct {
+	x     O
+	shape []int
+}
+
+// NewBroadcastTo returns a new BroadcastTo Function.
+func NewBroadcastTo[O Operand](x O, shape ...int) *BroadcastTo[O] {
+	return &BroadcastTo[O]{
+		x:     x,
+		shape: shape,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *BroadcastTo[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *BroadcastTo[O]) Forward() mat.Matrix {
+	return broadcastTo(r.x.Value(), r.shape)
+}
+
+// Backward computes the backward pass.
+func (r *BroadcastTo[O]) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		accReducedGrad(r.x, gy)
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *BroadcastTo[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = reduceToShape(g, r.x, gy)
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *BroadcastTo[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return broadcastTo(tangents[0], r.shape)
+}
+
+// broadcastTo returns a new matrix with the values of x broadcast to the
+// given shape, as described for BroadcastTo.
+func broadcastTo(x mat.Matrix, shape []int) mat.Matrix {
+	view := mat.AsTensor(x).BroadcastTo(shape...)
+	if _, ok := x.(mat.Tensor); !ok && len(shape) == 2 {
+		return x.NewMatrix(shape[0], shape[1], view.Data())
+	}
+	return view.Clone()
+}
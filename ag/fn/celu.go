@@ -45,3 +45,22 @@ func (r *CELU[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *CELU[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		// For x ≤ 0, the derivative is exp(x / α) = y / α + 1.
+		neg := r.x.Value().Apply(nonPositiveMask)
+		d := prodConst(g, g.Apply(NewDivScalar(y, r.alpha)), neg)
+		gxs[0] = g.Apply(NewProd(gy, g.Apply(NewAddScalar(d, scalarLike(g, r.x, 1)))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode. As in the
+// backward pass, alpha is treated as constant.
+func (r *CELU[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	deriv := r.x.Value().ApplyWithAlpha(celuDeriv, r.alpha.Value().Scalar().F64())
+	return deriv.ProdInPlace(tangents[0])
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Cholesky is a Function to calculate the Cholesky decomposition of a
+// symmetric positive definite matrix A, that is the lower triangular
+// matrix L such as LLᵀ = A.
+type Cholesky[O Operand] struct {
+	x O
+	l mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewCholesky returns a new Cholesky Function.
+func NewCholesky[O Operand](x O) *Cholesky[O] {
+	return &Cholesky[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Cholesky[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+// It panics if the matrix is not positive definite.
+func (r *Cholesky[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	requireSquare(x)
+	l, err := asLinAlgMatrix(x).Cholesky()
+	if err != nil {
+		panic("fn: " + err.Error())
+	}
+	r.l = l
+	return r.l
+}
+
+// Backward computes the backward pass.
+//
+// The input matrix is assumed to be symmetric, so the gradient is
+// symmetric too: given P = Φ(Lᵀ gy), where Φ takes the lower triangle
+// and halves the diagonal, and S = L⁻ᵀ P L⁻¹, the gradient is
+// gx = (S + Sᵀ) / 2.
+func (r *Cholesky[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if !r.x.RequiresGrad() {
+		return
+	}
+	lt := r.l.T()
+	defer mat.ReleaseMatrix(lt)
+	p := lt.Mul(gy)
+	defer mat.ReleaseMatrix(p)
+	p.ApplyInPlace(choleskyPhi, p)
+
+	lInv := r.l.Inverse()
+	defer mat.ReleaseMatrix(lInv)
+	lInvT := lInv.T()
+	defer mat.ReleaseMatrix(lInvT)
+	tmp := lInvT.Mul(p)
+	defer mat.ReleaseMatrix(tmp)
+	s := tmp.Mul(lInv)
+	defer mat.ReleaseMatrix(s)
+	st := s.T()
+	defer mat.ReleaseMatrix(st)
+
+	gx := s.Add(st)
+	defer mat.ReleaseMatrix(gx)
+	r.x.AccGrad(gx.ProdScalarInPlace(0.5))
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Cholesky[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		n := r.x.Value().Rows()
+		phi := r.x.Value().NewInitFuncMatrix(n, n, func(row, col int) float64 {
+			return choleskyPhi(row, col, 1)
+		})
+		p := prodConst(g, g.Apply(NewMul(g.Apply(NewTranspose(y)), gy)), phi)
+		lInv := g.Apply(NewInverse(y))
+		tmp := g.Apply(NewMul(g.Apply(NewTranspose(lInv)), p))
+		s := g.Apply(NewMul(tmp, lInv))
+		gxs[0] = g.Apply(NewProdScalar(g.Apply(NewAdd(s, g.Apply(NewTranspose(s)))), scalarLike(g, r.x, 0.5)))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+//
+// Only the lower triangle of the tangent is read, as in the forward pass:
+// given its symmetric completion tx, and S = L⁻¹ tx L⁻ᵀ, the tangent is
+// L Φ(S).
+func (r *Cholesky[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	t := tangents[0]
+	n := t.Rows()
+	sym := t.NewInitFuncMatrix(n, n, func(row, col int) float64 {
+		if row < col {
+			return t.ScalarAt(col, row).F64()
+		}
+		return t.ScalarAt(row, col).F64()
+	})
+	defer mat.ReleaseMatrix(sym)
+	lInv := y.Inverse()
+	defer mat.ReleaseMatrix(lInv)
+	lInvT := lInv.T()
+	defer mat.ReleaseMatrix(lInvT)
+	tmp := lInv.Mul(sym)
+	defer mat.ReleaseMatrix(tmp)
+	s := tmp.Mul(lInvT)
+	defer mat.ReleaseMatrix(s)
+	s.ApplyInPlace(choleskyPhi, s)
+	return y.Mul(s)
+}
+
+// choleskyPhi is the function Φ, taking the lower triangle of a matrix and
+// halving its diagonal.
+func choleskyPhi(row, col int, v float64) float64 {
+	switch {
+	case row == col:
+		return v / 2
+	case row < col:
+		return 0
+	default:
+		return v
+	}
+}
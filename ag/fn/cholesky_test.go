@@ -0,0 +1,104 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCholesky_Forward(t *testing.T) {
+	t.Run("float32", testCholeskyForward[float32])
+	t.Run("float64", testCholeskyForward[float64])
+}
+
+func testCholeskyForward[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(3, 3, []T{
+		4, 12, -16,
+		12, 37, -43,
+		-16, -43, 98,
+	}))
+
+	f := NewCholesky(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{
+		2, 0, 0,
+		6, 1, 0,
+		-8, 5, 3,
+	}, y.Data(), 1.0e-5)
+
+	f.Backward(mat.NewDense(3, 3, []T{
+		1, 0, 0,
+		0, 0, 0,
+		0, 0, 0,
+	}))
+
+	gx := x.grad.Data()
+	assert.InDeltaSlice(t, x.grad.T().Data(), gx, 1.0e-6, "symmetric gradient")
+
+	assert.Panics(t, func() {
+		NewCholesky(newVarWithGrad(mat.NewDense(2, 2, []T{1, 2, 2, 1}))).Forward()
+	})
+	assert.Panics(t, func() {
+		NewCholesky(newVarWithGrad(mat.NewEmptyDense[T](2, 3))).Forward()
+	})
+}
+
+func TestCholesky_Gradients(t *testing.T) {
+	const h = 1e-6
+	a := mat.NewDense(3, 3, []float64{
+		4, 1.2, -0.6,
+		1.2, 3.7, -0.3,
+		-0.6, -0.3, 2.8,
+	})
+	gy := mat.NewDense(3, 3, []float64{
+		0.1, -0.2, 0.3,
+		0.4, 0.5, -0.6,
+		-0.7, 0.8, 0.9,
+	})
+
+	x := newVarWithGrad(a)
+	f := NewCholesky(x)
+	f.Forward()
+	f.Backward(gy)
+	grad := x.grad.Data().F64()
+
+	// The input is symmetric, so each off-diagonal pair of elements is
+	// perturbed at the same time.
+	data := mat.Data[float64](a)
+	loss := func() float64 {
+		y := NewCholesky(newVarWithGrad(a)).Forward()
+		return y.Prod(gy).Sum().Scalar().F64()
+	}
+	n := a.Rows()
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			perturb := func(d float64) {
+				data[i*n+j] += d
+				if i != j {
+					data[j*n+i] += d
+				}
+			}
+			perturb(h)
+			lp := loss()
+			perturb(-2 * h)
+			lm := loss()
+			perturb(h)
+
+			numerical := (lp - lm) / (2 * h)
+			expected := grad[i*n+j]
+			if i != j {
+				expected += grad[j*n+i]
+			}
+			require.InDelta(t, numerical, expected, 1.0e-5, "element (%d, %d)", i, j)
+		}
+	}
+}
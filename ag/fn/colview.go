@@ -47,3 +47,21 @@ func (r *ColView[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ColView[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		x := r.x.Value()
+		e := x.NewInitFuncMatrix(1, x.Columns(), func(_, col int) float64 {
+			return oneIf(col == r.i)
+		})
+		gxs[0] = g.Apply(NewMul(gy, g.Constant(e)))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ColView[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].ExtractColumn(r.i)
+}
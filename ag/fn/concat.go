@@ -59,3 +59,23 @@ func (r *Concat[O]) Backward(gy mat.Matrix) {
 		mat.ReleaseMatrix(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Concat[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(r.xs))
+	offset := 0
+	for i, x := range r.xs {
+		xv := x.Value()
+		if required[i] {
+			gx := g.Apply(NewSlice(gy, offset, 0, offset+xv.Size(), 1))
+			gxs[i] = reshapeLike(g, x, gx)
+		}
+		offset += xv.Size()
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Concat[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].NewConcatV(tangents...)
+}
@@ -9,6 +9,7 @@ import (
 )
 
 // Div is an operator to perform element-wise division over two values.
+// The operands are broadcast together if their shapes differ.
 type Div[O Operand] struct {
 	x1 O
 	x2 O
@@ -34,23 +35,40 @@ func (r *Div[O]) Forward() mat.Matrix {
 
 // Backward computes the backward pass.
 func (r *Div[O]) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !mat.SameDims(x1v, gy) || !mat.SameDims(x2v, gy) {
-		panic("fn: matrices have incompatible dimensions")
-	}
 	if r.x1.RequiresGrad() {
 		gx := gy.Div(r.x2.Value())
 		defer mat.ReleaseMatrix(gx)
-		r.x1.AccGrad(gx)
+		accReducedGrad(r.x1, gx)
 	}
 	if r.x2.RequiresGrad() {
 		x2sq := r.x2.Value().Prod(r.x2.Value())
 		defer mat.ReleaseMatrix(x2sq)
-		gx := r.x1.Value().Prod(gy)
+		gyx1 := gy.Prod(r.x1.Value())
+		defer mat.ReleaseMatrix(gyx1)
+		gx := gyx1.ProdScalarInPlace(-1).Div(x2sq)
 		defer mat.ReleaseMatrix(gx)
-		gx.ProdScalarInPlace(-1)
-		gx.DivInPlace(x2sq)
-		r.x2.AccGrad(gx)
+		accReducedGrad(r.x2, gx)
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Div[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = reduceToShape(g, r.x1, g.Apply(NewDiv(gy, r.x2)))
 	}
+	if required[1] {
+		gyy := g.Apply(NewProd(gy, y))
+		gxs[1] = reduceToShape(g, r.x2, g.Apply(NewNeg(g.Apply(NewDiv(gyy, r.x2)))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Div[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	yx2 := y.Prod(tangents[1])
+	defer mat.ReleaseMatrix(yx2)
+	diff := tangents[0].Sub(yx2)
+	defer mat.ReleaseMatrix(diff)
+	return diff.Div(r.x2.Value())
 }
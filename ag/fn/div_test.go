@@ -41,3 +41,27 @@ func testDivForward[T float.DType](t *testing.T) {
 	assert.InDeltaSlice(t, []T{-2.5, 1.6666666666666, 1.6, 0}, x1.grad.Data(), 1.0e-6)
 	assert.InDeltaSlice(t, []T{0.625, -1.11111111111111, -0.96, 0}, x2.grad.Data(), 1.0e-6)
 }
+
+func TestDiv_Broadcasting(t *testing.T) {
+	t.Run("float32", testDivBroadcasting[float32])
+	t.Run("float64", testDivBroadcasting[float64])
+}
+
+func testDivBroadcasting[T float.DType](t *testing.T) {
+	x1 := newVarWithGrad(mat.NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+	x2 := newVarWithGrad(mat.NewScalar[T](2))
+
+	f := NewDiv(x1, x2)
+	y := f.Forward()
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{0.5, 1, 1.5, 2, 2.5, 3}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewInitDense[T](2, 3, 1))
+	assert.InDeltaSlice(t, []T{0.5, 0.5, 0.5, 0.5, 0.5, 0.5}, x1.grad.Data(), 1.0e-6)
+	assert.True(t, mat.IsScalar(x2.grad))
+	assert.InDelta(t, -5.25, x2.grad.Scalar().F64(), 1.0e-6)
+}
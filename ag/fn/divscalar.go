@@ -55,3 +55,23 @@ func (r *DivScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *DivScalar[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = g.Apply(NewDivScalar(gy, r.x2))
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewNeg(g.Apply(NewDivScalar(g.Apply(NewDot(gy, y)), r.x2))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *DivScalar[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	x2 := r.x2.Value().Scalar().F64()
+	yx2 := y.ProdScalar(tangents[1].Scalar().F64() / x2)
+	defer mat.ReleaseMatrix(yx2)
+	return tangents[0].ProdScalar(1 / x2).SubInPlace(yx2)
+}
@@ -59,3 +59,24 @@ func (r *Dot[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Dot[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = g.Apply(NewProdScalar(r.x2, gy))
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewProdScalar(r.x1, gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Dot[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	x1x2 := r.x1.Value().Prod(tangents[1])
+	defer mat.ReleaseMatrix(x1x2)
+	sum := tangents[0].Prod(r.x2.Value()).AddInPlace(x1x2)
+	defer mat.ReleaseMatrix(sum)
+	return sum.Sum()
+}
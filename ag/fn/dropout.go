@@ -56,6 +56,21 @@ func (r *Dropout[O]) Backward(gy mat.Matrix) {
 	defer mat.ReleaseMatrix(r.mask)
 	if r.x.RequiresGrad() {
 		gx := gy.Prod(r.mask)
+		defer mat.ReleaseMatrix(gx)
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Dropout[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = prodConst(g, gy, r.mask.Clone())
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Dropout[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].Prod(r.mask)
+}
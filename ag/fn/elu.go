@@ -46,3 +46,23 @@ func (r *ELU[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ELU[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		// For x ≤ 0, the derivative is α exp(x) = y + α.
+		pos := r.x.Value().Apply(positiveMask)
+		neg := r.x.Value().Apply(nonPositiveMask)
+		d := prodConst(g, g.Apply(NewAddScalar(y, r.alpha)), neg)
+		gxs[0] = g.Apply(NewProd(gy, g.Apply(NewAdd(g.Constant(pos), d))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode. As in the
+// backward pass, alpha is treated as constant.
+func (r *ELU[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	deriv := r.x.Value().ApplyWithAlpha(eluDeriv, r.alpha.Value().Scalar().F64())
+	return deriv.ProdInPlace(tangents[0])
+}
@@ -40,3 +40,17 @@ func (e *Exp[O]) Backward(gy mat.Matrix) {
 		e.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (e *Exp[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = g.Apply(NewProd(gy, y))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (e *Exp[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return y.Prod(tangents[0])
+}
@@ -30,6 +30,38 @@ type Function[O Operand] interface {
 	Operands() []O
 }
 
+// TangentFunction is optionally implemented by a Function supporting
+// forward-mode automatic differentiation.
+type TangentFunction interface {
+	// Tangent computes the tangent of the output (that is, the
+	// Jacobian-vector product), given the output y of the forward pass and
+	// the tangents of the operands, in the same order of the operands and
+	// with the same shape of their values.
+	Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix
+}
+
+// GradFunction is optionally implemented by a Function whose backward pass
+// can be expressed with further differentiable functions, supporting
+// higher-order derivatives.
+type GradFunction[O Operand] interface {
+	// Grad returns the gradients of the operands, in the same order of the
+	// operands, as new operands built on g, given the output y of the
+	// function and its gradients gy. Only the gradients of the operands
+	// for which required is true are built, the others being the zero
+	// value of O.
+	Grad(g Graph[O], y, gy O, required []bool) []O
+}
+
+// Graph is implemented by the automatic differentiation engine, so that a
+// GradFunction can build its backward pass with further functions.
+type Graph[O Operand] interface {
+	// Apply returns a new operand holding the output of the function f.
+	Apply(f Function[O]) O
+	// Constant returns a new operand holding the value m, which doesn't
+	// require gradients. The graph takes the ownership of m.
+	Constant(m mat.Matrix) O
+}
+
 func operandIsNil[O Operand](o O) bool {
 	if any(o) == nil {
 		return true
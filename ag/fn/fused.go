@@ -0,0 +1,306 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// ElementwiseStage is a single-input element-wise function, along with its
+// derivative, as a stage of a fused chain of functions.
+type ElementwiseStage[O Operand] struct {
+	// F is the function.
+	F func(i, j int, v float64) float64
+	// DF is the derivative of the function.
+	DF func(i, j int, v float64) float64
+	// grad builds the gradients of the function on a graph, for the
+	// stages returned by the Stages method of UnaryElementwise.
+	grad elementwiseGrad[O]
+}
+
+// Stages returns the element-wise function as a single stage, so that it
+// can be fused with other element-wise functions.
+func (r *UnaryElementwise[O]) Stages() []ElementwiseStage[O] {
+	return []ElementwiseStage[O]{{F: r.f, DF: r.df, grad: r.grad}}
+}
+
+// ElementwiseChain is a chain of single-input element-wise functions, fused
+// into a single function, which computes both the output and the gradients
+// with a single pass over the values.
+type ElementwiseChain[O Operand] struct {
+	*UnaryElementwise[O]
+	stages []ElementwiseStage[O]
+}
+
+// NewElementwiseChain returns a new ElementwiseChain Function, applying the
+// given stages to x, in order.
+func NewElementwiseChain[O Operand](x O, stages ...ElementwiseStage[O]) *ElementwiseChain[O] {
+	return &ElementwiseChain[O]{
+		UnaryElementwise: &UnaryElementwise[O]{
+			x:  x,
+			f:  chainFunc(stages),
+			df: chainDeriv(stages),
+		},
+		stages: stages,
+	}
+}
+
+// Stages returns the stages of the chain.
+func (r *ElementwiseChain[O]) Stages() []ElementwiseStage[O] {
+	return r.stages
+}
+
+// Grad builds the gradients of the operands on the graph g.
+//
+// The stages are applied again as separate functions, so that their
+// outputs are available to build the gradients. It panics if any stage was
+// not obtained from the Stages method of a function.
+func (r *ElementwiseChain[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = chainGrad(g, r.stages, r.x, gy)
+	}
+	return gxs
+}
+
+// AffineChain is an Affine function followed by a chain of single-input
+// element-wise functions, typically an activation, fused into a single
+// function.
+type AffineChain[O Operand] struct {
+	*Affine[O]
+	stages []ElementwiseStage[O]
+	f      func(i, j int, v float64) float64
+	df     func(i, j int, v float64) float64
+	// z is the output of the affine function, which is retained for the
+	// backward pass only if any operand requires gradients.
+	z mat.Matrix
+	// forwardOnly reports whether the backward pass is disabled (see
+	// ForwardOnly).
+	forwardOnly bool
+}
+
+// NewAffineChain returns a new AffineChain Function, applying the given
+// stages to the output of the affine function, in order.
+func NewAffineChain[O Operand](affine *Affine[O], stages ...ElementwiseStage[O]) *AffineChain[O] {
+	return &AffineChain[O]{
+		Affine: affine,
+		stages: stages,
+		f:      chainFunc(stages),
+		df:     chainDeriv(stages),
+	}
+}
+
+// Stages returns the element-wise stages following the affine function.
+func (r *AffineChain[O]) Stages() []ElementwiseStage[O] {
+	return r.stages
+}
+
+// ForwardOnly disables the backward pass of the function, for use where it
+// never happens, as in an ag.Plan: the output is then always computed in
+// place, without retaining the output of the affine function, and Backward
+// has no effect. It returns the function itself.
+func (r *AffineChain[O]) ForwardOnly() *AffineChain[O] {
+	r.forwardOnly = true
+	return r
+}
+
+// Forward computes the output of the function.
+func (r *AffineChain[O]) Forward() mat.Matrix {
+	if r.z != nil {
+		mat.ReleaseMatrix(r.z)
+		r.z = nil
+	}
+	z := r.Affine.Forward()
+	if r.forwardOnly || !r.requiresGrad() {
+		return z.ApplyInPlace(r.f, z)
+	}
+	r.z = z
+	return z.Apply(r.f)
+}
+
+// Backward computes the backward pass.
+func (r *AffineChain[O]) Backward(gy mat.Matrix) {
+	if r.z == nil {
+		return
+	}
+	if !mat.SameDims(r.z, gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	gz := r.z.Apply(r.df)
+	defer mat.ReleaseMatrix(gz)
+	gz.ProdInPlace(gy)
+	r.Affine.Backward(gz)
+}
+
+// Grad builds the gradients of the operands on the graph g.
+//
+// The affine function and the stages are applied again as separate
+// functions, as in ElementwiseChain.Grad.
+func (r *AffineChain[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	affine := *r.Affine
+	z := g.Apply(&affine)
+	return affine.Grad(g, z, chainGrad(g, r.stages, z, gy), required)
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *AffineChain[O]) Tangent(_ mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	z := r.z
+	if z == nil {
+		z = r.Affine.Forward()
+		defer mat.ReleaseMatrix(z)
+	}
+	dz := z.Apply(r.df)
+	defer mat.ReleaseMatrix(dz)
+	return r.Affine.Tangent(nil, tangents).ProdInPlace(dz)
+}
+
+// requiresGrad reports whether any operand requires gradients. The fields
+// are visited directly, since Operands allocates a new slice.
+func (r *AffineChain[O]) requiresGrad() bool {
+	a := r.Affine
+	if a.b.RequiresGrad() || a.w1.RequiresGrad() || a.x1.RequiresGrad() {
+		return true
+	}
+	for _, x := range a.wxPairs {
+		if x.RequiresGrad() {
+			return true
+		}
+	}
+	return false
+}
+
+// ProdAdd is the sum of the element-wise products of two pairs of operands,
+// y = x1 ⊙ x2 + x3 ⊙ x4, fused into a single function, which computes the
+// output in a single buffer. It's the typical gate math of recurrent
+// networks, such as the cell of an LSTM.
+//
+// The operands of each product are broadcast together if their shapes
+// differ, and so are the two products.
+type ProdAdd[O Operand] struct {
+	x1 O
+	x2 O
+	x3 O
+	x4 O
+}
+
+// NewProdAdd returns a new ProdAdd Function.
+func NewProdAdd[O Operand](x1, x2, x3, x4 O) *ProdAdd[O] {
+	return &ProdAdd[O]{
+		x1: x1,
+		x2: x2,
+		x3: x3,
+		x4: x4,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *ProdAdd[O]) Operands() []O {
+	return []O{r.x1, r.x2, r.x3, r.x4}
+}
+
+// Forward computes the output of the function.
+func (r *ProdAdd[O]) Forward() mat.Matrix {
+	y := r.x1.Value().Prod(r.x2.Value())
+	x3v, x4v := r.x3.Value(), r.x4.Value()
+	if addProdInPlace(y, x3v, x4v) {
+		return y
+	}
+	defer mat.ReleaseMatrix(y)
+	p := x3v.Prod(x4v)
+	defer mat.ReleaseMatrix(p)
+	return y.Add(p)
+}
+
+// Backward computes the backward pass.
+func (r *ProdAdd[O]) Backward(gy mat.Matrix) {
+	(&Prod[O]{x1: r.x1, x2: r.x2}).Backward(gy)
+	(&Prod[O]{x1: r.x3, x2: r.x4}).Backward(gy)
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ProdAdd[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := (&Prod[O]{x1: r.x1, x2: r.x2}).Grad(g, y, gy, required[:2])
+	return append(gxs, (&Prod[O]{x1: r.x3, x2: r.x4}).Grad(g, y, gy, required[2:])...)
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ProdAdd[O]) Tangent(_ mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	t1 := (&Prod[O]{x1: r.x1, x2: r.x2}).Tangent(nil, tangents[:2])
+	defer mat.ReleaseMatrix(t1)
+	t2 := (&Prod[O]{x1: r.x3, x2: r.x4}).Tangent(nil, tangents[2:])
+	defer mat.ReleaseMatrix(t2)
+	return t1.Add(t2)
+}
+
+// addProdInPlace adds the element-wise product of a and b to y, in a single
+// pass, if the three matrices are Dense with the same dimensions, and
+// reports whether it did.
+func addProdInPlace(y, a, b mat.Matrix) bool {
+	switch yd := y.(type) {
+	case *mat.Dense[float32]:
+		return addProdInPlaceDense(yd, a, b)
+	case *mat.Dense[float64]:
+		return addProdInPlaceDense(yd, a, b)
+	default:
+		return false
+	}
+}
+
+func addProdInPlaceDense[T float.DType](y *mat.Dense[T], a, b mat.Matrix) bool {
+	ad, ok := a.(*mat.Dense[T])
+	if !ok || !mat.SameDims(y, ad) {
+		return false
+	}
+	bd, ok := b.(*mat.Dense[T])
+	if !ok || !mat.SameDims(y, bd) {
+		return false
+	}
+	yData, aData, bData := mat.Data[T](y), mat.Data[T](ad), mat.Data[T](bd)
+	for i, v := range aData {
+		yData[i] += v * bData[i]
+	}
+	return true
+}
+
+// chainGrad builds the gradients of the input x of the chain of stages on
+// the graph g, given the gradients gy of its output.
+func chainGrad[O Operand](g Graph[O], stages []ElementwiseStage[O], x, gy O) O {
+	xs := make([]O, len(stages)+1)
+	xs[0] = x
+	for i, s := range stages {
+		if s.grad == nil {
+			panic("fn: the element-wise stage doesn't support Grad")
+		}
+		xs[i+1] = g.Apply(&UnaryElementwise[O]{x: xs[i], f: s.F, df: s.DF, grad: s.grad})
+	}
+	for i := len(stages) - 1; i >= 0; i-- {
+		gy = stages[i].grad(g, xs[i], xs[i+1], gy)
+	}
+	return gy
+}
+
+// chainFunc returns the composition of the stages.
+func chainFunc[O Operand](stages []ElementwiseStage[O]) func(i, j int, v float64) float64 {
+	return func(i, j int, v float64) float64 {
+		for _, s := range stages {
+			v = s.F(i, j, v)
+		}
+		return v
+	}
+}
+
+// chainDeriv returns the derivative of the composition of the stages,
+// according to the chain rule.
+func chainDeriv[O Operand](stages []ElementwiseStage[O]) func(i, j int, v float64) float64 {
+	return func(i, j int, v float64) float64 {
+		d := 1.0
+		for _, s := range stages {
+			d *= s.DF(i, j, v)
+			v = s.F(i, j, v)
+		}
+		return d
+	}
+}
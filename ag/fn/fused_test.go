@@ -0,0 +1,159 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElementwiseChain(t *testing.T) {
+	t.Run("float32", testElementwiseChain[float32])
+	t.Run("float64", testElementwiseChain[float64])
+}
+
+func testElementwiseChain[T float.DType](t *testing.T) {
+	// Unfused: y = sigmoid(tanh(x)).
+	x1 := newVarWithGrad(mat.NewVecDense([]T{0.1, -0.2, 0.3, 0.0}))
+	tanh := NewTanh(x1)
+	h := newVarWithGrad(tanh.Forward())
+	sigmoid := NewSigmoid(h)
+	expected := sigmoid.Forward()
+
+	gy := mat.NewVecDense([]T{-1.0, 0.5, 0.8, 0.0})
+	sigmoid.Backward(gy)
+	tanh.Backward(h.grad)
+
+	x2 := newVarWithGrad(mat.NewVecDense([]T{0.1, -0.2, 0.3, 0.0}))
+	stages := append(NewTanh(x2).Stages(), NewSigmoid(x2).Stages()...)
+	f := NewElementwiseChain(x2, stages...)
+	assert.Equal(t, []*variable{x2}, f.Operands())
+	assert.Len(t, f.Stages(), 2)
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, expected.Data(), y.Data(), 1.0e-6)
+
+	f.Backward(gy)
+	assert.InDeltaSlice(t, x1.grad.Data(), x2.grad.Data(), 1.0e-6)
+}
+
+func TestAffineChain(t *testing.T) {
+	t.Run("float32", testAffineChain[float32])
+	t.Run("float64", testAffineChain[float64])
+}
+
+func testAffineChain[T float.DType](t *testing.T) {
+	newOperands := func(requiresGrad bool) []*variable {
+		vs := []*variable{
+			newVarWithGrad(mat.NewVecDense([]T{0.1, -0.2})),
+			newVarWithGrad(mat.NewDense(2, 3, []T{0.5, 0.6, -0.8, 0.7, -0.4, 0.1})),
+			newVarWithGrad(mat.NewVecDense([]T{0.4, 0.6, -0.5})),
+		}
+		for _, v := range vs {
+			v.requiresGrad = requiresGrad
+		}
+		return vs
+	}
+
+	// Unfused: y = tanh(affine(b, w, x)).
+	xs1 := newOperands(true)
+	affine := NewAffine(xs1[0], xs1[1], xs1[2])
+	z := newVarWithGrad(affine.Forward())
+	tanh := NewTanh(z)
+	expected := tanh.Forward()
+
+	gy := mat.NewVecDense([]T{-1.0, 0.5})
+	tanh.Backward(gy)
+	affine.Backward(z.grad)
+
+	xs2 := newOperands(true)
+	f := NewAffineChain(NewAffine(xs2[0], xs2[1], xs2[2]), NewTanh(xs2[2]).Stages()...)
+	assert.Equal(t, xs2, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, expected.Data(), y.Data(), 1.0e-6)
+
+	f.Backward(gy)
+	for i, x := range xs2 {
+		assert.InDeltaSlice(t, xs1[i].grad.Data(), x.grad.Data(), 1.0e-6, "operand %d", i)
+	}
+
+	// Without gradients, the output of the affine function is not retained.
+	xs3 := newOperands(false)
+	f = NewAffineChain(NewAffine(xs3[0], xs3[1], xs3[2]), NewTanh(xs3[2]).Stages()...)
+	y = f.Forward()
+	assert.InDeltaSlice(t, expected.Data(), y.Data(), 1.0e-6)
+	assert.Nil(t, f.z)
+	f.Backward(gy)
+	for _, x := range xs3 {
+		assert.Nil(t, x.grad)
+	}
+
+	// Without the backward pass, the output is computed in place.
+	xs4 := newOperands(true)
+	f = NewAffineChain(NewAffine(xs4[0], xs4[1], xs4[2]), NewTanh(xs4[2]).Stages()...).ForwardOnly()
+	y = f.Forward()
+	assert.InDeltaSlice(t, expected.Data(), y.Data(), 1.0e-6)
+	assert.Nil(t, f.z)
+	f.Backward(gy)
+	for _, x := range xs4 {
+		assert.Nil(t, x.grad)
+	}
+}
+
+func TestProdAdd(t *testing.T) {
+	t.Run("float32", testProdAdd[float32])
+	t.Run("float64", testProdAdd[float64])
+}
+
+func testProdAdd[T float.DType](t *testing.T) {
+	newOperands := func(x4 mat.Matrix) []*variable {
+		return []*variable{
+			newVarWithGrad(mat.NewVecDense([]T{0.1, -0.2, 0.3})),
+			newVarWithGrad(mat.NewVecDense([]T{0.5, 0.6, -0.8})),
+			newVarWithGrad(mat.NewVecDense([]T{0.4, 0.6, -0.5})),
+			newVarWithGrad(x4),
+		}
+	}
+	gy := mat.NewVecDense([]T{-1.0, 0.5, 0.8})
+
+	t.Run("same shapes", func(t *testing.T) {
+		// Unfused: y = x1 ⊙ x2 + x3 ⊙ x4.
+		xs1 := newOperands(mat.NewVecDense([]T{0.7, -0.3, 0.2}))
+		p1, p2 := NewProd(xs1[0], xs1[1]), NewProd(xs1[2], xs1[3])
+		z1, z2 := newVarWithGrad(p1.Forward()), newVarWithGrad(p2.Forward())
+		add := NewAdd(z1, z2)
+		expected := add.Forward()
+		add.Backward(gy)
+		p1.Backward(z1.grad)
+		p2.Backward(z2.grad)
+
+		xs2 := newOperands(mat.NewVecDense([]T{0.7, -0.3, 0.2}))
+		f := NewProdAdd(xs2[0], xs2[1], xs2[2], xs2[3])
+		assert.Equal(t, xs2, f.Operands())
+
+		y := f.Forward()
+		assert.InDeltaSlice(t, expected.Data(), y.Data(), 1.0e-6)
+
+		f.Backward(gy)
+		for i, x := range xs2 {
+			assert.InDeltaSlice(t, xs1[i].grad.Data(), x.grad.Data(), 1.0e-6, "operand %d", i)
+		}
+	})
+
+	t.Run("broadcast", func(t *testing.T) {
+		xs := newOperands(mat.NewScalar[T](2))
+		f := NewProdAdd(xs[0], xs[1], xs[2], xs[3])
+
+		y := f.Forward()
+		assert.InDeltaSlice(t, []T{0.85, 1.08, -1.24}, mat.Data[T](y), 1.0e-6)
+
+		f.Backward(gy)
+		assert.InDeltaSlice(t, []T{-0.4 + 0.3 - 0.4}, mat.Data[T](xs[3].grad), 1.0e-6)
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testGraph is a simple implementation of Graph, computing the functions
+// eagerly on variables.
+type testGraph struct{}
+
+func (testGraph) Apply(f Function[*variable]) *variable {
+	return &variable{value: f.Forward()}
+}
+
+func (testGraph) Constant(m mat.Matrix) *variable {
+	return &variable{value: m}
+}
+
+func TestGradFunctions(t *testing.T) {
+	m := func(rows, cols int, data ...float64) mat.Matrix {
+		return mat.NewDense[float64](rows, cols, data)
+	}
+	v := func(data ...float64) mat.Matrix {
+		return mat.NewVecDense[float64](data)
+	}
+	s := func(value float64) mat.Matrix {
+		return mat.NewScalar[float64](value)
+	}
+	tensor := func(shape []int, data ...float64) mat.Matrix {
+		return mat.NewDenseTensor(shape, data)
+	}
+
+	testCases := []struct {
+		name  string
+		newFn func(xs ...*variable) Function[*variable]
+		xs    []mat.Matrix
+	}{
+		{"Identity", func(xs ...*variable) Function[*variable] { return NewIdentity(xs[0]) }, []mat.Matrix{v(1, 2)}},
+		{"Add", func(xs ...*variable) Function[*variable] { return NewAdd(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Add broadcast", func(xs ...*variable) Function[*variable] { return NewAdd(xs[0], xs[1]) }, []mat.Matrix{m(1, 2, 1, 2), m(2, 2, 3, 4, 5, 6)}},
+		{"Add tensor broadcast", func(xs ...*variable) Function[*variable] { return NewAdd(xs[0], xs[1]) }, []mat.Matrix{tensor([]int{2, 1, 2}, 1, 2, 3, 4), tensor([]int{1, 2, 2}, 5, 6, 7, 8)}},
+		{"Sub", func(xs ...*variable) Function[*variable] { return NewSub(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Prod", func(xs ...*variable) Function[*variable] { return NewProd(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Prod broadcast", func(xs ...*variable) Function[*variable] { return NewProd(xs[0], xs[1]) }, []mat.Matrix{m(2, 2, 3, 4, 5, 6), m(2, 1, 1, 2)}},
+		{"Square", func(xs ...*variable) Function[*variable] { return NewSquare(xs[0]) }, []mat.Matrix{v(1, -2)}},
+		{"Div", func(xs ...*variable) Function[*variable] { return NewDiv(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Mul", func(xs ...*variable) Function[*variable] { return NewMul(xs[0], xs[1]) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6), v(1, -1, 2)}},
+		{"MulT", func(xs ...*variable) Function[*variable] { return NewMulT(xs[0], xs[1]) }, []mat.Matrix{m(3, 2, 1, 2, 3, 4, 5, 6), v(1, -1, 2)}},
+		{"Dot", func(xs ...*variable) Function[*variable] { return NewDot(xs[0], xs[1]) }, []mat.Matrix{v(1, 2, 3), v(4, 5, 6)}},
+		{"AddScalar", func(xs ...*variable) Function[*variable] { return NewAddScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"SubScalar", func(xs ...*variable) Function[*variable] { return NewSubScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"ReverseSubScalar", func(xs ...*variable) Function[*variable] { return NewReverseSubScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"ProdScalar", func(xs ...*variable) Function[*variable] { return NewProdScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"DivScalar", func(xs ...*variable) Function[*variable] { return NewDivScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"Affine", func(xs ...*variable) Function[*variable] { return NewAffine(xs[0], xs[1], xs[2], xs[3], xs[4]) }, []mat.Matrix{v(1, 2), m(2, 2, 1, 2, 3, 4), v(-1, 1), m(2, 1, 5, 6), s(2)}},
+		{"AffineChain", func(xs ...*variable) Function[*variable] {
+			return NewAffineChain(NewAffine(xs[0], xs[1], xs[2]), NewTanh(xs[0]).Stages()...)
+		}, []mat.Matrix{v(0.1, -0.2), m(2, 2, 0.1, 0.2, 0.3, 0.4), v(-1, 1)}},
+		{"ProdAdd", func(xs ...*variable) Function[*variable] { return NewProdAdd(xs[0], xs[1], xs[2], xs[3]) }, []mat.Matrix{v(1, 2), v(3, 4), v(-1, 5), v(2, -3)}},
+		{"ElementwiseChain", func(xs ...*variable) Function[*variable] {
+			return NewElementwiseChain(xs[0], append(NewSin(xs[0]).Stages(), NewSigmoid(xs[0]).Stages()...)...)
+		}, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"ReduceSum", func(xs ...*variable) Function[*variable] { return NewReduceSum(xs[0]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4)}},
+		{"ReduceMean", func(xs ...*variable) Function[*variable] { return NewReduceMean(xs[0]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4)}},
+		{"ReduceMax", func(xs ...*variable) Function[*variable] { return NewReduceMax(xs[0]) }, []mat.Matrix{v(1, 4, 3)}},
+		{"ReduceSumAxis", func(xs ...*variable) Function[*variable] { return NewReduceSumAxis(xs[0], 1) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"ReduceMeanAxis", func(xs ...*variable) Function[*variable] { return NewReduceMeanAxis(xs[0], 2) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"SumToShape", func(xs ...*variable) Function[*variable] { return NewSumToShape(xs[0], 1, 3) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"BroadcastTo", func(xs ...*variable) Function[*variable] { return NewBroadcastTo(xs[0], 2, 3) }, []mat.Matrix{m(1, 3, 1, 2, 3)}},
+		{"Permute", func(xs ...*variable) Function[*variable] { return NewPermute(xs[0], 2, 0, 1) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"ReshapeTensor", func(xs ...*variable) Function[*variable] { return NewReshapeTensor(xs[0], 3, 2, 2) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"Transpose", func(xs ...*variable) Function[*variable] { return NewTranspose(xs[0]) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"Trace", func(xs ...*variable) Function[*variable] { return NewTrace(xs[0]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4)}},
+		{"Inverse", func(xs ...*variable) Function[*variable] { return NewInverse(xs[0]) }, []mat.Matrix{m(2, 2, 2, 1, 1, 3)}},
+		{"LogDet", func(xs ...*variable) Function[*variable] { return NewLogDet(xs[0]) }, []mat.Matrix{m(2, 2, 2, 1, 1, 3)}},
+		{"Cholesky", func(xs ...*variable) Function[*variable] { return NewCholesky(xs[0]) }, []mat.Matrix{m(2, 2, 2, 1, 1, 3)}},
+		{"Solve", func(xs ...*variable) Function[*variable] { return NewSolve(xs[0], xs[1]) }, []mat.Matrix{m(2, 2, 2, 1, 1, 3), m(2, 2, 1, 2, 3, 4)}},
+		{"Softmax", func(xs ...*variable) Function[*variable] { return NewSoftmax(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"SparseMax", func(xs ...*variable) Function[*variable] { return NewSparseMax(xs[0]) }, []mat.Matrix{v(0.1, 0.6, 0.9)}},
+		{"SparseMaxLoss", func(xs ...*variable) Function[*variable] { return NewSparseMaxLoss(xs[0]) }, []mat.Matrix{v(0.1, 0.5, 0.7)}},
+		{"Tan", func(xs ...*variable) Function[*variable] { return NewTan(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Tanh", func(xs ...*variable) Function[*variable] { return NewTanh(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Sigmoid", func(xs ...*variable) Function[*variable] { return NewSigmoid(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"HardSigmoid", func(xs ...*variable) Function[*variable] { return NewHardSigmoid(xs[0]) }, []mat.Matrix{v(0.1, -3.5, 2.7)}},
+		{"HardTanh", func(xs ...*variable) Function[*variable] { return NewHardTanh(xs[0]) }, []mat.Matrix{v(0.1, -1.5, 0.7)}},
+		{"ReLU", func(xs ...*variable) Function[*variable] { return NewReLU(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Softsign", func(xs ...*variable) Function[*variable] { return NewSoftsign(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Cos", func(xs ...*variable) Function[*variable] { return NewCos(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Sin", func(xs ...*variable) Function[*variable] { return NewSin(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Neg", func(xs ...*variable) Function[*variable] { return NewNeg(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Reciprocal", func(xs ...*variable) Function[*variable] { return NewReciprocal(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Abs", func(xs ...*variable) Function[*variable] { return NewAbs(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Mish", func(xs ...*variable) Function[*variable] { return NewMish(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"GELU", func(xs ...*variable) Function[*variable] { return NewGELU(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Swish", func(xs ...*variable) Function[*variable] { return NewSwish(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"SwishB", func(xs ...*variable) Function[*variable] { return NewSwishB(xs[0], xs[1]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(1.5)}},
+		{"LeakyReLU", func(xs ...*variable) Function[*variable] { return NewLeakyReLU(xs[0], xs[1]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(0.1)}},
+		{"ELU", func(xs ...*variable) Function[*variable] { return NewELU(xs[0], xs[1]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(0.5)}},
+		{"CELU", func(xs ...*variable) Function[*variable] { return NewCELU(xs[0], xs[1]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(0.5)}},
+		{"SELU", func(xs ...*variable) Function[*variable] { return NewSELU(xs[0], xs[1], xs[2]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(1.5), s(1.1)}},
+		{"SoftPlus", func(xs ...*variable) Function[*variable] { return NewSoftPlus(xs[0], xs[1], xs[2]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(2), s(1)}},
+		{"SoftShrink", func(xs ...*variable) Function[*variable] { return NewSoftShrink(xs[0], xs[1]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(0.2)}},
+		{"Threshold", func(xs ...*variable) Function[*variable] { return NewThreshold(xs[0], xs[1], xs[2]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(0.2), s(-1)}},
+		{"Max", func(xs ...*variable) Function[*variable] { return NewMax(xs[0], xs[1]) }, []mat.Matrix{v(1, 2, 3), v(3, 2.5, 1)}},
+		{"Min", func(xs ...*variable) Function[*variable] { return NewMin(xs[0], xs[1]) }, []mat.Matrix{v(1, 2, 3), v(3, 2.5, 1)}},
+		{"ScalarMax", func(xs ...*variable) Function[*variable] { return NewScalarMax(xs) }, []mat.Matrix{s(1), s(3), s(2)}},
+		{"MaxPooling", func(xs ...*variable) Function[*variable] { return NewMaxPooling(xs[0], 2, 2) }, []mat.Matrix{m(2, 4, 1, 5, 3, 2, 4, 2, 8, 7)}},
+		{"Exp", func(xs ...*variable) Function[*variable] { return NewExp(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Log", func(xs ...*variable) Function[*variable] { return NewLog(xs[0]) }, []mat.Matrix{v(0.1, 0.5, 0.7)}},
+		{"Sqrt", func(xs ...*variable) Function[*variable] { return NewSqrt(xs[0]) }, []mat.Matrix{v(0.1, 0.5, 0.7)}},
+		{"Pow", func(xs ...*variable) Function[*variable] { return NewPow(xs[0], 3) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Dropout", func(xs ...*variable) Function[*variable] { return NewDropout(xs[0], 0.5, rand.NewLockedRand(1)) }, []mat.Matrix{v(1, 2, 3, 4)}},
+		{"Concat", func(xs ...*variable) Function[*variable] { return NewConcat(xs) }, []mat.Matrix{v(1, 2), v(3, 4, 5)}},
+		{"Stack", func(xs ...*variable) Function[*variable] { return NewStack(xs) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"AppendRows", func(xs ...*variable) Function[*variable] { return NewAppendRows(xs[0], xs[1], xs[2]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4), m(1, 2, 5, 6), v(7, 8)}},
+		{"Reshape", func(xs ...*variable) Function[*variable] { return NewReshape(xs[0], 3, 2) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"Flatten", func(xs ...*variable) Function[*variable] { return NewFlatten(xs[0]) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"RotateR", func(xs ...*variable) Function[*variable] { return NewRotateR(xs[0], 1) }, []mat.Matrix{v(1, 2, 3, 4)}},
+		{"RowView", func(xs ...*variable) Function[*variable] { return NewRowView(xs[0], 1) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"ColView", func(xs ...*variable) Function[*variable] { return NewColView(xs[0], 2) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"At", func(xs ...*variable) Function[*variable] { return NewAt(xs[0], 1, 2) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"AtVec", func(xs ...*variable) Function[*variable] { return NewAtVec(xs[0], 1) }, []mat.Matrix{v(1, 2, 3)}},
+		{"Slice", func(xs ...*variable) Function[*variable] { return NewSlice(xs[0], 0, 1, 2, 3) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			xs := make([]*variable, len(tc.xs))
+			for i, x := range tc.xs {
+				xs[i] = newVarWithGrad(x)
+			}
+
+			f := tc.newFn(xs...)
+			gf, ok := f.(GradFunction[*variable])
+			require.True(t, ok, "%T must implement GradFunction", f)
+			y := f.Forward()
+			k := 0.0
+			gy := y.Apply(func(_, _ int, _ float64) float64 {
+				k++
+				return 0.3*k - 0.1
+			})
+
+			// Grad comes first, since Backward may release the
+			// intermediate values of the function, such as the mask of
+			// Dropout.
+			operands := f.Operands()
+			required := make([]bool, len(operands))
+			for i := range required {
+				required[i] = true
+			}
+			gxs := gf.Grad(testGraph{}, &variable{value: y}, &variable{value: gy}, required)
+			require.Len(t, gxs, len(operands))
+			actual := make(map[*variable]mat.Matrix, len(xs))
+			for i, o := range operands {
+				if operandIsNil(gxs[i]) {
+					continue
+				}
+				if acc, ok := actual[o]; ok {
+					actual[o] = acc.Add(gxs[i].Value())
+					continue
+				}
+				actual[o] = gxs[i].Value()
+			}
+
+			f.Backward(gy)
+
+			for i, x := range xs {
+				if x.grad == nil {
+					continue
+				}
+				require.Contains(t, actual, x, "operand %d", i)
+				// Some Backward functions accumulate the gradients with the
+				// shape of a vector, regardless of the shape of the operand.
+				assert.Equal(t, x.grad.Size(), actual[x].Size(), "operand %d", i)
+				assert.InDeltaSlice(t, x.grad.Data().F64(), actual[x].Data().F64(), 1.0e-6, "operand %d", i)
+			}
+		})
+	}
+}
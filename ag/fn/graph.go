@@ -0,0 +1,72 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// scalarLike returns a new constant scalar of the graph, with the value v
+// and the same type of the value of x.
+func scalarLike[O Operand](g Graph[O], x O, v float64) O {
+	return g.Constant(x.Value().NewScalar(v))
+}
+
+// prodConst returns the element-wise product of gy by the constant m, such
+// as the derivative of a piecewise linear function.
+func prodConst[O Operand](g Graph[O], gy O, m mat.Matrix) O {
+	return g.Apply(NewProd(gy, g.Constant(m)))
+}
+
+// reduceToShape returns the gradients gx of the operand x, summed over the
+// dimensions along which x was broadcast in the forward pass, if any.
+func reduceToShape[O Operand](g Graph[O], x, gx O) O {
+	xv := x.Value()
+	if !needsReduction(xv, gx.Value()) {
+		return gx
+	}
+	return g.Apply(NewSumToShape(gx, mat.Shape(xv)...))
+}
+
+// scatterLike returns a new operand with the dimensions of the value of x,
+// holding the values of gy at the positions given by index, and zeros
+// elsewhere: the value at the position i of gy, in row-major order, is moved
+// to the position index(i) of the result. It's computed as the product by a
+// constant selection matrix, so that it's differentiable with respect to gy.
+func scatterLike[O Operand](g Graph[O], x, gy O, index func(i int) int) O {
+	xv, gyv := x.Value(), gy.Value()
+	sel := xv.NewEmptyMatrix(xv.Size(), gyv.Size())
+	for i := 0; i < gyv.Size(); i++ {
+		sel.SetScalar(index(i), i, float.Interface(1.0))
+	}
+	flat := g.Apply(NewReshape(gy, gyv.Size(), 1))
+	return reshapeLike(g, x, g.Apply(NewMul(g.Constant(sel), flat)))
+}
+
+// oneIf returns 1 if the condition is true, 0 otherwise.
+func oneIf(cond bool) float64 {
+	if cond {
+		return 1
+	}
+	return 0
+}
+
+// positiveMask is 1 for the positive values, 0 otherwise.
+func positiveMask(_, _ int, v float64) float64 {
+	return oneIf(v > 0)
+}
+
+// nonPositiveMask is 1 for the values less than or equal to 0, 0 otherwise.
+func nonPositiveMask(_, _ int, v float64) float64 {
+	return oneIf(v <= 0)
+}
+
+// reshapeLike returns the gradients gx reshaped to the dimensions of the
+// value of the operand x.
+func reshapeLike[O Operand](g Graph[O], x, gx O) O {
+	rows, cols := x.Value().Dims()
+	return g.Apply(NewReshape(gx, rows, cols))
+}
@@ -36,3 +36,17 @@ func (r *Identity[O]) Backward(gy mat.Matrix) {
 	}
 	r.x.AccGrad(gy)
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Identity[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = gy
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Identity[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].Clone()
+}
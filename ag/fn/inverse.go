@@ -0,0 +1,72 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Inverse is a Function to calculate the inverse of a square matrix.
+type Inverse[O Operand] struct {
+	x O
+	y mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewInverse returns a new Inverse Function.
+func NewInverse[O Operand](x O) *Inverse[O] {
+	return &Inverse[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Inverse[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Inverse[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	requireSquare(x)
+	r.y = x.Inverse()
+	return r.y
+}
+
+// Backward computes the backward pass.
+//
+// Given Y = X⁻¹, the gradient is gx = -Yᵀ gy Yᵀ.
+func (r *Inverse[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x.Value(), gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		yt := r.y.T()
+		defer mat.ReleaseMatrix(yt)
+		tmp := yt.Mul(gy)
+		defer mat.ReleaseMatrix(tmp)
+		gx := tmp.Mul(yt)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx.ProdScalarInPlace(-1))
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Inverse[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		yt := g.Apply(NewTranspose(y))
+		gxs[0] = g.Apply(NewNeg(g.Apply(NewMul(g.Apply(NewMul(yt, gy)), yt))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+//
+// Given Y = X⁻¹, the tangent is -Y tx Y.
+func (r *Inverse[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	tmp := y.Mul(tangents[0])
+	defer mat.ReleaseMatrix(tmp)
+	return tmp.Mul(y).ProdScalarInPlace(-1)
+}
@@ -45,3 +45,19 @@ func (r *LeakyReLU[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *LeakyReLU[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		gxs[0] = prodConst(g, gy, r.x.Value().ApplyWithAlpha(leakyReLUDeriv, r.alpha.Value().Scalar().F64()))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode. As in the
+// backward pass, alpha is treated as constant.
+func (r *LeakyReLU[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	deriv := r.x.Value().ApplyWithAlpha(leakyReLUDeriv, r.alpha.Value().Scalar().F64())
+	return deriv.ProdInPlace(tangents[0])
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// linAlgMatrix is implemented by matrices providing the decompositions
+// required by the linear algebra functions, such as mat.Dense.
+type linAlgMatrix interface {
+	QR() (q, r mat.Matrix)
+	Cholesky() (mat.Matrix, error)
+	Solve(b mat.Matrix) mat.Matrix
+}
+
+// asLinAlgMatrix returns m as linAlgMatrix, panicking if the matrix type
+// does not support the required decompositions.
+func asLinAlgMatrix(m mat.Matrix) linAlgMatrix {
+	la, ok := m.(linAlgMatrix)
+	if !ok {
+		panic(fmt.Sprintf("fn: linear algebra functions are not supported by %T", m))
+	}
+	return la
+}
+
+// requireSquare panics if m is not a square matrix.
+func requireSquare(m mat.Matrix) {
+	if m.Rows() != m.Columns() {
+		panic("fn: matrix must be square")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsLinAlgMatrix(t *testing.T) {
+	m := mat.NewEmptyDense[float64](2, 2)
+	assert.Same(t, m, asLinAlgMatrix(m))
+
+	s := mat.NewSparse[float64](2, 2, nil, nil, nil)
+	require.Panics(t, func() {
+		asLinAlgMatrix(s)
+	})
+}
+
+// requireGradientsMatchNumerical checks the gradients computed by the
+// backward pass of the Function returned by newFn against central finite
+// differences of the scalar sum(gy ⊙ y), for each operand requiring
+// gradients. Operands must hold float64 Dense matrices.
+func requireGradientsMatchNumerical(
+	t *testing.T,
+	newFn func(xs ...*variable) Function[*variable],
+	gy mat.Matrix,
+	xs ...*variable,
+) {
+	t.Helper()
+	const (
+		h   = 1e-6
+		tol = 1e-5
+	)
+
+	f := newFn(xs...)
+	f.Forward()
+	f.Backward(gy)
+
+	loss := func() float64 {
+		y := newFn(xs...).Forward()
+		return y.Prod(gy).Sum().Scalar().F64()
+	}
+
+	for i, x := range xs {
+		if !x.requiresGrad {
+			continue
+		}
+		require.NotNil(t, x.grad, "operand %d", i)
+		data := mat.Data[float64](x.value)
+		grad := x.grad.Data().F64()
+		for k, v := range data {
+			data[k] = v + h
+			lp := loss()
+			data[k] = v - h
+			lm := loss()
+			data[k] = v
+
+			numerical := (lp - lm) / (2 * h)
+			require.InDelta(t, numerical, grad[k], tol, fmt.Sprintf("operand %d, element %d", i, k))
+		}
+	}
+}
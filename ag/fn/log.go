@@ -41,6 +41,15 @@ func (l *Log[O]) Backward(gy mat.Matrix) {
 	}
 }
 
+// Grad builds the gradients of the operands on the graph g.
+func (l *Log[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = g.Apply(NewDiv(gy, l.x))
+	}
+	return gxs
+}
+
 func safeLogDeriv(_, _ int, v float64) float64 {
 	if v > 0.0 {
 		return 1.0 / v
@@ -50,3 +59,8 @@ func safeLogDeriv(_, _ int, v float64) float64 {
 	}
 	panic("ag: invalid log for negative values")
 }
+
+// Tangent computes the tangent of the output, in forward mode.
+func (l *Log[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].Div(l.x.Value())
+}
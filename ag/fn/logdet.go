@@ -0,0 +1,82 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"math"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// LogDet is a Function to calculate the logarithm of the absolute value
+// of the determinant of a square matrix.
+type LogDet[O Operand] struct {
+	x O
+}
+
+// NewLogDet returns a new LogDet Function.
+func NewLogDet[O Operand](x O) *LogDet[O] {
+	return &LogDet[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *LogDet[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+//
+// The determinant is computed from the QR decomposition of the matrix,
+// as the product of the diagonal of R, avoiding overflow and underflow.
+func (r *LogDet[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	requireSquare(x)
+	q, rm := asLinAlgMatrix(x).QR()
+	defer mat.ReleaseMatrix(q)
+	defer mat.ReleaseMatrix(rm)
+	var y float64
+	for i := 0; i < x.Rows(); i++ {
+		y += math.Log(rm.ScalarAt(i, i).F64())
+	}
+	return x.NewScalar(y)
+}
+
+// Backward computes the backward pass.
+//
+// The gradient is gx = gy X⁻ᵀ.
+func (r *LogDet[O]) Backward(gy mat.Matrix) {
+	if !mat.IsScalar(gy) {
+		panic("fn: the gradient had to be a scalar")
+	}
+	if r.x.RequiresGrad() {
+		inv := r.x.Value().Inverse()
+		defer mat.ReleaseMatrix(inv)
+		gx := inv.T()
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx.ProdScalarInPlace(gy.Scalar().F64()))
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *LogDet[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = g.Apply(NewProdScalar(g.Apply(NewTranspose(g.Apply(NewInverse(r.x)))), gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+//
+// The tangent is tr(X⁻¹ tx).
+func (r *LogDet[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	inv := r.x.Value().Inverse()
+	defer mat.ReleaseMatrix(inv)
+	p := inv.Mul(tangents[0])
+	defer mat.ReleaseMatrix(p)
+	return y.NewScalar(trace(p))
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/mattest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogDet_Forward(t *testing.T) {
+	t.Run("float32", testLogDetForward[float32])
+	t.Run("float64", testLogDetForward[float64])
+}
+
+func testLogDetForward[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(2, 2, []T{
+		4, 7,
+		2, 6,
+	}))
+
+	f := NewLogDet(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDelta(t, math.Log(10), y.Scalar().F64(), 1.0e-6)
+
+	f.Backward(mat.NewScalar[T](2))
+
+	// 2 X⁻ᵀ
+	assert.InDeltaSlice(t, []T{
+		1.2, -0.4,
+		-1.4, 0.8,
+	}, x.grad.Data(), 1.0e-6)
+
+	// The absolute value of a negative determinant is used.
+	y = NewLogDet(newVarWithGrad(mat.NewDense(2, 2, []T{
+		0, 1,
+		2, 0,
+	}))).Forward()
+	assert.InDelta(t, math.Log(2), y.Scalar().F64(), 1.0e-6)
+
+	assert.Panics(t, func() {
+		NewLogDet(newVarWithGrad(mat.NewEmptyDense[T](2, 3))).Forward()
+	})
+}
+
+func TestLogDet_Gradients(t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(3, 3, []float64{
+		2, -1, 0.5,
+		0.3, -3, -1,
+		1, 0.2, 4,
+	}))
+	requireGradientsMatchNumerical(t, func(xs ...*variable) Function[*variable] {
+		return NewLogDet(xs[0])
+	}, mat.NewScalar(0.7), x)
+}
+
+func TestLogDet_ForwardReleasesDecomposition(t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(2, 2, []float64{
+		4, 7,
+		2, 6,
+	}))
+	f := NewLogDet(x)
+	mattest.AssertNoLeaks(t, func() {
+		mat.ReleaseMatrix(f.Forward())
+	})
+}
@@ -71,3 +71,46 @@ func (r *Max[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Max[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	x1v, x2v := r.x1.Value(), r.x2.Value()
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = prodConst(g, gy, x1v.Apply(func(i, j int, v float64) float64 {
+			return oneIf(v > x2v.ScalarAt(i, j).F64())
+		}))
+	}
+	if required[1] {
+		gxs[1] = prodConst(g, gy, x2v.Apply(func(i, j int, v float64) float64 {
+			return oneIf(v > x1v.ScalarAt(i, j).F64())
+		}))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Max[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return selectTangent(r.x1.Value(), r.x2.Value(), tangents, func(a, b float64) bool { return a > b })
+}
+
+// selectTangent returns the tangent of the first operand where first(x1, x2)
+// is true, the tangent of the second operand where first(x2, x1) is true,
+// and zero elsewhere, consistently with the backward pass of Max and Min.
+func selectTangent(x1, x2 mat.Matrix, tangents []mat.Matrix, first func(a, b float64) bool) mat.Matrix {
+	// FIXME: avoid casting to specific type
+	x1Data := x1.Data().F64()
+	x2Data := x2.Data().F64()
+	t1Data := tangents[0].Data().F64()
+	t2Data := tangents[1].Data().F64()
+	data := make([]float64, len(x1Data))
+	for i := range data {
+		switch {
+		case first(x1Data[i], x2Data[i]):
+			data[i] = t1Data[i]
+		case first(x2Data[i], x1Data[i]):
+			data[i] = t2Data[i]
+		}
+	}
+	return x1.NewMatrix(x1.Rows(), x1.Columns(), float.SliceInterface(data))
+}
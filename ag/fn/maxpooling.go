@@ -98,3 +98,29 @@ func (r *MaxPooling[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *MaxPooling[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		cols, xCols := r.y.Columns(), r.x.Value().Columns()
+		gxs[0] = scatterLike(g, r.x, gy, func(i int) int {
+			row, col := i/cols, i%cols
+			return r.argmaxI[row][col]*xCols + r.argmaxJ[row][col]
+		})
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *MaxPooling[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	ty := r.y.ZerosLike()
+	for row := 0; row < r.y.Rows(); row++ {
+		rowi := r.argmaxI[row]
+		rowj := r.argmaxJ[row]
+		for col := 0; col < r.y.Columns(); col++ {
+			ty.SetScalar(row, col, tangents[0].ScalarAt(rowi[col], rowj[col]))
+		}
+	}
+	return ty
+}
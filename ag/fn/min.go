@@ -71,3 +71,25 @@ func (r *Min[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Min[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	x1v, x2v := r.x1.Value(), r.x2.Value()
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = prodConst(g, gy, x1v.Apply(func(i, j int, v float64) float64 {
+			return oneIf(v < x2v.ScalarAt(i, j).F64())
+		}))
+	}
+	if required[1] {
+		gxs[1] = prodConst(g, gy, x2v.Apply(func(i, j int, v float64) float64 {
+			return oneIf(v < x1v.ScalarAt(i, j).F64())
+		}))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Min[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return selectTangent(r.x1.Value(), r.x2.Value(), tangents, func(a, b float64) bool { return a < b })
+}
@@ -17,9 +17,10 @@ type Tan[O Operand] struct {
 func NewTan[O Operand](x O) *Tan[O] {
 	return &Tan[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  tan,
-			df: tanDeriv,
+			x:    x,
+			f:    tan,
+			df:   tanDeriv,
+			grad: tanGrad[O],
 		},
 	}
 }
@@ -33,9 +34,10 @@ type Tanh[O Operand] struct {
 func NewTanh[O Operand](x O) *Tanh[O] {
 	return &Tanh[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  tanh,
-			df: tanhDeriv,
+			x:    x,
+			f:    tanh,
+			df:   tanhDeriv,
+			grad: tanhGrad[O],
 		},
 	}
 }
@@ -49,9 +51,10 @@ type Sigmoid[O Operand] struct {
 func NewSigmoid[O Operand](x O) *Sigmoid[O] {
 	return &Sigmoid[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  sigmoid,
-			df: sigmoidDeriv,
+			x:    x,
+			f:    sigmoid,
+			df:   sigmoidDeriv,
+			grad: sigmoidGrad[O],
 		},
 	}
 }
@@ -65,9 +68,10 @@ type HardSigmoid[O Operand] struct {
 func NewHardSigmoid[O Operand](x O) *HardSigmoid[O] {
 	return &HardSigmoid[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  hardSigmoid,
-			df: hardSigmoidDeriv,
+			x:    x,
+			f:    hardSigmoid,
+			df:   hardSigmoidDeriv,
+			grad: piecewiseLinearGrad[O](hardSigmoidDeriv),
 		},
 	}
 }
@@ -81,9 +85,10 @@ type HardTanh[O Operand] struct {
 func NewHardTanh[O Operand](x O) *HardTanh[O] {
 	return &HardTanh[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  hardTanh,
-			df: hardTanhDeriv,
+			x:    x,
+			f:    hardTanh,
+			df:   hardTanhDeriv,
+			grad: piecewiseLinearGrad[O](hardTanhDeriv),
 		},
 	}
 }
@@ -97,9 +102,10 @@ type ReLU[O Operand] struct {
 func NewReLU[O Operand](x O) *ReLU[O] {
 	return &ReLU[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  relu,
-			df: reluDeriv,
+			x:    x,
+			f:    relu,
+			df:   reluDeriv,
+			grad: piecewiseLinearGrad[O](reluDeriv),
 		},
 	}
 }
@@ -113,9 +119,10 @@ type Softsign[O Operand] struct {
 func NewSoftsign[O Operand](x O) *Softsign[O] {
 	return &Softsign[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  softsign,
-			df: softsignDeriv,
+			x:    x,
+			f:    softsign,
+			df:   softsignDeriv,
+			grad: softsignGrad[O],
 		},
 	}
 }
@@ -129,9 +136,10 @@ type Cos[O Operand] struct {
 func NewCos[O Operand](x O) *Cos[O] {
 	return &Cos[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(_, _ int, v float64) float64 { return math.Cos(v) },
-			df: func(_, _ int, v float64) float64 { return -math.Sin(v) },
+			x:    x,
+			f:    func(_, _ int, v float64) float64 { return math.Cos(v) },
+			df:   func(_, _ int, v float64) float64 { return -math.Sin(v) },
+			grad: cosGrad[O],
 		},
 	}
 }
@@ -145,9 +153,10 @@ type Sin[O Operand] struct {
 func NewSin[O Operand](x O) *Sin[O] {
 	return &Sin[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return math.Sin(v) },
-			df: func(i, j int, v float64) float64 { return math.Cos(v) },
+			x:    x,
+			f:    func(i, j int, v float64) float64 { return math.Sin(v) },
+			df:   func(i, j int, v float64) float64 { return math.Cos(v) },
+			grad: sinGrad[O],
 		},
 	}
 }
@@ -161,9 +170,10 @@ type Neg[O Operand] struct {
 func NewNeg[O Operand](x O) *Neg[O] {
 	return &Neg[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return -v },
-			df: func(i, j int, v float64) float64 { return -1.0 },
+			x:    x,
+			f:    func(i, j int, v float64) float64 { return -v },
+			df:   func(i, j int, v float64) float64 { return -1.0 },
+			grad: negGrad[O],
 		},
 	}
 }
@@ -177,9 +187,10 @@ type Reciprocal[O Operand] struct {
 func NewReciprocal[O Operand](x O) *Reciprocal[O] {
 	return &Reciprocal[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return 1.0 / v },
-			df: func(i, j int, v float64) float64 { return -1.0 / (v * v) },
+			x:    x,
+			f:    func(i, j int, v float64) float64 { return 1.0 / v },
+			df:   func(i, j int, v float64) float64 { return -1.0 / (v * v) },
+			grad: reciprocalGrad[O],
 		},
 	}
 }
@@ -193,9 +204,10 @@ type Abs[O Operand] struct {
 func NewAbs[O Operand](x O) *Abs[O] {
 	return &Abs[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  func(i, j int, v float64) float64 { return math.Abs(v) },
-			df: absDeriv,
+			x:    x,
+			f:    func(i, j int, v float64) float64 { return math.Abs(v) },
+			df:   absDeriv,
+			grad: piecewiseLinearGrad[O](absDeriv),
 		},
 	}
 }
@@ -215,9 +227,10 @@ type Mish[O Operand] struct {
 func NewMish[O Operand](x O) *Mish[O] {
 	return &Mish[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  mish,
-			df: mishDeriv,
+			x:    x,
+			f:    mish,
+			df:   mishDeriv,
+			grad: mishGrad[O],
 		},
 	}
 }
@@ -231,9 +244,10 @@ type GELU[O Operand] struct {
 func NewGELU[O Operand](x O) *GELU[O] {
 	return &GELU[O]{
 		UnaryElementwise: &UnaryElementwise[O]{
-			x:  x,
-			f:  gelu,
-			df: geluDeriv,
+			x:    x,
+			f:    gelu,
+			df:   geluDeriv,
+			grad: geluGrad[O],
 		},
 	}
 }
@@ -244,6 +258,79 @@ func NewSiLU[O Operand](x O) *Swish[O] {
 	return NewSwish[O](x)
 }
 
+// The following functions build the gradients of the element-wise functions
+// with a derivative which is not piecewise constant, as compositions of
+// further functions, so that they can be differentiated again.
+
+func tanGrad[O Operand](g Graph[O], x, y, gy O) O {
+	// 1 + tan²(x)
+	d := g.Apply(NewAddScalar(g.Apply(NewSquare(y)), scalarLike(g, x, 1)))
+	return g.Apply(NewProd(gy, d))
+}
+
+func tanhGrad[O Operand](g Graph[O], x, y, gy O) O {
+	// 1 - tanh²(x)
+	d := g.Apply(NewReverseSubScalar(g.Apply(NewSquare(y)), scalarLike(g, x, 1)))
+	return g.Apply(NewProd(gy, d))
+}
+
+func sigmoidGrad[O Operand](g Graph[O], x, y, gy O) O {
+	// sigmoid(x) (1 - sigmoid(x))
+	d := g.Apply(NewProd(y, g.Apply(NewReverseSubScalar(y, scalarLike(g, x, 1)))))
+	return g.Apply(NewProd(gy, d))
+}
+
+func softsignGrad[O Operand](g Graph[O], x, y, gy O) O {
+	// (1 - |softsign(x)|)²
+	d := g.Apply(NewReverseSubScalar(g.Apply(NewAbs(y)), scalarLike(g, x, 1)))
+	return g.Apply(NewProd(gy, g.Apply(NewSquare(d))))
+}
+
+func cosGrad[O Operand](g Graph[O], x, _, gy O) O {
+	return g.Apply(NewNeg(g.Apply(NewProd(gy, g.Apply(NewSin(x))))))
+}
+
+func sinGrad[O Operand](g Graph[O], x, _, gy O) O {
+	return g.Apply(NewProd(gy, g.Apply(NewCos(x))))
+}
+
+func negGrad[O Operand](g Graph[O], _, _, gy O) O {
+	return g.Apply(NewNeg(gy))
+}
+
+func reciprocalGrad[O Operand](g Graph[O], _, y, gy O) O {
+	// -1 / x²
+	return g.Apply(NewNeg(g.Apply(NewProd(gy, g.Apply(NewSquare(y))))))
+}
+
+func mishGrad[O Operand](g Graph[O], x, _, gy O) O {
+	// t + x (1 - t²) sigmoid(x), where t = tanh(log(1 + exp(x))).
+	one := scalarLike(g, x, 1)
+	sp := g.Apply(NewLog(g.Apply(NewAddScalar(g.Apply(NewExp(x)), one))))
+	t := g.Apply(NewTanh(sp))
+	dt := g.Apply(NewReverseSubScalar(g.Apply(NewSquare(t)), one))
+	d := g.Apply(NewProd(g.Apply(NewProd(x, dt)), g.Apply(NewSigmoid(x))))
+	return g.Apply(NewProd(gy, g.Apply(NewAdd(t, d))))
+}
+
+func geluGrad[O Operand](g Graph[O], x, _, gy O) O {
+	// Given u = c (x + k x³), with c = √(2/π), and t = tanh(u), the
+	// derivative is (1 + t) / 2 + x (1 - t²) c (1 + 3 k x²) / 2.
+	const k = 0.044715
+	c := math.Sqrt(2 / math.Pi)
+	one := scalarLike(g, x, 1)
+	half := scalarLike(g, x, 0.5)
+	x3 := g.Apply(NewPow(x, 3))
+	u := g.Apply(NewProdScalar(g.Apply(NewAdd(x, g.Apply(NewProdScalar(x3, scalarLike(g, x, k))))), scalarLike(g, x, c)))
+	t := g.Apply(NewTanh(u))
+	d1 := g.Apply(NewProdScalar(g.Apply(NewAddScalar(t, one)), half))
+	x2 := g.Apply(NewProdScalar(g.Apply(NewSquare(x)), scalarLike(g, x, 3*k)))
+	du := g.Apply(NewProdScalar(g.Apply(NewAddScalar(x2, one)), scalarLike(g, x, c/2)))
+	dt := g.Apply(NewReverseSubScalar(g.Apply(NewSquare(t)), one))
+	d2 := g.Apply(NewProd(g.Apply(NewProd(x, dt)), du))
+	return g.Apply(NewProd(gy, g.Apply(NewAdd(d1, d2))))
+}
+
 func absDeriv(_, _ int, v float64) float64 {
 	if v < 0 {
 		return -1
@@ -41,20 +41,16 @@ func (r *Mul[O]) Backward(gy mat.Matrix) {
 	}
 	var wg sync.WaitGroup
 	if r.x1.RequiresGrad() {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+		goBackward(&wg, func() {
 			x2t := r.x2.Value().T()
 			defer mat.ReleaseMatrix(x2t)
 			gx := gy.Mul(x2t)
 			defer mat.ReleaseMatrix(gx)
 			r.x1.AccGrad(gx)
-		}()
+		})
 	}
 	if r.x2.RequiresGrad() {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+		goBackward(&wg, func() {
 			//r.x2.AccGrad(gy.T().Mul(r.x1).T()) // alternative method
 			if gy.Columns() == 1 {
 				gx := r.x1.Value().MulT(gy)
@@ -67,7 +63,26 @@ func (r *Mul[O]) Backward(gy mat.Matrix) {
 				defer mat.ReleaseMatrix(gx)
 				r.x2.AccGrad(gx)
 			}
-		}()
+		})
 	}
 	wg.Wait()
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Mul[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = g.Apply(NewMul(gy, g.Apply(NewTranspose(r.x2))))
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewMul(g.Apply(NewTranspose(r.x1)), gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Mul[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	x1x2 := r.x1.Value().Mul(tangents[1])
+	defer mat.ReleaseMatrix(x1x2)
+	return tangents[0].Mul(r.x2.Value()).AddInPlace(x1x2)
+}
@@ -111,3 +111,31 @@ func testMulForwardMatrixVector[T float.DType](t *testing.T) {
 
 	assert.InDeltaSlice(t, []T{-0.62, 0.38, -0.22, -0.5}, x2.grad.Data(), 1.0e-6)
 }
+
+func TestMul_ForwardMatrixSparseVector(t *testing.T) {
+	t.Run("float32", testMulForwardMatrixSparseVector[float32])
+	t.Run("float64", testMulForwardMatrixSparseVector[float64])
+}
+
+func testMulForwardMatrixSparseVector[T float.DType](t *testing.T) {
+	x1 := newVarWithGrad(mat.NewDense(3, 4, []T{
+		0.1, 0.2, 0.3, 0.0,
+		0.4, 0.5, -0.6, 0.7,
+		-0.5, 0.8, -0.8, -0.1,
+	}))
+	x2 := newVarWithGrad(mat.NewVecSparse([]T{0, -0.9, 0, 1.0}))
+
+	f := NewMul(x1, x2)
+	y := f.Forward()
+
+	assert.InDeltaSlice(t, []T{-0.18, 0.25, -0.82}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewVecDense([]T{0.2, -0.6, 0.8}))
+
+	assert.InDeltaSlice(t, []T{
+		0, -0.18, 0, 0.2,
+		0, 0.54, 0, -0.6,
+		0, -0.72, 0, 0.8,
+	}, x1.grad.Data(), 1.0e-6)
+	assert.InDeltaSlice(t, []T{-0.62, 0.38, -0.22, -0.5}, x2.grad.Data(), 1.0e-6)
+}
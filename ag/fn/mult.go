@@ -41,20 +41,16 @@ func (r *MulT[O]) Backward(gy mat.Matrix) {
 	//}
 	var wg sync.WaitGroup
 	if r.x1.RequiresGrad() {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+		goBackward(&wg, func() {
 			x2t := r.x2.Value().T()
 			defer mat.ReleaseMatrix(x2t)
 			gx := gy.Mul(x2t)
 			defer mat.ReleaseMatrix(gx)
 			r.x1.AccGrad(gx.T())
-		}()
+		})
 	}
 	if r.x2.RequiresGrad() {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
+		goBackward(&wg, func() {
 			//r.x2.AccGrad(gy.T().MulT(r.x1).T()) // alternative method
 			if gy.Columns() == 1 {
 				gx := r.x1.Value().Mul(gy)
@@ -67,7 +63,26 @@ func (r *MulT[O]) Backward(gy mat.Matrix) {
 				defer mat.ReleaseMatrix(gx)
 				r.x2.AccGrad(gx)
 			}
-		}()
+		})
 	}
 	wg.Wait()
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *MulT[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = g.Apply(NewMul(r.x2, g.Apply(NewTranspose(gy))))
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewMul(r.x1, gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *MulT[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	x1tx2 := r.x1.Value().MulT(tangents[1])
+	defer mat.ReleaseMatrix(x1tx2)
+	return tangents[0].MulT(r.x2.Value()).AddInPlace(x1tx2)
+}
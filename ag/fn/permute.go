@@ -0,0 +1,75 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Permute is a Function which reorders the dimensions of a tensor operand
+// according to the given axes.
+type Permute[O Operand] struct {
+	x    O
+	axes []int
+}
+
+// NewPermute returns a new Permute Function.
+func NewPermute[O Operand](x O, axes ...int) *Permute[O] {
+	return &Permute[O]{
+		x:    x,
+		axes: axes,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Permute[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Permute[O]) Forward() mat.Matrix {
+	return mat.AsTensor(r.x.Value()).Permute(r.axes...).Clone()
+}
+
+// Backward computes the backward pass.
+func (r *Permute[O]) Backward(gy mat.Matrix) {
+	if !r.x.RequiresGrad() {
+		return
+	}
+	xShape := mat.Shape(r.x.Value())
+	if len(xShape) != len(r.axes) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	yShape := make([]int, len(r.axes))
+	inverse := make([]int, len(r.axes))
+	for i, axis := range r.axes {
+		if axis < 0 {
+			axis += len(r.axes)
+		}
+		yShape[i] = xShape[axis]
+		inverse[axis] = i
+	}
+	gx := mat.WithShape(gy, yShape...).Permute(inverse...).Clone()
+	defer mat.ReleaseMatrix(gx)
+	r.x.AccGrad(gx)
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Permute[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		inverse := make([]int, len(r.axes))
+		for i, axis := range r.axes {
+			inverse[normalizedAxis(axis, len(r.axes))] = i
+		}
+		gxs[0] = g.Apply(NewPermute(gy, inverse...))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Permute[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return mat.AsTensor(tangents[0]).Permute(r.axes...).Clone()
+}
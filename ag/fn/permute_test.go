@@ -0,0 +1,64 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermute_Forward(t *testing.T) {
+	t.Run("float32", testPermuteForward[float32])
+	t.Run("float64", testPermuteForward[float64])
+}
+
+func testPermuteForward[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDenseTensor([]int{2, 3, 2}, []T{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	}))
+
+	f := NewPermute(x, 2, 0, 1)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, []int{2, 2, 3}, mat.Shape(y))
+	assert.Equal(t, []T{
+		1, 3, 5, 7, 9, 11,
+		2, 4, 6, 8, 10, 12,
+	}, mat.Data[T](y))
+
+	f.Backward(mat.NewDenseTensor([]int{2, 2, 3}, []T{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	}))
+
+	assert.Equal(t, []int{2, 3, 2}, mat.Shape(x.grad))
+	assert.Equal(t, []T{
+		1, 7, 2, 8, 3, 9,
+		4, 10, 5, 11, 6, 12,
+	}, mat.Data[T](x.grad))
+}
+
+func TestPermute_Backward_DenseGradients(t *testing.T) {
+	t.Run("float32", testPermuteBackwardDenseGradients[float32])
+	t.Run("float64", testPermuteBackwardDenseGradients[float64])
+}
+
+func testPermuteBackwardDenseGradients[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(2, 3, []T{1, 2, 3, 4, 5, 6}))
+
+	f := NewPermute(x, 1, 0)
+	y := f.Forward()
+	assert.Equal(t, []T{1, 4, 2, 5, 3, 6}, mat.Data[T](y))
+
+	// gradients given as a plain matrix with the projected dimensions
+	f.Backward(mat.NewDense(3, 2, []T{1, 2, 3, 4, 5, 6}))
+	assert.Equal(t, []int{2, 3}, mat.Shape(x.grad))
+	assert.Equal(t, []T{1, 3, 5, 2, 4, 6}, mat.Data[T](x.grad))
+}
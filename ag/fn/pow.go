@@ -44,3 +44,18 @@ func (r *Pow[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Pow[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		d := g.Apply(NewPow(r.x, r.power-1))
+		gxs[0] = g.Apply(NewProd(gy, g.Apply(NewProdScalar(d, scalarLike(g, r.x, r.power)))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Pow[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return r.x.Value().Pow(r.power - 1).ProdScalarInPlace(r.power).ProdInPlace(tangents[0])
+}
@@ -9,6 +9,7 @@ import (
 )
 
 // Prod is an operator to perform element-wise product over two values.
+// The operands are broadcast together if their shapes differ.
 type Prod[O Operand] struct {
 	x1 O
 	x2 O
@@ -34,19 +35,33 @@ func (r *Prod[O]) Forward() mat.Matrix {
 
 // Backward computes the backward pass.
 func (r *Prod[O]) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !mat.SameDims(x1v, gy) || !mat.SameDims(x2v, gy) {
-		panic("fn: matrices have incompatible dimensions")
-	}
 	if r.x1.RequiresGrad() {
-		gx := r.x2.Value().Prod(gy)
+		gx := gy.Prod(r.x2.Value())
 		defer mat.ReleaseMatrix(gx)
-		r.x1.AccGrad(gx)
+		accReducedGrad(r.x1, gx)
 	}
 	if r.x2.RequiresGrad() {
-		gx := r.x1.Value().Prod(gy)
+		gx := gy.Prod(r.x1.Value())
 		defer mat.ReleaseMatrix(gx)
-		r.x2.AccGrad(gx)
+		accReducedGrad(r.x2, gx)
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Prod[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = reduceToShape(g, r.x1, g.Apply(NewProd(gy, r.x2)))
 	}
+	if required[1] {
+		gxs[1] = reduceToShape(g, r.x2, g.Apply(NewProd(gy, r.x1)))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Prod[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	x1x2 := r.x1.Value().Prod(tangents[1])
+	defer mat.ReleaseMatrix(x1x2)
+	return tangents[0].Prod(r.x2.Value()).AddInPlace(x1x2)
 }
@@ -41,3 +41,31 @@ func testProdForward[T float.DType](t *testing.T) {
 	assert.InDeltaSlice(t, []T{-0.4, 0.15, 0.4, 0}, x1.grad.Data(), 1.0e-6)
 	assert.InDeltaSlice(t, []T{-0.1, 0.1, 0.24, 0}, x2.grad.Data(), 1.0e-6)
 }
+
+func TestProd_Broadcasting(t *testing.T) {
+	t.Run("float32", testProdBroadcasting[float32])
+	t.Run("float64", testProdBroadcasting[float64])
+}
+
+func testProdBroadcasting[T float.DType](t *testing.T) {
+	x1 := newVarWithGrad(mat.NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+	x2 := newVarWithGrad(mat.NewDense[T](1, 3, []T{1, 2, 3}))
+
+	f := NewProd(x1, x2)
+	y := f.Forward()
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{1, 4, 9, 4, 10, 18}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+	assert.InDeltaSlice(t, []T{1, 4, 9, 4, 10, 18}, x1.grad.Data(), 1.0e-6)
+	assert.Equal(t, 1, x2.grad.Rows())
+	assert.Equal(t, 3, x2.grad.Columns())
+	assert.InDeltaSlice(t, []T{17, 29, 45}, x2.grad.Data(), 1.0e-6)
+}
@@ -50,3 +50,22 @@ func (r *ProdScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ProdScalar[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = g.Apply(NewProdScalar(gy, r.x2))
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewDot(gy, r.x1))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ProdScalar[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	x1x2 := r.x1.Value().ProdScalar(tangents[1].Scalar().F64())
+	defer mat.ReleaseMatrix(x1x2)
+	return tangents[0].ProdScalar(r.x2.Value().Scalar().F64()).AddInPlace(x1x2)
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// ReduceSumAxis is an operator to perform the sum of a tensor's values
+// along one axis, which is removed from the resulting shape.
+type ReduceSumAxis[O Operand] struct {
+	x    O
+	axis int
+}
+
+// NewReduceSumAxis returns a new ReduceSumAxis Function.
+func NewReduceSumAxis[O Operand](x O, axis int) *ReduceSumAxis[O] {
+	return &ReduceSumAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *ReduceSumAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *ReduceSumAxis[O]) Forward() mat.Matrix {
+	return mat.AsTensor(r.x.Value()).SumAxis(r.axis)
+}
+
+// Backward computes the backward pass.
+func (r *ReduceSumAxis[O]) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		gx := expandReducedAxis(gy, mat.Shape(r.x.Value()), r.axis)
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ReduceSumAxis[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = expandReducedAxisGrad(g, gy, mat.Shape(r.x.Value()), r.axis)
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ReduceSumAxis[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return mat.AsTensor(tangents[0]).SumAxis(r.axis)
+}
+
+// ReduceMeanAxis is an operator to perform the mean of a tensor's values
+// along one axis, which is removed from the resulting shape.
+type ReduceMeanAxis[O Operand] struct {
+	x    O
+	axis int
+}
+
+// NewReduceMeanAxis returns a new ReduceMeanAxis Function.
+func NewReduceMeanAxis[O Operand](x O, axis int) *ReduceMeanAxis[O] {
+	return &ReduceMeanAxis[O]{
+		x:    x,
+		axis: axis,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *ReduceMeanAxis[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *ReduceMeanAxis[O]) Forward() mat.Matrix {
+	return mat.AsTensor(r.x.Value()).MeanAxis(r.axis)
+}
+
+// Backward computes the backward pass.
+func (r *ReduceMeanAxis[O]) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		xShape := mat.Shape(r.x.Value())
+		gx := expandReducedAxis(gy, xShape, r.axis)
+		defer mat.ReleaseMatrix(gx)
+		if n := xShape[normalizedAxis(r.axis, len(xShape))]; n > 0 {
+			gx.ProdScalarInPlace(1 / float64(n))
+		}
+		r.x.AccGrad(gx)
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ReduceMeanAxis[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		xShape := mat.Shape(r.x.Value())
+		gx := expandReducedAxisGrad(g, gy, xShape, r.axis)
+		n := xShape[normalizedAxis(r.axis, len(xShape))]
+		gxs[0] = g.Apply(NewProdScalar(gx, scalarLike(g, r.x, 1/float64(n))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ReduceMeanAxis[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return mat.AsTensor(tangents[0]).MeanAxis(r.axis)
+}
+
+// expandReducedAxis broadcasts the gradients gy of a reduction along
+// the given axis back to the original shape of the operand.
+func expandReducedAxis(gy mat.Matrix, xShape []int, axis int) mat.Matrix {
+	axis = normalizedAxis(axis, len(xShape))
+	yShape := append(append([]int{}, xShape[:axis]...), xShape[axis+1:]...)
+	return mat.WithShape(gy, yShape...).Unsqueeze(axis).BroadcastTo(xShape...).Clone()
+}
+
+func normalizedAxis(axis, rank int) int {
+	if axis < 0 {
+		return axis + rank
+	}
+	return axis
+}
+
+// expandReducedAxisGrad is like expandReducedAxis, building the gradients
+// on the graph g.
+func expandReducedAxisGrad[O Operand](g Graph[O], gy O, xShape []int, axis int) O {
+	axis = normalizedAxis(axis, len(xShape))
+	shape := append(append([]int{}, xShape[:axis]...), 1)
+	shape = append(shape, xShape[axis+1:]...)
+	gx := g.Apply(NewReshapeTensor(gy, shape...))
+	return g.Apply(NewBroadcastTo(gx, xShape...))
+}
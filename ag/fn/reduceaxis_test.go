@@ -0,0 +1,65 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReduceSumAxis_Forward(t *testing.T) {
+	t.Run("float32", testReduceSumAxisForward[float32])
+	t.Run("float64", testReduceSumAxisForward[float64])
+}
+
+func testReduceSumAxisForward[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDenseTensor([]int{2, 3, 2}, []T{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	}))
+
+	f := NewReduceSumAxis(x, 1)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, []int{2, 2}, mat.Shape(y))
+	assert.InDeltaSlice(t, []T{9, 12, 27, 30}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense(2, 2, []T{1, 2, 3, 4}))
+	assert.Equal(t, []int{2, 3, 2}, mat.Shape(x.grad))
+	assert.InDeltaSlice(t, []T{
+		1, 2, 1, 2, 1, 2,
+		3, 4, 3, 4, 3, 4,
+	}, x.grad.Data(), 1.0e-6)
+}
+
+func TestReduceMeanAxis_Forward(t *testing.T) {
+	t.Run("float32", testReduceMeanAxisForward[float32])
+	t.Run("float64", testReduceMeanAxisForward[float64])
+}
+
+func testReduceMeanAxisForward[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(2, 4, []T{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+	}))
+
+	f := NewReduceMeanAxis(x, -1)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, []int{2}, mat.Shape(y))
+	assert.InDeltaSlice(t, []T{2.5, 6.5}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewVecDense([]T{4, 8}))
+	assert.Equal(t, []int{2, 4}, mat.Shape(x.grad))
+	assert.InDeltaSlice(t, []T{
+		1, 1, 1, 1,
+		2, 2, 2, 2,
+	}, x.grad.Data(), 1.0e-6)
+}
@@ -43,3 +43,19 @@ func (r *ReduceMean[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ReduceMean[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		x := r.x.Value()
+		d := x.NewInitMatrix(x.Rows(), x.Columns(), 1/float64(x.Size()))
+		gxs[0] = g.Apply(NewProdScalar(g.Constant(d), gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ReduceMean[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].Sum().ProdScalarInPlace(1 / float64(tangents[0].Size()))
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// ReshapeTensor is a Function which reshapes an operand into a new tensor
+// of the given shape.
+type ReshapeTensor[O Operand] struct {
+	x     O
+	shape []int
+}
+
+// NewReshapeTensor returns a new ReshapeTensor Function.
+func NewReshapeTensor[O Operand](x O, shape ...int) *ReshapeTensor[O] {
+	return &ReshapeTensor[O]{
+		x:     x,
+		shape: shape,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *ReshapeTensor[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *ReshapeTensor[O]) Forward() mat.Matrix {
+	view := mat.WithShape(r.x.Value(), r.shape...)
+	// A non-contiguous input is copied by WithShape, and then released,
+	// while releasing a view has no effect.
+	defer mat.ReleaseMatrix(view)
+	return view.Clone()
+}
+
+// Backward computes the backward pass.
+func (r *ReshapeTensor[O]) Backward(gy mat.Matrix) {
+	x := r.x.Value()
+	if gy.Size() != x.Size() {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if r.x.RequiresGrad() {
+		r.x.AccGrad(mat.WithShape(gy, mat.Shape(x)...))
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ReshapeTensor[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = g.Apply(NewReshapeTensor(gy, mat.Shape(r.x.Value())...))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ReshapeTensor[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	view := mat.WithShape(tangents[0], r.shape...)
+	defer mat.ReleaseMatrix(view)
+	return view.Clone()
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/mattest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReshapeTensor_Forward(t *testing.T) {
+	t.Run("float32", testReshapeTensorForward[float32])
+	t.Run("float64", testReshapeTensorForward[float64])
+}
+
+func testReshapeTensorForward[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(2, 6, []T{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	}))
+
+	f := NewReshapeTensor(x, 3, -1, 2)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.Equal(t, []int{3, 2, 2}, mat.Shape(y))
+	assert.Equal(t, mat.Data[T](x.value), mat.Data[T](y))
+
+	f.Backward(mat.NewInitDenseTensor[T](1, 3, 2, 2))
+	assert.Equal(t, []int{2, 6}, mat.Shape(x.grad))
+	assert.Equal(t, []T{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}, mat.Data[T](x.grad))
+
+	assert.Panics(t, func() { f.Backward(mat.NewEmptyDense[T](2, 2)) })
+}
+
+func TestReshapeTensor_ForwardNonContiguous(t *testing.T) {
+	x := newVarWithGrad(mat.AsTensor(mat.NewDense(2, 3, []float64{
+		1, 2, 3,
+		4, 5, 6,
+	})).Permute(1, 0))
+	f := NewReshapeTensor(x, 6)
+
+	mattest.AssertNoLeaks(t, func() {
+		y := f.Forward()
+		assert.Equal(t, []float64{1, 4, 2, 5, 3, 6}, mat.Data[float64](y))
+		mat.ReleaseMatrix(y)
+	})
+}
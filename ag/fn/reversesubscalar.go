@@ -48,3 +48,20 @@ func (r *ReverseSubScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ReverseSubScalar[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = g.Apply(NewNeg(gy))
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewReduceSum(gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ReverseSubScalar[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].ProdScalar(-1).AddScalarInPlace(tangents[1].Scalar().F64())
+}
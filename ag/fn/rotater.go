@@ -41,6 +41,21 @@ func (r *RotateR[O]) Backward(gy mat.Matrix) {
 	}
 }
 
+// Grad builds the gradients of the operands on the graph g.
+func (r *RotateR[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = g.Apply(NewRotateR(gy, r.x.Value().Size()-r.i))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *RotateR[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	t := tangents[0]
+	return rotate(t, t.Size()-r.i)
+}
+
 func rotate(m mat.Matrix, i int) mat.Matrix {
 	size := m.Size()
 
@@ -47,3 +47,21 @@ func (r *RowView[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *RowView[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		x := r.x.Value()
+		e := x.NewInitFuncMatrix(x.Rows(), 1, func(row, _ int) float64 {
+			return oneIf(row == r.i)
+		})
+		gxs[0] = g.Apply(NewMul(g.Constant(e), gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *RowView[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].ExtractRow(r.i)
+}
@@ -54,3 +54,17 @@ func (r *ScalarMax[O]) Backward(gy mat.Matrix) {
 		target.AccGrad(gy)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *ScalarMax[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(r.xs))
+	if required[r.argmax] {
+		gxs[r.argmax] = gy
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *ScalarMax[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[r.argmax].Clone()
+}
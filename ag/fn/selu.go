@@ -54,3 +54,28 @@ func (r *SELU[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *SELU[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		// For x ≤ 0, the derivative is scale α exp(x) = y + scale α.
+		pos := r.x.Value().Apply(positiveMask)
+		neg := r.x.Value().Apply(nonPositiveMask)
+		sa := g.Apply(NewProdScalar(r.alpha, r.scale))
+		d := prodConst(g, g.Apply(NewAddScalar(y, sa)), neg)
+		gxs[0] = g.Apply(NewProd(gy, g.Apply(NewAdd(g.Apply(NewProdScalar(g.Constant(pos), r.scale)), d))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode. As in the
+// backward pass, alpha and scale are treated as constant.
+func (r *SELU[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	deriv := r.x.Value().ApplyWithAlpha(
+		seluDeriv,
+		r.alpha.Value().Scalar().F64(),
+		r.scale.Value().Scalar().F64(),
+	)
+	return deriv.ProdInPlace(tangents[0])
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// sequentialBackward is 1 if the backward functions must propagate the
+// gradients to their operands sequentially, 0 otherwise.
+var sequentialBackward int32
+
+// SetSequentialBackward enables or disables the sequential propagation of
+// the gradients to the operands of the functions which otherwise do it
+// concurrently (such as Mul and Affine), and returns the previous setting.
+//
+// When enabled, the gradients are accumulated in the order of the operands,
+// so that operands appearing more than once always receive the same sum.
+// It's enabled by ag.SetDeterministic.
+func SetSequentialBackward(enabled bool) bool {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	return atomic.SwapInt32(&sequentialBackward, v) != 0
+}
+
+// goBackward calls f in a new goroutine tracked by wg or, if the sequential
+// backward mode is enabled, synchronously.
+func goBackward(wg *sync.WaitGroup, f func()) {
+	if atomic.LoadInt32(&sequentialBackward) != 0 {
+		f()
+		return
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f()
+	}()
+}
@@ -54,3 +54,24 @@ func (s *Slice[O]) Backward(gy mat.Matrix) {
 		s.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (s *Slice[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		x := s.x.Value()
+		rows := x.NewInitFuncMatrix(x.Rows(), s.toRow-s.fromRow, func(row, col int) float64 {
+			return oneIf(row == col+s.fromRow)
+		})
+		cols := x.NewInitFuncMatrix(s.toCol-s.fromCol, x.Columns(), func(row, col int) float64 {
+			return oneIf(col == row+s.fromCol)
+		})
+		gxs[0] = g.Apply(NewMul(g.Apply(NewMul(g.Constant(rows), gy)), g.Constant(cols)))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (s *Slice[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].Slice(s.fromRow, s.fromCol, s.toRow, s.toCol)
+}
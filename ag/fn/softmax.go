@@ -54,3 +54,19 @@ func (r *Softmax[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Softmax[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = g.Apply(NewProd(y, g.Apply(NewSubScalar(gy, g.Apply(NewDot(gy, y))))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Softmax[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	yx := y.DotUnitary(tangents[0])
+	defer mat.ReleaseMatrix(yx)
+	return tangents[0].SubScalar(yx.Scalar().F64()).ProdInPlace(y)
+}
@@ -54,3 +54,29 @@ func (r *SoftPlus[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *SoftPlus[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		// Below the threshold, the derivative is sigmoid(β x).
+		t := r.threshold.Value().Scalar().F64()
+		above := r.x.Value().Apply(func(_, _ int, v float64) float64 { return oneIf(v > t) })
+		below := r.x.Value().Apply(func(_, _ int, v float64) float64 { return oneIf(v <= t) })
+		s := g.Apply(NewSigmoid(g.Apply(NewProdScalar(r.x, r.beta))))
+		d := prodConst(g, s, below)
+		gxs[0] = g.Apply(NewProd(gy, g.Apply(NewAdd(g.Constant(above), d))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode. As in the
+// backward pass, beta and threshold are treated as constant.
+func (r *SoftPlus[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	deriv := r.x.Value().ApplyWithAlpha(
+		softPlusDeriv,
+		r.beta.Value().Scalar().F64(),
+		r.threshold.Value().Scalar().F64(),
+	)
+	return deriv.ProdInPlace(tangents[0])
+}
@@ -44,3 +44,19 @@ func (r *SoftShrink[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *SoftShrink[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		gxs[0] = prodConst(g, gy, r.x.Value().ApplyWithAlpha(softShrinkDeriv, r.lambda.Value().Scalar().F64()))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode. As in the
+// backward pass, lambda is treated as constant.
+func (r *SoftShrink[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	deriv := r.x.Value().ApplyWithAlpha(softShrinkDeriv, r.lambda.Value().Scalar().F64())
+	return deriv.ProdInPlace(tangents[0])
+}
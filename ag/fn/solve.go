@@ -0,0 +1,100 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"sync"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Solve is a Function to solve the linear system AX = B for X.
+type Solve[O Operand] struct {
+	a O          // square matrix
+	b O          // matrix or vector
+	x mat.Matrix // initialized during the forward pass (required by the backward pass)
+}
+
+// NewSolve returns a new Solve Function.
+func NewSolve[O Operand](a O, b O) *Solve[O] {
+	return &Solve[O]{
+		a: a,
+		b: b,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Solve[O]) Operands() []O {
+	return []O{r.a, r.b}
+}
+
+// Forward computes the output of the function.
+func (r *Solve[O]) Forward() mat.Matrix {
+	a := r.a.Value()
+	requireSquare(a)
+	r.x = asLinAlgMatrix(a).Solve(r.b.Value())
+	return r.x
+}
+
+// Backward computes the backward pass.
+//
+// The gradients are gb = A⁻ᵀ gy, and ga = -gb Xᵀ.
+func (r *Solve[O]) Backward(gy mat.Matrix) {
+	if !mat.SameDims(r.x, gy) {
+		panic("fn: matrices have incompatible dimensions")
+	}
+	if !r.a.RequiresGrad() && !r.b.RequiresGrad() {
+		return
+	}
+
+	at := r.a.Value().T()
+	defer mat.ReleaseMatrix(at)
+	gb := asLinAlgMatrix(at).Solve(gy)
+	defer mat.ReleaseMatrix(gb)
+
+	var wg sync.WaitGroup
+	if r.a.RequiresGrad() {
+		goBackward(&wg, func() {
+			xt := r.x.T()
+			defer mat.ReleaseMatrix(xt)
+			ga := gb.Mul(xt)
+			defer mat.ReleaseMatrix(ga)
+			r.a.AccGrad(ga.ProdScalarInPlace(-1))
+		})
+	}
+	if r.b.RequiresGrad() {
+		goBackward(&wg, func() {
+			r.b.AccGrad(gb)
+		})
+	}
+	wg.Wait()
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Solve[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if !required[0] && !required[1] {
+		return gxs
+	}
+	gb := g.Apply(NewSolve(g.Apply(NewTranspose(r.a)), gy))
+	if required[0] {
+		gxs[0] = g.Apply(NewNeg(g.Apply(NewMul(gb, g.Apply(NewTranspose(y))))))
+	}
+	if required[1] {
+		gxs[1] = gb
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+//
+// The tangent is A⁻¹ (tb - ta X).
+func (r *Solve[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	tax := tangents[0].Mul(y)
+	defer mat.ReleaseMatrix(tax)
+	rhs := tangents[1].Sub(tax)
+	defer mat.ReleaseMatrix(rhs)
+	return asLinAlgMatrix(r.a.Value()).Solve(rhs)
+}
@@ -0,0 +1,68 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolve_Forward(t *testing.T) {
+	t.Run("float32", testSolveForward[float32])
+	t.Run("float64", testSolveForward[float64])
+}
+
+func testSolveForward[T float.DType](t *testing.T) {
+	a := newVarWithGrad(mat.NewDense(2, 2, []T{
+		4, 7,
+		2, 6,
+	}))
+	b := newVarWithGrad(mat.NewVecDense([]T{1, 2}))
+
+	f := NewSolve(a, b)
+	assert.Equal(t, []*variable{a, b}, f.Operands())
+
+	y := f.Forward()
+	assert.InDeltaSlice(t, []T{-0.8, 0.6}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewVecDense([]T{1, 0}))
+
+	// gb = A⁻ᵀ gy
+	assert.InDeltaSlice(t, []T{0.6, -0.7}, b.grad.Data(), 1.0e-6)
+
+	// ga = -gb Xᵀ
+	assert.InDeltaSlice(t, []T{
+		0.48, -0.36,
+		-0.56, 0.42,
+	}, a.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() {
+		NewSolve(newVarWithGrad(mat.NewEmptyDense[T](2, 3)), b).Forward()
+	})
+}
+
+func TestSolve_Gradients(t *testing.T) {
+	a := newVarWithGrad(mat.NewDense(3, 3, []float64{
+		2, -1, 0.5,
+		0.3, 3, -1,
+		1, 0.2, 4,
+	}))
+	b := newVarWithGrad(mat.NewDense(3, 2, []float64{
+		1, -2,
+		0.5, 3,
+		-1, 0.4,
+	}))
+	gy := mat.NewDense(3, 2, []float64{
+		0.1, -0.2,
+		0.3, 0.4,
+		-0.5, 0.6,
+	})
+	requireGradientsMatchNumerical(t, func(xs ...*variable) Function[*variable] {
+		return NewSolve(xs[0], xs[1])
+	}, gy, a, b)
+}
@@ -69,6 +69,43 @@ func (r *SparseMax[O]) Backward(gy mat.Matrix) {
 	}
 }
 
+// Grad builds the gradients of the operands on the graph g.
+func (r *SparseMax[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		var nzCount float64
+		m := y.Value().Apply(func(_, _ int, v float64) float64 {
+			if v == 0 {
+				return 0
+			}
+			nzCount++
+			return 1
+		})
+		mask := g.Constant(m)
+		sum := g.Apply(NewDot(gy, mask))
+		mean := g.Apply(NewProdScalar(sum, scalarLike(g, r.x, 1/nzCount)))
+		gxs[0] = g.Apply(NewProd(mask, g.Apply(NewSubScalar(gy, mean))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *SparseMax[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	var nzSum float64
+	var nzCount float64
+	r.y.DoVecNonZero(func(i int, _ float64) {
+		nzSum += tangents[0].ScalarAtVec(i).F64()
+		nzCount++
+	})
+	nzSum = nzSum / nzCount
+	ty := r.x.Value().ZerosLike()
+	r.y.DoVecNonZero(func(i int, _ float64) {
+		ti := tangents[0].ScalarAtVec(i).F64()
+		ty.SetVecScalar(i, float.Interface(ti-nzSum))
+	})
+	return ty
+}
+
 func sparseMaxCommon(v mat.Matrix) (zs, cumSumInput mat.Matrix, bounds []float64, tau float64) {
 	// FIXME: avoid casting to specific type
 	zsData := make([]float64, v.Size())
@@ -72,3 +72,25 @@ func (r *SparseMaxLoss[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *SparseMaxLoss[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		p := g.Apply(NewSparseMax(r.x))
+		gxs[0] = g.Apply(NewSub(gy, g.Apply(NewProdScalar(p, g.Apply(NewReduceSum(gy))))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *SparseMaxLoss[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	tau := r.tau
+	sparseMax := r.x.Value().Apply(func(_, _ int, v float64) float64 {
+		return math.Max(0, v-tau)
+	})
+	defer mat.ReleaseMatrix(sparseMax)
+	st := sparseMax.DotUnitary(tangents[0])
+	defer mat.ReleaseMatrix(st)
+	return tangents[0].SubScalar(st.Scalar().F64())
+}
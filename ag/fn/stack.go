@@ -49,3 +49,20 @@ func (r *Stack[O]) Backward(gy mat.Matrix) {
 		mat.ReleaseMatrix(gyRow)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Stack[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(r.xs))
+	for i, x := range r.xs {
+		if required[i] {
+			gx := g.Apply(NewRowView(gy, i))
+			gxs[i] = reshapeLike(g, x, gx)
+		}
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Stack[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].NewStack(tangents...)
+}
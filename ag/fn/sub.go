@@ -34,17 +34,29 @@ func (r *Sub[O]) Forward() mat.Matrix {
 
 // Backward computes the backward pass.
 func (r *Sub[O]) Backward(gy mat.Matrix) {
-	x1v := r.x1.Value()
-	x2v := r.x2.Value()
-	if !mat.SameDims(x1v, gy) || !mat.SameDims(x2v, gy) {
-		panic("fn: matrices have incompatible dimensions")
-	}
 	if r.x1.RequiresGrad() {
-		r.x1.AccGrad(gy)
+		accReducedGrad(r.x1, gy)
 	}
 	if r.x2.RequiresGrad() {
 		gx := gy.ProdScalar(-1.0)
 		defer mat.ReleaseMatrix(gx)
-		r.x2.AccGrad(gx)
+		accReducedGrad(r.x2, gx)
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Sub[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = reduceToShape(g, r.x1, gy)
 	}
+	if required[1] {
+		gxs[1] = reduceToShape(g, r.x2, g.Apply(NewNeg(gy)))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Sub[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].Sub(tangents[1])
 }
@@ -41,3 +41,31 @@ func testSubForward[T float.DType](t *testing.T) {
 	assert.InDeltaSlice(t, []T{-1.0, 0.5, 0.8, 0.0}, x1.grad.Data(), 1.0e-6)
 	assert.InDeltaSlice(t, []T{1.0, -0.5, -0.8, 0.0}, x2.grad.Data(), 1.0e-6)
 }
+
+func TestSub_Broadcasting(t *testing.T) {
+	t.Run("float32", testSubBroadcasting[float32])
+	t.Run("float64", testSubBroadcasting[float64])
+}
+
+func testSubBroadcasting[T float.DType](t *testing.T) {
+	x1 := newVarWithGrad(mat.NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+	x2 := newVarWithGrad(mat.NewDense[T](2, 1, []T{1, 2}))
+
+	f := NewSub(x1, x2)
+	y := f.Forward()
+	assert.Equal(t, 2, y.Rows())
+	assert.Equal(t, 3, y.Columns())
+	assert.InDeltaSlice(t, []T{0, 1, 2, 2, 3, 4}, y.Data(), 1.0e-6)
+
+	f.Backward(mat.NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	}))
+	assert.InDeltaSlice(t, []T{1, 2, 3, 4, 5, 6}, x1.grad.Data(), 1.0e-6)
+	assert.Equal(t, 2, x2.grad.Rows())
+	assert.Equal(t, 1, x2.grad.Columns())
+	assert.InDeltaSlice(t, []T{-6, -15}, x2.grad.Data(), 1.0e-6)
+}
@@ -48,3 +48,20 @@ func (r *SubScalar[O]) Backward(gy mat.Matrix) {
 		r.x2.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *SubScalar[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if required[0] {
+		gxs[0] = gy
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewNeg(g.Apply(NewReduceSum(gy))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *SubScalar[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return tangents[0].SubScalar(tangents[1].Scalar().F64())
+}
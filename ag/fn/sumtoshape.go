@@ -0,0 +1,60 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// SumToShape is a Function which reduces an operand to the given shape,
+// summing its values over the dimensions that would be expanded
+// broadcasting a value of that shape to the shape of the operand (see
+// mat.SumToShape). It's the reverse of BroadcastTo, typically used to
+// reduce the gradients of broadcast operands.
+type SumToShape[O Operand] struct {
+	x     O
+	shape []int
+}
+
+// NewSumToShape returns a new SumToShape Function.
+func NewSumToShape[O Operand](x O, shape ...int) *SumToShape[O] {
+	return &SumToShape[O]{
+		x:     x,
+		shape: shape,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *SumToShape[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *SumToShape[O]) Forward() mat.Matrix {
+	return mat.SumToShape(r.x.Value(), r.shape...)
+}
+
+// Backward computes the backward pass.
+func (r *SumToShape[O]) Backward(gy mat.Matrix) {
+	if r.x.RequiresGrad() {
+		gx := mat.BroadcastLike(gy, r.x.Value())
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx)
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *SumToShape[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = g.Apply(NewBroadcastTo(gy, mat.Shape(r.x.Value())...))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *SumToShape[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return mat.SumToShape(tangents[0], r.shape...)
+}
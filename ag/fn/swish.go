@@ -47,6 +47,22 @@ func (l *Swish[O]) Backward(gy mat.Matrix) {
 	}
 }
 
+// Grad builds the gradients of the operands on the graph g.
+func (l *Swish[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		// The derivative is s + y (1 - s), where s = sigmoid(x).
+		s := g.Apply(NewSigmoid(l.x))
+		gxs[0] = g.Apply(NewProd(gy, g.Apply(NewAdd(s, g.Apply(NewProd(y, g.Apply(NewReverseSubScalar(s, scalarLike(g, l.x, 1)))))))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (l *Swish[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return l.x.Value().Apply(swishDeriv).ProdInPlace(tangents[0])
+}
+
 func swishDeriv(_, _ int, v float64) float64 {
 	exp := math.Exp(v)
 	expPlusOne := exp + 1
@@ -59,3 +59,35 @@ func (r *SwishB[O]) Backward(gy mat.Matrix) {
 		r.beta.AccGrad(gb)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *SwishB[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 2)
+	if !required[0] && !required[1] {
+		return gxs
+	}
+	// Given s = sigmoid(β x), the derivatives are s + β y (1 - s) with
+	// respect to x, and x y (1 - s) with respect to β.
+	s := g.Apply(NewSigmoid(g.Apply(NewProdScalar(r.x, r.beta))))
+	ys := g.Apply(NewProd(y, g.Apply(NewReverseSubScalar(s, scalarLike(g, r.x, 1)))))
+	if required[0] {
+		d := g.Apply(NewAdd(s, g.Apply(NewProdScalar(ys, r.beta))))
+		gxs[0] = g.Apply(NewProd(gy, d))
+	}
+	if required[1] {
+		gxs[1] = g.Apply(NewDot(gy, g.Apply(NewProd(r.x, ys))))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *SwishB[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	x := r.x.Value()
+	beta := r.beta.Value().Scalar().F64()
+	gb := x.Apply(func(_, _ int, v float64) float64 {
+		return swishBBetaDeriv(v, beta)
+	})
+	defer mat.ReleaseMatrix(gb)
+	gb.ProdScalarInPlace(tangents[1].Scalar().F64())
+	return x.ApplyWithAlpha(swishBDeriv, beta).ProdInPlace(tangents[0]).AddInPlace(gb)
+}
@@ -0,0 +1,166 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/rand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTangentFunctions(t *testing.T) {
+	m := func(rows, cols int, data ...float64) mat.Matrix {
+		return mat.NewDense[float64](rows, cols, data)
+	}
+	v := func(data ...float64) mat.Matrix {
+		return mat.NewVecDense[float64](data)
+	}
+	s := func(value float64) mat.Matrix {
+		return mat.NewScalar[float64](value)
+	}
+	tensor := func(shape []int, data ...float64) mat.Matrix {
+		return mat.NewDenseTensor(shape, data)
+	}
+	// The parameters which are constant in the backward pass are not
+	// perturbed either.
+	constant := func(m mat.Matrix) *variable {
+		return &variable{value: m}
+	}
+
+	testCases := []struct {
+		name  string
+		newFn func(xs ...*variable) Function[*variable]
+		xs    []mat.Matrix
+	}{
+		{"Identity", func(xs ...*variable) Function[*variable] { return NewIdentity(xs[0]) }, []mat.Matrix{v(1, 2)}},
+		{"Add", func(xs ...*variable) Function[*variable] { return NewAdd(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Add broadcast", func(xs ...*variable) Function[*variable] { return NewAdd(xs[0], xs[1]) }, []mat.Matrix{m(1, 2, 1, 2), m(2, 2, 3, 4, 5, 6)}},
+		{"Sub", func(xs ...*variable) Function[*variable] { return NewSub(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Prod", func(xs ...*variable) Function[*variable] { return NewProd(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Prod broadcast", func(xs ...*variable) Function[*variable] { return NewProd(xs[0], xs[1]) }, []mat.Matrix{m(2, 2, 3, 4, 5, 6), m(2, 1, 1, 2)}},
+		{"Square", func(xs ...*variable) Function[*variable] { return NewSquare(xs[0]) }, []mat.Matrix{v(1, -2)}},
+		{"Div", func(xs ...*variable) Function[*variable] { return NewDiv(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Mul", func(xs ...*variable) Function[*variable] { return NewMul(xs[0], xs[1]) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6), v(1, -1, 2)}},
+		{"Dot", func(xs ...*variable) Function[*variable] { return NewDot(xs[0], xs[1]) }, []mat.Matrix{v(1, 2, 3), v(4, 5, 6)}},
+		{"AddScalar", func(xs ...*variable) Function[*variable] { return NewAddScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"SubScalar", func(xs ...*variable) Function[*variable] { return NewSubScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"ReverseSubScalar", func(xs ...*variable) Function[*variable] { return NewReverseSubScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"ProdScalar", func(xs ...*variable) Function[*variable] { return NewProdScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"DivScalar", func(xs ...*variable) Function[*variable] { return NewDivScalar(xs[0], xs[1]) }, []mat.Matrix{v(1, 2), s(3)}},
+		{"Affine", func(xs ...*variable) Function[*variable] { return NewAffine(xs[0], xs[1], xs[2], xs[3], xs[4]) }, []mat.Matrix{v(1, 2), m(2, 2, 1, 2, 3, 4), v(-1, 1), m(2, 1, 5, 6), s(2)}},
+		{"AffineChain", func(xs ...*variable) Function[*variable] {
+			return NewAffineChain(NewAffine(xs[0], xs[1], xs[2]), NewTanh(xs[0]).Stages()...)
+		}, []mat.Matrix{v(0.1, -0.2), m(2, 2, 0.1, 0.2, 0.3, 0.4), v(-1, 1)}},
+		{"ProdAdd", func(xs ...*variable) Function[*variable] { return NewProdAdd(xs[0], xs[1], xs[2], xs[3]) }, []mat.Matrix{v(1, 2), v(3, 4), v(-1, 5), v(2, -3)}},
+		{"ProdAdd broadcast", func(xs ...*variable) Function[*variable] { return NewProdAdd(xs[0], xs[1], xs[2], xs[3]) }, []mat.Matrix{v(1, 2), v(3, 4), m(2, 2, -1, 5, 2, 1), v(2, -3)}},
+		{"ReduceSum", func(xs ...*variable) Function[*variable] { return NewReduceSum(xs[0]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4)}},
+		{"ReduceMean", func(xs ...*variable) Function[*variable] { return NewReduceMean(xs[0]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4)}},
+		{"Transpose", func(xs ...*variable) Function[*variable] { return NewTranspose(xs[0]) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"Softmax", func(xs ...*variable) Function[*variable] { return NewSoftmax(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Tanh", func(xs ...*variable) Function[*variable] { return NewTanh(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Sigmoid", func(xs ...*variable) Function[*variable] { return NewSigmoid(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Sin", func(xs ...*variable) Function[*variable] { return NewSin(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"GELU", func(xs ...*variable) Function[*variable] { return NewGELU(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"ElementwiseChain", func(xs ...*variable) Function[*variable] {
+			return NewElementwiseChain(xs[0], append(NewSin(xs[0]).Stages(), NewSigmoid(xs[0]).Stages()...)...)
+		}, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Exp", func(xs ...*variable) Function[*variable] { return NewExp(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Log", func(xs ...*variable) Function[*variable] { return NewLog(xs[0]) }, []mat.Matrix{v(0.1, 0.5, 0.7)}},
+		{"Sqrt", func(xs ...*variable) Function[*variable] { return NewSqrt(xs[0]) }, []mat.Matrix{v(0.1, 0.5, 0.7)}},
+		{"Pow", func(xs ...*variable) Function[*variable] { return NewPow(xs[0], 3) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Concat", func(xs ...*variable) Function[*variable] { return NewConcat(xs) }, []mat.Matrix{v(1, 2), v(3, 4, 5)}},
+		{"Stack", func(xs ...*variable) Function[*variable] { return NewStack(xs) }, []mat.Matrix{v(1, 2), v(3, 4)}},
+		{"Reshape", func(xs ...*variable) Function[*variable] { return NewReshape(xs[0], 3, 2) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"Flatten", func(xs ...*variable) Function[*variable] { return NewFlatten(xs[0]) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"RowView", func(xs ...*variable) Function[*variable] { return NewRowView(xs[0], 1) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"ColView", func(xs ...*variable) Function[*variable] { return NewColView(xs[0], 2) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"MulT", func(xs ...*variable) Function[*variable] { return NewMulT(xs[0], xs[1]) }, []mat.Matrix{m(3, 2, 1, 2, 3, 4, 5, 6), v(1, -1, 2)}},
+		{"ReduceMax", func(xs ...*variable) Function[*variable] { return NewReduceMax(xs[0]) }, []mat.Matrix{v(1, 4, 3)}},
+		{"ReduceSumAxis", func(xs ...*variable) Function[*variable] { return NewReduceSumAxis(xs[0], 1) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"ReduceMeanAxis", func(xs ...*variable) Function[*variable] { return NewReduceMeanAxis(xs[0], 2) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"SumToShape", func(xs ...*variable) Function[*variable] { return NewSumToShape(xs[0], 1, 3) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"BroadcastTo", func(xs ...*variable) Function[*variable] { return NewBroadcastTo(xs[0], 2, 3) }, []mat.Matrix{m(1, 3, 1, 2, 3)}},
+		{"Permute", func(xs ...*variable) Function[*variable] { return NewPermute(xs[0], 2, 0, 1) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"ReshapeTensor", func(xs ...*variable) Function[*variable] { return NewReshapeTensor(xs[0], 3, 2, 2) }, []mat.Matrix{tensor([]int{2, 3, 2}, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}},
+		{"Trace", func(xs ...*variable) Function[*variable] { return NewTrace(xs[0]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4)}},
+		{"Inverse", func(xs ...*variable) Function[*variable] { return NewInverse(xs[0]) }, []mat.Matrix{m(2, 2, 2, 1, 1, 3)}},
+		{"LogDet", func(xs ...*variable) Function[*variable] { return NewLogDet(xs[0]) }, []mat.Matrix{m(2, 2, 2, 1, 1, 3)}},
+		{"Cholesky", func(xs ...*variable) Function[*variable] { return NewCholesky(xs[0]) }, []mat.Matrix{m(3, 3, 4, 2, 1, 2, 5, 3, 1, 3, 6)}},
+		{"Solve", func(xs ...*variable) Function[*variable] { return NewSolve(xs[0], xs[1]) }, []mat.Matrix{m(2, 2, 2, 1, 1, 3), m(2, 2, 1, 2, 3, 4)}},
+		{"SparseMax", func(xs ...*variable) Function[*variable] { return NewSparseMax(xs[0]) }, []mat.Matrix{v(0.1, 0.6, 0.9)}},
+		{"SparseMaxLoss", func(xs ...*variable) Function[*variable] { return NewSparseMaxLoss(xs[0]) }, []mat.Matrix{v(0.1, 0.5, 0.7)}},
+		{"Swish", func(xs ...*variable) Function[*variable] { return NewSwish(xs[0]) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"SwishB", func(xs ...*variable) Function[*variable] { return NewSwishB(xs[0], xs[1]) }, []mat.Matrix{v(0.1, -0.5, 0.7), s(1.5)}},
+		{"LeakyReLU", func(xs ...*variable) Function[*variable] { return NewLeakyReLU(xs[0], constant(s(0.1))) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"ELU", func(xs ...*variable) Function[*variable] { return NewELU(xs[0], constant(s(0.5))) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"CELU", func(xs ...*variable) Function[*variable] { return NewCELU(xs[0], constant(s(0.5))) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"SELU", func(xs ...*variable) Function[*variable] { return NewSELU(xs[0], constant(s(1.5)), constant(s(1.1))) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"SoftPlus", func(xs ...*variable) Function[*variable] { return NewSoftPlus(xs[0], constant(s(2)), constant(s(1))) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"SoftShrink", func(xs ...*variable) Function[*variable] { return NewSoftShrink(xs[0], constant(s(0.2))) }, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Threshold", func(xs ...*variable) Function[*variable] {
+			return NewThreshold(xs[0], constant(s(0.2)), constant(s(-1)))
+		}, []mat.Matrix{v(0.1, -0.5, 0.7)}},
+		{"Max", func(xs ...*variable) Function[*variable] { return NewMax(xs[0], xs[1]) }, []mat.Matrix{v(1, 2, 3), v(3, 2.5, 1)}},
+		{"Min", func(xs ...*variable) Function[*variable] { return NewMin(xs[0], xs[1]) }, []mat.Matrix{v(1, 2, 3), v(3, 2.5, 1)}},
+		{"ScalarMax", func(xs ...*variable) Function[*variable] { return NewScalarMax(xs) }, []mat.Matrix{s(1), s(3), s(2)}},
+		{"MaxPooling", func(xs ...*variable) Function[*variable] { return NewMaxPooling(xs[0], 2, 2) }, []mat.Matrix{m(2, 4, 1, 5, 3, 2, 4, 2, 8, 7)}},
+		{"Dropout", func(xs ...*variable) Function[*variable] { return NewDropout(xs[0], 0.5, rand.NewLockedRand(1)) }, []mat.Matrix{v(1, 2, 3, 4)}},
+		{"AppendRows", func(xs ...*variable) Function[*variable] { return NewAppendRows(xs[0], xs[1], xs[2]) }, []mat.Matrix{m(2, 2, 1, 2, 3, 4), m(1, 2, 5, 6), v(7, 8)}},
+		{"RotateR", func(xs ...*variable) Function[*variable] { return NewRotateR(xs[0], 1) }, []mat.Matrix{v(1, 2, 3, 4)}},
+		{"At", func(xs ...*variable) Function[*variable] { return NewAt(xs[0], 1, 2) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+		{"AtVec", func(xs ...*variable) Function[*variable] { return NewAtVec(xs[0], 1) }, []mat.Matrix{v(1, 2, 3)}},
+		{"Slice", func(xs ...*variable) Function[*variable] { return NewSlice(xs[0], 0, 1, 2, 3) }, []mat.Matrix{m(2, 3, 1, 2, 3, 4, 5, 6)}},
+	}
+
+	const h = 1.0e-6
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			xs := make([]*variable, len(tc.xs))
+			tangents := make([]mat.Matrix, len(tc.xs))
+			for i, x := range tc.xs {
+				xs[i] = newVarWithGrad(x)
+				k := 0.0
+				tangents[i] = x.Apply(func(_, _ int, _ float64) float64 {
+					k++
+					return 0.3*k - 0.1*float64(i+1)
+				})
+			}
+
+			f := tc.newFn(xs...)
+			tf, ok := f.(TangentFunction)
+			require.True(t, ok, "%T must implement TangentFunction", f)
+			y := f.Forward()
+
+			// Operands may be repeated, as in Square.
+			tangentOf := make(map[*variable]mat.Matrix, len(xs))
+			for i, x := range xs {
+				tangentOf[x] = tangents[i]
+			}
+			operands := f.Operands()
+			operandTangents := make([]mat.Matrix, len(operands))
+			for i, o := range operands {
+				operandTangents[i] = tangentOf[o]
+			}
+			actual := tf.Tangent(y, operandTangents)
+
+			perturbed := func(sign float64) mat.Matrix {
+				pxs := make([]*variable, len(tc.xs))
+				for i, x := range tc.xs {
+					pxs[i] = newVarWithGrad(x.Add(tangents[i].ProdScalar(sign * h)))
+				}
+				return tc.newFn(pxs...).Forward()
+			}
+			expected := perturbed(1).Sub(perturbed(-1)).ProdScalarInPlace(1 / (2 * h))
+
+			assert.Equal(t, expected.Rows(), actual.Rows())
+			assert.Equal(t, expected.Columns(), actual.Columns())
+			assert.InDeltaSlice(t, expected.Data(), actual.Data(), 1.0e-6)
+		})
+	}
+}
@@ -55,3 +55,27 @@ func (r *Threshold[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Threshold[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, len(required))
+	if required[0] {
+		gxs[0] = prodConst(g, gy, r.x.Value().ApplyWithAlpha(
+			thresholdDeriv,
+			r.threshold.Value().Scalar().F64(),
+			r.k.Value().Scalar().F64(),
+		))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode. As in the
+// backward pass, threshold and k are treated as constant.
+func (r *Threshold[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	deriv := r.x.Value().ApplyWithAlpha(
+		thresholdDeriv,
+		r.threshold.Value().Scalar().F64(),
+		r.k.Value().Scalar().F64(),
+	)
+	return deriv.ProdInPlace(tangents[0])
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Trace is a Function to calculate the sum of the elements on the main
+// diagonal of a square matrix.
+type Trace[O Operand] struct {
+	x O
+}
+
+// NewTrace returns a new Trace Function.
+func NewTrace[O Operand](x O) *Trace[O] {
+	return &Trace[O]{
+		x: x,
+	}
+}
+
+// Operands returns the list of operands.
+func (r *Trace[O]) Operands() []O {
+	return []O{r.x}
+}
+
+// Forward computes the output of the function.
+func (r *Trace[O]) Forward() mat.Matrix {
+	x := r.x.Value()
+	requireSquare(x)
+	return x.NewScalar(trace(x))
+}
+
+// Backward computes the backward pass.
+func (r *Trace[O]) Backward(gy mat.Matrix) {
+	if !mat.IsScalar(gy) {
+		panic("fn: the gradient had to be a scalar")
+	}
+	if r.x.RequiresGrad() {
+		gx := r.x.Value().NewIdentityMatrix(r.x.Value().Rows())
+		defer mat.ReleaseMatrix(gx)
+		r.x.AccGrad(gx.ProdScalarInPlace(gy.Scalar().F64()))
+	}
+}
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *Trace[O]) Grad(g Graph[O], _, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		x := r.x.Value()
+		gxs[0] = g.Apply(NewProdScalar(g.Constant(x.NewIdentityMatrix(x.Rows())), gy))
+	}
+	return gxs
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *Trace[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return y.NewScalar(trace(tangents[0]))
+}
+
+// trace returns the sum of the elements on the main diagonal of x.
+func trace(x mat.Matrix) float64 {
+	var y float64
+	for i := 0; i < x.Rows(); i++ {
+		y += x.ScalarAt(i, i).F64()
+	}
+	return y
+}
@@ -0,0 +1,54 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrace_Forward(t *testing.T) {
+	t.Run("float32", testTraceForward[float32])
+	t.Run("float64", testTraceForward[float64])
+}
+
+func testTraceForward[T float.DType](t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(3, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, -9,
+	}))
+
+	f := NewTrace(x)
+	assert.Equal(t, []*variable{x}, f.Operands())
+
+	y := f.Forward()
+	assert.InDelta(t, -3, y.Scalar().F64(), 1.0e-6)
+
+	f.Backward(mat.NewScalar[T](0.5))
+
+	assert.InDeltaSlice(t, []T{
+		0.5, 0, 0,
+		0, 0.5, 0,
+		0, 0, 0.5,
+	}, x.grad.Data(), 1.0e-6)
+
+	assert.Panics(t, func() {
+		NewTrace(newVarWithGrad(mat.NewEmptyDense[T](2, 3))).Forward()
+	})
+}
+
+func TestTrace_Gradients(t *testing.T) {
+	x := newVarWithGrad(mat.NewDense(2, 2, []float64{
+		1, -2,
+		3, 4,
+	}))
+	requireGradientsMatchNumerical(t, func(xs ...*variable) Function[*variable] {
+		return NewTrace(xs[0])
+	}, mat.NewScalar(-1.5), x)
+}
@@ -13,8 +13,15 @@ type UnaryElementwise[O Operand] struct {
 	x  O
 	f  func(i, j int, v float64) float64 // function
 	df func(i, j int, v float64) float64 // derivative
+	// grad builds the gradients of x on a graph (see GradFunction).
+	grad elementwiseGrad[O]
 }
 
+// elementwiseGrad builds the gradients of the operand x of a single-input
+// element-wise function on the graph g, given the output y and its
+// gradients gy.
+type elementwiseGrad[O Operand] func(g Graph[O], x, y, gy O) O
+
 // Operands returns the list of operands.
 func (r *UnaryElementwise[O]) Operands() []O {
 	return []O{r.x}
@@ -37,3 +44,25 @@ func (r *UnaryElementwise[O]) Backward(gy mat.Matrix) {
 		r.x.AccGrad(gx)
 	}
 }
+
+// Grad builds the gradients of the operands on the graph g.
+func (r *UnaryElementwise[O]) Grad(g Graph[O], y, gy O, required []bool) []O {
+	gxs := make([]O, 1)
+	if required[0] {
+		gxs[0] = r.grad(g, r.x, y, gy)
+	}
+	return gxs
+}
+
+// piecewiseLinearGrad returns the elementwiseGrad of a function whose
+// derivative df is piecewise constant, and thus a constant of the graph.
+func piecewiseLinearGrad[O Operand](df func(i, j int, v float64) float64) elementwiseGrad[O] {
+	return func(g Graph[O], x, _, gy O) O {
+		return prodConst(g, gy, x.Value().Apply(df))
+	}
+}
+
+// Tangent computes the tangent of the output, in forward mode.
+func (r *UnaryElementwise[O]) Tangent(y mat.Matrix, tangents []mat.Matrix) mat.Matrix {
+	return r.x.Value().Apply(r.df).ProdInPlace(tangents[0])
+}
@@ -0,0 +1,153 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/ag/fn"
+)
+
+// elementwiseFunction is implemented by the single-input element-wise
+// functions, such as fn.Tanh, and by fn.ElementwiseChain.
+type elementwiseFunction interface {
+	fn.Function[Node]
+	Stages() []fn.ElementwiseStage[Node]
+}
+
+// Fuse merges the steps of the plan which can be computed by a single
+// function, returning the number of steps removed.
+//
+// A single-input element-wise function (such as an activation) is fused
+// with the preceding step into an fn.ElementwiseChain, if that step is an
+// element-wise function as well, or into an fn.AffineChain, if it's an
+// affine function. The sum of two element-wise products, as in the gates of
+// recurrent networks, is fused into an fn.ProdAdd. A preceding step is
+// fused only if its value is not an output of the plan, and it's not used
+// by any other step.
+//
+// The fused functions compute the same output values with fewer passes and
+// intermediate buffers. Since a plan never runs the backward pass, the
+// affine chains compute their output in place (see fn.AffineChain.ForwardOnly).
+func (p *Plan) Fuse() int {
+	index := make(map[*Operator]int, len(p.steps))
+	for i, s := range p.steps {
+		index[s.op] = i
+	}
+	consumers := make([]int, len(p.steps))
+	for _, s := range p.steps {
+		for _, slot := range s.operands {
+			if slot >= len(p.inputs) {
+				consumers[slot-len(p.inputs)]++
+			}
+		}
+	}
+
+	// fusable returns the index of the step computing the operand, if it
+	// can be fused with the step using it. The operand is checked as is,
+	// without unwrapping it, since a wrapper would be dropped along with
+	// the fused step.
+	fusable := func(operand Node) (*Operator, int, bool) {
+		prev, ok := operand.(*Operator)
+		if !ok {
+			return nil, 0, false
+		}
+		j, ok := index[prev]
+		if !ok || consumers[j] != 1 || p.isOutput[j] {
+			return nil, 0, false
+		}
+		return prev, j, true
+	}
+
+	removed := make([]bool, len(p.steps))
+	count := 0
+	for _, s := range p.steps {
+		if add, ok := s.op.function.(*fn.Add[Node]); ok {
+			count += fuseProdAdd(s.op, add, fusable, removed)
+			continue
+		}
+		ef, ok := elementwise(s.op)
+		if !ok {
+			continue
+		}
+		prev, j, ok := fusable(ef.Operands()[0])
+		if !ok {
+			continue
+		}
+
+		var fused fn.Function[Node]
+		switch pf := prev.function.(type) {
+		case *fn.Affine[Node]:
+			fused = fn.NewAffineChain(pf, ef.Stages()...).ForwardOnly()
+		case *fn.AffineChain[Node]:
+			fused = fn.NewAffineChain(pf.Affine, concatStages(pf.Stages(), ef.Stages())...).ForwardOnly()
+		default:
+			pef, ok := elementwise(prev)
+			if !ok {
+				continue
+			}
+			fused = fn.NewElementwiseChain(pef.Operands()[0], concatStages(pef.Stages(), ef.Stages())...)
+		}
+
+		s.op.function = fused
+		s.op.operands = nil
+		removed[j] = true
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+
+	steps := make([]planStep, 0, len(p.steps)-count)
+	for i, s := range p.steps {
+		if !removed[i] {
+			steps = append(steps, s)
+		}
+	}
+	p.steps = steps
+	p.schedule()
+	return count
+}
+
+// fuseProdAdd replaces the function of the operator, adding two element-wise
+// products, with an fn.ProdAdd, if both products can be fused (see
+// fusable), marking them as removed. It returns the number of steps removed.
+func fuseProdAdd(op *Operator, add *fn.Add[Node], fusable func(Node) (*Operator, int, bool), removed []bool) int {
+	operands := add.Operands()
+	var prods [2][]Node
+	var steps [2]int
+	for i, operand := range operands {
+		prev, j, ok := fusable(operand)
+		if !ok {
+			return 0
+		}
+		prod, ok := prev.function.(*fn.Prod[Node])
+		if !ok {
+			return 0
+		}
+		prods[i], steps[i] = prod.Operands(), j
+	}
+	if steps[0] == steps[1] {
+		return 0
+	}
+	op.function = fn.NewProdAdd(prods[0][0], prods[0][1], prods[1][0], prods[1][1])
+	op.operands = nil
+	removed[steps[0]], removed[steps[1]] = true, true
+	return 2
+}
+
+// elementwise returns the function of the operator, if it's a
+// single-input element-wise function.
+func elementwise(op *Operator) (elementwiseFunction, bool) {
+	if _, ok := op.function.(*fn.AffineChain[Node]); ok {
+		return nil, false
+	}
+	ef, ok := op.function.(elementwiseFunction)
+	return ef, ok
+}
+
+func concatStages(a, b []fn.ElementwiseStage[Node]) []fn.ElementwiseStage[Node] {
+	stages := make([]fn.ElementwiseStage[Node], 0, len(a)+len(b))
+	stages = append(stages, a...)
+	return append(stages, b...)
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan_Fuse(t *testing.T) {
+	t.Run("float32", testPlanFuse[float32])
+	t.Run("float64", testPlanFuse[float64])
+}
+
+func testPlanFuse[T float.DType](t *testing.T) {
+	w := Var(mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5}))
+	b := Var(mat.NewVecDense[T]([]T{0.1, -0.2}))
+
+	f := func(xs ...Node) []Node {
+		// Affine, Sigmoid and Neg are fused together.
+		g := Neg(Sigmoid(Affine(b, w, xs[0])))
+		// Sin, Tanh and Abs are fused together.
+		h := Abs(Tanh(Sin(xs[1])))
+		// Cos is used twice, so it's not fused.
+		c := Cos(xs[1])
+		k := Add(Tanh(c), c)
+		// ReLU is an output, so it's not fused.
+		r := ReLU(xs[1])
+		// The products and the sum are fused together.
+		s := Add(Prod(h, xs[1]), Prod(xs[1], r))
+		return []Node{Prod(g, h), Sigmoid(StopGrad(k)), r, Neg(r), s}
+	}
+	newInputs := func(k T) []mat.Matrix {
+		return []mat.Matrix{
+			mat.NewVecDense[T]([]T{0.7 * k, -1.1, 0.4 + k}),
+			mat.NewVecDense[T]([]T{k, -k}),
+		}
+	}
+
+	p := TracePlan(f, newInputs(1)...)
+	unfused := len(p.Steps())
+	assert.Equal(t, 6, p.Fuse())
+	assert.Equal(t, 0, p.Fuse())
+
+	steps := p.Steps()
+	require.Len(t, steps, unfused-6)
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		names[i] = s.Operator
+	}
+	assert.Equal(t, []string{
+		"AffineChain", "ElementwiseChain", "Prod",
+		"Cos", "Tanh", "Add", "Sigmoid", "ReLU", "Neg", "ProdAdd",
+	}, names)
+	assert.Equal(t, PlanStep{Operator: "AffineChain", Operands: []int{-1, -1, 0}, Rows: 2, Columns: 1}, steps[0])
+	assert.Equal(t, PlanStep{Operator: "ElementwiseChain", Operands: []int{1}, Rows: 2, Columns: 1}, steps[1])
+
+	for _, k := range []T{1, 2, -0.5} {
+		inputs := newInputs(k)
+		expected := f(Var(inputs[0]), Var(inputs[1]))
+		actual := p.Run(inputs...)
+		for i, y := range expected {
+			assert.InDeltaSlice(t, y.Value().Data(), actual[i].Data(), 1.0e-6, "output %d", i)
+		}
+	}
+	p.Release()
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Grad computes the gradients of the sum of the outputs with respect to
+// each of the inputs, and returns them as new nodes, in the same order of
+// the inputs.
+//
+// Unlike Backward, it doesn't accumulate any gradient into the nodes of the
+// graph. Instead, the backward pass is expressed with further operators,
+// built on top of the existing graph: if createGraph is true, these
+// operators are part of the returned gradients, which can thus be
+// differentiated again, for example to compute gradient penalties or
+// Hessian-vector products. If createGraph is false, the returned gradients
+// are new Variables not requiring gradients, and the intermediate operators
+// are evaluated in inference mode and released.
+//
+// The gradient of an input that doesn't require gradients, or which the
+// outputs don't depend on, is nil.
+//
+// The operators build their backward pass with the fn.GradFunction
+// implementation of their function; Grad panics if the outputs depend on
+// any operator whose function doesn't implement it, such as Custom.
+func Grad(outputs, inputs []Node, createGraph bool) []Node {
+	isInput := make(map[Node]bool, len(inputs))
+	for _, x := range inputs {
+		isInput[x] = true
+	}
+
+	// Only the operators depending on some input are relevant, sorted in
+	// topological order.
+	relevant := make(map[Node]bool)
+	var order []*Operator
+	var visit func(n Node) bool
+	visit = func(n Node) bool {
+		if r, ok := relevant[n]; ok {
+			return r
+		}
+		r := isInput[n]
+		if op, ok := n.(*Operator); ok && op.RequiresGrad() {
+			dependsOnInputs := false
+			for _, operand := range op.Operands() {
+				if visit(operand) {
+					dependsOnInputs = true
+				}
+			}
+			if dependsOnInputs {
+				order = append(order, op)
+				r = true
+			}
+		}
+		relevant[n] = r
+		return r
+	}
+
+	g := &gradGraph{noGrad: !createGraph}
+	grads := make(map[Node]Node, len(order))
+	for _, y := range outputs {
+		if visit(y) && y.RequiresGrad() {
+			accGradNode(g, grads, y, g.Constant(y.Value().OnesLike()))
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		op := order[i]
+		gy, ok := grads[op]
+		if !ok {
+			continue
+		}
+		gf, ok := op.function.(fn.GradFunction[Node])
+		if !ok {
+			panic(fmt.Sprintf("ag: Grad is not supported by %s operators", op.Name()))
+		}
+		operands := op.Operands()
+		required := make([]bool, len(operands))
+		for j, operand := range operands {
+			required[j] = relevant[operand] && operand.RequiresGrad()
+		}
+		for j, gx := range gf.Grad(g, op, gy, required) {
+			if gx != nil {
+				accGradNode(g, grads, operands[j], gx)
+			}
+		}
+	}
+
+	result := make([]Node, len(inputs))
+	for i, x := range inputs {
+		if gx, ok := grads[x]; ok && x.RequiresGrad() {
+			result[i] = gx
+		}
+	}
+	if createGraph {
+		return result
+	}
+
+	for i, gx := range result {
+		if gx != nil {
+			result[i] = Var(gx.Value().Clone())
+		}
+	}
+	g.release()
+	return result
+}
+
+// accGradNode adds gx to the gradient node associated to x.
+func accGradNode(g *gradGraph, grads map[Node]Node, x Node, gx Node) {
+	if gs, ok := grads[x]; ok {
+		grads[x] = g.Apply(fn.NewAdd(gs, gx))
+		return
+	}
+	grads[x] = gx
+}
+
+// gradGraph is the fn.Graph on which Grad builds the backward pass. It
+// keeps track of the nodes it creates, so that they can be released.
+type gradGraph struct {
+	// noGrad reports whether the operators are created in inference mode,
+	// since the gradients are not differentiated again.
+	noGrad bool
+	nodes  []Node
+}
+
+// Apply returns a new operator node holding the output of the function f.
+func (g *gradGraph) Apply(f fn.Function[Node]) Node {
+	var n Node
+	if g.noGrad {
+		n = newNoGradOperator(f, f.Operands(), nil)
+	} else {
+		n = NewOperator(f)
+	}
+	g.nodes = append(g.nodes, n)
+	return n
+}
+
+// Constant returns a new Variable holding the value m, which doesn't
+// require gradients.
+func (g *gradGraph) Constant(m mat.Matrix) Node {
+	n := Var(m)
+	g.nodes = append(g.nodes, n)
+	return n
+}
+
+// release releases the values of the nodes created on the graph, without
+// affecting the nodes of the original graph.
+func (g *gradGraph) release() {
+	for _, n := range g.nodes {
+		mat.ReleaseMatrix(n.Value())
+	}
+	g.nodes = nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gradTestCases are functions of one 2×2 matrix, defined for positive
+// values, covering the operators supported by Grad.
+var gradTestCases = map[string]func(x Node) Node{
+	"Identity":      func(x Node) Node { return Identity(x) },
+	"Add":           func(x Node) Node { return Add(x, Square(x)) },
+	"Sub":           func(x Node) Node { return Sub(Exp(x), x) },
+	"Prod":          func(x Node) Node { return Prod(x, Sin(x)) },
+	"Div":           func(x Node) Node { return Div(Cos(x), x) },
+	"broadcast":     func(x Node) Node { return Prod(Sub(x, Mul(ones(x, 1, 2), x)), Add(x, Mul(x, ones(x, 2, 1)))) },
+	"scalar":        func(x Node) Node { return Div(x, Sub(ReduceSum(x), x)) },
+	"Mul":           func(x Node) Node { return Mul(x, Tanh(x)) },
+	"MulT":          func(x Node) Node { return Prod(MulT(Exp(x), ColView(Square(x), 1)), ColView(x, 0)) },
+	"Affine":        func(x Node) Node { return Affine(Sigmoid(x), x, Square(x), Log(x), x) },
+	"Dot":           func(x Node) Node { return Dot(x, Sqrt(x)) },
+	"AddScalar":     func(x Node) Node { return AddScalar(Square(x), ReduceSum(x)) },
+	"SubScalar":     func(x Node) Node { return SubScalar(Square(x), ReduceMean(x)) },
+	"ReverseSub":    func(x Node) Node { return ReverseSub(Square(x), Dot(x, Exp(x))) },
+	"ProdScalar":    func(x Node) Node { return ProdScalar(Square(x), Trace(x)) },
+	"DivScalar":     func(x Node) Node { return DivScalar(Square(x), Dot(x, x)) },
+	"Pow":           func(x Node) Node { return Pow(x, 2.5) },
+	"Neg":           func(x Node) Node { return Neg(Reciprocal(x)) },
+	"ReLU":          func(x Node) Node { return Square(ReLU(SubScalar(x, scalar(x, 1)))) },
+	"Abs":           func(x Node) Node { return Prod(Abs(Neg(x)), x) },
+	"LeakyReLU":     func(x Node) Node { return Square(LeakyReLU(SubScalar(x, scalar(x, 1)), scalar(x, 0.1))) },
+	"HardTanh":      func(x Node) Node { return Prod(HardTanh(x), Exp(x)) },
+	"Threshold":     func(x Node) Node { return Square(Threshold(x, scalar(x, 1), scalar(x, 0.2))) },
+	"SoftShrink":    func(x Node) Node { return Square(SoftShrink(x, scalar(x, 0.7))) },
+	"Tan":           func(x Node) Node { return Tan(ProdScalar(x, scalar(x, 0.5))) },
+	"Softsign":      func(x Node) Node { return Softsign(SubScalar(x, scalar(x, 1))) },
+	"GELU":          func(x Node) Node { return GELU(SubScalar(x, scalar(x, 1))) },
+	"Mish":          func(x Node) Node { return Mish(SubScalar(x, scalar(x, 1))) },
+	"Swish":         func(x Node) Node { return Swish(SubScalar(x, scalar(x, 1))) },
+	"SwishB":        func(x Node) Node { return SwishB(SubScalar(x, scalar(x, 1)), ReduceMean(x)) },
+	"SoftPlus":      func(x Node) Node { return SoftPlus(SubScalar(x, scalar(x, 1)), scalar(x, 2), scalar(x, 1)) },
+	"ELU":           func(x Node) Node { return ELU(SubScalar(x, scalar(x, 1)), scalar(x, 0.5)) },
+	"CELU":          func(x Node) Node { return CELU(SubScalar(x, scalar(x, 1)), scalar(x, 0.5)) },
+	"SELU":          func(x Node) Node { return SELU(SubScalar(x, scalar(x, 1)), scalar(x, 1.5), scalar(x, 1.1)) },
+	"Max":           func(x Node) Node { return Prod(Max(Square(x), x), Exp(x)) },
+	"Min":           func(x Node) Node { return Prod(Min(Square(x), x), Exp(x)) },
+	"Softmax":       func(x Node) Node { return Prod(Softmax(Mul(x, ones(x, 2, 1))), Mul(Square(x), ones(x, 2, 1))) },
+	"SparseMax":     func(x Node) Node { return Prod(SparseMax(Reshape(x, 4, 1)), Exp(Reshape(x, 4, 1))) },
+	"SparseMaxLoss": func(x Node) Node { return SparseMaxLoss(Reshape(Square(x), 4, 1)) },
+	"T":             func(x Node) Node { return Mul(T(x), Exp(x)) },
+	"Inverse":       func(x Node) Node { return Inverse(Add(x, Var(x.Value().NewIdentityMatrix(2)))) },
+	"LogDet":        func(x Node) Node { return LogDet(Add(Mul(x, T(x)), Var(x.Value().NewIdentityMatrix(2)))) },
+	"Cholesky":      func(x Node) Node { return Cholesky(Add(Mul(x, T(x)), Var(x.Value().NewIdentityMatrix(2)))) },
+	"Solve":         func(x Node) Node { return Solve(Add(x, Var(x.Value().NewIdentityMatrix(2))), Square(x)) },
+	"Concat": func(x Node) Node {
+		return Prod(Concat(Flatten(x), Square(ColView(x, 1))), Concat(Exp(ColView(x, 0)), Flatten(x)))
+	},
+	"Stack":      func(x Node) Node { return Square(Stack(Flatten(x), Flatten(Exp(x)))) },
+	"AppendRows": func(x Node) Node { return Square(AppendRows(Exp(x), RowView(x, 1))) },
+	"RowView":    func(x Node) Node { return Prod(RowView(x, 1), RowView(Exp(x), 0)) },
+	"ColView":    func(x Node) Node { return Prod(ColView(x, 1), ColView(Exp(x), 0)) },
+	"At":         func(x Node) Node { return ProdScalar(Exp(x), At(Square(x), 1, 0)) },
+	"AtVec":      func(x Node) Node { return ProdScalar(Exp(x), AtVec(Square(Reshape(x, 4, 1)), 2)) },
+	"Slice":      func(x Node) Node { return Square(Slice(Exp(x), 0, 1, 2, 2)) },
+	"RotateR":    func(x Node) Node { return Prod(RotateR(Reshape(x, 4, 1), 1), Exp(Reshape(x, 4, 1))) },
+	"ReduceMax":  func(x Node) Node { return ProdScalar(Square(x), ReduceMax(Exp(Reshape(x, 4, 1)))) },
+	"ScalarMax":  func(x Node) Node { return ProdScalar(Exp(x), ScalarMax([]Node{At(x, 0, 0), Square(At(x, 1, 1))})) },
+	"MaxPooling": func(x Node) Node { return Prod(x, ProdScalar(x, MaxPooling(Square(x), 2, 2))) },
+	"Permute":    func(x Node) Node { return Prod(Permute(Exp(x), 1, 0), ReshapeTensor(Square(x), 2, 2)) },
+	"ReshapeTensor": func(x Node) Node {
+		return Prod(ReshapeTensor(x, 4), ReshapeTensor(Exp(x), 4))
+	},
+	"ReduceAxis": func(x Node) Node {
+		return Prod(ReduceSumAxis(ReshapeTensor(Square(x), 2, 2), 0), ReduceMeanAxis(ReshapeTensor(Exp(x), 2, 2), 1))
+	},
+	"tensor broadcast": func(x Node) Node {
+		a := Add(ReshapeTensor(x, 2, 1, 2), ReshapeTensor(Square(x), 1, 2, 2))
+		return Prod(a, Sin(a))
+	},
+	"AffineChain": func(x Node) Node {
+		return NewOperator(fn.NewAffineChain(fn.NewAffine[Node](Sigmoid(x), x, Square(x)), fn.NewTanh[Node](x).Stages()...))
+	},
+	"ElementwiseChain": func(x Node) Node {
+		stages := append(fn.NewSin[Node](x).Stages(), fn.NewGELU[Node](x).Stages()...)
+		return NewOperator(fn.NewElementwiseChain[Node](Square(x), stages...))
+	},
+	"ProdAdd": func(x Node) Node { return NewOperator(fn.NewProdAdd[Node](x, Exp(x), Square(x), Sin(x))) },
+}
+
+func ones(x Node, rows, cols int) Node {
+	return Var(x.Value().NewInitMatrix(rows, cols, 1))
+}
+
+func scalar(x Node, v float64) Node {
+	return Var(x.Value().NewScalar(v))
+}
+
+func TestGrad(t *testing.T) {
+	t.Run("float32", testGrad[float32])
+	t.Run("float64", testGrad[float64])
+}
+
+func testGrad[T float.DType](t *testing.T) {
+	eps, tol := 1.0e-6, 1.0e-5
+	if float.Interface(T(0)).BitSize() == 32 {
+		eps, tol = 1.0e-2, 1.0e-2
+	}
+
+	for name, f := range gradTestCases {
+		t.Run(name, func(t *testing.T) {
+			x := Var(mat.NewDense[T](2, 2, []T{0.5, 1.2, 0.8, 1.5})).WithGrad(true)
+			y := f(x)
+
+			gx := Grad([]Node{y}, []Node{x}, false)
+			require.Len(t, gx, 1)
+			require.NotNil(t, gx[0])
+			assert.False(t, gx[0].RequiresGrad())
+			assert.Nil(t, x.Grad(), "Grad must not accumulate gradients")
+
+			data := mat.Data[T](x.Value())
+			for i, v := range data {
+				data[i] = v + T(eps)
+				lp := f(x).Value().Sum().Scalar().F64()
+				data[i] = v - T(eps)
+				lm := f(x).Value().Sum().Scalar().F64()
+				data[i] = v
+				assert.InDelta(t, (lp-lm)/(2*eps), gx[0].Value().Data().F64()[i], tol, "element %d", i)
+			}
+		})
+	}
+}
+
+func TestGrad_SecondOrder(t *testing.T) {
+	const eps = 1.0e-6
+
+	for name, f := range gradTestCases {
+		t.Run(name, func(t *testing.T) {
+			x := Var(mat.NewDense[float64](2, 2, []float64{0.5, 1.2, 0.8, 1.5})).WithGrad(true)
+			v := Var(mat.NewDense[float64](2, 2, []float64{1, -0.5, 0.3, 2}))
+
+			// Hessian-vector product, as the gradient of the gradient projected on v.
+			g := Grad([]Node{f(x)}, []Node{x}, true)[0]
+			hv := Grad([]Node{Dot(g, v)}, []Node{x}, false)[0]
+
+			grad := func(delta float64) []float64 {
+				xd := Var(x.Value().Add(v.Value().ProdScalar(delta))).WithGrad(true)
+				return Grad([]Node{f(xd)}, []Node{xd}, false)[0].Value().Data().F64()
+			}
+			gPlus, gMinus := grad(eps), grad(-eps)
+			expected := make([]float64, len(gPlus))
+			for i := range expected {
+				expected[i] = (gPlus[i] - gMinus[i]) / (2 * eps)
+			}
+			if hv == nil {
+				// The gradient doesn't depend on x, so the Hessian is zero.
+				assert.InDeltaSlice(t, make([]float64, len(expected)), expected, 1.0e-5)
+				return
+			}
+			assert.InDeltaSlice(t, expected, hv.Value().Data(), 1.0e-5)
+		})
+	}
+}
+
+func TestGrad_GradientPenalty(t *testing.T) {
+	t.Run("float32", testGradGradientPenalty[float32])
+	t.Run("float64", testGradGradientPenalty[float64])
+}
+
+func testGradGradientPenalty[T float.DType](t *testing.T) {
+	// A gradient penalty (||∂D(x)/∂x|| - 1)², as in WGAN-GP, for a small
+	// critic D(x) = sum(tanh(Wx + b)).
+	w := Var(mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5})).WithGrad(true)
+	b := Var(mat.NewVecDense[T]([]T{0.1, -0.2})).WithGrad(true)
+	x := Var(mat.NewVecDense[T]([]T{0.7, -1.1, 0.4})).WithGrad(true)
+
+	penalty := func() Node {
+		d := ReduceSum(Tanh(Affine(b, w, x)))
+		gx := Grad([]Node{d}, []Node{x}, true)[0]
+		return Square(SubScalar(Sqrt(Dot(gx, gx)), Scalar[T](1)))
+	}
+
+	Backward(penalty())
+	require.NotNil(t, w.Grad())
+	require.NotNil(t, b.Grad())
+
+	const eps = 1.0e-3
+	for _, p := range []*Variable{w, b} {
+		data := mat.Data[T](p.Value())
+		grad := p.Grad().Data().F64()
+		for i, v := range data {
+			data[i] = v + eps
+			lp := penalty().Value().Scalar().F64()
+			data[i] = v - eps
+			lm := penalty().Value().Scalar().F64()
+			data[i] = v
+			assert.InDelta(t, (lp-lm)/(2*eps), grad[i], 1.0e-3)
+		}
+	}
+}
+
+func TestGrad_Inputs(t *testing.T) {
+	x1 := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+	x2 := Var(mat.NewVecDense[float64]([]float64{3, 4})).WithGrad(true)
+	x3 := Var(mat.NewVecDense[float64]([]float64{5, 6}))
+	unused := Var(mat.NewVecDense[float64]([]float64{7, 8})).WithGrad(true)
+
+	y1 := Prod(x1, x3)
+	y2 := Dot(x1, x2)
+
+	gs := Grad([]Node{y1, y2}, []Node{x1, x2, x3, unused}, false)
+	require.Len(t, gs, 4)
+	assert.Equal(t, []float64{5 + 3, 6 + 4}, gs[0].Value().Data().F64())
+	assert.Equal(t, []float64{1, 2}, gs[1].Value().Data().F64())
+	assert.Nil(t, gs[2])
+	assert.Nil(t, gs[3])
+
+	// The original graph is still usable.
+	assert.Equal(t, []float64{5, 12}, y1.Value().Data().F64())
+	Backward(y2)
+	assert.Equal(t, []float64{3, 4}, x1.Grad().Data().F64())
+}
+
+func TestGrad_Unsupported(t *testing.T) {
+	x := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+	y := NamedCustom("Double",
+		func(xs []mat.Matrix) mat.Matrix { return xs[0].ProdScalar(2) },
+		func(gy mat.Matrix, _ []mat.Matrix, _ mat.Matrix) []mat.Matrix { return []mat.Matrix{gy.ProdScalar(2)} },
+		x)
+	assert.Panics(t, func() { Grad([]Node{y}, []Node{x}, false) })
+}
@@ -0,0 +1,307 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gradcheck provides numerical verification of the gradients
+// computed by the automatic differentiation of the ag package.
+//
+// Given a function of ag.Node values and a set of input matrices, Check
+// computes the Jacobian of the function output with respect to each input
+// twice: numerically, through central finite differences, and
+// analytically, through ag.Backward. The two Jacobians are then compared
+// element by element, with tolerances depending on the floating point
+// type of the inputs.
+//
+// It is primarily intended for testing custom fn.Function implementations.
+package gradcheck
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Func is a function of graph nodes whose gradients are verified.
+type Func func(xs ...ag.Node) ag.Node
+
+// Config contains the parameters of a gradient check.
+type Config struct {
+	// Epsilon is the step used to perturb each input value when computing
+	// finite differences.
+	Epsilon float64
+	// AbsTol is the absolute tolerance.
+	AbsTol float64
+	// RelTol is the tolerance relative to the magnitude of the numerical
+	// derivative.
+	RelTol float64
+}
+
+// Option allows to configure a gradient check with your specific needs.
+type Option func(*Config)
+
+// WithEpsilon sets the finite difference step.
+func WithEpsilon(eps float64) Option {
+	return func(c *Config) {
+		c.Epsilon = eps
+	}
+}
+
+// WithTolerance sets the absolute and relative tolerances.
+func WithTolerance(absTol, relTol float64) Option {
+	return func(c *Config) {
+		c.AbsTol = absTol
+		c.RelTol = relTol
+	}
+}
+
+// DefaultConfig returns the default configuration for inputs of the given
+// bit size (32 or 64).
+//
+// Single precision finite differences are considerably noisier than double
+// precision ones, so they are computed with a larger step and compared
+// with looser tolerances.
+func DefaultConfig(bitSize int) Config {
+	switch bitSize {
+	case 32:
+		return Config{Epsilon: 1e-3, AbsTol: 1e-3, RelTol: 1e-2}
+	case 64:
+		return Config{Epsilon: 1e-6, AbsTol: 1e-5, RelTol: 1e-3}
+	default:
+		panic(fmt.Sprintf("gradcheck: unexpected bit size %d", bitSize))
+	}
+}
+
+// Mismatch describes a single element of a Jacobian for which the
+// analytical and numerical derivatives differ beyond the tolerances.
+type Mismatch struct {
+	// Input is the index of the input matrix.
+	Input int
+	// InputElement is the index of the input value, in row-major order.
+	InputElement int
+	// OutputElement is the index of the output value, in row-major order.
+	OutputElement int
+	// Analytical is the derivative computed by ag.Backward.
+	Analytical float64
+	// Numerical is the derivative computed with finite differences.
+	Numerical float64
+}
+
+// AbsError returns the absolute difference between the analytical and the
+// numerical derivatives.
+func (m Mismatch) AbsError() float64 {
+	return math.Abs(m.Analytical - m.Numerical)
+}
+
+// String returns a human-readable description of the mismatch.
+func (m Mismatch) String() string {
+	return fmt.Sprintf("input %d, element %d, output element %d: analytical %g, numerical %g, error %g",
+		m.Input, m.InputElement, m.OutputElement, m.Analytical, m.Numerical, m.AbsError())
+}
+
+// Report is the result of a gradient check.
+type Report struct {
+	// Config is the configuration used for the check.
+	Config Config
+	// Analytical contains, for each input, the Jacobian computed by
+	// ag.Backward, as a float64 matrix of size output-size × input-size.
+	Analytical []mat.Matrix
+	// Numerical contains, for each input, the Jacobian computed with finite
+	// differences, with the same layout of Analytical.
+	Numerical []mat.Matrix
+	// Mismatches lists all the Jacobian elements exceeding the tolerances.
+	Mismatches []Mismatch
+}
+
+// Passed reports whether the analytical and numerical Jacobians match.
+func (r *Report) Passed() bool {
+	return len(r.Mismatches) == 0
+}
+
+// MaxAbsError returns the maximum absolute difference between analytical
+// and numerical derivatives, over all inputs.
+func (r *Report) MaxAbsError() float64 {
+	maxErr := 0.0
+	for i, a := range r.Analytical {
+		an, nu := a.Data().F64(), r.Numerical[i].Data().F64()
+		for k, v := range an {
+			maxErr = math.Max(maxErr, math.Abs(v-nu[k]))
+		}
+	}
+	return maxErr
+}
+
+// Err returns an error listing all mismatches, or nil if the check passed.
+func (r *Report) Err() error {
+	if r.Passed() {
+		return nil
+	}
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "gradcheck: %d Jacobian elements exceed the tolerances (abs %g, rel %g):",
+		len(r.Mismatches), r.Config.AbsTol, r.Config.RelTol)
+	for _, m := range r.Mismatches {
+		sb.WriteString("\n  ")
+		sb.WriteString(m.String())
+	}
+	return fmt.Errorf("%s", sb.String())
+}
+
+// Check compares the gradients of f computed by ag.Backward with the ones
+// obtained through finite differences, at the point given by inputs.
+//
+// The function is evaluated many times, always on new ag.Variable nodes,
+// requiring gradients, built from copies of the inputs; it must therefore
+// be deterministic and free of side effects. The inputs are never modified.
+//
+// The default configuration is chosen according to the bit size of the
+// first input, and it can be adjusted with the given options.
+func Check(f Func, inputs []mat.Matrix, opts ...Option) *Report {
+	if len(inputs) == 0 {
+		panic("gradcheck: at least one input is required")
+	}
+	config := DefaultConfig(inputs[0].Data().BitSize())
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	r := &Report{
+		Config:     config,
+		Analytical: AnalyticalJacobians(f, inputs),
+		Numerical:  NumericalJacobians(f, inputs, config.Epsilon),
+	}
+
+	for i, a := range r.Analytical {
+		an, nu := a.Data().F64(), r.Numerical[i].Data().F64()
+		cols := a.Columns()
+		for k, v := range an {
+			if math.Abs(v-nu[k]) <= config.AbsTol+config.RelTol*math.Abs(nu[k]) {
+				continue
+			}
+			r.Mismatches = append(r.Mismatches, Mismatch{
+				Input:         i,
+				InputElement:  k % cols,
+				OutputElement: k / cols,
+				Analytical:    v,
+				Numerical:     nu[k],
+			})
+		}
+	}
+	return r
+}
+
+// AnalyticalJacobians computes the Jacobians of f with respect to each
+// input with ag.Backward, back-propagating one-hot output gradients.
+//
+// Each Jacobian is a float64 matrix of size output-size × input-size.
+func AnalyticalJacobians(f Func, inputs []mat.Matrix) []mat.Matrix {
+	outSize := len(evaluate(f, inputs))
+	jacobians := newJacobians(outSize, inputs)
+
+	for k := 0; k < outSize; k++ {
+		xs := newVariables(inputs)
+		y := f(xs...)
+
+		oneHot := make([]float64, outSize)
+		oneHot[k] = 1
+		gy := y.Value().ZerosLike()
+		gy.SetData(float.SliceInterface(oneHot))
+
+		ag.Backward(y, gy)
+		for i, x := range xs {
+			if grad := x.Grad(); grad != nil {
+				copy(mat.Data[float64](jacobians[i])[k*inputs[i].Size():], grad.Data().F64())
+			}
+		}
+		ag.ReleaseGraph(y)
+		mat.ReleaseMatrix(gy)
+		releaseVariables(xs)
+	}
+	return jacobians
+}
+
+// NumericalJacobians computes the Jacobians of f with respect to each
+// input with central finite differences, using the given step.
+//
+// Each Jacobian is a float64 matrix of size output-size × input-size.
+func NumericalJacobians(f Func, inputs []mat.Matrix, eps float64) []mat.Matrix {
+	outSize := len(evaluate(f, inputs))
+	jacobians := newJacobians(outSize, inputs)
+
+	perturbed := make([]mat.Matrix, len(inputs))
+	copy(perturbed, inputs)
+
+	for i, input := range inputs {
+		data := append([]float64(nil), input.Data().F64()...)
+		jacobian := mat.Data[float64](jacobians[i])
+		for j, v := range data {
+			// The actual step may differ from eps, due to the rounding
+			// of the perturbed values to the input type.
+			perturbed[i], data[j] = perturb(input, data, j, v+eps)
+			yPlus := evaluate(f, perturbed)
+			xPlus := data[j]
+			mat.ReleaseMatrix(perturbed[i])
+
+			perturbed[i], data[j] = perturb(input, data, j, v-eps)
+			yMinus := evaluate(f, perturbed)
+			xMinus := data[j]
+			mat.ReleaseMatrix(perturbed[i])
+
+			data[j] = v
+			for k := range yPlus {
+				jacobian[k*len(data)+j] = (yPlus[k] - yMinus[k]) / (xPlus - xMinus)
+			}
+		}
+		perturbed[i] = input
+	}
+	return jacobians
+}
+
+// perturb returns a copy of m, whose data is set from data with the value
+// at index j replaced by v. It also returns the perturbed value as actually
+// stored in the new matrix.
+func perturb(m mat.Matrix, data []float64, j int, v float64) (mat.Matrix, float64) {
+	data[j] = v
+	p := m.Clone()
+	p.SetData(float.SliceInterface(data))
+	return p, p.Data().F64()[j]
+}
+
+// evaluate computes the output data of f, without requiring gradients.
+func evaluate(f Func, inputs []mat.Matrix) []float64 {
+	xs := make([]ag.Node, len(inputs))
+	for i, x := range inputs {
+		xs[i] = ag.Var(x)
+	}
+	y := f(xs...)
+	data := append([]float64(nil), y.Value().Data().F64()...)
+	ag.ReleaseGraph(y)
+	return data
+}
+
+func newVariables(inputs []mat.Matrix) []ag.Node {
+	xs := make([]ag.Node, len(inputs))
+	for i, x := range inputs {
+		xs[i] = ag.Var(x.Clone()).WithGrad(true)
+	}
+	return xs
+}
+
+// releaseVariables releases the values and the gradients of the variables
+// created by newVariables.
+func releaseVariables(xs []ag.Node) {
+	for _, x := range xs {
+		x.ZeroGrad()
+		mat.ReleaseMatrix(x.Value())
+	}
+}
+
+func newJacobians(outSize int, inputs []mat.Matrix) []mat.Matrix {
+	jacobians := make([]mat.Matrix, len(inputs))
+	for i, x := range inputs {
+		jacobians[i] = mat.NewEmptyDense[float64](outSize, x.Size())
+	}
+	return jacobians
+}
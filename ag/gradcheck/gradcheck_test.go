@@ -0,0 +1,184 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gradcheck
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/nlpodyssey/spago/mat/mattest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("float32", testCheck[float32])
+	t.Run("float64", testCheck[float64])
+}
+
+func testCheck[T float.DType](t *testing.T) {
+	testCases := []struct {
+		name   string
+		f      Func
+		inputs []mat.Matrix
+	}{
+		{
+			name: "Sigmoid(Mul)",
+			f: func(xs ...ag.Node) ag.Node {
+				return ag.Sigmoid(ag.Mul(xs[0], xs[1]))
+			},
+			inputs: []mat.Matrix{
+				mat.NewDense[T](2, 3, []T{0.1, -0.2, 0.3, 0.4, 0.5, -0.6}),
+				mat.NewVecDense[T]([]T{1, 0.5, -1}),
+			},
+		},
+		{
+			name: "Softmax",
+			f: func(xs ...ag.Node) ag.Node {
+				return ag.Softmax(xs[0])
+			},
+			inputs: []mat.Matrix{
+				mat.NewVecDense[T]([]T{0.5, -1, 2, 0}),
+			},
+		},
+		{
+			name: "broadcast Prod",
+			f: func(xs ...ag.Node) ag.Node {
+				return ag.Prod(xs[0], xs[1])
+			},
+			inputs: []mat.Matrix{
+				mat.NewDense[T](2, 3, []T{1, 2, 3, 4, 5, 6}),
+				mat.NewDense[T](1, 3, []T{-1, 0.5, 2}),
+			},
+		},
+		{
+			name: "LogDet",
+			f: func(xs ...ag.Node) ag.Node {
+				return ag.LogDet(xs[0])
+			},
+			inputs: []mat.Matrix{
+				mat.NewDense[T](2, 2, []T{2, 0.5, 0.3, 1.5}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := make([]mat.Matrix, len(tc.inputs))
+			for i, x := range tc.inputs {
+				original[i] = x.Clone()
+			}
+
+			r := Check(tc.f, tc.inputs)
+			require.NoError(t, r.Err())
+			assert.True(t, r.Passed())
+			assert.Empty(t, r.Mismatches)
+			assert.Less(t, r.MaxAbsError(), r.Config.AbsTol*10)
+
+			outSize := len(evaluate(tc.f, tc.inputs))
+			for i, x := range tc.inputs {
+				assert.Equal(t, original[i].Data(), x.Data(), "input %d must not be modified", i)
+				assertDims(t, outSize, x.Size(), r.Analytical[i])
+				assertDims(t, outSize, x.Size(), r.Numerical[i])
+			}
+		})
+	}
+}
+
+func TestCheck_Mismatches(t *testing.T) {
+	t.Run("float32", testCheckMismatches[float32])
+	t.Run("float64", testCheckMismatches[float64])
+}
+
+func testCheckMismatches[T float.DType](t *testing.T) {
+	f := func(xs ...ag.Node) ag.Node {
+		return ag.NewOperator(&wrongSquare{x: xs[0]})
+	}
+	x := mat.NewVecDense[T]([]T{1, 0, 3})
+
+	r := Check(f, []mat.Matrix{x})
+	assert.False(t, r.Passed())
+	assert.Error(t, r.Err())
+
+	// The wrong derivative x is equal to the correct one, 2x, only at zero.
+	require.Len(t, r.Mismatches, 2)
+	for i, m := range r.Mismatches {
+		element := []int{0, 2}[i]
+		assert.Equal(t, 0, m.Input)
+		assert.Equal(t, element, m.InputElement)
+		assert.Equal(t, element, m.OutputElement)
+		assert.InDelta(t, float64(element+1), m.Analytical, 1.0e-6)
+		assert.InDelta(t, float64(2*(element+1)), m.Numerical, 1.0e-2)
+	}
+	assert.InDelta(t, 3, r.MaxAbsError(), 1.0e-2)
+
+	r = Check(f, []mat.Matrix{x}, WithTolerance(4, 0))
+	assert.True(t, r.Passed())
+	assert.NoError(t, r.Err())
+}
+
+func TestDefaultConfig(t *testing.T) {
+	c32, c64 := DefaultConfig(32), DefaultConfig(64)
+	assert.Greater(t, c32.Epsilon, c64.Epsilon)
+	assert.Greater(t, c32.AbsTol, c64.AbsTol)
+	assert.Greater(t, c32.RelTol, c64.RelTol)
+
+	assert.Panics(t, func() { DefaultConfig(16) })
+}
+
+func TestOptions(t *testing.T) {
+	f := func(xs ...ag.Node) ag.Node { return ag.Square(xs[0]) }
+	r := Check(f, []mat.Matrix{mat.NewScalar[float64](2)}, WithEpsilon(1e-4), WithTolerance(1e-6, 0))
+	assert.Equal(t, Config{Epsilon: 1e-4, AbsTol: 1e-6, RelTol: 0}, r.Config)
+	assert.True(t, r.Passed())
+}
+
+// wrongSquare is a square function with a wrong derivative.
+type wrongSquare struct {
+	x ag.Node
+}
+
+var _ fn.Function[ag.Node] = &wrongSquare{}
+
+func (r *wrongSquare) Operands() []ag.Node {
+	return []ag.Node{r.x}
+}
+
+func (r *wrongSquare) Forward() mat.Matrix {
+	return r.x.Value().Prod(r.x.Value())
+}
+
+func (r *wrongSquare) Backward(gy mat.Matrix) {
+	gx := r.x.Value().Prod(gy)
+	defer mat.ReleaseMatrix(gx)
+	r.x.AccGrad(gx)
+}
+
+func assertDims(t *testing.T, rows, cols int, m mat.Matrix) {
+	t.Helper()
+	assert.Equal(t, rows, m.Rows())
+	assert.Equal(t, cols, m.Columns())
+}
+
+func TestCheck_NoLeaks(t *testing.T) {
+	f := func(xs ...ag.Node) ag.Node {
+		return ag.Tanh(ag.Mul(xs[0], xs[1]))
+	}
+	inputs := []mat.Matrix{
+		mat.NewDense[float64](2, 3, []float64{0.1, -0.2, 0.3, 0.4, 0.5, -0.6}),
+		mat.NewVecDense[float64]([]float64{1, 0.5, -1}),
+	}
+	mattest.AssertNoLeaks(t, func() {
+		r := Check(f, inputs)
+		require.NoError(t, r.Err())
+		for i := range inputs {
+			mat.ReleaseMatrix(r.Analytical[i])
+			mat.ReleaseMatrix(r.Numerical[i])
+		}
+	})
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// JVP computes f at the given primals, together with the Jacobian-vector
+// product of f at that point with the given tangents, in forward mode.
+//
+// The tangents are in the same order of the primals and have the same
+// shape of their values; a nil tangent is treated as zero. The primals
+// don't need to require gradients, and no gradient is accumulated into the
+// nodes of the graph. The tangents of the intermediate operators are
+// released as soon as they are no longer needed.
+//
+// Each operator propagates the tangents of its operands with the
+// fn.TangentFunction implementation of its function; JVP panics if the
+// output depends on any operator whose function doesn't implement it, such
+// as Custom.
+func JVP(f func(xs ...Node) Node, primals []Node, tangents []mat.Matrix) (Node, mat.Matrix) {
+	if len(primals) != len(tangents) {
+		panic("ag: JVP requires one tangent for each primal")
+	}
+
+	y := f(primals...)
+
+	isPrimal := make(map[Node]bool, len(primals))
+	tangentOf := make(map[Node]mat.Matrix, len(primals))
+	for i, x := range primals {
+		isPrimal[x] = true
+		if tangents[i] != nil {
+			tangentOf[x] = tangents[i]
+		}
+	}
+
+	order := operatorsInTopologicalOrder(y)
+	consumers := make(map[Node]int, len(order))
+	for _, op := range order {
+		for _, x := range op.Operands() {
+			consumers[x]++
+		}
+	}
+
+	for _, op := range order {
+		operands := op.Operands()
+		if !isPrimal[op] {
+			ts := make([]mat.Matrix, len(operands))
+			found := false
+			for i, x := range operands {
+				ts[i] = tangentOf[x]
+				found = found || ts[i] != nil
+			}
+			if found {
+				tangentOf[op] = operatorTangent(op, ts)
+			}
+		}
+
+		// The tangents of the primals belong to the caller.
+		for _, x := range operands {
+			consumers[x]--
+			if t, ok := tangentOf[x]; ok && consumers[x] == 0 && x != y && !isPrimal[x] {
+				mat.ReleaseMatrix(t)
+				delete(tangentOf, x)
+			}
+		}
+	}
+
+	if t, ok := tangentOf[y]; ok {
+		return y, t
+	}
+	return y, y.Value().ZerosLike()
+}
+
+// operatorsInTopologicalOrder returns the operators the node depends on,
+// including the node itself, sorted in topological order.
+func operatorsInTopologicalOrder(n Node) []*Operator {
+	visited := make(map[Node]struct{})
+	var order []*Operator
+	var visit func(n Node)
+	visit = func(n Node) {
+		if _, ok := visited[n]; ok {
+			return
+		}
+		visited[n] = struct{}{}
+		op, ok := n.(*Operator)
+		if !ok {
+			return
+		}
+		for _, operand := range op.Operands() {
+			visit(operand)
+		}
+		order = append(order, op)
+	}
+	visit(n)
+	return order
+}
+
+// operatorTangent returns the tangent of the operator output, given the
+// tangents of its operands, some of which may be nil.
+func operatorTangent(op *Operator, tangents []mat.Matrix) mat.Matrix {
+	tf, ok := op.function.(fn.TangentFunction)
+	if !ok {
+		panic(fmt.Sprintf("ag: JVP is not supported by %s operators", op.Name()))
+	}
+
+	// Operands with a value, but without a tangent, are constants.
+	var zeros []mat.Matrix
+	for i, x := range op.Operands() {
+		if tangents[i] != nil {
+			continue
+		}
+		if v := x.Value(); v != nil {
+			tangents[i] = v.ZerosLike()
+			zeros = append(zeros, tangents[i])
+		}
+	}
+	defer func() {
+		for _, z := range zeros {
+			mat.ReleaseMatrix(z)
+		}
+	}()
+	return tf.Tangent(op.Value(), tangents)
+}
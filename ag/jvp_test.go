@@ -0,0 +1,145 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJVP(t *testing.T) {
+	t.Run("float32", testJVP[float32])
+	t.Run("float64", testJVP[float64])
+}
+
+func testJVP[T float.DType](t *testing.T) {
+	eps, tol := 1.0e-6, 1.0e-5
+	if float.Interface(T(0)).BitSize() == 32 {
+		eps, tol = 1.0e-2, 1.0e-2
+	}
+
+	// The test cases of Grad cover every built-in function.
+	for name, f := range gradTestCases {
+		t.Run(name, func(t *testing.T) {
+			x := Var(mat.NewDense[T](2, 2, []T{0.5, 1.2, 0.8, 1.5}))
+			v := mat.NewDense[T](2, 2, []T{1, -0.5, 0.3, 2})
+
+			y, jvp := JVP(func(xs ...Node) Node { return f(xs[0]) }, []Node{x}, []mat.Matrix{v})
+			require.NotNil(t, jvp)
+			assert.Equal(t, f(x).Value().Data(), y.Value().Data())
+			assert.False(t, x.RequiresGrad())
+			assert.Nil(t, x.Grad())
+
+			yPlus := f(Var(x.Value().Add(v.ProdScalar(eps)))).Value()
+			yMinus := f(Var(x.Value().Sub(v.ProdScalar(eps)))).Value()
+			expected := yPlus.Sub(yMinus).ProdScalarInPlace(1 / (2 * eps))
+			assert.Equal(t, expected.Rows(), jvp.Rows())
+			assert.Equal(t, expected.Columns(), jvp.Columns())
+			// The tolerance is relative to the large values, whose
+			// finite-difference approximations are less accurate.
+			actual := jvp.Data().F64()
+			for i, e := range expected.Data().F64() {
+				assert.InDelta(t, e, actual[i], tol*math.Max(1, math.Abs(e)), "element %d", i)
+			}
+		})
+	}
+}
+
+func TestJVP_ReverseMode(t *testing.T) {
+	t.Run("float32", testJVPReverseMode[float32])
+	t.Run("float64", testJVPReverseMode[float64])
+}
+
+func testJVPReverseMode[T float.DType](t *testing.T) {
+	// For a scalar output, the JVP is the dot product of the gradients,
+	// computed in reverse mode, with the tangents.
+	f := func(xs ...Node) Node {
+		h := Tanh(Affine(xs[2], xs[1], xs[0]))
+		return ReduceSum(Prod(Softmax(h), Exp(h)))
+	}
+	inputs := []mat.Matrix{
+		mat.NewVecDense[T]([]T{0.7, -1.1, 0.4}),
+		mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5}),
+		mat.NewVecDense[T]([]T{0.1, -0.2}),
+	}
+	tangents := []mat.Matrix{
+		mat.NewVecDense[T]([]T{1, 0.5, -1}),
+		mat.NewDense[T](2, 3, []T{0.2, 0.1, 0, -0.3, 0.4, 1}),
+		nil,
+	}
+
+	primals := make([]Node, len(inputs))
+	for i, x := range inputs {
+		primals[i] = Var(x.Clone())
+	}
+	y, jvp := JVP(f, primals, tangents)
+	require.Equal(t, 1, jvp.Size())
+
+	xs := make([]Node, len(inputs))
+	for i, x := range inputs {
+		xs[i] = Var(x.Clone()).WithGrad(true)
+	}
+	ry := f(xs...)
+	Backward(ry)
+	expected := 0.0
+	for i, x := range xs {
+		if tangents[i] != nil {
+			expected += x.Grad().Prod(tangents[i]).Sum().Scalar().F64()
+		}
+	}
+
+	assert.InDelta(t, ry.Value().Scalar().F64(), y.Value().Scalar().F64(), 1.0e-6)
+	assert.InDelta(t, expected, jvp.Scalar().F64(), 1.0e-5)
+}
+
+func TestJVP_Independent(t *testing.T) {
+	x1 := Var(mat.NewVecDense[float64]([]float64{1, 2}))
+	x2 := Var(mat.NewVecDense[float64]([]float64{3, 4}))
+
+	f := func(xs ...Node) Node { return Exp(xs[1]) }
+	y, jvp := JVP(f, []Node{x1, x2}, []mat.Matrix{mat.NewVecDense[float64]([]float64{1, 1}), nil})
+	assert.Equal(t, y.Value().Rows(), jvp.Rows())
+	assert.Equal(t, []float64{0, 0}, jvp.Data().F64())
+}
+
+func TestJVP_Unsupported(t *testing.T) {
+	x := Var(mat.NewVecDense[float64]([]float64{1, 2}))
+	f := func(xs ...Node) Node {
+		y := NamedCustom("Double",
+			func(xs []mat.Matrix) mat.Matrix { return xs[0].ProdScalar(2) },
+			func(gy mat.Matrix, _ []mat.Matrix, _ mat.Matrix) []mat.Matrix { return []mat.Matrix{gy.ProdScalar(2)} },
+			xs[0])
+		// The forward pass must not outlive the test, since JVP panics
+		// without waiting for it.
+		y.Value()
+		return y
+	}
+	assert.Panics(t, func() { JVP(f, []Node{x}, []mat.Matrix{x.Value().OnesLike()}) })
+	assert.Panics(t, func() { JVP(f, []Node{x}, nil) })
+}
+
+func TestJVP_ReleasesIntermediateTangents(t *testing.T) {
+	prev := mat.SetPoolTracking(true)
+	defer mat.SetPoolTracking(prev)
+	s0 := mat.ReadPoolStats()
+
+	x := Var(mat.NewVecDense[float64]([]float64{0.1, -0.5, 0.7}))
+	v := mat.NewVecDense[float64]([]float64{1, 2, 3})
+
+	f := func(xs ...Node) Node { return Exp(Prod(Sin(xs[0]), Tanh(xs[0]))) }
+	y, jvp := JVP(f, []Node{x}, []mat.Matrix{v})
+	assert.Equal(t, 3, v.Size(), "the tangent of the primal must not be released")
+
+	mat.ReleaseMatrix(jvp)
+	ReleaseGraph(y)
+	mat.ReleaseMatrix(x.Value())
+	mat.ReleaseMatrix(v)
+	assert.Equal(t, s0.Live, mat.ReadPoolStats().Live)
+}
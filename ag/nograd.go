@@ -0,0 +1,63 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+// NoGrad returns a node in inference mode, with the same value of the
+// given node, to be passed as input to a forward function.
+//
+// Any operator having an operand in inference mode is in inference mode
+// as well, so the mode is scoped to the part of the graph depending on
+// the returned node, regardless of the goroutines building it, and other
+// graphs, such as the ones built concurrently for training, are not
+// affected.
+//
+// An operator in inference mode is evaluated synchronously on creation,
+// without any gradient bookkeeping: it never requires gradients, so the
+// parameters among its operands behave as constants, and the backward
+// pass never reaches it. It's not associated with any time step either.
+//
+// The mode spreads only through the operands, so operators depending on
+// parameters alone, such as T(w), or on initial states which are not in
+// inference mode, are ordinary operators. Use NoGradScope to put in
+// inference mode every operator created by a forward function.
+func NoGrad(node Node) Node {
+	return &Wrapper{
+		Node:   node,
+		noGrad: true,
+	}
+}
+
+// NoGradScope calls f in inference mode: every operator created by the
+// calling goroutine while f is running is in inference mode (see NoGrad),
+// regardless of its operands, including the ones depending on parameters
+// alone. The parameters behave as constants within f.
+//
+// The scope is bound to the calling goroutine: the operators created by
+// other goroutines, such as the ones building graphs for training, or the
+// goroutines started by f itself, are not affected. The nodes returned by f
+// keep the mode after the scope ends, spreading it through the operands as
+// usual.
+//
+// While any goroutine is within a scope, NewOperator looks up the scope of
+// the calling goroutine, which slightly slows down the creation of the
+// operators outside inference mode.
+func NoGradScope(f func()) {
+	runInScope(func(s scope) scope {
+		s.noGrad = true
+		return s
+	}, f)
+}
+
+// isNoGrad reports whether the node is in inference mode.
+func isNoGrad(node Node) bool {
+	switch n := node.(type) {
+	case *Operator:
+		return n.noGrad
+	case *Wrapper:
+		return n.noGrad || isNoGrad(n.Node)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoGrad(t *testing.T) {
+	t.Run("float32", testNoGrad[float32])
+	t.Run("float64", testNoGrad[float64])
+}
+
+func testNoGrad[T float.DType](t *testing.T) {
+	w := Var(mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5})).WithGrad(true)
+	b := Var(mat.NewVecDense[T]([]T{0.1, -0.2})).WithGrad(true)
+	f := func(x Node) Node {
+		return ReduceSum(Tanh(Affine(b, w, x)))
+	}
+	x := mat.NewVecDense[T]([]T{0.7, -1.1, 0.4})
+
+	expected := f(Var(x))
+	y := f(NoGrad(Var(x)))
+
+	op := y.(*Operator)
+	assert.True(t, op.noGrad)
+	assert.False(t, y.RequiresGrad())
+	assert.Nil(t, op.cond.L)
+	assert.Zero(t, op.createdAt)
+	// The value is available without waiting.
+	assert.NotNil(t, op.value.Load())
+	assert.Equal(t, expected.Value().Data(), y.Value().Data())
+
+	// The parameters behave as constants.
+	Backward(y)
+	assert.Nil(t, w.Grad())
+	assert.Nil(t, b.Grad())
+	assert.Nil(t, y.Grad())
+
+	// The graph not depending on the input is not affected.
+	Backward(expected)
+	assert.NotNil(t, w.Grad())
+	assert.NotNil(t, b.Grad())
+
+	// Stopping the gradients keeps the inference mode.
+	z := Exp(StopGrad(y))
+	assert.True(t, z.(*Operator).noGrad)
+
+	ReleaseGraph(expected, y, z)
+}
+
+func TestNoGradScope(t *testing.T) {
+	w := Var(mat.NewDense[float64](2, 2, []float64{1, 2, 3, 4})).WithGrad(true)
+	h0 := Var(mat.NewVecDense[float64]([]float64{1, 2})).WithGrad(true)
+
+	// Outside the scope, the operators depending on the parameters only
+	// are ordinary operators.
+	expected := Tanh(Mul(T(w), h0))
+	assert.False(t, expected.(*Operator).noGrad)
+	assert.True(t, expected.RequiresGrad())
+
+	var wt, y, concurrent Node
+	NoGradScope(func() {
+		wt = T(w)
+		y = Tanh(Mul(wt, h0))
+
+		// Other goroutines are not affected.
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			concurrent = T(w)
+		}()
+		<-done
+	})
+	for _, n := range []Node{wt, y} {
+		op := n.(*Operator)
+		assert.True(t, op.noGrad)
+		assert.False(t, op.RequiresGrad())
+		assert.Nil(t, op.cond.L)
+	}
+	assert.False(t, concurrent.(*Operator).noGrad)
+	assert.Equal(t, expected.Value().Data(), y.Value().Data())
+
+	// The parameters behave as constants.
+	Backward(y)
+	assert.Nil(t, w.Grad())
+	assert.Nil(t, h0.Grad())
+
+	// The scope ends with the function, but the nodes keep the mode.
+	z := Exp(y)
+	assert.True(t, z.(*Operator).noGrad)
+	assert.False(t, T(w).(*Operator).noGrad)
+
+	ReleaseGraph(expected, y, z, concurrent)
+}
+
+func TestNoGradScope_Nested(t *testing.T) {
+	x := Var(mat.NewScalar[float64](1)).WithGrad(true)
+	NoGradScope(func() {
+		NoGradScope(func() {
+			assert.True(t, Exp(x).(*Operator).noGrad)
+		})
+		assert.True(t, Exp(x).(*Operator).noGrad)
+	})
+	assert.False(t, Exp(x).(*Operator).noGrad)
+	assert.Zero(t, atomic.LoadInt32(&scopedGoroutines))
+	assert.Empty(t, scopes)
+}
+
+func TestNoGradScope_Panic(t *testing.T) {
+	assert.Panics(t, func() {
+		NoGradScope(func() { panic("test") })
+	})
+	assert.Zero(t, atomic.LoadInt32(&scopedGoroutines))
+	assert.Empty(t, scopes)
+}
+
+func TestNoGrad_Failure(t *testing.T) {
+	x := NoGrad(Var(mat.NewVecDense[float64]([]float64{1, 2})))
+	y := Add(x, Var(mat.NewVecDense[float64]([]float64{1, 2, 3})))
+	z := Tanh(y)
+
+	_, err := ValueContext(context.Background(), z)
+	require.Error(t, err)
+	assert.IsType(t, &OperatorError{}, err)
+	assert.Equal(t, "Add", err.(*OperatorError).Operator)
+	assert.Panics(t, func() { z.Value() })
+	ReleaseGraph(z)
+}
@@ -5,6 +5,7 @@
 package ag
 
 import (
+	"context"
 	"reflect"
 	"strings"
 	"sync"
@@ -26,6 +27,9 @@ type Operator struct {
 	function      fn.Function[Node]
 	// value is the results of a forward evaluation, as mat.Matrix.
 	value atomic.Value
+	// err is the error occurred during the forward evaluation, if any.
+	// It's set while holding cond.L, before notifying the waiting goroutines.
+	err error
 	// cond is the condition variable used as rendezvous points for
 	// goroutines involved in both forward and backward operations.
 	// NewOperator sets cond.L to &mx.
@@ -41,28 +45,66 @@ type Operator struct {
 	createdAt uint64
 	// Function's operands are memoized here after the first request.
 	operands []Node
+	// profileID identifies the operator within the measurements of a
+	// Profiler. It's 0 if no profiler was running on creation.
+	profileID uint64
+	// noGrad reports whether the operator was created in inference mode
+	// (see NoGrad), in which case it's evaluated synchronously, cond is
+	// not set up, and it never requires gradients.
+	noGrad bool
+	// ctx is the context of the ContextScope the operator was created in,
+	// if any. The forward function is not executed once it's done.
+	ctx context.Context
 }
 
 // NewOperator creates a new operator along with its forward pass.
+//
+// If any operand is in inference mode (see NoGrad), or the operator is
+// created within NoGradScope, the operator is in inference mode as well.
+// If it's created within ContextScope, its forward function is not executed
+// once the context is done. In deterministic mode (see SetDeterministic), the forward pass is
+// executed synchronously.
 func NewOperator(f fn.Function[Node]) Node {
+	operands := f.Operands()
+	s, _ := currentScope()
+	if s.noGrad {
+		return newNoGradOperator(f, operands, s.ctx)
+	}
+	for _, x := range operands {
+		if isNoGrad(x) {
+			return newNoGradOperator(f, operands, s.ctx)
+		}
+	}
+
 	op := &Operator{
 		requiresGrad:  -1, // lazy evaluation
 		backwardState: idle,
 		function:      f,
 		pendingGrads:  0,
 		createdAt:     atomic.LoadUint64(&tsCounter),
+		operands:      operands,
+		profileID:     newProfileID(),
+		ctx:           s.ctx,
 	}
 
 	op.cond.L = &op.mx
 
+	if IsDeterministic() {
+		op.forward()
+		return op
+	}
 	go op.forward()
 
 	return op
 }
 
 // Name returns the Name of the operator.
-// The name is taken from the name of r.function via reflection.
+// The name is taken from the name of r.function via reflection, unless the
+// function provides its own name, as the ones created by Custom do.
 func (o *Operator) Name() string {
+	if f, ok := o.function.(namedFunction); ok {
+		return f.Name()
+	}
 	name := reflect.ValueOf(o.function).Elem().Type().Name()
 	// Strip trailing generics, if any: "foo[bar]" becomes "foo".
 	if i := strings.IndexByte(name, '['); i != -1 {
@@ -80,16 +122,34 @@ func (o *Operator) Operands() []Node {
 }
 
 // Value returns the result of the function.
+//
+// If the forward evaluation of the operator failed, it panics with an
+// *OperatorError; see ValueContext for a non-panicking alternative.
 func (o *Operator) Value() mat.Matrix {
+	value, err := o.wait()
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// wait waits for the forward evaluation to complete, returning its result.
+func (o *Operator) wait() (mat.Matrix, error) {
 	if v := o.value.Load(); v != nil {
-		return v.(mat.Matrix)
+		return v.(mat.Matrix), nil
+	}
+	if o.noGrad {
+		return nil, o.err // evaluated synchronously
 	}
 
 	o.cond.L.Lock()
 	defer o.cond.L.Unlock()
 	for {
 		if v := o.value.Load(); v != nil {
-			return v.(mat.Matrix)
+			return v.(mat.Matrix), nil
+		}
+		if o.err != nil {
+			return nil, o.err
 		}
 		o.cond.Wait()
 	}
@@ -147,6 +207,7 @@ func (o *Operator) AccGrad(grad mat.Matrix) {
 	if !o.RequiresGrad() {
 		return
 	}
+	CheckGrad(grad)
 	o.cond.L.Lock()
 	defer o.cond.L.Unlock()
 
@@ -187,29 +248,87 @@ func (o *Operator) initOutputGrad(outputGrad mat.Matrix) {
 }
 
 // forward executes the function and inform all goroutines that have been waiting for the result.
+// A panic occurred during the execution is recovered and stored as error.
 func (o *Operator) forward() {
-	o.value.Store(o.function.Forward())
+	value, err := o.safeForward()
+	if err == nil {
+		o.value.Store(value)
+	}
 	o.cond.L.Lock()
+	o.err = err
 	o.cond.Broadcast()
 	o.cond.L.Unlock()
 }
 
-// backward executes the backward
-func (o *Operator) backward() {
-	if !o.RequiresGrad() {
-		return
+func (o *Operator) safeForward() (_ mat.Matrix, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = newOperatorError(o, false, p)
+		}
+	}()
+	if o.ctx != nil {
+		// The context is checked once the operands are available, right
+		// before the actual computation.
+		for _, x := range o.operands {
+			if err := waitValue(x); err != nil {
+				return nil, err
+			}
+		}
+		if err := o.ctx.Err(); err != nil {
+			return nil, newOperatorError(o, false, err)
+		}
+	}
+	var value mat.Matrix
+	if p := runningProfiler(); p != nil {
+		value = p.forward(o)
+	} else {
+		value = o.function.Forward()
 	}
+	if detectAnomalies() {
+		checkFinite(o, value)
+	}
+	return value, nil
+}
 
-	grad := o.Grad()
-	if grad == nil {
-		return
+// waitValue waits for the forward evaluation of the node, if it's an
+// operator, returning the error occurred, if any.
+func waitValue(node Node) error {
+	switch n := node.(type) {
+	case *Operator:
+		_, err := n.wait()
+		return err
+	case *Wrapper:
+		return waitValue(n.Node)
+	default:
+		return nil
+	}
+}
+
+// newNoGradOperator creates a new operator in inference mode, evaluating
+// its function synchronously.
+func newNoGradOperator(f fn.Function[Node], operands []Node, ctx context.Context) *Operator {
+	op := &Operator{
+		requiresGrad:  0,
+		backwardState: idle,
+		function:      f,
+		operands:      operands,
+		profileID:     newProfileID(),
+		noGrad:        true,
+		ctx:           ctx,
+	}
+	value, err := op.safeForward()
+	if err == nil {
+		op.value.Store(value)
 	}
-	o.function.Backward(grad)
+	op.err = err
+	return op
 }
 
 // releaseValue sets the operator's value to nil releases the memory.
 func (o *Operator) releaseValue() {
-	value := o.Value() // also safely waits for any forward goroutine to finish
-	mat.ReleaseMatrix(value)
+	value, _ := o.wait() // safely waits for any forward goroutine to finish
+	if value != nil {
+		mat.ReleaseMatrix(value)
+	}
 	o.value = atomic.Value{}
 }
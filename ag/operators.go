@@ -53,6 +53,11 @@ func CELU(x, alpha Node) Node {
 	return NewOperator(fn.NewCELU(x, alpha))
 }
 
+// Cholesky returns a new operator node as a result of the fn.Cholesky function.
+func Cholesky(x Node) Node {
+	return NewOperator(fn.NewCholesky(x))
+}
+
 // ColView returns a new operator node as a result of the fn.ColView function.
 func ColView(x Node, column int) Node {
 	return NewOperator(fn.NewColView(x, column))
@@ -138,6 +143,11 @@ func Identity(x Node) Node {
 	return NewOperator(fn.NewIdentity(x))
 }
 
+// Inverse returns a new operator node as a result of the fn.Inverse function.
+func Inverse(x Node) Node {
+	return NewOperator(fn.NewInverse(x))
+}
+
 // LeakyReLU returns a new operator node as a result of the fn.LeakyReLU function.
 func LeakyReLU(x, alpha Node) Node {
 	return NewOperator(fn.NewLeakyReLU(x, alpha))
@@ -148,6 +158,11 @@ func Log(x Node) Node {
 	return NewOperator(fn.NewLog(x))
 }
 
+// LogDet returns a new operator node as a result of the fn.LogDet function.
+func LogDet(x Node) Node {
+	return NewOperator(fn.NewLogDet(x))
+}
+
 // Max returns a new operator node as a result of the fn.Max function.
 func Max(x1, x2 Node) Node {
 	return NewOperator(fn.NewMax(x1, x2))
@@ -182,6 +197,11 @@ func Neg(x Node) Node {
 	return NewOperator(fn.NewNeg(x))
 }
 
+// Permute returns a new operator node as a result of the fn.Permute function.
+func Permute(x Node, axes ...int) Node {
+	return NewOperator(fn.NewPermute(x, axes...))
+}
+
 // Pow returns a new operator node as a result of the fn.Pow function.
 func Pow(x Node, power float64) Node {
 	return NewOperator(fn.NewPow(x, power))
@@ -212,11 +232,21 @@ func ReduceMean(x Node) Node {
 	return NewOperator(fn.NewReduceMean(x))
 }
 
+// ReduceMeanAxis returns a new operator node as a result of the fn.ReduceMeanAxis function.
+func ReduceMeanAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewReduceMeanAxis(x, axis))
+}
+
 // ReduceSum returns a new operator node as a result of the fn.ReduceSum function.
 func ReduceSum(x Node) Node {
 	return NewOperator(fn.NewReduceSum(x))
 }
 
+// ReduceSumAxis returns a new operator node as a result of the fn.ReduceSumAxis function.
+func ReduceSumAxis(x Node, axis int) Node {
+	return NewOperator(fn.NewReduceSumAxis(x, axis))
+}
+
 // ReLU returns a new operator node as a result of the `ReLU` function.
 func ReLU(x Node) Node {
 	return NewOperator(fn.NewReLU(x))
@@ -227,6 +257,11 @@ func Reshape(x Node, rows, columns int) Node {
 	return NewOperator(fn.NewReshape(x, rows, columns))
 }
 
+// ReshapeTensor returns a new operator node as a result of the fn.ReshapeTensor function.
+func ReshapeTensor(x Node, shape ...int) Node {
+	return NewOperator(fn.NewReshapeTensor(x, shape...))
+}
+
 // ReverseSub returns a new operator node as a result of the fn.ReverseSub function.
 func ReverseSub(x1, x2 Node) Node {
 	return NewOperator(fn.NewReverseSubScalar(x1, x2))
@@ -293,6 +328,11 @@ func Softsign(x Node) Node {
 	return NewOperator(fn.NewSoftsign(x))
 }
 
+// Solve returns a new operator node as a result of the fn.Solve function.
+func Solve(a, b Node) Node {
+	return NewOperator(fn.NewSolve(a, b))
+}
+
 // SparseMax returns a new operator node as a result of the fn.SparseMax function.
 func SparseMax(x Node) Node {
 	return NewOperator(fn.NewSparseMax(x))
@@ -357,3 +397,8 @@ func Tanh(x Node) Node {
 func Threshold(x, threshold, k Node) Node {
 	return NewOperator(fn.NewThreshold(x, threshold, k))
 }
+
+// Trace returns a new operator node as a result of the fn.Trace function.
+func Trace(x Node) Node {
+	return NewOperator(fn.NewTrace(x))
+}
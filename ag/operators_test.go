@@ -0,0 +1,90 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTensorOperators(t *testing.T) {
+	t.Run("float32", testTensorOperators[float32])
+	t.Run("float64", testTensorOperators[float64])
+}
+
+func testTensorOperators[T float.DType](t *testing.T) {
+	// A batch of 2 sequences, each of 3 vectors of size 2.
+	x := Var(mat.NewDenseTensor([]int{2, 3, 2}, []T{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	})).WithGrad(true)
+	w := Var(mat.NewDenseTensor([]int{2, 2, 3}, []T{
+		1, 1, 1, 2, 2, 2,
+		3, 3, 3, 4, 4, 4,
+	})).WithGrad(true)
+
+	// Element-wise operators preserve the shape of the tensors.
+	y := Prod(Permute(x, 0, 2, 1), w)
+	assert.Equal(t, []int{2, 2, 3}, mat.Shape(y.Value()))
+
+	// Sum over the sequence, then average over the batch.
+	z := ReduceMeanAxis(ReduceSumAxis(y, 2), 0)
+	assert.Equal(t, []int{2}, mat.Shape(z.Value()))
+	assert.InDeltaSlice(t, []T{45, 72}, z.Value().Data(), 1e-6)
+
+	Backward(ReduceSum(z))
+
+	assert.Equal(t, []int{2, 3, 2}, mat.Shape(x.Grad()))
+	assert.InDeltaSlice(t, []T{
+		0.5, 1, 0.5, 1, 0.5, 1,
+		1.5, 2, 1.5, 2, 1.5, 2,
+	}, x.Grad().Data(), 1e-6)
+
+	assert.Equal(t, []int{2, 2, 3}, mat.Shape(w.Grad()))
+	assert.InDeltaSlice(t, []T{
+		0.5, 1.5, 2.5, 1, 2, 3,
+		3.5, 4.5, 5.5, 4, 5, 6,
+	}, w.Grad().Data(), 1e-6)
+
+	y = ReshapeTensor(x, 6, 2)
+	assert.Equal(t, []int{6, 2}, mat.Shape(y.Value()))
+}
+
+func TestLinAlgOperators(t *testing.T) {
+	// Negative log-likelihood (up to constants) of y under a Gaussian with
+	// covariance BBᵀ + I, plus additional terms using the other operators.
+	loss := func(b, y Node) Node {
+		cov := Add(Mul(b, T(b)), Var(mat.NewIdentityDense[float64](3)))
+		return Add(
+			Add(LogDet(cov), Dot(y, Solve(cov, y))),
+			Add(Trace(Inverse(cov)), ReduceSum(Mul(Cholesky(cov), y))),
+		)
+	}
+
+	bData := []float64{
+		0.5, -0.2,
+		0.1, 0.8,
+		-0.4, 0.3,
+	}
+	y := Var(mat.NewVecDense([]float64{1, -2, 0.5}))
+	b := Var(mat.NewDense(3, 2, bData)).WithGrad(true)
+	Backward(loss(b, y))
+	grad := b.Grad().Data().F64()
+
+	const h = 1e-6
+	for i, v := range bData {
+		eval := func(d float64) float64 {
+			data := append([]float64{}, bData...)
+			data[i] = v + d
+			return loss(Var(mat.NewDense(3, 2, data)), y).Value().Scalar().F64()
+		}
+		numerical := (eval(h) - eval(-h)) / (2 * h)
+		assert.InDelta(t, numerical, grad[i], 1e-5, fmt.Sprintf("element %d", i))
+	}
+}
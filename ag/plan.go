@@ -0,0 +1,246 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// Plan is a static execution plan, obtained by tracing a forward function
+// with TracePlan, which can be run on new inputs.
+//
+// Running a plan evaluates the traced operators sequentially, in
+// topological order, on the calling goroutine: no goroutine is spawned for
+// each operator, and no synchronization is involved. The value of each
+// intermediate operator is released as soon as it's no longer needed, so
+// that its buffer is reused by the following operators, and across runs.
+//
+// The plan only supports the forward pass: the traced operators can't be
+// used for backpropagation. A Plan is not safe for concurrent use.
+type Plan struct {
+	inputs  []*Variable
+	steps   []planStep
+	outputs []Node
+	// isOutput reports, for each step, whether its value is an output.
+	isOutput []bool
+	values   []mat.Matrix
+}
+
+// planStep is a single operation of a Plan.
+type planStep struct {
+	op       *Operator
+	operands []int
+	rows     int
+	cols     int
+	// release contains the indices of the steps whose values are no longer
+	// needed after this one.
+	release []int
+}
+
+// PlanStep describes a single operation of a Plan.
+type PlanStep struct {
+	// Operator is the name of the operator.
+	Operator string
+	// Operands contains the slot of each operand: the inputs of the plan
+	// come first, followed by the steps, in order. The slot of constant
+	// operands, such as parameters, is -1.
+	Operands []int
+	// Rows is the number of rows of the value computed while tracing.
+	Rows int
+	// Columns is the number of columns of the value computed while tracing.
+	Columns int
+}
+
+// TracePlan evaluates f on new Variables holding the given input values, and
+// records the operators it creates into a Plan.
+//
+// The plan reproduces the operations performed by f during the tracing,
+// including the ones on constant nodes, such as model parameters, whose
+// values are read again on each run. Any control flow of f depending on
+// the input values is instead frozen by the tracing.
+//
+// The output values of the tracing are released.
+func TracePlan(f func(xs ...Node) []Node, inputs ...mat.Matrix) *Plan {
+	p := &Plan{
+		inputs: make([]*Variable, len(inputs)),
+	}
+	xs := make([]Node, len(inputs))
+	visited := make(map[Node]struct{}, len(inputs))
+	for i, x := range inputs {
+		p.inputs[i] = Var(x)
+		xs[i] = p.inputs[i]
+		visited[p.inputs[i]] = struct{}{}
+	}
+
+	ys := f(xs...)
+
+	var visit func(n Node)
+	visit = func(n Node) {
+		n = unwrapNode(n)
+		if _, ok := visited[n]; ok {
+			return
+		}
+		visited[n] = struct{}{}
+		op, ok := n.(*Operator)
+		if !ok {
+			return
+		}
+		for _, operand := range op.Operands() {
+			visit(operand)
+		}
+		value := op.Value()
+		p.steps = append(p.steps, planStep{
+			op:   op,
+			rows: value.Rows(),
+			cols: value.Columns(),
+		})
+	}
+	p.outputs = make([]Node, len(ys))
+	for i, y := range ys {
+		visit(y)
+		p.outputs[i] = unwrapNode(y)
+	}
+	p.schedule()
+
+	for _, s := range p.steps {
+		s.op.releaseValue()
+	}
+	p.values = make([]mat.Matrix, len(p.outputs))
+	return p
+}
+
+// schedule computes the operand slots of each step, and determines when
+// the value of each step can be released.
+func (p *Plan) schedule() {
+	slots := make(map[Node]int, len(p.inputs)+len(p.steps))
+	for i, x := range p.inputs {
+		slots[x] = i
+	}
+	for i, s := range p.steps {
+		slots[s.op] = len(p.inputs) + i
+	}
+
+	p.isOutput = make([]bool, len(p.steps))
+	for _, y := range p.outputs {
+		if slot, ok := slots[y]; ok && slot >= len(p.inputs) {
+			p.isOutput[slot-len(p.inputs)] = true
+		}
+	}
+
+	lastUse := make([]int, len(p.steps))
+	for i := range p.steps {
+		s := &p.steps[i]
+		s.release = nil
+		s.operands = make([]int, len(s.op.Operands()))
+		for j, operand := range s.op.Operands() {
+			slot, ok := slots[unwrapNode(operand)]
+			if !ok {
+				s.operands[j] = -1
+				continue
+			}
+			s.operands[j] = slot
+			if slot >= len(p.inputs) {
+				lastUse[slot-len(p.inputs)] = i
+			}
+		}
+	}
+	for j, i := range lastUse {
+		if !p.isOutput[j] {
+			p.steps[i].release = append(p.steps[i].release, j)
+		}
+	}
+}
+
+// unwrapNode returns the node wrapped by a Wrapper, or the node itself.
+func unwrapNode(n Node) Node {
+	for {
+		w, ok := n.(*Wrapper)
+		if !ok {
+			return n
+		}
+		n = w.Node
+	}
+}
+
+// Steps describes the operations of the plan, in execution order.
+func (p *Plan) Steps() []PlanStep {
+	steps := make([]PlanStep, len(p.steps))
+	for i, s := range p.steps {
+		steps[i] = PlanStep{
+			Operator: s.op.Name(),
+			Operands: append([]int(nil), s.operands...),
+			Rows:     s.rows,
+			Columns:  s.cols,
+		}
+	}
+	return steps
+}
+
+// Run evaluates the plan on the given inputs, which must have the same
+// shape of the ones used for tracing, and returns the output values.
+//
+// The output values are owned by the plan: they are valid until the next
+// call to Run or Release.
+func (p *Plan) Run(inputs ...mat.Matrix) []mat.Matrix {
+	if len(inputs) != len(p.inputs) {
+		panic(fmt.Sprintf("ag: plan expected %d inputs, got %d", len(p.inputs), len(inputs)))
+	}
+	for i, x := range inputs {
+		traced := p.inputs[i].value
+		if x.Rows() != traced.Rows() || x.Columns() != traced.Columns() {
+			panic(fmt.Sprintf("ag: plan input %d expected to be %d×%d, got %d×%d",
+				i, traced.Rows(), traced.Columns(), x.Rows(), x.Columns()))
+		}
+	}
+
+	p.Release()
+	for i, x := range inputs {
+		p.inputs[i].value = x
+	}
+
+	profiler := runningProfiler()
+	for _, s := range p.steps {
+		if profiler != nil {
+			s.op.value.Store(profiler.run(s.op, false, s.op.function.Forward))
+		} else {
+			s.op.value.Store(s.op.function.Forward())
+		}
+		if detectAnomalies() {
+			checkFinite(s.op, s.op.value.Load().(mat.Matrix))
+		}
+		for _, j := range s.release {
+			releaseStepValue(p.steps[j].op)
+		}
+	}
+
+	for i, y := range p.outputs {
+		p.values[i] = y.Value()
+	}
+	return p.values
+}
+
+// Release releases the output values of the last run.
+func (p *Plan) Release() {
+	for i, s := range p.steps {
+		if p.isOutput[i] {
+			releaseStepValue(s.op)
+		}
+	}
+	for i := range p.values {
+		p.values[i] = nil
+	}
+}
+
+// releaseStepValue releases the value of an operator evaluated by a plan,
+// if any.
+func releaseStepValue(op *Operator) {
+	if v := op.value.Load(); v != nil {
+		mat.ReleaseMatrix(v.(mat.Matrix))
+		op.value = atomic.Value{}
+	}
+}
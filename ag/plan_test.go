@@ -0,0 +1,132 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracePlan(t *testing.T) {
+	t.Run("float32", testTracePlan[float32])
+	t.Run("float64", testTracePlan[float64])
+}
+
+func testTracePlan[T float.DType](t *testing.T) {
+	w := Var(mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5}))
+	b := Var(mat.NewVecDense[T]([]T{0.1, -0.2}))
+
+	f := func(xs ...Node) []Node {
+		h := Tanh(Affine(b, w, xs[0]))
+		y := Softmax(Add(h, StopGrad(Reshape(xs[1], 2, 1))))
+		return []Node{y, ReduceSum(h), xs[1]}
+	}
+	newInputs := func(k T) []mat.Matrix {
+		return []mat.Matrix{
+			mat.NewVecDense[T]([]T{0.7 * k, -1.1, 0.4 + k}),
+			mat.NewDense[T](1, 2, []T{k, -k}),
+		}
+	}
+
+	p := TracePlan(f, newInputs(1)...)
+
+	steps := p.Steps()
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		names[i] = s.Operator
+	}
+	assert.Equal(t, []string{"Affine", "Tanh", "Reshape", "Add", "Softmax", "ReduceSum"}, names)
+	assert.Equal(t, PlanStep{Operator: "Affine", Operands: []int{-1, -1, 0}, Rows: 2, Columns: 1}, steps[0])
+	assert.Equal(t, PlanStep{Operator: "Add", Operands: []int{3, 4}, Rows: 2, Columns: 1}, steps[3])
+	assert.Equal(t, PlanStep{Operator: "ReduceSum", Operands: []int{3}, Rows: 1, Columns: 1}, steps[5])
+
+	for _, k := range []T{1, 2, -0.5} {
+		inputs := newInputs(k)
+		expected := f(Var(inputs[0]), Var(inputs[1]))
+		actual := p.Run(inputs...)
+		require.Len(t, actual, 3)
+		for i, y := range expected {
+			assert.Equal(t, y.Value().Data(), actual[i].Data(), "output %d", i)
+		}
+		assert.Same(t, inputs[1], actual[2])
+	}
+
+	// Constants, such as parameters, are read on each run.
+	mat.Data[T](b.Value())[0] = 5
+	inputs := newInputs(1)
+	expected := f(Var(inputs[0]), Var(inputs[1]))[0].Value()
+	assert.Equal(t, expected.Data(), p.Run(inputs...)[0].Data())
+
+	p.Release()
+}
+
+func TestPlan_Run(t *testing.T) {
+	x := mat.NewVecDense[float64]([]float64{1, 2, 3})
+	p := TracePlan(func(xs ...Node) []Node {
+		h := xs[0]
+		for i := 0; i < 10; i++ {
+			h = Sigmoid(Add(h, xs[0]))
+		}
+		return []Node{h}
+	}, x)
+
+	t.Run("intermediate values are released", func(t *testing.T) {
+		p.Run(x)
+		for i, s := range p.steps {
+			assert.Equal(t, i == len(p.steps)-1, s.op.value.Load() != nil, "step %d", i)
+		}
+	})
+
+	t.Run("wrong inputs", func(t *testing.T) {
+		assert.Panics(t, func() { p.Run() })
+		assert.Panics(t, func() { p.Run(x, x) })
+		assert.Panics(t, func() { p.Run(mat.NewVecDense[float64]([]float64{1, 2})) })
+	})
+
+	t.Run("allocations", func(t *testing.T) {
+		// After the first runs, the buffers are recycled.
+		allocs := testing.AllocsPerRun(100, func() { p.Run(x) })
+		assert.Less(t, allocs, float64(len(p.steps)))
+	})
+}
+
+func BenchmarkPlan(b *testing.B) {
+	f := func(xs ...Node) []Node {
+		h := xs[0]
+		for i := 0; i < 20; i++ {
+			h = Tanh(Affine(xs[2], xs[1], h))
+		}
+		return []Node{h}
+	}
+	inputs := []mat.Matrix{
+		mat.NewInitVecDense[float32](8, 0.5),
+		mat.NewInitDense[float32](8, 8, 0.1),
+		mat.NewInitVecDense[float32](8, 0.1),
+	}
+
+	b.Run("dynamic", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			xs := []Node{Var(inputs[0]), Var(inputs[1]), Var(inputs[2])}
+			y := f(xs...)[0]
+			y.Value()
+			ReleaseGraph(y)
+		}
+	})
+
+	b.Run("plan", func(b *testing.B) {
+		p := TracePlan(f, inputs...)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.Run(inputs...)
+		}
+		p.Release()
+	})
+}
@@ -0,0 +1,381 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nlpodyssey/spago/mat"
+)
+
+var (
+	// activeProfiler is the running *Profiler, if any.
+	activeProfiler atomic.Value
+	// profilerMu guards the starting and stopping of the profilers.
+	profilerMu sync.Mutex
+	// profiledNodes is the counter of the operators created while a
+	// profiler is running, used to identify them.
+	profiledNodes uint64
+)
+
+// runningProfiler returns the running profiler, or nil.
+func runningProfiler() *Profiler {
+	p, _ := activeProfiler.Load().(*Profiler)
+	return p
+}
+
+// compositeFunction is implemented by the functions evaluating a sub-graph
+// of operators on their own, such as the checkpoint function.
+//
+// Their evaluation is not serialized by the Profiler, since it depends
+// on other operators.
+type compositeFunction interface {
+	composite()
+}
+
+// Profiler records the wall time spent by the operators during the forward
+// and the backward pass, along with the matrices obtained from the pools
+// (see mat.ReadPoolStats) and the shapes of the output values.
+//
+// Profiling is opt-in, and it has no cost until a Profiler is started.
+// A running Profiler enables the tracking of the pools (see
+// mat.SetPoolTracking), so that released matrices must not be used again.
+// While a Profiler is running, the forward and backward functions of the
+// operators are serialized, so that their measurements don't overlap: the
+// graph is still evaluated by concurrent goroutines, but the times spent
+// waiting for the operands are not measured.
+//
+// The measurements are aggregated by operator type (see Operator.Name)
+// and by named node (see ProfileName).
+type Profiler struct {
+	start time.Time
+	// exec serializes the evaluation of the operators.
+	exec sync.Mutex
+	// mu guards the fields below.
+	mu       sync.Mutex
+	events   []profileEvent
+	names    map[uint64]string
+	tracking bool
+	stopped  bool
+}
+
+// profileEvent is a single forward or backward evaluation of an operator.
+type profileEvent struct {
+	operator  string
+	node      uint64
+	backward  bool
+	composite bool
+	start     time.Duration
+	duration  time.Duration
+	rows      int
+	cols      int
+	matrices  uint64
+	bytes     uint64
+}
+
+// StartProfiler starts recording the evaluation of the operators, until
+// Profiler.Stop is called.
+//
+// It panics if another profiler is already running.
+func StartProfiler() *Profiler {
+	profilerMu.Lock()
+	defer profilerMu.Unlock()
+	if runningProfiler() != nil {
+		panic("ag: a profiler is already running")
+	}
+	p := &Profiler{
+		start:    time.Now(),
+		names:    make(map[uint64]string),
+		tracking: mat.SetPoolTracking(true),
+	}
+	activeProfiler.Store(p)
+	return p
+}
+
+// Stop stops the profiler. The recorded measurements are still available.
+func (p *Profiler) Stop() {
+	profilerMu.Lock()
+	defer profilerMu.Unlock()
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+	activeProfiler.Store((*Profiler)(nil))
+	mat.SetPoolTracking(p.tracking)
+}
+
+// ProfileName names the given node, so that the measurements of its
+// operator are also reported on their own (see Profiler.NodeStats).
+// More nodes can have the same name, such as the same layer of a model
+// applied at different time steps.
+//
+// It has no effect on nodes other than operators, or if the operator
+// was created while no profiler was running.
+func ProfileName(node Node, name string) Node {
+	op, ok := node.(*Operator)
+	if !ok || op.profileID == 0 {
+		return node
+	}
+	if p := runningProfiler(); p != nil {
+		p.mu.Lock()
+		p.names[op.profileID] = name
+		p.mu.Unlock()
+	}
+	return node
+}
+
+// newProfileID returns a new identifier for an operator, if a profiler is
+// running, otherwise 0.
+func newProfileID() uint64 {
+	if runningProfiler() == nil {
+		return 0
+	}
+	return atomic.AddUint64(&profiledNodes, 1)
+}
+
+// forward evaluates the operator, once its operands are available.
+func (p *Profiler) forward(op *Operator) mat.Matrix {
+	if _, ok := op.function.(compositeFunction); !ok {
+		for _, x := range op.Operands() {
+			if x != nil {
+				x.Value()
+			}
+		}
+	}
+	return p.run(op, false, op.function.Forward)
+}
+
+// backward back-propagates the gradients of the operator.
+func (p *Profiler) backward(op *Operator, gy mat.Matrix) {
+	p.run(op, true, func() mat.Matrix {
+		op.function.Backward(gy)
+		return gy
+	})
+}
+
+// run measures the execution of f, which returns the matrix whose shape is
+// recorded.
+func (p *Profiler) run(op *Operator, backward bool, f func() mat.Matrix) mat.Matrix {
+	_, composite := op.function.(compositeFunction)
+	if !composite {
+		p.exec.Lock()
+		defer p.exec.Unlock()
+	}
+
+	s0 := mat.ReadPoolStats()
+	start := time.Now()
+	y := f()
+	duration := time.Since(start)
+	s1 := mat.ReadPoolStats()
+
+	e := profileEvent{
+		operator:  op.Name(),
+		node:      op.profileID,
+		backward:  backward,
+		composite: composite,
+		start:     start.Sub(p.start),
+		duration:  duration,
+	}
+	if !composite {
+		// The allocations of a composite function overlap with others.
+		e.matrices, e.bytes = s1.Gets-s0.Gets, s1.GetBytes-s0.GetBytes
+	}
+	if y != nil {
+		e.rows, e.cols = y.Rows(), y.Columns()
+	}
+
+	p.mu.Lock()
+	p.events = append(p.events, e)
+	p.mu.Unlock()
+	return y
+}
+
+// ProfileStats are the measurements of a group of operators, aggregated
+// by the Profiler.
+type ProfileStats struct {
+	// Name is the operator type, or the name of the nodes.
+	Name string
+	// Forwards is the number of forward evaluations.
+	Forwards int
+	// Backwards is the number of backward evaluations.
+	Backwards int
+	// ForwardTime is the time spent in the forward evaluations.
+	ForwardTime time.Duration
+	// BackwardTime is the time spent in the backward evaluations.
+	BackwardTime time.Duration
+	// Matrices is the number of matrices allocated from the pools.
+	Matrices uint64
+	// Bytes is the size of the data of the allocated matrices.
+	Bytes uint64
+	// Shapes are the distinct shapes of the output values, formatted as
+	// "rows×columns", in order of appearance.
+	Shapes []string
+}
+
+// Stats returns the measurements aggregated by operator type, sorted by
+// descending total time.
+func (p *Profiler) Stats() []ProfileStats {
+	return p.aggregate(func(e profileEvent) string {
+		return e.operator
+	})
+}
+
+// NodeStats returns the measurements aggregated by the names of the nodes
+// (see ProfileName), sorted by descending total time. The operators
+// without a name are not reported.
+func (p *Profiler) NodeStats() []ProfileStats {
+	p.mu.Lock()
+	names := make(map[uint64]string, len(p.names))
+	for id, name := range p.names {
+		names[id] = name
+	}
+	p.mu.Unlock()
+
+	return p.aggregate(func(e profileEvent) string {
+		return names[e.node]
+	})
+}
+
+// aggregate groups the events by the given key, skipping empty keys.
+func (p *Profiler) aggregate(key func(e profileEvent) string) []ProfileStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	index := make(map[string]int)
+	var stats []ProfileStats
+	for _, e := range p.events {
+		k := key(e)
+		if k == "" {
+			continue
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(stats)
+			index[k] = i
+			stats = append(stats, ProfileStats{Name: k})
+		}
+		s := &stats[i]
+		if e.backward {
+			s.Backwards++
+			s.BackwardTime += e.duration
+		} else {
+			s.Forwards++
+			s.ForwardTime += e.duration
+			s.Shapes = appendShape(s.Shapes, e.rows, e.cols)
+		}
+		s.Matrices += e.matrices
+		s.Bytes += e.bytes
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].ForwardTime+stats[i].BackwardTime > stats[j].ForwardTime+stats[j].BackwardTime
+	})
+	return stats
+}
+
+func appendShape(shapes []string, rows, cols int) []string {
+	shape := fmt.Sprintf("%d×%d", rows, cols)
+	for _, s := range shapes {
+		if s == shape {
+			return shapes
+		}
+	}
+	return append(shapes, shape)
+}
+
+// WriteTable writes the measurements aggregated by operator type, followed
+// by the ones aggregated by named node, if any, as plain-text tables.
+func (p *Profiler) WriteTable(w io.Writer) error {
+	if err := writeStatsTable(w, "OPERATOR", p.Stats()); err != nil {
+		return err
+	}
+	if stats := p.NodeStats(); len(stats) > 0 {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+		return writeStatsTable(w, "NODE", stats)
+	}
+	return nil
+}
+
+func writeStatsTable(w io.Writer, title string, stats []ProfileStats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "%s\tFORWARDS\tFORWARD TIME\tBACKWARDS\tBACKWARD TIME\tMATRICES\tBYTES\tSHAPES\t\n", title)
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%d\t%s\t%d\t%d\t%v\t\n",
+			s.Name, s.Forwards, s.ForwardTime, s.Backwards, s.BackwardTime, s.Matrices, s.Bytes, s.Shapes)
+	}
+	return tw.Flush()
+}
+
+// traceEvent is a complete event of the Chrome trace-event format.
+type traceEvent struct {
+	Name      string         `json:"name"`
+	Category  string         `json:"cat"`
+	Phase     string         `json:"ph"`
+	Timestamp float64        `json:"ts"`
+	Duration  float64        `json:"dur"`
+	PID       int            `json:"pid"`
+	TID       int            `json:"tid"`
+	Args      map[string]any `json:"args"`
+}
+
+// WriteTrace writes the recorded evaluations in the Chrome trace-event
+// JSON format, which can be loaded by trace viewers such as Perfetto or
+// chrome://tracing.
+//
+// Each evaluation is an event named after the node, if it has a name, or
+// the operator type otherwise, with category "forward" or "backward".
+// The evaluations of composite operators, such as checkpoints, which
+// overlap with the others, are reported on a separate thread.
+func (p *Profiler) WriteTrace(w io.Writer) error {
+	p.mu.Lock()
+	events := make([]traceEvent, len(p.events))
+	for i, e := range p.events {
+		name := p.names[e.node]
+		if name == "" {
+			name = e.operator
+		}
+		category := "forward"
+		if e.backward {
+			category = "backward"
+		}
+		tid := 1
+		if e.composite {
+			tid = 2
+		}
+		events[i] = traceEvent{
+			Name:      name,
+			Category:  category,
+			Phase:     "X",
+			Timestamp: float64(e.start.Nanoseconds()) / 1e3,
+			Duration:  float64(e.duration.Nanoseconds()) / 1e3,
+			PID:       1,
+			TID:       tid,
+			Args: map[string]any{
+				"operator": e.operator,
+				"shape":    fmt.Sprintf("%d×%d", e.rows, e.cols),
+				"matrices": e.matrices,
+				"bytes":    e.bytes,
+			},
+		}
+	}
+	p.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{
+		TraceEvents:     events,
+		DisplayTimeUnit: "ns",
+	})
+}
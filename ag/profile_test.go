@@ -0,0 +1,121 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	t.Run("float32", testProfiler[float32])
+	t.Run("float64", testProfiler[float64])
+}
+
+func testProfiler[T float.DType](t *testing.T) {
+	w := Var(mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5})).WithGrad(true)
+	x := Var(mat.NewVecDense[T]([]T{0.7, -1.1, 0.4}))
+
+	p := StartProfiler()
+	assert.Panics(t, func() { StartProfiler() })
+
+	var ys []Node
+	for i := 0; i < 3; i++ {
+		h := ProfileName(Mul(w, x), "layer")
+		ys = append(ys, ReduceSum(Tanh(h)))
+	}
+	y := Add(Add(ys[0], ys[1]), ys[2])
+	Backward(y)
+	p.Stop()
+	p.Stop() // no-op
+
+	// Once stopped, nothing is recorded.
+	z := Tanh(x)
+	z.Value()
+	assert.Zero(t, z.(*Operator).profileID)
+
+	stats := make(map[string]ProfileStats)
+	for _, s := range p.Stats() {
+		stats[s.Name] = s
+	}
+	require.Len(t, stats, 4)
+	assert.Equal(t, 3, stats["Mul"].Forwards)
+	assert.Equal(t, 3, stats["Mul"].Backwards)
+	assert.Equal(t, []string{"2×1"}, stats["Mul"].Shapes)
+	assert.Equal(t, 3, stats["ReduceSum"].Forwards)
+	assert.Equal(t, []string{"1×1"}, stats["ReduceSum"].Shapes)
+	assert.Equal(t, 2, stats["Add"].Forwards)
+	assert.Equal(t, 3, stats["Tanh"].Forwards)
+	assert.Equal(t, 3, stats["Tanh"].Backwards)
+	assert.Positive(t, stats["Mul"].ForwardTime)
+	assert.Positive(t, stats["Mul"].Matrices)
+	assert.Positive(t, stats["Mul"].Bytes)
+
+	nodes := p.NodeStats()
+	require.Len(t, nodes, 1)
+	assert.Equal(t, "layer", nodes[0].Name)
+	assert.Equal(t, stats["Mul"].Forwards, nodes[0].Forwards)
+	assert.Equal(t, stats["Mul"].Backwards, nodes[0].Backwards)
+	assert.Equal(t, stats["Mul"].Matrices, nodes[0].Matrices)
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, p.WriteTable(&buf))
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 8)
+		assert.Contains(t, lines[0], "OPERATOR")
+		assert.Contains(t, lines[6], "NODE")
+		assert.Contains(t, lines[7], "layer")
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, p.WriteTrace(&buf))
+		var trace struct {
+			TraceEvents []struct {
+				Name     string         `json:"name"`
+				Category string         `json:"cat"`
+				Phase    string         `json:"ph"`
+				Args     map[string]any `json:"args"`
+			} `json:"traceEvents"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &trace))
+		require.Len(t, trace.TraceEvents, 2*11)
+		counts := make(map[string]int)
+		for _, e := range trace.TraceEvents {
+			assert.Equal(t, "X", e.Phase)
+			counts[e.Name+"/"+e.Category]++
+		}
+		assert.Equal(t, 3, counts["layer/forward"])
+		assert.Equal(t, 3, counts["layer/backward"])
+		assert.Equal(t, 3, counts["Tanh/backward"])
+	})
+}
+
+func TestProfiler_Checkpoint(t *testing.T) {
+	p := StartProfiler()
+	defer p.Stop()
+
+	x := Var(mat.NewVecDense[float64]([]float64{1, 2, 3})).WithGrad(true)
+	ys := Checkpoint(func(xs ...Node) []Node {
+		return []Node{ReduceSum(Tanh(xs[0]))}
+	}, x)
+	Backward(ys[0])
+	assert.NotNil(t, x.Grad())
+
+	names := make(map[string]int)
+	for _, s := range p.Stats() {
+		names[s.Name] = s.Backwards
+	}
+	assert.Equal(t, 1, names["checkpoint"])
+	assert.Equal(t, 1, names["Tanh"])
+}
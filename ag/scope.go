@@ -0,0 +1,94 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// scope holds the settings applied by NewOperator to the operators created
+// by a goroutine within a scoped function, such as NoGradScope and
+// ContextScope.
+type scope struct {
+	// noGrad reports whether the operators are created in inference mode.
+	noGrad bool
+	// ctx is the context checked by the operators before their forward
+	// evaluation, if not nil.
+	ctx context.Context
+}
+
+var (
+	// scopedGoroutines is the number of goroutines running a scoped
+	// function. While it's 0, currentScope returns immediately.
+	scopedGoroutines int32
+	// scopes maps the ID of each goroutine running a scoped function to
+	// the settings of its innermost scope.
+	scopes   = make(map[uint64]scope)
+	scopesMu sync.RWMutex
+)
+
+// runInScope calls f, applying the settings returned by update, given the
+// settings of the enclosing scope, to the operators created by the calling
+// goroutine while f is running.
+func runInScope(update func(s scope) scope, f func()) {
+	id := goroutineID()
+	scopesMu.Lock()
+	outer, nested := scopes[id]
+	scopes[id] = update(outer)
+	scopesMu.Unlock()
+	if !nested {
+		atomic.AddInt32(&scopedGoroutines, 1)
+	}
+
+	defer func() {
+		scopesMu.Lock()
+		if nested {
+			scopes[id] = outer
+		} else {
+			delete(scopes, id)
+		}
+		scopesMu.Unlock()
+		if !nested {
+			atomic.AddInt32(&scopedGoroutines, -1)
+		}
+	}()
+
+	f()
+}
+
+// currentScope returns the settings of the innermost scope of the calling
+// goroutine, if any.
+func currentScope() (scope, bool) {
+	if atomic.LoadInt32(&scopedGoroutines) == 0 {
+		return scope{}, false
+	}
+	id := goroutineID()
+	scopesMu.RLock()
+	s, ok := scopes[id]
+	scopesMu.RUnlock()
+	return s, ok
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// header of its stack trace, which reads "goroutine <id> [<status>]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	const prefix = "goroutine "
+	if len(b) < len(prefix) {
+		panic("ag: unexpected stack trace format")
+	}
+	var id uint64
+	for _, c := range b[len(prefix):] {
+		if c < '0' || c > '9' {
+			break
+		}
+		id = id*10 + uint64(c-'0')
+	}
+	return id
+}
@@ -98,6 +98,7 @@ func (r *Variable) AccGrad(grad mat.Matrix) {
 	if !r.requiresGrad {
 		return
 	}
+	CheckGrad(grad)
 	r.gradMu.Lock()
 	defer r.gradMu.Unlock()
 	if r.grad == nil {
@@ -137,6 +137,15 @@ func testVariableGradients[T float.DType](t *testing.T) {
 		assert.False(t, v.HasGrad())
 	})
 
+	t.Run("accumulating a gradient of different dimensions", func(t *testing.T) {
+		v := Var(mat.NewVecDense([]T{1, 2, 3})).WithGrad(true)
+		v.AccGrad(mat.NewVecDense([]T{1, 1, 1}))
+		require.Panics(t, func() {
+			v.AccGrad(mat.NewScalar[T](5))
+		})
+		mattest.RequireMatrixEquals(t, mat.NewVecDense([]T{1, 1, 1}), v.Grad())
+	})
+
 	t.Run("with requires gradient false", func(t *testing.T) {
 		v := Var(mat.NewScalar[T](42)).WithGrad(false)
 		require.Nil(t, v.Grad())
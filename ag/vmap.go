@@ -0,0 +1,321 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+)
+
+// VMap evaluates the per-example function f over a batch of examples, and
+// returns a matrix whose i-th row is the output of f for the i-th example.
+//
+// Each batch is a matrix holding one example per row, and all batches must
+// have the same number of rows. The function receives the i-th row of each
+// batch as a column vector, and must return a vector or a scalar, as the
+// forward functions of the models usually do.
+//
+// The function is traced once, on the first example. Then, each operator
+// depending on the examples is rewritten to an equivalent operator on the
+// whole batch: for example, the product of a weight matrix by an example
+// becomes the product of the batch by the transposed weight matrix. The
+// operators which don't depend on the examples, such as the ones on the
+// parameters, are shared as they are. The supported operators are the
+// single-input element-wise functions, the arithmetic functions (including
+// the scalar ones), Mul and Affine by weights which don't depend on the
+// examples, Dot, ReduceSum and ReduceMean. If f involves any other operator
+// depending on the examples, VMap falls back to evaluating f on each
+// example, and stacking the outputs.
+//
+// As with TracePlan, any control flow of f depending on the input values is
+// frozen by the tracing. The result can be backpropagated as usual, to both
+// the batches and the nodes used by f.
+func VMap(f func(xs ...Node) Node, batches ...Node) Node {
+	if len(batches) == 0 {
+		panic("ag: VMap requires at least one batch")
+	}
+	size := batches[0].Value().Rows()
+	for _, b := range batches[1:] {
+		if b.Value().Rows() != size {
+			panic("ag: VMap batches must have the same number of rows")
+		}
+	}
+	if size == 0 {
+		panic("ag: VMap requires at least one example")
+	}
+
+	t := &vmapTrace{
+		batched: make(map[Node]Node, len(batches)),
+		visited: make(map[Node]bool),
+	}
+	xs := make([]Node, len(batches))
+	for i, b := range batches {
+		v := b.Value()
+		x := Var(v.ExtractRow(0).ReshapeInPlace(v.Columns(), 1))
+		xs[i] = x
+		t.batched[x] = b
+		t.visited[x] = true
+	}
+	y := f(xs...)
+
+	t.visit(y)
+	var result Node
+	if !t.unsupported && t.visited[y] {
+		result = t.build(y)
+	}
+	for _, op := range t.order {
+		op.releaseValue()
+	}
+	for _, x := range xs {
+		mat.ReleaseMatrix(x.Value())
+	}
+	if result != nil {
+		return result
+	}
+	return vmapRows(f, batches, size)
+}
+
+// vmapRows evaluates f on each example, stacking the outputs.
+func vmapRows(f func(xs ...Node) Node, batches []Node, size int) Node {
+	ys := make([]Node, size)
+	xs := make([]Node, len(batches))
+	for i := range ys {
+		for j, b := range batches {
+			xs[j] = T(RowView(b, i))
+		}
+		ys[i] = f(xs...)
+	}
+	return Stack(ys...)
+}
+
+// vmapTrace holds the operators of a graph traced by VMap which depend on
+// the examples.
+type vmapTrace struct {
+	// batched maps each input example to its batch, and then each operator
+	// depending on the examples to its batched equivalent, once built.
+	batched map[Node]Node
+	// visited reports whether each visited node depends on the examples.
+	visited map[Node]bool
+	// order contains the operators depending on the examples, in
+	// topological order.
+	order []*Operator
+	rules []vmapRuleFunc
+	// unsupported reports whether any of the visited nodes depending on the
+	// examples has no batched equivalent.
+	unsupported bool
+}
+
+// vmapRuleFunc builds the batched equivalent of an operator, given its
+// operands.
+type vmapRuleFunc func(o vmapOperands) Node
+
+// vmapOperands are the operands of an operator traced by VMap, along with
+// their batched equivalents, which are nil for the operands not depending
+// on the examples.
+type vmapOperands struct {
+	xs      []Node
+	batched []Node
+}
+
+// row returns the batched equivalent of the i-th operand or, if it doesn't
+// depend on the examples, the operand as a row vector, which is broadcast
+// over the batch.
+func (o vmapOperands) row(i int) Node {
+	if b := o.batched[i]; b != nil {
+		return b
+	}
+	if v := o.xs[i].Value(); v != nil && v.Rows() > 1 {
+		return T(o.xs[i])
+	}
+	return o.xs[i]
+}
+
+// visit reports whether the node depends on the examples, recording the
+// operators which do, along with their batching rules.
+func (t *vmapTrace) visit(n Node) bool {
+	if n == nil {
+		return false
+	}
+	if dependent, ok := t.visited[n]; ok {
+		return dependent
+	}
+	dependent := false
+	switch v := n.(type) {
+	case *Wrapper:
+		// A wrapper would be dropped by the batched equivalent.
+		if dependent = t.visit(v.Node); dependent {
+			t.unsupported = true
+		}
+	case *Operator:
+		operands := v.Operands()
+		isBatched := make([]bool, len(operands))
+		for i, x := range operands {
+			isBatched[i] = t.visit(x)
+			dependent = dependent || isBatched[i]
+		}
+		if dependent {
+			t.order = append(t.order, v)
+			rule := vmapRule(v, isBatched)
+			if value := v.Value(); rule == nil || value == nil || value.Columns() != 1 {
+				t.unsupported = true
+			}
+			t.rules = append(t.rules, rule)
+		}
+	}
+	t.visited[n] = dependent
+	return dependent
+}
+
+// build creates the batched equivalent of each operator, returning the one
+// of the given node.
+func (t *vmapTrace) build(y Node) Node {
+	for i, op := range t.order {
+		operands := op.Operands()
+		batched := make([]Node, len(operands))
+		for j, x := range operands {
+			batched[j] = t.batched[x]
+		}
+		t.batched[op] = t.rules[i](vmapOperands{xs: operands, batched: batched})
+	}
+	return t.batched[y]
+}
+
+// vmapRule returns the batching rule of the operator, given which of its
+// operands depend on the examples, or nil if it's not supported.
+func vmapRule(op *Operator, isBatched []bool) vmapRuleFunc {
+	// constant reports whether none of the given operands depends on the
+	// examples.
+	constant := func(indices ...int) bool {
+		for _, i := range indices {
+			if isBatched[i] {
+				return false
+			}
+		}
+		return true
+	}
+	// scalar returns the rule of an operator with a scalar second operand,
+	// which is kept as it is, unless the scalar depends on the examples.
+	scalar := func(same, elementwise func(x1, x2 Node) Node) vmapRuleFunc {
+		if isBatched[0] && !isBatched[1] {
+			return func(o vmapOperands) Node { return same(o.batched[0], o.xs[1]) }
+		}
+		return func(o vmapOperands) Node { return elementwise(o.row(0), o.row(1)) }
+	}
+
+	switch op.function.(type) {
+	case *fn.Add[Node]:
+		return func(o vmapOperands) Node { return Add(o.row(0), o.row(1)) }
+	case *fn.Sub[Node]:
+		return func(o vmapOperands) Node { return Sub(o.row(0), o.row(1)) }
+	case *fn.Prod[Node]:
+		return func(o vmapOperands) Node { return Prod(o.row(0), o.row(1)) }
+	case *fn.Div[Node]:
+		return func(o vmapOperands) Node { return Div(o.row(0), o.row(1)) }
+	case *fn.AddScalar[Node]:
+		return scalar(AddScalar, Add)
+	case *fn.SubScalar[Node]:
+		return scalar(SubScalar, Sub)
+	case *fn.ReverseSubScalar[Node]:
+		return scalar(ReverseSub, func(x1, x2 Node) Node { return Sub(x2, x1) })
+	case *fn.ProdScalar[Node]:
+		return scalar(ProdScalar, Prod)
+	case *fn.DivScalar[Node]:
+		return scalar(DivScalar, Div)
+	case *fn.Mul[Node]:
+		// W·x for each example x is X·Wᵀ for the batch X.
+		if !constant(0) {
+			return nil
+		}
+		return func(o vmapOperands) Node { return Mul(o.batched[1], T(o.xs[0])) }
+	case *fn.Affine[Node]:
+		for i := 1; i < len(isBatched); i += 2 {
+			if !constant(i) {
+				return nil
+			}
+		}
+		return vmapAffine
+	case *fn.Dot[Node]:
+		// Both operands are column vectors of the same size.
+		switch {
+		case constant(1):
+			return func(o vmapOperands) Node { return Mul(o.batched[0], o.xs[1]) }
+		case constant(0):
+			return func(o vmapOperands) Node { return Mul(o.batched[1], o.xs[0]) }
+		default:
+			return func(o vmapOperands) Node {
+				return Mul(Prod(o.batched[0], o.batched[1]), vmapFilled(o.xs[0], 1))
+			}
+		}
+	case *fn.ReduceSum[Node]:
+		return func(o vmapOperands) Node { return Mul(o.batched[0], vmapFilled(o.xs[0], 1)) }
+	case *fn.ReduceMean[Node]:
+		return func(o vmapOperands) Node {
+			return Mul(o.batched[0], vmapFilled(o.xs[0], 1/float64(o.xs[0].Value().Size())))
+		}
+	case *fn.Identity[Node]:
+		return func(o vmapOperands) Node { return o.batched[0] }
+	case *fn.Exp[Node]:
+		return func(o vmapOperands) Node { return Exp(o.batched[0]) }
+	case *fn.Log[Node]:
+		return func(o vmapOperands) Node { return Log(o.batched[0]) }
+	case *fn.Sqrt[Node]:
+		return func(o vmapOperands) Node { return Sqrt(o.batched[0]) }
+	case *fn.Square[Node]:
+		return func(o vmapOperands) Node { return Square(o.batched[0]) }
+	case *fn.LeakyReLU[Node]:
+		if !constant(1) {
+			return nil
+		}
+		return func(o vmapOperands) Node { return LeakyReLU(o.batched[0], o.xs[1]) }
+	case *fn.ELU[Node]:
+		if !constant(1) {
+			return nil
+		}
+		return func(o vmapOperands) Node { return ELU(o.batched[0], o.xs[1]) }
+	case *fn.SwishB[Node]:
+		if !constant(1) {
+			return nil
+		}
+		return func(o vmapOperands) Node { return SwishB(o.batched[0], o.xs[1]) }
+	}
+
+	if ef, ok := elementwise(op); ok {
+		stages := ef.Stages()
+		return func(o vmapOperands) Node {
+			return NewOperator(fn.NewElementwiseChain(o.batched[0], stages...))
+		}
+	}
+	return nil
+}
+
+// vmapAffine is the batching rule of the Affine function, whose weights
+// don't depend on the examples.
+func vmapAffine(o vmapOperands) Node {
+	var y Node
+	if o.xs[0] != nil {
+		y = o.row(0)
+	}
+	for i := 1; i < len(o.xs); i += 2 {
+		var wx Node
+		if x := o.batched[i+1]; x != nil {
+			wx = Mul(x, T(o.xs[i]))
+		} else {
+			wx = T(Mul(o.xs[i], o.xs[i+1]))
+		}
+		if y == nil {
+			y = wx
+			continue
+		}
+		y = Add(wx, y)
+	}
+	return y
+}
+
+// vmapFilled returns a new column vector, with the same number of rows of
+// the value of x, filled with v.
+func vmapFilled(x Node, v float64) Node {
+	xv := x.Value()
+	return Var(xv.NewInitMatrix(xv.Rows(), 1, v))
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ag
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag/fn"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVMap(t *testing.T) {
+	t.Run("float32", testVMap[float32])
+	t.Run("float64", testVMap[float64])
+}
+
+func testVMap[T float.DType](t *testing.T) {
+	tol := 1.0e-12
+	if float.Interface(T(0)).BitSize() == 32 {
+		tol = 1.0e-4
+	}
+
+	newInputs := func() []Node {
+		return []Node{
+			Var(mat.NewVecDense[T]([]T{0.1, -0.2})).WithGrad(true),
+			Var(mat.NewDense[T](2, 3, []T{0.1, -0.4, 0.3, 0.6, 0.2, -0.5})).WithGrad(true),
+			Var(mat.NewDense[T](2, 2, []T{0.5, -0.3, 0.8, 0.1})).WithGrad(true),
+			Var(mat.NewVecDense[T]([]T{1, 2})),
+			Var(mat.NewDense[T](4, 3, []T{
+				0.7, -1.1, 0.4,
+				0.2, 0.3, -0.9,
+				-0.6, 0.5, 0.1,
+				1.2, -0.4, 0.8,
+			})).WithGrad(true),
+			Var(mat.NewDense[T](4, 2, []T{
+				0.3, -0.1,
+				0.5, 0.9,
+				-0.2, 0.4,
+				0.6, -0.7,
+			})).WithGrad(true),
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		f       func(ps []Node) func(xs ...Node) Node
+		batched bool
+	}{
+		{
+			name: "vector output",
+			f: func(ps []Node) func(xs ...Node) Node {
+				return func(xs ...Node) Node {
+					h := Tanh(Affine(ps[0], ps[1], xs[0]))
+					h = Add(Mul(ps[2], h), Prod(xs[1], ps[3]))
+					return Sigmoid(AddScalar(h, Scalar(T(0.5))))
+				}
+			},
+			batched: true,
+		},
+		{
+			name: "scalar output",
+			f: func(ps []Node) func(xs ...Node) Node {
+				return func(xs ...Node) Node {
+					h := Exp(Mul(ps[1], xs[0]))
+					return Add(Dot(h, xs[1]), ReduceMean(Square(h)))
+				}
+			},
+			batched: true,
+		},
+		{
+			name: "fallback",
+			f: func(ps []Node) func(xs ...Node) Node {
+				return func(xs ...Node) Node {
+					return Softmax(Affine(xs[1], ps[1], xs[0]))
+				}
+			},
+			batched: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expectedInputs := newInputs()
+			f := tc.f(expectedInputs)
+			batches := expectedInputs[4:]
+			ys := make([]Node, batches[0].Value().Rows())
+			for i := range ys {
+				ys[i] = f(
+					Reshape(RowView(batches[0], i), batches[0].Value().Columns(), 1),
+					Reshape(RowView(batches[1], i), batches[1].Value().Columns(), 1),
+				)
+			}
+			expected := Stack(ys...)
+			Backward(ReduceSum(Square(Flatten(expected))))
+
+			actualInputs := newInputs()
+			actual := VMap(tc.f(actualInputs), actualInputs[4:]...)
+			_, isStack := actual.(*Operator).function.(*fn.Stack[Node])
+			assert.Equal(t, tc.batched, !isStack)
+
+			require.Equal(t, expected.Value().Rows(), actual.Value().Rows())
+			require.Equal(t, expected.Value().Columns(), actual.Value().Columns())
+			assert.InDeltaSlice(t, expected.Value().Data().F64(), actual.Value().Data().F64(), tol)
+
+			Backward(ReduceSum(Square(Flatten(actual))))
+			for i, p := range actualInputs {
+				if expectedInputs[i].Grad() == nil {
+					assert.Nil(t, p.Grad(), "input %d", i)
+					continue
+				}
+				require.NotNil(t, p.Grad(), "input %d", i)
+				assert.InDeltaSlice(t, expectedInputs[i].Grad().Data().F64(), p.Grad().Data().F64(), tol, "input %d", i)
+			}
+		})
+	}
+}
+
+func TestVMap_Panics(t *testing.T) {
+	f := func(xs ...Node) Node { return xs[0] }
+	assert.Panics(t, func() { VMap(f) })
+	assert.Panics(t, func() {
+		VMap(f, Var(mat.NewEmptyDense[float64](2, 3)), Var(mat.NewEmptyDense[float64](3, 3)))
+	})
+	assert.Panics(t, func() { VMap(f, Var(mat.NewEmptyDense[float64](0, 3))) })
+}
@@ -23,6 +23,8 @@ type Wrapper struct {
 	// It's primarily useful for later associating a correct time-step
 	// to this wrapper node, if needed for truncated backpropagation.
 	createdAt uint64
+	// noGrad reports whether the wrapper marks the inference mode (see NoGrad).
+	noGrad bool
 }
 
 // StopGrad creates a new Wrapper Node that stops the accumulated gradients from
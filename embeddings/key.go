@@ -29,6 +29,20 @@ func encodeKey[K Key](k K) []byte {
 	}
 }
 
+func decodeKey[K Key](b []byte) K {
+	var k K
+	switch any(k).(type) {
+	case []byte:
+		return any(append([]byte(nil), b...)).(K)
+	case string:
+		return any(string(b)).(K)
+	case int:
+		return any(int(binary.LittleEndian.Uint64(b))).(K)
+	default:
+		panic(fmt.Errorf("embeddings: unexpected key type %T", k))
+	}
+}
+
 func stringifyKey[K Key](k K) string {
 	switch kt := any(k).(type) {
 	case string:
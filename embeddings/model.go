@@ -34,7 +34,10 @@ type Config struct {
 	Trainable bool
 }
 
-var _ nn.ParamsTraverser = &Model[string]{}
+var (
+	_ nn.ParamsTraverser = &Model[string]{}
+	_ nn.ParamsQuantizer = &Model[string]{}
+)
 
 // A Model for handling embeddings.
 type Model[K Key] struct {
@@ -111,6 +114,31 @@ func paramNameType(p nn.Param) (nn.Param, string, nn.ParamsType) {
 	return p, p.Name(), p.Type()
 }
 
+// QuantizeParams converts all the embeddings in the store, and the
+// ZeroEmbedding, to quantized form (see nn.Quantize).
+// It returns the number of converted embeddings.
+// It panics in case of errors reading from or writing to the store.
+func (m *Model[K]) QuantizeParams() int {
+	n := 0
+	if m.ZeroEmbedding != nil && nn.QuantizeParam(m.ZeroEmbedding) {
+		n++
+	}
+	keys, err := m.Store.Keys()
+	if err != nil {
+		panic(fmt.Errorf("embeddings: error reading keys from store: %w", err))
+	}
+	for _, key := range keys {
+		e := &Embedding[K]{
+			model: m,
+			key:   decodeKey[K](key),
+		}
+		if nn.QuantizeParam(e) {
+			n++
+		}
+	}
+	return n
+}
+
 // Count counts how many embedding key/value pairs are currently stored.
 // It panics in case of reading errors.
 func (m *Model[_]) Count() int {
@@ -239,6 +267,7 @@ func (m *Model[K]) accGrad(e *Embedding[K], gx mat.Matrix) {
 	if !m.Trainable {
 		return
 	}
+	ag.CheckGrad(gx)
 	key := stringifyKey(e.key)
 
 	m.mu.Lock()
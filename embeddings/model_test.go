@@ -9,9 +9,11 @@ import (
 	"encoding/gob"
 	"errors"
 	"fmt"
+	"math"
 	"github.com/nlpodyssey/spago/mat/mattest"
 	"testing"
 
+	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/embeddings"
 	"github.com/nlpodyssey/spago/embeddings/store"
 	"github.com/nlpodyssey/spago/embeddings/store/memstore"
@@ -255,6 +257,27 @@ func TestModel_ClearEmbeddingsWithGrad(t *testing.T) {
 	assert.Nil(t, e.Grad())
 }
 
+func TestModel_AccGrad_AnomalyDetection(t *testing.T) {
+	type T = float32
+	defer ag.SetAnomalyDetection(ag.SetAnomalyDetection(true))
+
+	repo := memstore.NewRepository()
+	conf := embeddings.Config{
+		Size:      3,
+		StoreName: "test-store",
+		Trainable: true,
+	}
+	m := embeddings.New[T, string](conf, repo)
+
+	e, _ := m.Embedding("e")
+	inf := T(math.Inf(1))
+	assert.Panics(t, func() { e.AccGrad(mat.NewVecDense([]T{1, inf, 3})) })
+	assert.Nil(t, e.Grad())
+
+	e.AccGrad(mat.NewVecDense([]T{1, 2, 3}))
+	mattest.AssertMatrixEquals(t, mat.NewVecDense([]T{1, 2, 3}), e.Grad())
+}
+
 func TestModel_UseRepository(t *testing.T) {
 	type T = float32
 
@@ -483,6 +506,35 @@ func TestModel_TraverseParams(t *testing.T) {
 	})
 }
 
+func TestModel_QuantizeParams(t *testing.T) {
+	type T = float32
+
+	repo := memstore.NewRepository()
+	conf := embeddings.Config{
+		Size:             3,
+		UseZeroEmbedding: true,
+		StoreName:        "test-store",
+		Trainable:        true,
+	}
+	m := embeddings.New[T, int](conf, repo)
+
+	for i, v := range [][]T{{1, 2, 3}, {-1, 0, 0.5}} {
+		e, _ := m.Embedding(i)
+		e.ReplaceValue(mat.NewVecDense(v))
+	}
+
+	report := nn.Quantize(m, nil)
+	assert.Equal(t, 3, report.Params)
+
+	e, ok := m.Embedding(1)
+	require.True(t, ok)
+	require.IsType(t, &mat.Quantized[T]{}, e.Value())
+	assert.InDeltaSlice(t, []T{-1, 0, 0.5}, mat.Data[T](e.Value()), 1.0e-2)
+	assert.IsType(t, &mat.Quantized[T]{}, m.ZeroEmbedding.Value())
+
+	assert.Equal(t, 0, m.QuantizeParams())
+}
+
 type repoStub struct {
 	fnStore   func(name string) (store.Store, error)
 	fnDropAll func() error
@@ -7,6 +7,7 @@ package gd
 import (
 	"runtime"
 
+	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/gd/clipper"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/nn"
@@ -68,14 +69,20 @@ func (o *Optimizer) collectParams() []nn.Param {
 }
 
 // updateParams applies the optimization method to all the observed parameters.
+// In deterministic mode (see ag.SetDeterministic), the parameters are updated
+// one at a time, in the order of collectParams.
 func (o *Optimizer) updateParams(params []nn.Param) {
+	if ag.IsDeterministic() {
+		for _, p := range params {
+			o.updateParam(p)
+		}
+		return
+	}
 	ch := make(chan struct{}, runtime.NumCPU())
 	for _, param := range params {
 		ch <- struct{}{}
 		go func(p nn.Param) {
-			delta := o.method.Delta(p)
-			p.ApplyDelta(delta)
-			p.ZeroGrad()
+			o.updateParam(p)
 			<-ch
 		}(param)
 	}
@@ -85,6 +92,12 @@ func (o *Optimizer) updateParams(params []nn.Param) {
 	close(ch)
 }
 
+func (o *Optimizer) updateParam(p nn.Param) {
+	delta := o.method.Delta(p)
+	p.ApplyDelta(delta)
+	p.ZeroGrad()
+}
+
 // clipGrad applies the gradient clipping to all the observed parameters.
 func (o *Optimizer) clipGradsInPlace(params []nn.Param) {
 	if o.gradClipper == nil {
@@ -0,0 +1,120 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gd_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/gd"
+	"github.com/nlpodyssey/spago/gd/adam"
+	"github.com/nlpodyssey/spago/initializers"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/mattest"
+	"github.com/nlpodyssey/spago/nn"
+	"github.com/nlpodyssey/spago/nn/linear"
+	"github.com/stretchr/testify/require"
+)
+
+type testModel struct {
+	nn.Module
+	Hidden *linear.Model
+	Output *linear.Model
+}
+
+func newTestModel() *testModel {
+	m := &testModel{
+		Hidden: linear.New[float64](4, 8),
+		Output: linear.New[float64](8, 2),
+	}
+	rnd := ag.ManualSeed(42)
+	nn.ForEachParam(m, func(param nn.Param, _ string, _ nn.ParamsType) {
+		initializers.XavierUniform(param.Value(), 1, rnd)
+	})
+	return m
+}
+
+// forward runs a few steps of a recurrence sharing the hidden weights, so
+// that their gradients are accumulated from many operators.
+func (m *testModel) forward(x ag.Node) ag.Node {
+	h := x
+	for i := 0; i < 3; i++ {
+		h = ag.Dropout(ag.Tanh(m.Hidden.Forward(ag.Add(x, ag.Slice(h, 0, 0, 4, 1)))[0]), 0.1)
+	}
+	return m.Output.Forward(h)[0]
+}
+
+func trainTestModel(steps int) *testModel {
+	m := newTestModel()
+	optimizer := gd.NewOptimizer(m, adam.New[float64](adam.NewDefaultConfig()))
+	for step := 0; step < steps; step++ {
+		trainStep(m, optimizer, step)
+	}
+	return m
+}
+
+// trainStep trains the model on a batch of examples, releasing all the
+// matrices involved.
+func trainStep(m *testModel, optimizer *gd.Optimizer, step int) {
+	var loss ag.Node
+	var inputs []ag.Node
+	for i := 0; i < 8; i++ {
+		v := float64(step*8 + i)
+		x := ag.Var(mat.NewVecDense([]float64{math.Sin(v), math.Cos(v), v / 100, -v / 50}))
+		target := ag.Var(mat.NewVecDense([]float64{math.Sin(2 * v), math.Cos(3 * v)}))
+		inputs = append(inputs, x, target)
+		l := ag.ReduceSum(ag.Square(ag.Sub(m.forward(x), target)))
+		if loss == nil {
+			loss = l
+			continue
+		}
+		loss = ag.Add(loss, l)
+	}
+	releaseGraph := ag.Backward(loss)
+	optimizer.IncBatch()
+	optimizer.Do()
+	releaseGraph()
+	for _, x := range inputs {
+		mat.ReleaseMatrix(x.Value())
+	}
+}
+
+func TestOptimizer_Deterministic(t *testing.T) {
+	defer ag.SetDeterministic(ag.SetDeterministic(true))
+
+	m1 := trainTestModel(10)
+	m2 := trainTestModel(10)
+
+	var params1, params2 []nn.Param
+	nn.ForEachParam(m1, func(param nn.Param, _ string, _ nn.ParamsType) {
+		params1 = append(params1, param)
+	})
+	nn.ForEachParam(m2, func(param nn.Param, _ string, _ nn.ParamsType) {
+		params2 = append(params2, param)
+	})
+	require.Len(t, params2, len(params1))
+
+	for i, p1 := range params1 {
+		data1 := p1.Value().Data().F64()
+		data2 := params2[i].Value().Data().F64()
+		require.Len(t, data2, len(data1))
+		for j, v := range data1 {
+			if math.Float64bits(v) != math.Float64bits(data2[j]) {
+				t.Fatalf("param %d, element %d: %v != %v", i, j, v, data2[j])
+			}
+		}
+	}
+}
+
+func TestOptimizer_NoLeaks(t *testing.T) {
+	m := newTestModel()
+	optimizer := gd.NewOptimizer(m, adam.New[float64](adam.NewDefaultConfig()))
+	trainStep(m, optimizer, 0) // allocates the support structures of Adam
+
+	mattest.AssertNoLeaks(t, func() {
+		trainStep(m, optimizer, 1)
+	})
+}
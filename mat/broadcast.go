@@ -0,0 +1,174 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// BroadcastShapes returns the shape resulting from broadcasting the given
+// shapes together, following the NumPy rules: shapes are aligned to the
+// right, and each pair of dimensions must either be equal, or one of them
+// must be 1. It panics if the shapes are not compatible.
+func BroadcastShapes(shapes ...[]int) []int {
+	rank := 0
+	for _, s := range shapes {
+		if len(s) > rank {
+			rank = len(s)
+		}
+	}
+	out := make([]int, rank)
+	for i := range out {
+		out[i] = 1
+	}
+	for _, s := range shapes {
+		lead := rank - len(s)
+		for i, v := range s {
+			switch o := out[lead+i]; {
+			case o == v || v == 1:
+				continue
+			case o == 1:
+				out[lead+i] = v
+			default:
+				panic("mat: matrices have incompatible dimensions")
+			}
+		}
+	}
+	return out
+}
+
+// BroadcastLike returns a new matrix with the values of m broadcast to the
+// shape of like, following the same rules of BroadcastShapes. It is meant
+// to be used explicitly before the in-place element-wise operations, which
+// require operands of the same dimensions:
+//
+//	x.AddInPlace(mat.BroadcastLike(b, x))
+//
+// If like is a Tensor the result is a Tensor, otherwise it is a Dense matrix.
+// It panics if m cannot be broadcast to the shape of like.
+func BroadcastLike(m, like Matrix) Matrix {
+	if t, ok := like.(Tensor); ok {
+		return AsTensor(m).BroadcastTo(t.Shape()...).Clone()
+	}
+	rows, cols := like.Dims()
+	return m.NewMatrix(rows, cols, AsTensor(m).BroadcastTo(rows, cols).Data())
+}
+
+// SumToShape reduces m to the given shape, summing its values over the
+// dimensions that would be expanded broadcasting a matrix of the given
+// shape to the shape of m. It is the reverse operation of broadcasting,
+// typically used to compute the gradients of broadcast operands.
+//
+// If m is not a Tensor and shape has two dimensions, the result is a Dense
+// matrix; otherwise it is a Tensor. A new matrix is always returned.
+func SumToShape(m Matrix, shape ...int) Matrix {
+	switch t := AsTensor(m).(type) {
+	case *DenseTensor[float32]:
+		return sumToShape(m, t, shape)
+	case *DenseTensor[float64]:
+		return sumToShape(m, t, shape)
+	default:
+		panic(fmt.Sprintf("mat: unexpected tensor type %T", t))
+	}
+}
+
+func sumToShape[T float.DType](m Matrix, t *DenseTensor[T], shape []int) Matrix {
+	lead := len(t.shape) - len(shape)
+	if lead < 0 {
+		panic("mat: cannot reduce to a shape of higher rank")
+	}
+	_, isTensor := m.(Tensor)
+
+	r := t
+	reduced := false
+	reduce := func(axis int) {
+		next := r.SumAxis(axis).(*DenseTensor[T])
+		if reduced {
+			r.release()
+		}
+		r, reduced = next, true
+	}
+
+	for i := 0; i < lead; i++ {
+		reduce(0)
+	}
+	for i := len(shape) - 1; i >= 0; i-- {
+		switch v := r.shape[i]; {
+		case v == shape[i]:
+			continue
+		case shape[i] == 1:
+			reduce(i)
+		default:
+			panic("mat: matrices have incompatible dimensions")
+		}
+	}
+
+	if !reduced {
+		if !isTensor && len(shape) == 2 {
+			return m.Clone()
+		}
+		return t.Clone()
+	}
+
+	r.shape = append([]int(nil), shape...)
+	r.strides = contiguousStrides(r.shape)
+	if !isTensor && len(shape) == 2 {
+		if r.owner != nil {
+			r.owner.rows, r.owner.cols = shape[0], shape[1]
+			return r.owner
+		}
+		return NewDense(shape[0], shape[1], r.data)
+	}
+	return r
+}
+
+// broadcastDims returns the dimensions resulting from broadcasting two
+// matrices, viewed as two-dimensional arrays. It panics if the dimensions
+// are not compatible.
+func broadcastDims(a, b Matrix) (rows, cols int) {
+	s := BroadcastShapes(
+		[]int{a.Rows(), a.Columns()},
+		[]int{b.Rows(), b.Columns()},
+	)
+	return s[0], s[1]
+}
+
+// broadcastOp applies the element-wise function op to the values of a and
+// b, broadcast together, returning a new matrix.
+func broadcastOp[T float.DType](a *Dense[T], b Matrix, op func(x, y T) T) *Dense[T] {
+	rows, cols := broadcastDims(a, b)
+	out := densePool[T]().Get(rows, cols)
+	aRowStride, aColStride := broadcastStrides(a.rows, a.cols)
+	bRowStride, bColStride := broadcastStrides(b.Rows(), b.Columns())
+	bData := Data[T](b)
+	k := 0
+	for i := 0; i < rows; i++ {
+		ai, bi := i*aRowStride, i*bRowStride
+		for j := 0; j < cols; j++ {
+			out.data[k] = op(a.data[ai+j*aColStride], bData[bi+j*bColStride])
+			k++
+		}
+	}
+	return out
+}
+
+// broadcastStrides returns the row-major strides of a matrix with the given
+// dimensions, with a zero stride for each dimension of size 1.
+func broadcastStrides(rows, cols int) (rowStride, colStride int) {
+	if rows > 1 {
+		rowStride = cols
+	}
+	if cols > 1 {
+		colStride = 1
+	}
+	return
+}
+
+func addOp[T float.DType](x, y T) T  { return x + y }
+func subOp[T float.DType](x, y T) T  { return x - y }
+func prodOp[T float.DType](x, y T) T { return x * y }
+func divOp[T float.DType](x, y T) T  { return x / y }
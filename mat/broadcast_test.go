@@ -0,0 +1,237 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroadcastShapes(t *testing.T) {
+	testCases := []struct {
+		shapes [][]int
+		want   []int
+	}{
+		{[][]int{{2, 3}, {2, 3}}, []int{2, 3}},
+		{[][]int{{2, 3}, {1, 3}}, []int{2, 3}},
+		{[][]int{{2, 1}, {1, 3}}, []int{2, 3}},
+		{[][]int{{4, 2, 3}, {3}}, []int{4, 2, 3}},
+		{[][]int{{4, 1, 3}, {2, 1}}, []int{4, 2, 3}},
+		{[][]int{{}, {2, 3}}, []int{2, 3}},
+		{[][]int{{1, 1}, {5, 1}, {1, 7}}, []int{5, 7}},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, BroadcastShapes(tc.shapes...))
+	}
+
+	require.Panics(t, func() {
+		BroadcastShapes([]int{2, 3}, []int{2, 4})
+	})
+	require.Panics(t, func() {
+		BroadcastShapes([]int{4, 2, 3}, []int{3, 1})
+	})
+}
+
+func TestDense_Broadcasting(t *testing.T) {
+	t.Run("float32", testDenseBroadcasting[float32])
+	t.Run("float64", testDenseBroadcasting[float64])
+}
+
+func testDenseBroadcasting[T float.DType](t *testing.T) {
+	a := NewDense[T](2, 3, []T{
+		1, 2, 3,
+		4, 5, 6,
+	})
+
+	t.Run("row vector", func(t *testing.T) {
+		b := NewDense[T](1, 3, []T{10, 20, 30})
+		y := a.Add(b)
+		assertDenseDims(t, 2, 3, y.(*Dense[T]))
+		assert.Equal(t, []T{11, 22, 33, 14, 25, 36}, Data[T](y))
+
+		y = b.Sub(a)
+		assertDenseDims(t, 2, 3, y.(*Dense[T]))
+		assert.Equal(t, []T{9, 18, 27, 6, 15, 24}, Data[T](y))
+	})
+
+	t.Run("column vector", func(t *testing.T) {
+		b := NewDense[T](2, 1, []T{2, 4})
+		y := a.Prod(b)
+		assertDenseDims(t, 2, 3, y.(*Dense[T]))
+		assert.Equal(t, []T{2, 4, 6, 16, 20, 24}, Data[T](y))
+
+		y = a.Div(b)
+		assertDenseDims(t, 2, 3, y.(*Dense[T]))
+		assert.Equal(t, []T{0.5, 1, 1.5, 1, 1.25, 1.5}, Data[T](y))
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		b := NewScalar[T](10)
+		y := b.Sub(a)
+		assertDenseDims(t, 2, 3, y.(*Dense[T]))
+		assert.Equal(t, []T{9, 8, 7, 6, 5, 4}, Data[T](y))
+	})
+
+	t.Run("outer", func(t *testing.T) {
+		c := NewDense[T](2, 1, []T{1, 2})
+		r := NewDense[T](1, 3, []T{10, 20, 30})
+		y := c.Add(r)
+		assertDenseDims(t, 2, 3, y.(*Dense[T]))
+		assert.Equal(t, []T{11, 21, 31, 12, 22, 32}, Data[T](y))
+	})
+
+	t.Run("in place", func(t *testing.T) {
+		x := a.Clone()
+		x.AddInPlace(BroadcastLike(NewDense[T](1, 3, []T{1, 1, 1}), x))
+		x.SubInPlace(BroadcastLike(NewDense[T](2, 1, []T{1, 2}), x))
+		x.ProdInPlace(BroadcastLike(NewScalar[T](2), x))
+		x.DivInPlace(BroadcastLike(NewDense[T](1, 3, []T{1, 2, 4}), x))
+		assert.Equal(t, []T{2, 2, 1.5, 6, 4, 2.5}, Data[T](x))
+	})
+
+	t.Run("in place requires the same dimensions", func(t *testing.T) {
+		x := a.Clone()
+		b := NewDense[T](1, 3, []T{1, 1, 1})
+		require.Panics(t, func() { x.AddInPlace(b) })
+		require.Panics(t, func() { x.SubInPlace(b) })
+		require.Panics(t, func() { x.ProdInPlace(b) })
+		require.Panics(t, func() { x.DivInPlace(b) })
+		assert.Equal(t, Data[T](a), Data[T](x))
+	})
+
+	t.Run("broadcast like expanding the receiver", func(t *testing.T) {
+		r := NewDense[T](1, 3, []T{1, 2, 3})
+		require.Panics(t, func() {
+			BroadcastLike(a, r)
+		})
+	})
+}
+
+func TestDenseTensor_Broadcasting(t *testing.T) {
+	t.Run("float32", testDenseTensorBroadcasting[float32])
+	t.Run("float64", testDenseTensorBroadcasting[float64])
+}
+
+func testDenseTensorBroadcasting[T float.DType](t *testing.T) {
+	a := NewDenseTensor[T]([]int{2, 2, 3}, []T{
+		1, 2, 3,
+		4, 5, 6,
+
+		7, 8, 9,
+		10, 11, 12,
+	})
+
+	t.Run("trailing vector", func(t *testing.T) {
+		b := NewDenseTensor[T]([]int{3}, []T{10, 20, 30})
+		y := a.Add(b).(Tensor)
+		assert.Equal(t, []int{2, 2, 3}, y.Shape())
+		assert.Equal(t, []T{
+			11, 22, 33, 14, 25, 36,
+			17, 28, 39, 20, 31, 42,
+		}, Data[T](y))
+	})
+
+	t.Run("dense matrix", func(t *testing.T) {
+		b := NewDense[T](2, 1, []T{1, 2})
+		y := a.Prod(b).(Tensor)
+		assert.Equal(t, []int{2, 2, 3}, y.Shape())
+		assert.Equal(t, []T{
+			1, 2, 3, 8, 10, 12,
+			7, 8, 9, 20, 22, 24,
+		}, Data[T](y))
+	})
+
+	t.Run("expanding both operands", func(t *testing.T) {
+		b := NewDenseTensor[T]([]int{2, 1, 1}, []T{1, 2})
+		c := NewDenseTensor[T]([]int{3}, []T{1, 2, 3})
+		y := b.Sub(c).(Tensor)
+		assert.Equal(t, []int{2, 1, 3}, y.Shape())
+		assert.Equal(t, []T{0, -1, -2, 1, 0, -1}, Data[T](y))
+	})
+
+	t.Run("in place", func(t *testing.T) {
+		x := a.Clone().(Tensor)
+		x.DivInPlace(BroadcastLike(NewDenseTensor[T]([]int{2, 1, 1}, []T{1, 2}), x))
+		assert.Equal(t, []int{2, 2, 3}, x.Shape())
+		assert.Equal(t, []T{
+			1, 2, 3, 4, 5, 6,
+			3.5, 4, 4.5, 5, 5.5, 6,
+		}, Data[T](x))
+
+		require.Panics(t, func() {
+			x.AddInPlace(NewDenseTensor[T]([]int{2, 1, 1}, []T{1, 2}))
+		})
+		require.Panics(t, func() {
+			x.AddInPlace(NewDenseTensor[T]([]int{3, 2, 2}, make([]T, 12)))
+		})
+		require.Panics(t, func() {
+			BroadcastLike(a, NewDenseTensor[T]([]int{3}, []T{1, 2, 3}))
+		})
+	})
+}
+
+func TestSumToShape(t *testing.T) {
+	t.Run("float32", testSumToShape[float32])
+	t.Run("float64", testSumToShape[float64])
+}
+
+func testSumToShape[T float.DType](t *testing.T) {
+	t.Run("dense", func(t *testing.T) {
+		m := NewDense[T](2, 3, []T{
+			1, 2, 3,
+			4, 5, 6,
+		})
+
+		y := SumToShape(m, 1, 3)
+		assertDenseDims(t, 1, 3, y.(*Dense[T]))
+		assert.Equal(t, []T{5, 7, 9}, Data[T](y))
+
+		y = SumToShape(m, 2, 1)
+		assertDenseDims(t, 2, 1, y.(*Dense[T]))
+		assert.Equal(t, []T{6, 15}, Data[T](y))
+
+		y = SumToShape(m, 1, 1)
+		assertDenseDims(t, 1, 1, y.(*Dense[T]))
+		assert.Equal(t, []T{21}, Data[T](y))
+
+		y = SumToShape(m, 2, 3)
+		assert.NotSame(t, m, y)
+		assert.Equal(t, Data[T](m), Data[T](y))
+	})
+
+	t.Run("tensor", func(t *testing.T) {
+		m := NewDenseTensor[T]([]int{2, 2, 3}, []T{
+			1, 2, 3,
+			4, 5, 6,
+
+			7, 8, 9,
+			10, 11, 12,
+		})
+
+		y := SumToShape(m, 3).(Tensor)
+		assert.Equal(t, []int{3}, y.Shape())
+		assert.Equal(t, []T{22, 26, 30}, Data[T](y))
+
+		y = SumToShape(m, 2, 1).(Tensor)
+		assert.Equal(t, []int{2, 1}, y.Shape())
+		assert.Equal(t, []T{30, 48}, Data[T](y))
+
+		y = SumToShape(m, 2, 1, 3).(Tensor)
+		assert.Equal(t, []int{2, 1, 3}, y.Shape())
+		assert.Equal(t, []T{5, 7, 9, 17, 19, 21}, Data[T](y))
+	})
+
+	t.Run("incompatible shape", func(t *testing.T) {
+		require.Panics(t, func() {
+			SumToShape(NewEmptyDense[T](2, 3), 2)
+		})
+		require.Panics(t, func() {
+			SumToShape(NewEmptyDense[T](2, 3), 1, 2, 3)
+		})
+	})
+}
@@ -11,8 +11,8 @@ import (
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/nlpodyssey/spago/mat/internal/f32"
 	"github.com/nlpodyssey/spago/mat/internal/f32/asm32"
-	"github.com/nlpodyssey/spago/mat/internal/f64"
 	"github.com/nlpodyssey/spago/mat/internal/f64/asm64"
+	"github.com/nlpodyssey/spago/mat/internal/gemm"
 	"github.com/nlpodyssey/spago/mat/internal/matfuncs"
 )
 
@@ -26,21 +26,25 @@ type Dense[T float.DType] struct {
 
 // Rows returns the number of rows of the matrix.
 func (d *Dense[_]) Rows() int {
+	d.checkReleased()
 	return d.rows
 }
 
 // Columns returns the number of columns of the matrix.
 func (d *Dense[_]) Columns() int {
+	d.checkReleased()
 	return d.cols
 }
 
 // Dims returns the number of rows and columns of the matrix.
 func (d *Dense[_]) Dims() (r, c int) {
+	d.checkReleased()
 	return d.rows, d.cols
 }
 
 // The Size of the matrix (rows*columns).
 func (d *Dense[_]) Size() int {
+	d.checkReleased()
 	return len(d.data)
 }
 
@@ -50,12 +54,14 @@ func (d *Dense[_]) Size() int {
 // The data slice IS NOT a copy: any changes applied to the returned slice are
 // reflected in the Dense matrix too.
 func (d *Dense[T]) Data() float.Slice {
+	d.checkReleased()
 	return float.SliceInterface(d.data)
 }
 
 // SetData sets the content of the matrix, copying the given raw
 // data representation as one-dimensional slice.
 func (d *Dense[T]) SetData(data float.Slice) {
+	d.checkReleased()
 	v := float.SliceValueOf[T](data)
 	if len(v) != len(d.data) {
 		panic(fmt.Sprintf("mat: incompatible data size, expected %d, actual %d", len(d.data), len(v)))
@@ -66,12 +72,14 @@ func (d *Dense[T]) SetData(data float.Slice) {
 // ZerosLike returns a new matrix with the same dimensions of the
 // receiver, initialized with zeroes.
 func (d *Dense[T]) ZerosLike() Matrix {
+	d.checkReleased()
 	return NewEmptyDense[T](d.rows, d.cols)
 }
 
 // OnesLike returns a new matrix with the same dimensions of the
 // receiver, initialized with ones.
 func (d *Dense[T]) OnesLike() Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	data := out.data // avoid bounds check in loop
 	for i := range data {
@@ -83,6 +91,7 @@ func (d *Dense[T]) OnesLike() Matrix {
 // Scalar returns the scalar value.
 // It panics if the matrix does not contain exactly one element.
 func (d *Dense[T]) Scalar() float.Float {
+	d.checkReleased()
 	if !IsScalar(d) {
 		panic("mat: expected scalar but the matrix contains more elements")
 	}
@@ -91,6 +100,7 @@ func (d *Dense[T]) Scalar() float.Float {
 
 // Zeros sets all the values of the matrix to zero.
 func (d *Dense[T]) Zeros() {
+	d.checkReleased()
 	data := d.data // avoid bounds check in loop
 	for i := range data {
 		data[i] = T(0)
@@ -100,28 +110,33 @@ func (d *Dense[T]) Zeros() {
 // Set sets the scalar value from a 1×1 matrix at row r and column c.
 // It panics if the given matrix is not 1×1, or if indices are out of range.
 func (d *Dense[T]) Set(r int, c int, m Matrix) {
+	d.checkReleased()
 	d.set(r, c, float.ValueOf[T](m.Scalar()))
 }
 
 // At returns the value at row r and column c as a 1×1 matrix.
 // It panics if the given indices are out of range.
 func (d *Dense[T]) At(r int, c int) Matrix {
+	d.checkReleased()
 	return NewScalar[T](d.at(r, c))
 }
 
 // SetScalar sets the value v at row r and column c.
 // It panics if the given indices are out of range.
 func (d *Dense[T]) SetScalar(r int, c int, v float.Float) {
+	d.checkReleased()
 	d.set(r, c, float.ValueOf[T](v))
 }
 
 // ScalarAt returns the value at row r and column c.
 // It panics if the given indices are out of range.
 func (d *Dense[T]) ScalarAt(r int, c int) float.Float {
+	d.checkReleased()
 	return float.Interface(d.at(r, c))
 }
 
 func (d *Dense[T]) set(r int, c int, v T) {
+	d.checkReleased()
 	if r < 0 || r >= d.rows {
 		panic("mat: 'r' argument out of range")
 	}
@@ -132,6 +147,7 @@ func (d *Dense[T]) set(r int, c int, v T) {
 }
 
 func (d *Dense[T]) at(r int, c int) T {
+	d.checkReleased()
 	if r < 0 || r >= d.rows {
 		panic("mat: 'r' argument out of range")
 	}
@@ -145,28 +161,33 @@ func (d *Dense[T]) at(r int, c int) T {
 // vector. It panics if the receiver is not a vector, or the given matrix is
 // not 1×1, or the position is out of range.
 func (d *Dense[T]) SetVec(i int, m Matrix) {
+	d.checkReleased()
 	d.setVec(i, float.ValueOf[T](m.Scalar()))
 }
 
 // AtVec returns the value at position i of a vector as a 1×1 matrix.
 // It panics if the receiver is not a vector or the position is out of range.
 func (d *Dense[T]) AtVec(i int) Matrix {
+	d.checkReleased()
 	return NewScalar[T](d.atVec(i))
 }
 
 // SetVecScalar sets the value v at position i of a vector.
 // It panics if the receiver is not a vector or the position is out of range.
 func (d *Dense[T]) SetVecScalar(i int, v float.Float) {
+	d.checkReleased()
 	d.setVec(i, float.ValueOf[T](v))
 }
 
 // ScalarAtVec returns the value at position i of a vector.
 // It panics if the receiver is not a vector or the position is out of range.
 func (d *Dense[T]) ScalarAtVec(i int) float.Float {
+	d.checkReleased()
 	return float.Interface(d.atVec(i))
 }
 
 func (d *Dense[T]) setVec(i int, v T) {
+	d.checkReleased()
 	if !(IsVector(d)) {
 		panic("mat: expected vector")
 	}
@@ -177,6 +198,7 @@ func (d *Dense[T]) setVec(i int, v T) {
 }
 
 func (d *Dense[T]) atVec(i int) T {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -189,6 +211,7 @@ func (d *Dense[T]) atVec(i int) T {
 // ExtractRow returns a copy of the i-th row of the matrix,
 // as a row vector (1×cols).
 func (d *Dense[T]) ExtractRow(i int) Matrix {
+	d.checkReleased()
 	if i < 0 || i >= d.rows {
 		panic("mat: index out of range")
 	}
@@ -201,6 +224,7 @@ func (d *Dense[T]) ExtractRow(i int) Matrix {
 // ExtractColumn returns a copy of the i-th column of the matrix,
 // as a column vector (rows×1).
 func (d *Dense[T]) ExtractColumn(i int) Matrix {
+	d.checkReleased()
 	if i < 0 || i >= d.cols {
 		panic("mat: index out of range")
 	}
@@ -215,6 +239,7 @@ func (d *Dense[T]) ExtractColumn(i int) Matrix {
 
 // View returns a new Matrix sharing the same underlying data.
 func (d *Dense[T]) View(rows, cols int) Matrix {
+	d.checkReleased()
 	if rows < 0 || cols < 0 {
 		panic("mat: negative values for rows and cols are not allowed")
 	}
@@ -233,6 +258,7 @@ func (d *Dense[T]) View(rows, cols int) Matrix {
 // given positions. The parameters "fromRow" and "fromCol" are inclusive,
 // while "toRow" and "toCol" are exclusive.
 func (d *Dense[T]) Slice(fromRow, fromCol, toRow, toCol int) Matrix {
+	d.checkReleased()
 	dRows := d.rows
 	dCols := d.cols
 	if fromRow < 0 || fromRow >= dRows || fromCol < 0 || fromCol >= dCols ||
@@ -261,6 +287,7 @@ func (d *Dense[T]) Slice(fromRow, fromCol, toRow, toCol int) Matrix {
 // Reshape returns a copy of the matrix.
 // It panics if the dimensions are incompatible.
 func (d *Dense[T]) Reshape(rows, cols int) Matrix {
+	d.checkReleased()
 	if rows < 0 || cols < 0 {
 		panic("mat: negative values for rows and cols are not allowed")
 	}
@@ -274,6 +301,7 @@ func (d *Dense[T]) Reshape(rows, cols int) Matrix {
 // matrix itself.
 // It panics if the dimensions are incompatible.
 func (d *Dense[T]) ReshapeInPlace(rows, cols int) Matrix {
+	d.checkReleased()
 	if rows < 0 || cols < 0 {
 		panic("mat: negative values for rows and cols are not allowed")
 	}
@@ -288,6 +316,7 @@ func (d *Dense[T]) ReshapeInPlace(rows, cols int) Matrix {
 // Flatten creates a new row vector (1×size) corresponding to the
 // "flattened" row-major ordered representation of the initial matrix.
 func (d *Dense[T]) Flatten() Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(1, len(d.data))
 	copy(out.data, d.data)
 	return out
@@ -298,6 +327,7 @@ func (d *Dense[T]) Flatten() Matrix {
 // ordered representation of the initial value.
 // It returns the matrix itself.
 func (d *Dense[T]) FlattenInPlace() Matrix {
+	d.checkReleased()
 	d.rows = 1
 	d.cols = len(d.data)
 	return d
@@ -309,6 +339,7 @@ func (d *Dense[T]) FlattenInPlace() Matrix {
 // elements are removed. If it's bigger, the additional tail elements
 // are set to zero.
 func (d *Dense[T]) ResizeVector(newSize int) Matrix {
+	d.checkReleased()
 	if !(IsVector(d)) {
 		panic("mat: expected vector")
 	}
@@ -327,6 +358,7 @@ func (d *Dense[T]) ResizeVector(newSize int) Matrix {
 
 // T returns the transpose of the matrix.
 func (d *Dense[T]) T() Matrix {
+	d.checkReleased()
 	dRows := d.rows
 	dCols := d.cols
 
@@ -351,6 +383,7 @@ func (d *Dense[T]) T() Matrix {
 // TransposeInPlace transposes the matrix in place, and returns the
 // matrix itself.
 func (d *Dense[T]) TransposeInPlace() Matrix {
+	d.checkReleased()
 	d.rows, d.cols = d.cols, d.rows
 
 	// Vector, scalar, or empty data
@@ -411,8 +444,9 @@ mainLoop:
 
 // Add returns the addition between the receiver and another matrix.
 func (d *Dense[T]) Add(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return broadcastOp(d, other, addOp[T])
 	}
 	out := NewEmptyDense[T](d.rows, d.cols)
 	switch any(T(0)).(type) {
@@ -430,6 +464,7 @@ func (d *Dense[T]) Add(other Matrix) Matrix {
 
 // AddInPlace performs the in-place addition with the other matrix.
 func (d *Dense[T]) AddInPlace(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -448,6 +483,7 @@ func (d *Dense[T]) AddInPlace(other Matrix) Matrix {
 
 // AddScalar performs the addition between the matrix and the given value.
 func (d *Dense[T]) AddScalar(n float64) Matrix {
+	d.checkReleased()
 	out := NewDense(d.rows, d.cols, d.data)
 	switch any(T(0)).(type) {
 	case float32:
@@ -462,6 +498,7 @@ func (d *Dense[T]) AddScalar(n float64) Matrix {
 
 // AddScalarInPlace adds the scalar to all values of the matrix.
 func (d *Dense[T]) AddScalarInPlace(n float64) Matrix {
+	d.checkReleased()
 	switch any(T(0)).(type) {
 	case float32:
 		f32.AddConst(float32(n), any(d.data).([]float32))
@@ -475,8 +512,9 @@ func (d *Dense[T]) AddScalarInPlace(n float64) Matrix {
 
 // Sub returns the subtraction of the other matrix from the receiver.
 func (d *Dense[T]) Sub(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return broadcastOp(d, other, subOp[T])
 	}
 	out := NewEmptyDense[T](d.rows, d.cols)
 	switch any(T(0)).(type) {
@@ -494,6 +532,7 @@ func (d *Dense[T]) Sub(other Matrix) Matrix {
 
 // SubInPlace performs the in-place subtraction with the other matrix.
 func (d *Dense[T]) SubInPlace(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -512,6 +551,7 @@ func (d *Dense[T]) SubInPlace(other Matrix) Matrix {
 
 // SubScalar performs a subtraction between the matrix and the given value.
 func (d *Dense[T]) SubScalar(n float64) Matrix {
+	d.checkReleased()
 	out := NewDense(d.rows, d.cols, d.data)
 	switch any(T(0)).(type) {
 	case float32:
@@ -526,6 +566,7 @@ func (d *Dense[T]) SubScalar(n float64) Matrix {
 
 // SubScalarInPlace subtracts the scalar from the receiver's values.
 func (d *Dense[T]) SubScalarInPlace(n float64) Matrix {
+	d.checkReleased()
 	switch any(T(0)).(type) {
 	case float32:
 		f32.AddConst(-float32(n), any(d.data).([]float32))
@@ -539,8 +580,9 @@ func (d *Dense[T]) SubScalarInPlace(n float64) Matrix {
 
 // Prod performs the element-wise product between the receiver and the other matrix.
 func (d *Dense[T]) Prod(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return broadcastOp(d, other, prodOp[T])
 	}
 
 	out := densePool[T]().Get(d.rows, d.cols)
@@ -563,6 +605,7 @@ func (d *Dense[T]) Prod(other Matrix) Matrix {
 
 // ProdInPlace performs the in-place element-wise product with the other matrix.
 func (d *Dense[T]) ProdInPlace(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -580,6 +623,7 @@ func (d *Dense[T]) ProdInPlace(other Matrix) Matrix {
 
 // ProdScalar returns the multiplication between the matrix and the given value.
 func (d *Dense[T]) ProdScalar(n float64) Matrix {
+	d.checkReleased()
 	out := NewEmptyDense[T](d.rows, d.cols)
 	switch any(T(0)).(type) {
 	case float32:
@@ -595,6 +639,7 @@ func (d *Dense[T]) ProdScalar(n float64) Matrix {
 // ProdScalarInPlace performs the in-place multiplication between the
 // matrix and the given value.
 func (d *Dense[T]) ProdScalarInPlace(n float64) Matrix {
+	d.checkReleased()
 	switch any(T(0)).(type) {
 	case float32:
 		asm32.ScalUnitary(float32(n), any(d.data).([]float32))
@@ -609,6 +654,7 @@ func (d *Dense[T]) ProdScalarInPlace(n float64) Matrix {
 // ProdMatrixScalarInPlace multiplies the given matrix with the value,
 // storing the result in the receiver.
 func (d *Dense[T]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
+	d.checkReleased()
 	if !SameDims(d, m) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -627,8 +673,9 @@ func (d *Dense[T]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
 
 // Div returns the result of the element-wise division of the receiver by the other matrix.
 func (d *Dense[T]) Div(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
-		panic("mat: matrices have incompatible dimensions")
+		return broadcastOp(d, other, divOp[T])
 	}
 	out := NewEmptyDense[T](d.rows, d.cols)
 	switch any(T(0)).(type) {
@@ -646,6 +693,7 @@ func (d *Dense[T]) Div(other Matrix) Matrix {
 
 // DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
 func (d *Dense[T]) DivInPlace(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -664,10 +712,30 @@ func (d *Dense[T]) DivInPlace(other Matrix) Matrix {
 // Mul performs the multiplication row by column.
 // If A is an i×j Matrix, and B is j×k, then the resulting Matrix
 // C = AB will be i×k.
+//
+// If the other matrix is Sparse, only its non-zero values are visited.
+// If it is Quantized, its quantized values are used directly. If it is a
+// HalfDense and the receiver is a float32 matrix, its values are upcast
+// one row at a time.
+//
+// The product of two Dense matrices is computed with a cache-blocked
+// algorithm, splitting the work across multiple goroutines if the
+// matrices are large enough (see SetMulParallelism).
 func (d *Dense[T]) Mul(other Matrix) Matrix {
+	d.checkReleased()
 	if d.cols != other.Rows() {
 		panic("mat: matrices have incompatible dimensions")
 	}
+	switch o := other.(type) {
+	case *Sparse[T]:
+		return o.denseMul(d)
+	case *Quantized[T]:
+		return o.denseMul(d)
+	case halfDenseMatrix:
+		if d32, ok := any(d).(*Dense[float32]); ok {
+			return o.denseMul(d32)
+		}
+	}
 	outRows := d.rows
 	outCols := other.Columns()
 
@@ -676,10 +744,11 @@ func (d *Dense[T]) Mul(other Matrix) Matrix {
 		otherData := float32Data(other)
 		if outCols != 1 {
 			out := densePoolFloat32.GetEmpty(outRows, outCols)
-			f32.MatrixMul(
-				d.rows,                    // aRows
-				d.cols,                    // aCols
-				other.Columns(),           // bCols
+			gemm.Mul(
+				MulParallelism(),          // parallelism
+				d.rows,                    // m
+				d.cols,                    // k
+				other.Columns(),           // n
 				any(d.data).([]float32),   // a
 				otherData,                 // b
 				any(out.data).([]float32), // c
@@ -703,10 +772,11 @@ func (d *Dense[T]) Mul(other Matrix) Matrix {
 		out := densePoolFloat64.GetEmpty(outRows, outCols)
 		otherData := float64Data(other)
 		if outCols != 1 {
-			f64.MatrixMul(
-				d.rows,                    // aRows
-				d.cols,                    // aCols
-				other.Columns(),           // bCols
+			gemm.Mul(
+				MulParallelism(),          // parallelism
+				d.rows,                    // m
+				d.cols,                    // k
+				other.Columns(),           // n
 				any(d.data).([]float64),   // a
 				otherData,                 // b
 				any(out.data).([]float64), // c
@@ -736,10 +806,26 @@ func (d *Dense[T]) Mul(other Matrix) Matrix {
 // ATB = C, where AT is the transpose of A
 // if A is an r x c Matrix, and B is j x k, r = j the resulting
 // Matrix C will be c x k.
+//
+// If the other matrix is Sparse or Quantized, or a HalfDense with a float32
+// receiver, it is not required to be a column vector. Only the non-zero
+// values of a Sparse matrix are visited, while the values of a Quantized or
+// HalfDense matrix are used directly.
 func (d *Dense[T]) MulT(other Matrix) Matrix {
+	d.checkReleased()
 	if d.rows != other.Rows() {
 		panic("mat: matrices have incompatible dimensions")
 	}
+	switch o := other.(type) {
+	case *Sparse[T]:
+		return o.denseMulT(d)
+	case *Quantized[T]:
+		return o.denseMulT(d)
+	case halfDenseMatrix:
+		if d32, ok := any(d).(*Dense[float32]); ok {
+			return o.denseMulT(d32)
+		}
+	}
 	if other.Columns() != 1 {
 		panic("mat: the other matrix must have exactly 1 column")
 	}
@@ -782,6 +868,7 @@ func (d *Dense[T]) MulT(other Matrix) Matrix {
 
 // DotUnitary returns the dot product of two vectors as a scalar Matrix.
 func (d *Dense[T]) DotUnitary(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -799,6 +886,7 @@ func (d *Dense[T]) DotUnitary(other Matrix) Matrix {
 
 // ClipInPlace clips in place each value of the matrix.
 func (d *Dense[T]) ClipInPlace(min, max float64) Matrix {
+	d.checkReleased()
 	if max < min {
 		panic("mat: cannot clip values with max < min")
 	}
@@ -822,6 +910,7 @@ func (d *Dense[T]) ClipInPlace(min, max float64) Matrix {
 
 // Maximum returns a new matrix containing the element-wise maxima.
 func (d *Dense[T]) Maximum(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -848,6 +937,7 @@ func (d *Dense[T]) Maximum(other Matrix) Matrix {
 
 // Minimum returns a new matrix containing the element-wise minima.
 func (d *Dense[T]) Minimum(other Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: matrices have incompatible dimensions")
 	}
@@ -874,6 +964,7 @@ func (d *Dense[T]) Minimum(other Matrix) Matrix {
 
 // Abs returns a new matrix applying the absolute value function to all elements.
 func (d *Dense[T]) Abs() Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	dData := d.data
 	if len(dData) == 0 {
@@ -890,6 +981,7 @@ func (d *Dense[T]) Abs() Matrix {
 // Pow returns a new matrix, applying the power function with given exponent
 // to all elements of the matrix.
 func (d *Dense[T]) Pow(power float64) Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	dData := d.data
 	if len(dData) == 0 {
@@ -905,6 +997,7 @@ func (d *Dense[T]) Pow(power float64) Matrix {
 
 // Sqrt returns a new matrix applying the square root function to all elements.
 func (d *Dense[T]) Sqrt() Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	inData := d.data
 	lastIndex := len(inData) - 1
@@ -921,6 +1014,7 @@ func (d *Dense[T]) Sqrt() Matrix {
 
 // Log returns a new matrix applying the natural logarithm function to each element.
 func (d *Dense[T]) Log() Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	outData := out.data
 	if len(outData) == 0 {
@@ -936,6 +1030,7 @@ func (d *Dense[T]) Log() Matrix {
 
 // Exp returns a new matrix applying the base-e exponential function to each element.
 func (d *Dense[T]) Exp() Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	outData := out.data
 	if len(outData) == 0 {
@@ -955,6 +1050,7 @@ func (d *Dense[T]) Exp() Matrix {
 
 // Sigmoid returns a new matrix applying the sigmoid function to each element.
 func (d *Dense[T]) Sigmoid() Matrix {
+	d.checkReleased()
 	if d.Size() == 0 {
 		return d.Clone()
 	}
@@ -979,6 +1075,7 @@ func (d *Dense[T]) Sigmoid() Matrix {
 
 // Sum returns the sum of all values of the matrix as a scalar Matrix.
 func (d *Dense[T]) Sum() Matrix {
+	d.checkReleased()
 	return NewScalar(d.sum())
 }
 
@@ -995,6 +1092,7 @@ func (d *Dense[T]) sum() T {
 
 // Max returns the maximum value of the matrix as a scalar Matrix.
 func (d *Dense[T]) Max() Matrix {
+	d.checkReleased()
 	return NewScalar(d.max())
 }
 
@@ -1013,6 +1111,7 @@ func (d *Dense[T]) max() T {
 
 // Min returns the minimum value of the matrix as a scalar Matrix.
 func (d *Dense[T]) Min() Matrix {
+	d.checkReleased()
 	if len(d.data) == 0 {
 		panic("mat: cannot find the minimum value in an empty matrix")
 	}
@@ -1027,6 +1126,7 @@ func (d *Dense[T]) Min() Matrix {
 
 // ArgMax returns the index of the vector's element with the maximum value.
 func (d *Dense[T]) ArgMax() int {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -1048,6 +1148,7 @@ func (d *Dense[T]) ArgMax() int {
 // Softmax applies the softmax function to the vector, returning the
 // result as a new column vector.
 func (d *Dense[T]) Softmax() Matrix {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -1083,6 +1184,7 @@ func (d *Dense[T]) Softmax() Matrix {
 // CumSum computes the cumulative sum of the vector's elements, returning
 // the result as a new column vector.
 func (d *Dense[T]) CumSum() Matrix {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -1107,6 +1209,7 @@ func (d *Dense[T]) CumSum() Matrix {
 // Range creates a new vector initialized with data extracted from the
 // matrix raw data, from start (inclusive) to end (exclusive).
 func (d *Dense[T]) Range(start, end int) Matrix {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -1122,6 +1225,7 @@ func (d *Dense[T]) Range(start, end int) Matrix {
 // SplitV splits the vector in N chunks of given sizes,
 // so that N[i] has size sizes[i].
 func (d *Dense[T]) SplitV(sizes ...int) []Matrix {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -1146,6 +1250,7 @@ func (d *Dense[T]) SplitV(sizes ...int) []Matrix {
 
 // Augment places the identity matrix at the end of the original matrix.
 func (d *Dense[T]) Augment() Matrix {
+	d.checkReleased()
 	if d.cols != d.rows {
 		panic("mat: matrix must be square")
 	}
@@ -1162,6 +1267,7 @@ func (d *Dense[T]) Augment() Matrix {
 
 // SwapInPlace swaps two rows of the matrix in place.
 func (d *Dense[T]) SwapInPlace(r1, r2 int) Matrix {
+	d.checkReleased()
 	if r1 < 0 || r1 >= d.rows {
 		panic("mat: 'r1' argument out of range")
 	}
@@ -1179,6 +1285,7 @@ func (d *Dense[T]) SwapInPlace(r1, r2 int) Matrix {
 // PadRows returns a copy of the matrix with n additional tail rows.
 // The additional elements are set to zero.
 func (d *Dense[T]) PadRows(n int) Matrix {
+	d.checkReleased()
 	if n < 0 {
 		panic("mat: negative 'n' argument is not allowed")
 	}
@@ -1201,6 +1308,7 @@ func (d *Dense[T]) PadRows(n int) Matrix {
 // PadColumns returns a copy of the matrix with n additional tail columns.
 // The additional elements are set to zero.
 func (d *Dense[T]) PadColumns(n int) Matrix {
+	d.checkReleased()
 	if n < 0 {
 		panic("mat: negative 'n' argument is not allowed")
 	}
@@ -1228,6 +1336,7 @@ func (d *Dense[T]) PadColumns(n int) Matrix {
 // It accepts row or column vectors indifferently, virtually treating all of
 // them as row vectors.
 func (d *Dense[T]) AppendRows(vs ...Matrix) Matrix {
+	d.checkReleased()
 	cols := d.cols
 	out := densePool[T]().Get(d.rows+len(vs), cols)
 	dData := d.data
@@ -1251,6 +1360,7 @@ func (d *Dense[T]) AppendRows(vs ...Matrix) Matrix {
 // Norm returns the vector's norm. Use pow = 2.0 to compute the Euclidean norm.
 // The result is a scalar Matrix.
 func (d *Dense[T]) Norm(pow float64) Matrix {
+	d.checkReleased()
 	return NewScalar(T(d.norm(pow)))
 }
 
@@ -1267,6 +1377,7 @@ func (d *Dense[T]) norm(pow float64) float64 {
 
 // Normalize2 normalizes an array with the Euclidean norm.
 func (d *Dense[T]) Normalize2() Matrix {
+	d.checkReleased()
 	norm2 := d.norm(2)
 	if norm2 == 0 {
 		return d.Clone()
@@ -1277,6 +1388,7 @@ func (d *Dense[T]) Normalize2() Matrix {
 // Pivoting returns the partial pivots of a square matrix to reorder rows.
 // Considerate square sub-matrix from element (offset, offset).
 func (d *Dense[T]) Pivoting(row int) (Matrix, bool, [2]int) {
+	d.checkReleased()
 	if d.rows != d.cols {
 		panic("mat: matrix must be square")
 	}
@@ -1315,6 +1427,7 @@ func (d *Dense[T]) Pivoting(row int) (Matrix, bool, [2]int) {
 // LU performs lower–upper (LU) decomposition of a square matrix D such as
 // PLU = D, L is lower diagonal and U is upper diagonal, p are pivots.
 func (d *Dense[T]) LU() (l, u, p Matrix) {
+	d.checkReleased()
 	if d.rows != d.cols {
 		panic("mat: matrix must be square")
 	}
@@ -1346,6 +1459,7 @@ func (d *Dense[T]) LU() (l, u, p Matrix) {
 
 // Inverse returns the inverse of the Matrix.
 func (d *Dense[T]) Inverse() Matrix {
+	d.checkReleased()
 	if d.cols != d.rows {
 		panic("mat: matrix must be square")
 	}
@@ -1381,6 +1495,7 @@ func (d *Dense[T]) Inverse() Matrix {
 // VecForEach calls fn for each element of the vector.
 // It panics if the receiver is not a vector.
 func (d *Dense[T]) VecForEach(fn func(i int, v float64)) {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -1391,6 +1506,7 @@ func (d *Dense[T]) VecForEach(fn func(i int, v float64)) {
 
 // Apply creates a new matrix executing the unary function fn.
 func (d *Dense[T]) Apply(fn func(r, c int, v float64) float64) Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	if len(d.data) == 0 {
 		return out
@@ -1417,6 +1533,7 @@ func (d *Dense[T]) Apply(fn func(r, c int, v float64) float64) Matrix {
 // ApplyInPlace executes the unary function fn over the matrix a,
 // and stores the result in the receiver, returning the receiver itself.
 func (d *Dense[T]) ApplyInPlace(fn func(r, c int, v float64) float64, a Matrix) Matrix {
+	d.checkReleased()
 	if !SameDims(d, a) {
 		panic("mat: incompatible matrix dimensions")
 	}
@@ -1443,6 +1560,7 @@ func (d *Dense[T]) ApplyInPlace(fn func(r, c int, v float64) float64, a Matrix)
 // ApplyWithAlpha creates a new matrix executing the unary function fn,
 // taking additional parameters alpha.
 func (d *Dense[T]) ApplyWithAlpha(fn func(r, c int, v float64, alpha ...float64) float64, alpha ...float64) Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	if len(d.data) == 0 {
 		return out
@@ -1470,6 +1588,7 @@ func (d *Dense[T]) ApplyWithAlpha(fn func(r, c int, v float64, alpha ...float64)
 // taking additional parameters alpha, and stores the result in the
 // receiver, returning the receiver itself.
 func (d *Dense[T]) ApplyWithAlphaInPlace(fn func(r, c int, v float64, alpha ...float64) float64, a Matrix, alpha ...float64) Matrix {
+	d.checkReleased()
 	if !SameDims(d, a) {
 		panic("mat: incompatible matrix dimensions")
 	}
@@ -1485,6 +1604,7 @@ func (d *Dense[T]) ApplyWithAlphaInPlace(fn func(r, c int, v float64, alpha ...f
 // DoNonZero calls a function for each non-zero element of the matrix.
 // The parameters of the function are the element's indices and value.
 func (d *Dense[T]) DoNonZero(fn func(r, c int, v float64)) {
+	d.checkReleased()
 	for r, di := 0, 0; r < d.rows; r++ {
 		for c := 0; c < d.cols; c, di = c+1, di+1 {
 			v := d.data[di]
@@ -1499,6 +1619,7 @@ func (d *Dense[T]) DoNonZero(fn func(r, c int, v float64)) {
 // DoVecNonZero calls a function for each non-zero element of the vector.
 // The parameters of the function are the element's index and value.
 func (d *Dense[T]) DoVecNonZero(fn func(i int, v float64)) {
+	d.checkReleased()
 	if !IsVector(d) {
 		panic("mat: expected vector")
 	}
@@ -1512,6 +1633,7 @@ func (d *Dense[T]) DoVecNonZero(fn func(i int, v float64)) {
 
 // Clone returns a new matrix, copying all its values from the receiver.
 func (d *Dense[T]) Clone() Matrix {
+	d.checkReleased()
 	out := densePool[T]().Get(d.rows, d.cols)
 	copy(out.data, d.data)
 	return out
@@ -1520,6 +1642,7 @@ func (d *Dense[T]) Clone() Matrix {
 // Copy copies the data from the other matrix to the receiver.
 // It panics if the matrices have different dimensions.
 func (d *Dense[T]) Copy(other Matrix) {
+	d.checkReleased()
 	if !SameDims(d, other) {
 		panic("mat: incompatible matrix dimensions")
 	}
@@ -1528,6 +1651,7 @@ func (d *Dense[T]) Copy(other Matrix) {
 
 // String returns a string representation of the matrix.
 func (d *Dense[T]) String() string {
+	d.checkReleased()
 	return fmt.Sprintf("Matrix|Dense[%T](%d×%d)%v", T(0), d.rows, d.cols, d.data)
 }
 
@@ -1537,36 +1661,42 @@ func (d *Dense[T]) String() string {
 // Rows and columns MUST not be negative, and the length of data MUST be
 // equal to rows*cols, otherwise the method panics.
 func (d *Dense[T]) NewMatrix(rows, cols int, data float.Slice) Matrix {
+	d.checkReleased()
 	return NewDense[T](rows, cols, float.SliceValueOf[T](data))
 }
 
 // NewVec creates a new column vector (len(data)×1), of the same type of
 // the receiver, initialized with a copy of raw data.
 func (d *Dense[T]) NewVec(data float.Slice) Matrix {
+	d.checkReleased()
 	return NewVecDense[T](float.SliceValueOf[T](data))
 }
 
 // NewScalar creates a new 1×1 matrix, of the same type of the receiver,
 // containing the given value.
 func (d *Dense[T]) NewScalar(v float64) Matrix {
+	d.checkReleased()
 	return NewScalar(T(v))
 }
 
 // NewEmptyVec creates a new vector, of the same type of the receiver,
 // with dimensions size×1, initialized with zeros.
 func (d *Dense[T]) NewEmptyVec(size int) Matrix {
+	d.checkReleased()
 	return NewEmptyVecDense[T](size)
 }
 
 // NewEmptyMatrix creates a new rows×cols matrix, of the same type of the
 // receiver, initialized with zeros.
 func (d *Dense[T]) NewEmptyMatrix(rows, cols int) Matrix {
+	d.checkReleased()
 	return NewEmptyDense[T](rows, cols)
 }
 
 // NewInitMatrix creates a new rows×cols dense matrix, of the same type
 // of the receiver, initialized with a constant value.
 func (d *Dense[T]) NewInitMatrix(rows, cols int, v float64) Matrix {
+	d.checkReleased()
 	return NewInitDense(rows, cols, T(v))
 }
 
@@ -1574,6 +1704,7 @@ func (d *Dense[T]) NewInitMatrix(rows, cols int, v float64) Matrix {
 // of the receiver, initialized with the values returned from the
 // callback function.
 func (d *Dense[T]) NewInitFuncMatrix(rows, cols int, fn func(r, c int) float64) Matrix {
+	d.checkReleased()
 	return NewInitFuncDense(rows, cols, func(r, c int) T {
 		return T(fn(r, c))
 	})
@@ -1582,6 +1713,7 @@ func (d *Dense[T]) NewInitFuncMatrix(rows, cols int, fn func(r, c int) float64)
 // NewInitVec creates a new column vector (size×1), of the same type of
 // the receiver, initialized with a constant value.
 func (d *Dense[T]) NewInitVec(size int, v float64) Matrix {
+	d.checkReleased()
 	return NewInitVecDense(size, T(v))
 }
 
@@ -1589,12 +1721,14 @@ func (d *Dense[T]) NewInitVec(size int, v float64) Matrix {
 // the same type of the receiver, that is, with ones on the diagonal
 // and zeros elsewhere.
 func (d *Dense[T]) NewIdentityMatrix(size int) Matrix {
+	d.checkReleased()
 	return NewIdentityDense[T](size)
 }
 
 // NewOneHotVec creates a new one-hot column vector (size×1), of the same
 // type of the receiver.
 func (d *Dense[T]) NewOneHotVec(size int, oneAt int) Matrix {
+	d.checkReleased()
 	return NewOneHotVecDense[T](size, oneAt)
 }
 
@@ -1603,6 +1737,7 @@ func (d *Dense[T]) NewOneHotVec(size int, oneAt int) Matrix {
 // It accepts row or column vectors indifferently, virtually
 // treating all of them as column vectors.
 func (d *Dense[T]) NewConcatV(vs ...Matrix) Matrix {
+	d.checkReleased()
 	return ConcatV[T](vs...)
 }
 
@@ -1612,5 +1747,6 @@ func (d *Dense[T]) NewConcatV(vs ...Matrix) Matrix {
 // It accepts row or column vectors indifferently, virtually treating all of
 // them as row vectors.
 func (d *Dense[T]) NewStack(vs ...Matrix) Matrix {
+	d.checkReleased()
 	return Stack[T](vs...)
 }
@@ -0,0 +1,500 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// ErrNotPositiveDefinite is returned by Cholesky decomposition when the
+// matrix is not symmetric positive definite.
+var ErrNotPositiveDefinite = errors.New("mat: matrix is not positive definite")
+
+// maxJacobiSweeps is the maximum number of sweeps performed by the Jacobi
+// methods used for SVD and symmetric eigendecomposition. Convergence is
+// quadratic, so in practice only a handful of sweeps are needed.
+const maxJacobiSweeps = 100
+
+// epsilon is the machine epsilon for float64 values.
+const epsilon = 0x1p-52
+
+// QR performs the QR decomposition of the matrix D, using Householder
+// reflections, such as QR = D.
+//
+// Given D with r rows and c columns, and k = min(r, c), the reduced
+// decomposition is returned: Q is an r×k matrix with orthonormal columns,
+// and R is a k×c upper triangular (trapezoidal) matrix. The diagonal of R
+// is non-negative, which makes the decomposition unique for full-rank
+// matrices.
+func (d *Dense[T]) QR() (q, r Matrix) {
+	d.checkReleased()
+	m, n := d.rows, d.cols
+	qData, rData := qrHouseholder(denseToFloat64(d), m, n)
+	k := minInt(m, n)
+	return newDenseFromFloat64[T](m, k, qData), newDenseFromFloat64[T](k, n, rData)
+}
+
+// qrHouseholder computes the reduced QR decomposition of the m×n
+// row-major matrix a, overwriting it.
+func qrHouseholder(a []float64, m, n int) (q, r []float64) {
+	k := minInt(m, n)
+	vs := make([][]float64, k)
+	betas := make([]float64, k)
+
+	for j := 0; j < k; j++ {
+		var norm float64
+		for i := j; i < m; i++ {
+			norm = math.Hypot(norm, a[i*n+j])
+		}
+		if norm == 0 {
+			continue
+		}
+		alpha := -norm
+		if a[j*n+j] < 0 {
+			alpha = norm
+		}
+
+		v := make([]float64, m-j)
+		var vNorm2 float64
+		for i := range v {
+			v[i] = a[(i+j)*n+j]
+		}
+		v[0] -= alpha
+		for _, vi := range v {
+			vNorm2 += vi * vi
+		}
+		if vNorm2 == 0 {
+			continue
+		}
+		beta := 2 / vNorm2
+		vs[j], betas[j] = v, beta
+
+		for c := j; c < n; c++ {
+			var s float64
+			for i, vi := range v {
+				s += vi * a[(i+j)*n+c]
+			}
+			s *= beta
+			for i, vi := range v {
+				a[(i+j)*n+c] -= s * vi
+			}
+		}
+	}
+
+	qData := make([]float64, m*k)
+	for i := 0; i < k; i++ {
+		qData[i*k+i] = 1
+	}
+	for j := k - 1; j >= 0; j-- {
+		v, beta := vs[j], betas[j]
+		if v == nil {
+			continue
+		}
+		for c := 0; c < k; c++ {
+			var s float64
+			for i, vi := range v {
+				s += vi * qData[(i+j)*k+c]
+			}
+			s *= beta
+			for i, vi := range v {
+				qData[(i+j)*k+c] -= s * vi
+			}
+		}
+	}
+
+	rData := make([]float64, k*n)
+	for i := 0; i < k; i++ {
+		copy(rData[i*n+i:i*n+n], a[i*n+i:i*n+n])
+	}
+
+	for i := 0; i < k; i++ {
+		if rData[i*n+i] >= 0 {
+			continue
+		}
+		for c := i; c < n; c++ {
+			rData[i*n+c] = -rData[i*n+c]
+		}
+		for row := 0; row < m; row++ {
+			qData[row*k+i] = -qData[row*k+i]
+		}
+	}
+	return qData, rData
+}
+
+// Solve solves the linear system DX = B for X, using QR decomposition, and
+// returns X. It panics if D is not square, if B does not have the same
+// number of rows of D, or if D is singular.
+func (d *Dense[T]) Solve(b Matrix) Matrix {
+	d.checkReleased()
+	if d.rows != d.cols {
+		panic("mat: matrix must be square")
+	}
+	if b.Rows() != d.rows {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	n, cols := d.rows, b.Columns()
+	q, r := qrHouseholder(denseToFloat64(d), n, n)
+
+	// Diagonal elements of R below tol are treated as zero.
+	var tol float64
+	for i := 0; i < n; i++ {
+		tol = math.Max(tol, math.Abs(r[i*n+i]))
+	}
+	tol *= float64(n) * epsilon
+
+	bData := b.Data().F64()
+	x := make([]float64, n*cols)
+	for c := 0; c < cols; c++ {
+		// y = Qᵀb
+		for i := 0; i < n; i++ {
+			var sum float64
+			for k := 0; k < n; k++ {
+				sum += q[k*n+i] * bData[k*cols+c]
+			}
+			x[i*cols+c] = sum
+		}
+		// Rx = y
+		for i := n - 1; i >= 0; i-- {
+			rii := r[i*n+i]
+			if math.Abs(rii) <= tol {
+				panic("mat: matrix is singular")
+			}
+			sum := x[i*cols+c]
+			for k := i + 1; k < n; k++ {
+				sum -= r[i*n+k] * x[k*cols+c]
+			}
+			x[i*cols+c] = sum / rii
+		}
+	}
+	return newDenseFromFloat64[T](n, cols, x)
+}
+
+// Cholesky performs the Cholesky decomposition of the symmetric positive
+// definite matrix D, such as LLᵀ = D, where L is lower triangular with a
+// positive diagonal.
+//
+// Only the lower triangle of D is read. It panics if D is not square, and
+// returns ErrNotPositiveDefinite if D is not positive definite.
+func (d *Dense[T]) Cholesky() (Matrix, error) {
+	d.checkReleased()
+	if d.rows != d.cols {
+		panic("mat: matrix must be square")
+	}
+	n := d.rows
+	l := make([]float64, n*n)
+	for j := 0; j < n; j++ {
+		s := float64(d.data[j*n+j])
+		for k := 0; k < j; k++ {
+			s -= l[j*n+k] * l[j*n+k]
+		}
+		if !(s > 0) || math.IsInf(s, 0) {
+			return nil, ErrNotPositiveDefinite
+		}
+		ljj := math.Sqrt(s)
+		l[j*n+j] = ljj
+		for i := j + 1; i < n; i++ {
+			s := float64(d.data[i*n+j])
+			for k := 0; k < j; k++ {
+				s -= l[i*n+k] * l[j*n+k]
+			}
+			l[i*n+j] = s / ljj
+		}
+	}
+	return newDenseFromFloat64[T](n, n, l), nil
+}
+
+// SVD performs the singular value decomposition of the matrix D, using
+// one-sided Jacobi rotations, such as U × diag(S) × Vᵀ = D.
+//
+// Given D with r rows and c columns, and k = min(r, c), the thin
+// decomposition is returned: U is an r×k matrix and V a c×k matrix, both
+// with orthonormal columns, and S is a vector of size k holding the
+// singular values in descending order.
+func (d *Dense[T]) SVD() (u, s, v Matrix) {
+	d.checkReleased()
+	m, n := d.rows, d.cols
+	a := denseToFloat64(d)
+	if m < n {
+		uData, sData, vData := svdJacobi(transposeFloat64(a, m, n), n, m)
+		return newDenseFromFloat64[T](m, m, vData),
+			newDenseFromFloat64[T](m, 1, sData),
+			newDenseFromFloat64[T](n, m, uData)
+	}
+	uData, sData, vData := svdJacobi(a, m, n)
+	return newDenseFromFloat64[T](m, n, uData),
+		newDenseFromFloat64[T](n, 1, sData),
+		newDenseFromFloat64[T](n, n, vData)
+}
+
+// svdJacobi computes the thin SVD of the m×n row-major matrix a, with
+// m >= n, overwriting a with U.
+func svdJacobi(a []float64, m, n int) (u, s, v []float64) {
+	u = a
+	v = make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		v[i*n+i] = 1
+	}
+
+	const eps = 1e-15
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				var alpha, beta, gamma float64
+				for i := 0; i < m; i++ {
+					up, uq := u[i*n+p], u[i*n+q]
+					alpha += up * up
+					beta += uq * uq
+					gamma += up * uq
+				}
+				if gamma == 0 || math.Abs(gamma) <= eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+
+				c, s := jacobiRotation(alpha, beta, gamma)
+				rotateColumns(u, m, n, p, q, c, s)
+				rotateColumns(v, n, n, p, q, c, s)
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	s = make([]float64, n)
+	for j := 0; j < n; j++ {
+		var norm float64
+		for i := 0; i < m; i++ {
+			norm = math.Hypot(norm, u[i*n+j])
+		}
+		s[j] = norm
+		if norm == 0 {
+			continue
+		}
+		for i := 0; i < m; i++ {
+			u[i*n+j] /= norm
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return s[order[i]] > s[order[j]]
+	})
+	s = permuteColumns(s, 1, n, order)
+	u = permuteColumns(u, m, n, order)
+	v = permuteColumns(v, n, n, order)
+
+	completeOrthonormalColumns(u, m, n, s)
+	return u, s, v
+}
+
+// completeOrthonormalColumns replaces the columns of the m×n row-major
+// matrix u corresponding to zero singular values with unit vectors
+// orthogonal to all the other columns.
+func completeOrthonormalColumns(u []float64, m, n int, s []float64) {
+	col := make([]float64, m)
+	for j := 0; j < n; j++ {
+		if s[j] != 0 {
+			continue
+		}
+		for e := 0; e < m; e++ {
+			for i := range col {
+				col[i] = 0
+			}
+			col[e] = 1
+			// Orthogonalize twice for numerical stability.
+			for pass := 0; pass < 2; pass++ {
+				for k := 0; k < n; k++ {
+					if k == j || (s[k] == 0 && k > j) {
+						continue
+					}
+					var dot float64
+					for i := 0; i < m; i++ {
+						dot += col[i] * u[i*n+k]
+					}
+					for i := 0; i < m; i++ {
+						col[i] -= dot * u[i*n+k]
+					}
+				}
+			}
+			var norm float64
+			for _, x := range col {
+				norm = math.Hypot(norm, x)
+			}
+			if norm < 0.5 {
+				continue
+			}
+			for i := 0; i < m; i++ {
+				u[i*n+j] = col[i] / norm
+			}
+			break
+		}
+	}
+}
+
+// EigenSym performs the eigendecomposition of the symmetric matrix D, using
+// cyclic Jacobi rotations, such as V × diag(values) × Vᵀ = D.
+//
+// The eigenvalues are returned as a vector in ascending order, and the
+// corresponding orthonormal eigenvectors are the columns of the matrix V.
+// Only the upper triangle of D is read. It panics if D is not square.
+func (d *Dense[T]) EigenSym() (values, vectors Matrix) {
+	d.checkReleased()
+	if d.rows != d.cols {
+		panic("mat: matrix must be square")
+	}
+	n := d.rows
+	a := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			v := float64(d.data[i*n+j])
+			a[i*n+j], a[j*n+i] = v, v
+		}
+	}
+	v := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		v[i*n+i] = 1
+	}
+
+	for sweep := 0; sweep < maxJacobiSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := a[p*n+q]
+				if apq == 0 {
+					continue
+				}
+				app, aqq := a[p*n+p], a[q*n+q]
+				g := 100 * math.Abs(apq)
+				if sweep > 3 && math.Abs(app)+g == math.Abs(app) && math.Abs(aqq)+g == math.Abs(aqq) {
+					a[p*n+q], a[q*n+p] = 0, 0
+					continue
+				}
+				rotated = true
+
+				c, s := jacobiRotation(app, aqq, apq)
+				rotateColumns(a, n, n, p, q, c, s)
+				rotateRows(a, n, p, q, c, s)
+				a[p*n+q], a[q*n+p] = 0, 0
+				rotateColumns(v, n, n, p, q, c, s)
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = a[i*n+i]
+	}
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return w[order[i]] < w[order[j]]
+	})
+
+	return newDenseFromFloat64[T](n, 1, permuteColumns(w, 1, n, order)),
+		newDenseFromFloat64[T](n, n, permuteColumns(v, n, n, order))
+}
+
+// jacobiRotation returns the cosine and sine of the rotation which
+// annihilates the off-diagonal element gamma of the symmetric 2×2 matrix
+// [alpha gamma; gamma beta].
+func jacobiRotation(alpha, beta, gamma float64) (c, s float64) {
+	theta := (beta - alpha) / (2 * gamma)
+	var t float64
+	if math.IsInf(theta*theta, 0) {
+		t = 1 / (2 * theta)
+	} else {
+		t = 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+		if theta < 0 {
+			t = -t
+		}
+	}
+	c = 1 / math.Sqrt(t*t+1)
+	return c, t * c
+}
+
+// rotateColumns applies the Jacobi rotation (c, s) to the columns p and q
+// of the rows×cols row-major matrix a.
+func rotateColumns(a []float64, rows, cols, p, q int, c, s float64) {
+	for i := 0; i < rows; i++ {
+		ap, aq := a[i*cols+p], a[i*cols+q]
+		a[i*cols+p] = c*ap - s*aq
+		a[i*cols+q] = s*ap + c*aq
+	}
+}
+
+// rotateRows applies the Jacobi rotation (c, s) to the rows p and q of the
+// n×n row-major matrix a.
+func rotateRows(a []float64, n, p, q int, c, s float64) {
+	for k := 0; k < n; k++ {
+		ap, aq := a[p*n+k], a[q*n+k]
+		a[p*n+k] = c*ap - s*aq
+		a[q*n+k] = s*ap + c*aq
+	}
+}
+
+// permuteColumns returns a copy of the rows×cols row-major matrix a with
+// the columns reordered according to order.
+func permuteColumns(a []float64, rows, cols int, order []int) []float64 {
+	out := make([]float64, len(a))
+	for i := 0; i < rows; i++ {
+		for j, o := range order {
+			out[i*cols+j] = a[i*cols+o]
+		}
+	}
+	return out
+}
+
+// transposeFloat64 returns the transpose of the rows×cols row-major
+// matrix a.
+func transposeFloat64(a []float64, rows, cols int) []float64 {
+	out := make([]float64, len(a))
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			out[j*rows+i] = a[i*cols+j]
+		}
+	}
+	return out
+}
+
+// denseToFloat64 returns a float64 copy of the data of d. Decompositions
+// are always computed in double precision, regardless of T.
+func denseToFloat64[T float.DType](d *Dense[T]) []float64 {
+	out := make([]float64, len(d.data))
+	for i, v := range d.data {
+		out[i] = float64(v)
+	}
+	return out
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// newDenseFromFloat64 creates a new Dense matrix from float64 data,
+// converting the values to T.
+func newDenseFromFloat64[T float.DType](rows, cols int, data []float64) *Dense[T] {
+	out := NewEmptyDense[T](rows, cols)
+	for i, v := range data {
+		out.data[i] = T(v)
+	}
+	return out
+}
@@ -0,0 +1,338 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDense_QR(t *testing.T) {
+	t.Run("float32", testDenseQR[float32])
+	t.Run("float64", testDenseQR[float64])
+}
+
+func testDenseQR[T float.DType](t *testing.T) {
+	t.Run("known decomposition", func(t *testing.T) {
+		d := NewDense[T](3, 3, []T{
+			12, -51, 4,
+			6, 167, -68,
+			-4, 24, -41,
+		})
+		q, r := d.QR()
+
+		assertDenseDims(t, 3, 3, q.(*Dense[T]))
+		assert.InDeltaSlice(t, []T{
+			6. / 7, -69. / 175, -58. / 175,
+			3. / 7, 158. / 175, 6. / 175,
+			-2. / 7, 6. / 35, -33. / 35,
+		}, q.Data(), 1.0e-05)
+
+		assertDenseDims(t, 3, 3, r.(*Dense[T]))
+		assert.InDeltaSlice(t, []T{
+			14, 21, -14,
+			0, 175, -70,
+			0, 0, 35,
+		}, r.Data(), 1.0e-04)
+	})
+
+	testCases := []*Dense[T]{
+		NewEmptyDense[T](0, 0),
+		NewDense[T](1, 1, []T{-3}),
+		NewDense[T](4, 2, []T{
+			1, 2,
+			3, 4,
+			5, 6,
+			7, 8,
+		}),
+		NewDense[T](2, 4, []T{
+			1, -2, 3, 0,
+			4, 5, -6, 1,
+		}),
+		NewDense[T](3, 3, []T{
+			1, 2, 3,
+			2, 4, 6,
+			0, 0, 1,
+		}),
+	}
+
+	for _, d := range testCases {
+		t.Run(fmt.Sprintf("%d x %d", d.rows, d.cols), func(t *testing.T) {
+			q, r := d.QR()
+			k := minInt(d.rows, d.cols)
+
+			assertDenseDims(t, d.rows, k, q.(*Dense[T]))
+			assertDenseDims(t, k, d.cols, r.(*Dense[T]))
+			assertOrthonormalColumns(t, q)
+
+			rData := Data[T](r)
+			for i := 0; i < k; i++ {
+				assert.GreaterOrEqual(t, float64(rData[i*d.cols+i]), 0.0)
+				for j := 0; j < i; j++ {
+					assert.Zero(t, rData[i*d.cols+j])
+				}
+			}
+			assert.InDeltaSlice(t, d.Data(), q.Mul(r).Data(), 1.0e-04)
+		})
+	}
+}
+
+func TestDense_Cholesky(t *testing.T) {
+	t.Run("float32", testDenseCholesky[float32])
+	t.Run("float64", testDenseCholesky[float64])
+}
+
+func testDenseCholesky[T float.DType](t *testing.T) {
+	t.Run("non square matrix", func(t *testing.T) {
+		d := NewEmptyDense[T](2, 3)
+		require.Panics(t, func() {
+			d.Cholesky()
+		})
+	})
+
+	t.Run("not positive definite", func(t *testing.T) {
+		for _, d := range []*Dense[T]{
+			NewDense[T](2, 2, []T{1, 2, 2, 1}),
+			NewDense[T](2, 2, []T{0, 0, 0, 0}),
+			NewDense[T](1, 1, []T{-1}),
+		} {
+			l, err := d.Cholesky()
+			assert.ErrorIs(t, err, ErrNotPositiveDefinite)
+			assert.Nil(t, l)
+		}
+	})
+
+	testCases := []struct {
+		d *Dense[T]
+		l []T
+	}{
+		{NewEmptyDense[T](0, 0), []T{}},
+		{NewDense[T](1, 1, []T{9}), []T{3}},
+		{
+			NewDense[T](3, 3, []T{
+				4, 12, -16,
+				12, 37, -43,
+				-16, -43, 98,
+			}),
+			[]T{
+				2, 0, 0,
+				6, 1, 0,
+				-8, 5, 3,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d x %d", tc.d.rows, tc.d.cols), func(t *testing.T) {
+			l, err := tc.d.Cholesky()
+			require.NoError(t, err)
+			assertDenseDims(t, tc.d.rows, tc.d.cols, l.(*Dense[T]))
+			assert.InDeltaSlice(t, tc.l, l.Data(), 1.0e-05)
+			assert.InDeltaSlice(t, tc.d.Data(), l.Mul(l.T()).Data(), 1.0e-04)
+		})
+	}
+}
+
+func TestDense_SVD(t *testing.T) {
+	t.Run("float32", testDenseSVD[float32])
+	t.Run("float64", testDenseSVD[float64])
+}
+
+func testDenseSVD[T float.DType](t *testing.T) {
+	testCases := []struct {
+		d *Dense[T]
+		s []T
+	}{
+		{NewEmptyDense[T](0, 0), []T{}},
+		{NewDense[T](1, 1, []T{-2}), []T{2}},
+		{
+			NewDense[T](2, 3, []T{
+				3, 2, 2,
+				2, 3, -2,
+			}),
+			[]T{5, 3},
+		},
+		{
+			NewDense[T](3, 2, []T{
+				3, 2,
+				2, 3,
+				2, -2,
+			}),
+			[]T{5, 3},
+		},
+		{
+			NewDense[T](4, 3, []T{
+				2, 0, 0,
+				0, 0, -7,
+				0, 1, 0,
+				0, 0, 0,
+			}),
+			[]T{7, 2, 1},
+		},
+		{
+			// Rank deficient
+			NewDense[T](3, 3, []T{
+				1, 2, 3,
+				2, 4, 6,
+				3, 6, 9,
+			}),
+			[]T{14, 0, 0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d x %d", tc.d.rows, tc.d.cols), func(t *testing.T) {
+			u, s, v := tc.d.SVD()
+			k := minInt(tc.d.rows, tc.d.cols)
+
+			assertDenseDims(t, tc.d.rows, k, u.(*Dense[T]))
+			assertDenseDims(t, k, 1, s.(*Dense[T]))
+			assertDenseDims(t, tc.d.cols, k, v.(*Dense[T]))
+
+			assert.InDeltaSlice(t, tc.s, s.Data(), 1.0e-05)
+			assertOrthonormalColumns(t, u)
+			assertOrthonormalColumns(t, v)
+
+			us := u.Prod(s.T())
+			assert.InDeltaSlice(t, tc.d.Data(), us.Mul(v.T()).Data(), 1.0e-04)
+		})
+	}
+}
+
+func TestDense_EigenSym(t *testing.T) {
+	t.Run("float32", testDenseEigenSym[float32])
+	t.Run("float64", testDenseEigenSym[float64])
+}
+
+func testDenseEigenSym[T float.DType](t *testing.T) {
+	t.Run("non square matrix", func(t *testing.T) {
+		d := NewEmptyDense[T](2, 3)
+		require.Panics(t, func() {
+			d.EigenSym()
+		})
+	})
+
+	t.Run("upper triangle only", func(t *testing.T) {
+		d := NewDense[T](2, 2, []T{
+			2, 1,
+			42, 2,
+		})
+		values, _ := d.EigenSym()
+		assert.InDeltaSlice(t, []T{1, 3}, values.Data(), 1.0e-05)
+	})
+
+	testCases := []struct {
+		d      *Dense[T]
+		values []T
+	}{
+		{NewEmptyDense[T](0, 0), []T{}},
+		{NewDense[T](1, 1, []T{-5}), []T{-5}},
+		{
+			NewDense[T](2, 2, []T{
+				2, 1,
+				1, 2,
+			}),
+			[]T{1, 3},
+		},
+		{
+			NewDense[T](3, 3, []T{
+				2, 0, 0,
+				0, 3, 4,
+				0, 4, 9,
+			}),
+			[]T{1, 2, 11},
+		},
+		{
+			NewDense[T](4, 4, []T{
+				4, -30, 60, -35,
+				-30, 300, -675, 420,
+				60, -675, 1620, -1050,
+				-35, 420, -1050, 700,
+			}),
+			[]T{0.1666428611718905, 1.4780548447781369, 37.10149136512459, 2585.253810928919},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%d x %d", tc.d.rows, tc.d.cols), func(t *testing.T) {
+			values, vectors := tc.d.EigenSym()
+
+			assertDenseDims(t, tc.d.rows, 1, values.(*Dense[T]))
+			assertDenseDims(t, tc.d.rows, tc.d.cols, vectors.(*Dense[T]))
+
+			assert.InDeltaSlice(t, tc.values, values.Data(), 1.0e-03)
+			assertOrthonormalColumns(t, vectors)
+
+			vl := vectors.Prod(values.T())
+			assert.InDeltaSlice(t, tc.d.Data(), vl.Mul(vectors.T()).Data(), 1.0e-03)
+		})
+	}
+}
+
+func assertOrthonormalColumns(t *testing.T, m Matrix) {
+	t.Helper()
+	k := m.Columns()
+	gram := m.T().Mul(m)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			expected := 0.0
+			if i == j {
+				expected = 1
+			}
+			assert.InDelta(t, expected, gram.ScalarAt(i, j).F64(), 1.0e-05, "Gram matrix at (%d, %d)", i, j)
+		}
+	}
+}
+
+func TestDense_Solve(t *testing.T) {
+	t.Run("float32", testDenseSolve[float32])
+	t.Run("float64", testDenseSolve[float64])
+}
+
+func testDenseSolve[T float.DType](t *testing.T) {
+	t.Run("non square matrix", func(t *testing.T) {
+		d := NewEmptyDense[T](2, 3)
+		require.Panics(t, func() {
+			d.Solve(NewEmptyVecDense[T](2))
+		})
+	})
+
+	t.Run("incompatible dimensions", func(t *testing.T) {
+		d := NewEmptyDense[T](2, 2)
+		require.Panics(t, func() {
+			d.Solve(NewEmptyVecDense[T](3))
+		})
+	})
+
+	t.Run("singular matrix", func(t *testing.T) {
+		d := NewDense[T](2, 2, []T{1, 2, 2, 4})
+		require.Panics(t, func() {
+			d.Solve(NewEmptyVecDense[T](2))
+		})
+	})
+
+	d := NewDense[T](3, 3, []T{
+		2, 1, -1,
+		-3, -1, 2,
+		-2, 1, 2,
+	})
+
+	x := d.Solve(NewVecDense[T]([]T{8, -11, -3}))
+	assertDenseDims(t, 3, 1, x.(*Dense[T]))
+	assert.InDeltaSlice(t, []T{2, 3, -1}, x.Data(), 1.0e-05)
+
+	b := NewDense[T](3, 2, []T{
+		8, 1,
+		-11, 0,
+		-3, 0,
+	})
+	x = d.Solve(b)
+	assertDenseDims(t, 3, 2, x.(*Dense[T]))
+	assert.InDeltaSlice(t, b.Data(), d.Mul(x).Data(), 1.0e-05)
+}
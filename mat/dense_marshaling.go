@@ -10,6 +10,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+
+	"github.com/nlpodyssey/spago/mat/float"
 )
 
 func init() {
@@ -20,10 +22,13 @@ func init() {
 const (
 	binaryDenseFloat32 byte = iota
 	binaryDenseFloat64
+	binaryDenseFloat16
+	binaryDenseBFloat16
 )
 
 // MarshalBinary marshals a Dense matrix into binary form.
 func (d Dense[T]) MarshalBinary() ([]byte, error) {
+	d.checkReleased()
 	switch any(T(0)).(type) {
 	case float32:
 		return d.marshalBinaryFloat32()
@@ -34,8 +39,39 @@ func (d Dense[T]) MarshalBinary() ([]byte, error) {
 	}
 }
 
+// MarshalBinaryFloat16 marshals a Dense matrix into binary form, storing
+// its values as IEEE 754 half-precision numbers (see float.Float16).
+// Values are rounded to the nearest representable number, so the
+// conversion is lossy.
+func (d Dense[T]) MarshalBinaryFloat16() ([]byte, error) {
+	d.checkReleased()
+	return marshalBinaryHalf[T, float.Float16](d, binaryDenseFloat16), nil
+}
+
+// MarshalBinaryBFloat16 marshals a Dense matrix into binary form, storing
+// its values as bfloat16 numbers (see float.BFloat16).
+// Values are rounded to the nearest representable number, so the
+// conversion is lossy.
+func (d Dense[T]) MarshalBinaryBFloat16() ([]byte, error) {
+	d.checkReleased()
+	return marshalBinaryHalf[T, float.BFloat16](d, binaryDenseBFloat16), nil
+}
+
 // UnmarshalBinary unmarshals a binary representation of a Dense matrix.
+//
+// A representation produced by MarshalBinary can only be unmarshaled into
+// a matrix of the same data type, while the 16-bit representations produced
+// by MarshalBinaryFloat16 and MarshalBinaryBFloat16 are converted to the
+// data type of the receiver.
 func (d *Dense[T]) UnmarshalBinary(data []byte) error {
+	if len(data) > 0 {
+		switch data[0] {
+		case binaryDenseFloat16:
+			return unmarshalBinaryHalf[T, float.Float16](d, data)
+		case binaryDenseBFloat16:
+			return unmarshalBinaryHalf[T, float.BFloat16](d, data)
+		}
+	}
 	switch any(T(0)).(type) {
 	case float32:
 		return d.unmarshalBinaryFloat32(data)
@@ -129,3 +165,44 @@ func (d *Dense[T]) unmarshalBinaryFloat64(data []byte) error {
 	d.flags = 0
 	return nil
 }
+
+// Dense matrix - float16 and bfloat16 marshaling:
+// - 1 byte - identifier binaryDenseFloat16 or binaryDenseBFloat16 (byte)
+// - 8 bytes - rows (uint64)
+// - 8 bytes - cols (uint64)
+// - 2*size bytes - data (float16 or bfloat16 as uint16-bits)
+
+func marshalBinaryHalf[T float.DType, H float.Half](d Dense[T], identifier byte) []byte {
+	data := make([]byte, 17+len(d.data)*2)
+	data[0] = identifier
+	binary.LittleEndian.PutUint64(data[1:], uint64(d.rows))
+	binary.LittleEndian.PutUint64(data[9:], uint64(d.cols))
+
+	s := data[17:]
+	for _, v := range d.data {
+		binary.LittleEndian.PutUint16(s, uint16(float.HalfFromFloat32[H](float32(v))))
+		s = s[2:]
+	}
+
+	return data
+}
+
+func unmarshalBinaryHalf[T float.DType, H float.Half](d *Dense[T], data []byte) error {
+	d.rows = int(binary.LittleEndian.Uint64(data[1:]))
+	d.cols = int(binary.LittleEndian.Uint64(data[9:]))
+	size := d.rows * d.cols
+	if len(data) != 17+size*2 {
+		return fmt.Errorf("mat: cannot unmarshal Dense[%T]: invalid data length", T(0))
+	}
+	d.data = make([]T, size)
+
+	data = data[17:]
+	dData := d.data
+	for i := range dData {
+		dData[i] = T(H(binary.LittleEndian.Uint16(data)).Float32())
+		data = data[2:]
+	}
+
+	d.flags = 0
+	return nil
+}
@@ -19,6 +19,9 @@ func TestDense_Marshaling(t *testing.T) {
 	t.Run("float32", testDenseMarshaling[float32])
 	t.Run("float64", testDenseMarshaling[float64])
 
+	t.Run("half float32-float64", testDenseMarshalingHalf[float32, float64])
+	t.Run("half float64-float32", testDenseMarshalingHalf[float64, float32])
+
 	t.Run("wrong types float32-float64", testDenseMarshalingWrongType[float32, float64])
 	t.Run("wrong types float64-float32", testDenseMarshalingWrongType[float64, float32])
 
@@ -86,6 +89,46 @@ func testDenseMarshaling[T float.DType](t *testing.T) {
 	}
 }
 
+func testDenseMarshalingHalf[T1, T2 float.DType](t *testing.T) {
+	d := NewDense[T1](2, 3, []T1{
+		1, -2, 0.5,
+		0.1, 65504, 1e-8,
+	})
+
+	t.Run("float16", func(t *testing.T) {
+		data, err := d.MarshalBinaryFloat16()
+		require.NoError(t, err)
+		assert.Len(t, data, 17+6*2)
+
+		y := new(Dense[T2])
+		y.flags = 0xFF // dirty value to make sure it will be reset
+		err = y.UnmarshalBinary(data)
+		require.NoError(t, err)
+		assertDenseDims(t, 2, 3, y)
+		assert.InDeltaSlice(t, []T2{1, -2, 0.5, 0.1, 65504, 0}, y.data, 1.0e-4)
+		assert.Equal(t, denseFlag(0), y.flags)
+	})
+
+	t.Run("bfloat16", func(t *testing.T) {
+		data, err := d.MarshalBinaryBFloat16()
+		require.NoError(t, err)
+		assert.Len(t, data, 17+6*2)
+
+		y := new(Dense[T2])
+		err = y.UnmarshalBinary(data)
+		require.NoError(t, err)
+		assertDenseDims(t, 2, 3, y)
+		assert.InDeltaSlice(t, []T2{1, -2, 0.5, 0.1, 65536, 1e-8}, y.data, 1.0e-3)
+	})
+
+	t.Run("invalid data length", func(t *testing.T) {
+		data, err := d.MarshalBinaryFloat16()
+		require.NoError(t, err)
+		err = new(Dense[T2]).UnmarshalBinary(data[:len(data)-1])
+		assert.Error(t, err)
+	})
+}
+
 func testDenseMarshalingWrongType[T1, T2 float.DType](t *testing.T) {
 	d := NewScalar[T1](42)
 	data, err := d.MarshalBinary()
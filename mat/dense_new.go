@@ -27,6 +27,25 @@ func NewDense[T float.DType](rows, cols int, data []T) *Dense[T] {
 	return d
 }
 
+// NewDenseFromHalf returns a new matrix of size rows×cols, initialized with
+// the given 16-bit floating point values, converted to the data type T.
+//
+// Rows and columns MUST not be negative, and the length of data MUST be
+// equal to rows*cols, otherwise the method panics.
+func NewDenseFromHalf[T float.DType, H float.Half](rows, cols int, data []H) *Dense[T] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if len(data) != rows*cols {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", rows*cols))
+	}
+	d := densePool[T]().Get(rows, cols)
+	for i, v := range data {
+		d.data[i] = T(v.Float32())
+	}
+	return d
+}
+
 // NewVecDense returns a new column vector (len(data)×1) initialized with
 // a copy of raw data.
 func NewVecDense[T float.DType](data []T) *Dense[T] {
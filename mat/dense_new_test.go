@@ -75,6 +75,27 @@ func testNewDense[T float.DType](t *testing.T) {
 	})
 }
 
+func TestNewDenseFromHalf(t *testing.T) {
+	t.Run("float32-float16", testNewDenseFromHalf[float32, float.Float16])
+	t.Run("float32-bfloat16", testNewDenseFromHalf[float32, float.BFloat16])
+	t.Run("float64-float16", testNewDenseFromHalf[float64, float.Float16])
+	t.Run("float64-bfloat16", testNewDenseFromHalf[float64, float.BFloat16])
+}
+
+func testNewDenseFromHalf[T float.DType, H float.Half](t *testing.T) {
+	t.Run("elements length mismatch", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewDenseFromHalf[T](1, 1, []H{0, 0})
+		})
+	})
+
+	h := float.HalfSliceValueOf[H](float.SliceInterface([]float32{1, -2, 0.5, 4}))
+	d := NewDenseFromHalf[T](2, 2, h)
+	assertDenseDims(t, 2, 2, d)
+	assert.Equal(t, []T{1, -2, 0.5, 4}, Data[T](d))
+	assert.Equal(t, h, HalfData[H](d))
+}
+
 func TestNewVecDense(t *testing.T) {
 	t.Run("float32", testNewVecDense[float32])
 	t.Run("float64", testNewVecDense[float64])
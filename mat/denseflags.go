@@ -10,4 +10,10 @@ const (
 	denseIsFromPool denseFlag = 1 << iota
 	denseIsNew
 	denseIsView
+	// denseIsTracked is set on the matrices obtained from the pools while
+	// they are tracked (see SetPoolTracking), until their release.
+	denseIsTracked
+	// denseIsReleased is set on the matrices released while the pools are
+	// tracked, which are never recycled.
+	denseIsReleased
 )
@@ -18,6 +18,7 @@ import (
 	"math"
 	"math/bits"
 	"sync"
+	"sync/atomic"
 
 	"github.com/nlpodyssey/spago/mat/float"
 )
@@ -61,6 +62,9 @@ func (dp *densePoolType[T]) makeNewFunc(bitsLen int) func() any {
 		length = 1<<bitsLen - 1
 	}
 	return func() any {
+		if trackingPools() {
+			atomic.AddUint64(&poolCounters.misses, 1)
+		}
 		return &Dense[T]{
 			rows:  -1,
 			cols:  -1,
@@ -104,6 +108,9 @@ func (dp *densePoolType[T]) get(rows, cols int) *Dense[T] {
 	bitsLen := bits.Len(length)
 	d := dp[bitsLen].Get().(*Dense[T])
 	d.data = d.data[:length]
+	if trackingPools() {
+		trackGet(d, bitsLen)
+	}
 	d.rows = rows
 	d.cols = cols
 	return d
@@ -113,23 +120,57 @@ func (dp *densePoolType[T]) get(rows, cols int) *Dense[T] {
 //
 // It MUST not be called with a matrix where references to the underlying data
 // slice have been kept.
+//
+// While the pools are tracked (see SetPoolTracking), the data of the matrix
+// are handed over to a new matrix, which is added to the pool instead, and
+// releasing the matrix again panics.
 func (dp *densePoolType[T]) Put(d *Dense[T]) {
 	if d.flags&denseIsFromPool == 0 {
 		panic("mat: only matrices originated from the workspace can return to it")
 	}
+	if d.flags&denseIsReleased != 0 {
+		atomic.AddUint64(&poolCounters.doubleReleases, 1)
+		panic("mat: Dense matrix released twice")
+	}
 	bitsLen := bits.Len(uint(cap(d.data)))
+	if d.flags&denseIsTracked != 0 {
+		trackPut(d, bitsLen)
+	}
+	if trackingPools() {
+		dp[bitsLen].Put(&Dense[T]{
+			rows:  -1,
+			cols:  -1,
+			flags: denseIsFromPool,
+			data:  d.data,
+		})
+		d.data = nil
+		d.flags |= denseIsReleased
+		return
+	}
 	dp[bitsLen].Put(d)
 }
 
 // ReleaseMatrix puts the given matrix in the appropriate global pool.
-// It currently works with Dense matrices only. For any other matrix
-// implementation, it panics.
+// It currently works with Dense matrices and DenseTensors only (releasing a
+// DenseTensor view has no effect). Sparse, Quantized and HalfDense matrices
+// are not pooled, so releasing them has no effect. For any other matrix implementation,
+// it panics.
 func ReleaseMatrix(m Matrix) {
 	switch mt := m.(type) {
 	case *Dense[float32]:
 		densePoolFloat32.Put(mt)
 	case *Dense[float64]:
 		densePoolFloat64.Put(mt)
+	case *DenseTensor[float32]:
+		mt.release()
+	case *DenseTensor[float64]:
+		mt.release()
+	case *Sparse[float32], *Sparse[float64]:
+		// sparse matrices are not pooled
+	case *Quantized[float32], *Quantized[float64]:
+		// quantized matrices are not pooled
+	case *HalfDense[float.Float16], *HalfDense[float.BFloat16]:
+		// half-precision matrices are not pooled
 	default:
 		panic(fmt.Sprintf("mat: cannot release matrix of type %T", mt))
 	}
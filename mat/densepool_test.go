@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"runtime"
 	"testing"
+	"unsafe"
 
 	"github.com/nlpodyssey/spago/mat/float"
 	"github.com/stretchr/testify/assert"
@@ -230,3 +231,71 @@ func testReleaseMatrix[T float.DType](t *testing.T) {
 type foreignMatrixImplementation[T float.DType] struct {
 	*Dense[T]
 }
+
+func TestPoolTracking(t *testing.T) {
+	t.Run("float32", testPoolTracking[float32])
+	t.Run("float64", testPoolTracking[float64])
+}
+
+func testPoolTracking[T float.DType](t *testing.T) {
+	size := int64(unsafe.Sizeof(T(0)))
+
+	untracked := NewEmptyDense[T](2, 2)
+	s0 := ReadPoolStats()
+
+	prev := SetPoolTracking(true)
+	defer SetPoolTracking(prev)
+	assert.False(t, prev)
+
+	a := NewEmptyDense[T](2, 3)
+	b := NewEmptyDense[T](1, 5)
+	c := a.Add(a)
+	s1 := ReadPoolStats()
+	assert.Equal(t, s0.Gets+3, s1.Gets)
+	assert.Equal(t, s0.GetBytes+uint64(17*size), s1.GetBytes)
+	assert.LessOrEqual(t, s1.Hits-s0.Hits, uint64(3))
+	assert.Equal(t, s0.Live+3, s1.Live)
+	assert.Equal(t, s0.LiveBytes+3*7*size, s1.LiveBytes)
+	assert.Contains(t, s1.Buckets, PoolBucketStats{Capacity: 7, Live: 3, LiveBytes: 3 * 7 * size})
+
+	ReleaseMatrix(a)
+	ReleaseMatrix(b)
+	ReleaseMatrix(untracked) // obtained before the tracking
+	s2 := ReadPoolStats()
+	assert.Equal(t, s0.Live+1, s2.Live)
+	assert.Equal(t, s0.LiveBytes+7*size, s2.LiveBytes)
+
+	t.Run("double release", func(t *testing.T) {
+		assert.Panics(t, func() { ReleaseMatrix(a) })
+		assert.Panics(t, func() { ReleaseDense(b) })
+		s := ReadPoolStats()
+		assert.Equal(t, s2.DoubleReleases+2, s.DoubleReleases)
+		assert.Equal(t, s2.Live, s.Live)
+	})
+
+	t.Run("use after release", func(t *testing.T) {
+		assert.Panics(t, func() { a.Data() })
+		assert.Panics(t, func() { a.ScalarAt(0, 0) })
+		assert.Panics(t, func() { b.SetVecScalar(0, float.Interface(T(1))) })
+		assert.Panics(t, func() { b.Clone() })
+		assert.Panics(t, func() { a.Sum() })
+		assert.Panics(t, func() { a.Rows() })
+		assert.Panics(t, func() { c.Add(a) })
+		assert.Panics(t, func() { c.AddInPlace(b.T()) })
+		assert.Equal(t, s2.UsesAfterRelease+8, ReadPoolStats().UsesAfterRelease)
+
+		// The data were handed over to a new matrix.
+		d := NewDense[T](2, 3, []T{1, 2, 3, 4, 5, 6})
+		assert.Equal(t, []T{1, 2, 3, 4, 5, 6}, Data[T](d))
+		ReleaseMatrix(d)
+	})
+
+	ReleaseMatrix(c)
+	SetPoolTracking(false)
+	assert.Equal(t, s0.Live, ReadPoolStats().Live)
+}
+
+func TestPoolStats_HitRate(t *testing.T) {
+	assert.Equal(t, 0.0, PoolStats{}.HitRate())
+	assert.Equal(t, 0.75, PoolStats{Gets: 4, Hits: 3}.HitRate())
+}
@@ -0,0 +1,159 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// poolTracking is 1 if the Dense pools are tracked, 0 otherwise.
+var poolTracking int32
+
+// poolCounters are the counters of the Dense pools, updated while the
+// tracking is enabled (see SetPoolTracking). The live counters are indexed
+// by pool bucket.
+var poolCounters struct {
+	live             [65]int64
+	liveBytes        [65]int64
+	gets             uint64
+	getBytes         uint64
+	misses           uint64
+	doubleReleases   uint64
+	usesAfterRelease uint64
+}
+
+// SetPoolTracking enables or disables the tracking of the Dense matrices
+// obtained from the pools and released with ReleaseMatrix or ReleaseDense,
+// and returns the previous setting, so that it can be restored later.
+//
+// While the tracking is enabled, the pools collect the statistics reported
+// by ReadPoolStats, and each released matrix is left permanently empty and
+// marked as released, handing its data over to a new matrix in the pool.
+// This way, releasing it again or calling any of its methods panics, rather
+// than silently sharing the data with a newer matrix.
+//
+// The tracking is meant for debugging and profiling (it's enabled by
+// ag.StartProfiler), and it's disabled by default.
+func SetPoolTracking(enabled bool) bool {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	return atomic.SwapInt32(&poolTracking, v) != 0
+}
+
+func trackingPools() bool {
+	return atomic.LoadInt32(&poolTracking) != 0
+}
+
+// PoolStats are the statistics of the Dense pools, collected while the
+// tracking is enabled (see SetPoolTracking).
+type PoolStats struct {
+	// Live is the number of matrices obtained from the pools which have
+	// not been released yet.
+	Live int64
+	// LiveBytes is the size of the data of the live matrices, in bytes.
+	LiveBytes int64
+	// Buckets are the statistics of each pool bucket with live matrices,
+	// in ascending order of capacity.
+	Buckets []PoolBucketStats
+	// Gets is the number of matrices obtained from the pools.
+	Gets uint64
+	// GetBytes is the size of the data of the matrices obtained from the
+	// pools, in bytes, as requested rather than as allocated.
+	GetBytes uint64
+	// Hits is the number of matrices obtained from the pools by recycling
+	// a released one, rather than by allocating a new one.
+	Hits uint64
+	// DoubleReleases is the number of times a matrix was released after
+	// having been released already.
+	DoubleReleases uint64
+	// UsesAfterRelease is the number of times the methods of a matrix were
+	// called after its release.
+	UsesAfterRelease uint64
+}
+
+// PoolBucketStats are the statistics of a single pool bucket, holding the
+// matrices whose data have the same capacity.
+type PoolBucketStats struct {
+	// Capacity is the number of elements of the data of the matrices.
+	Capacity uint64
+	// Live is the number of live matrices in the bucket.
+	Live int64
+	// LiveBytes is the size of the data of the live matrices in the
+	// bucket, in bytes.
+	LiveBytes int64
+}
+
+// HitRate returns the ratio of Hits to Gets, or 0 if there are no Gets.
+func (s PoolStats) HitRate() float64 {
+	if s.Gets == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Gets)
+}
+
+// ReadPoolStats returns the statistics of the Dense pools of all the
+// types, collected while the tracking was enabled (see SetPoolTracking).
+//
+// The values are never reset: the statistics of a piece of code are
+// obtained by comparing the values read before and after it.
+func ReadPoolStats() PoolStats {
+	c := &poolCounters
+	gets := atomic.LoadUint64(&c.gets)
+	misses := atomic.LoadUint64(&c.misses)
+	s := PoolStats{
+		Gets:             gets,
+		GetBytes:         atomic.LoadUint64(&c.getBytes),
+		DoubleReleases:   atomic.LoadUint64(&c.doubleReleases),
+		UsesAfterRelease: atomic.LoadUint64(&c.usesAfterRelease),
+	}
+	if misses < gets {
+		s.Hits = gets - misses
+	}
+	for i := range c.live {
+		live := atomic.LoadInt64(&c.live[i])
+		if live == 0 {
+			continue
+		}
+		b := PoolBucketStats{
+			Capacity:  1<<i - 1,
+			Live:      live,
+			LiveBytes: atomic.LoadInt64(&c.liveBytes[i]),
+		}
+		s.Live += b.Live
+		s.LiveBytes += b.LiveBytes
+		s.Buckets = append(s.Buckets, b)
+	}
+	return s
+}
+
+// trackGet records a matrix obtained from the pool bucket.
+func trackGet[T float.DType](d *Dense[T], bucket int) {
+	d.flags |= denseIsTracked
+	atomic.AddUint64(&poolCounters.gets, 1)
+	atomic.AddUint64(&poolCounters.getBytes, uint64(len(d.data))*uint64(unsafe.Sizeof(T(0))))
+	atomic.AddInt64(&poolCounters.live[bucket], 1)
+	atomic.AddInt64(&poolCounters.liveBytes[bucket], int64(cap(d.data))*int64(unsafe.Sizeof(T(0))))
+}
+
+// trackPut records a matrix released to the pool bucket.
+func trackPut[T float.DType](d *Dense[T], bucket int) {
+	d.flags &= ^denseIsTracked
+	atomic.AddInt64(&poolCounters.live[bucket], -1)
+	atomic.AddInt64(&poolCounters.liveBytes[bucket], -int64(cap(d.data))*int64(unsafe.Sizeof(T(0))))
+}
+
+// checkReleased panics if the matrix has been released while the pools were
+// tracked (see SetPoolTracking).
+func (d *Dense[T]) checkReleased() {
+	if d.flags&denseIsReleased != 0 {
+		atomic.AddUint64(&poolCounters.usesAfterRelease, 1)
+		panic("mat: use of a Dense matrix after its release")
+	}
+}
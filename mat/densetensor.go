@@ -0,0 +1,1171 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// A DenseTensor is an N-dimensional Tensor implementation, backed by a
+// dense slice of values and a set of strides.
+//
+// Views (see for example Permute, Narrow and BroadcastTo) share the same
+// underlying data of the originating tensor. Only the tensors created
+// through the constructors of this package own their data and give it back
+// to the pool when released with ReleaseMatrix; releasing a view has no
+// effect.
+type DenseTensor[T float.DType] struct {
+	data    []T
+	shape   []int
+	strides []int
+	offset  int
+	// owner is the pooled Dense matrix the data comes from, if the tensor
+	// is responsible for releasing it.
+	owner *Dense[T]
+}
+
+// NewDenseTensor returns a new tensor of the given shape, initialized with
+// a copy of raw data in row-major order.
+//
+// The shape MUST not contain negative values, and the length of data MUST be
+// equal to the product of all dimensions, otherwise the function panics.
+func NewDenseTensor[T float.DType](shape []int, data []T) *DenseTensor[T] {
+	size := shapeSize(shape)
+	if len(data) != size {
+		panic(fmt.Sprintf("mat: wrong tensor shape. Elements size must be: %d", size))
+	}
+	d := densePool[T]().Get(projectedDims(shape))
+	copy(d.data, data)
+	return newTensorFromDense(d, shape)
+}
+
+// NewEmptyDenseTensor returns a new tensor of the given shape, initialized
+// with zeros.
+func NewEmptyDenseTensor[T float.DType](shape ...int) *DenseTensor[T] {
+	shapeSize(shape) // validation
+	return newTensorFromDense(densePool[T]().GetEmpty(projectedDims(shape)), shape)
+}
+
+// NewInitDenseTensor returns a new tensor of the given shape, initialized
+// with a constant value.
+func NewInitDenseTensor[T float.DType](v T, shape ...int) *DenseTensor[T] {
+	shapeSize(shape) // validation
+	d := densePool[T]().Get(projectedDims(shape))
+	data := d.data
+	for i := range data {
+		data[i] = v
+	}
+	return newTensorFromDense(d, shape)
+}
+
+// newTensorFromDense wraps the data of d into a contiguous tensor of the
+// given shape, taking the ownership of d if it comes from the pool.
+func newTensorFromDense[T float.DType](d *Dense[T], shape []int) *DenseTensor[T] {
+	t := &DenseTensor[T]{
+		data:    d.data,
+		shape:   append([]int{}, shape...),
+		strides: contiguousStrides(shape),
+	}
+	if d.flags&denseIsFromPool != 0 {
+		t.owner = d
+	}
+	return t
+}
+
+// denseAsTensor returns a rank-2 tensor view of d.
+func denseAsTensor[T float.DType](d *Dense[T]) *DenseTensor[T] {
+	shape := []int{d.rows, d.cols}
+	return &DenseTensor[T]{
+		data:    d.data,
+		shape:   shape,
+		strides: contiguousStrides(shape),
+	}
+}
+
+// Shape returns a copy of the size of each dimension.
+func (t *DenseTensor[T]) Shape() []int {
+	return append([]int{}, t.shape...)
+}
+
+// Strides returns a copy of the number of elements to skip in the
+// underlying data to move one step along each dimension.
+func (t *DenseTensor[T]) Strides() []int {
+	return append([]int{}, t.strides...)
+}
+
+// Rank returns the number of dimensions.
+func (t *DenseTensor[T]) Rank() int {
+	return len(t.shape)
+}
+
+// IsContiguous reports whether the elements are laid out in the
+// underlying data in row-major order, without gaps.
+func (t *DenseTensor[T]) IsContiguous() bool {
+	expected := 1
+	for k := len(t.shape) - 1; k >= 0; k-- {
+		if t.shape[k] == 0 {
+			return true
+		}
+		if t.shape[k] != 1 && t.strides[k] != expected {
+			return false
+		}
+		expected *= t.shape[k]
+	}
+	return true
+}
+
+// Item returns the value at the given multi-dimensional index.
+// It panics if the index is out of range.
+func (t *DenseTensor[T]) Item(index ...int) float.Float {
+	return float.Interface(t.data[t.offsetOf(index)])
+}
+
+// SetItem sets the value v at the given multi-dimensional index.
+// It panics if the index is out of range.
+func (t *DenseTensor[T]) SetItem(v float.Float, index ...int) {
+	t.data[t.offsetOf(index)] = float.ValueOf[T](v)
+}
+
+// ViewShape returns a Tensor with the given shape, sharing the same
+// underlying data whenever the receiver is contiguous, or a copy
+// otherwise. At most one dimension can be -1, in which case its size is
+// inferred from the total number of elements.
+func (t *DenseTensor[T]) ViewShape(shape ...int) Tensor {
+	shape = inferShape(shape, t.Size())
+	if !t.IsContiguous() {
+		return newTensorFromDense(t.contiguousDense(), shape)
+	}
+	return &DenseTensor[T]{
+		data:    t.data,
+		shape:   shape,
+		strides: contiguousStrides(shape),
+		offset:  t.offset,
+	}
+}
+
+// Permute returns a view of the Tensor with its dimensions reordered
+// according to the given axes.
+func (t *DenseTensor[T]) Permute(axes ...int) Tensor {
+	rank := len(t.shape)
+	if len(axes) != rank {
+		panic(fmt.Sprintf("mat: permutation must have %d axes, got %d", rank, len(axes)))
+	}
+	seen := make([]bool, rank)
+	shape := make([]int, rank)
+	strides := make([]int, rank)
+	for i, axis := range axes {
+		axis = normalizeAxis(axis, rank)
+		if seen[axis] {
+			panic(fmt.Sprintf("mat: repeated axis %d in permutation", axis))
+		}
+		seen[axis] = true
+		shape[i] = t.shape[axis]
+		strides[i] = t.strides[axis]
+	}
+	return t.view(shape, strides, t.offset)
+}
+
+// TransposeAxes returns a view of the Tensor with the two given
+// dimensions swapped.
+func (t *DenseTensor[T]) TransposeAxes(a, b int) Tensor {
+	rank := len(t.shape)
+	a, b = normalizeAxis(a, rank), normalizeAxis(b, rank)
+	shape, strides := t.Shape(), t.Strides()
+	shape[a], shape[b] = shape[b], shape[a]
+	strides[a], strides[b] = strides[b], strides[a]
+	return t.view(shape, strides, t.offset)
+}
+
+// Narrow returns a view of the Tensor restricted to the indices from
+// (inclusive) and to (exclusive) along the given axis.
+func (t *DenseTensor[T]) Narrow(axis, from, to int) Tensor {
+	axis = normalizeAxis(axis, len(t.shape))
+	if from < 0 || to > t.shape[axis] || to < from {
+		panic("mat: parameters are invalid or incompatible with the tensor dimensions")
+	}
+	shape := t.Shape()
+	shape[axis] = to - from
+	return t.view(shape, t.Strides(), t.offset+from*t.strides[axis])
+}
+
+// Select returns a view of the Tensor at the given index along the given
+// axis. The resulting Tensor has one dimension less than the receiver.
+func (t *DenseTensor[T]) Select(axis, index int) Tensor {
+	axis = normalizeAxis(axis, len(t.shape))
+	if index < 0 || index >= t.shape[axis] {
+		panic("mat: index out of range")
+	}
+	shape := append(t.Shape()[:axis], t.shape[axis+1:]...)
+	strides := append(t.Strides()[:axis], t.strides[axis+1:]...)
+	return t.view(shape, strides, t.offset+index*t.strides[axis])
+}
+
+// Unsqueeze returns a view of the Tensor with a new dimension of size 1
+// inserted at the given position.
+func (t *DenseTensor[T]) Unsqueeze(axis int) Tensor {
+	rank := len(t.shape)
+	axis = normalizeAxis(axis, rank+1)
+	stride := 1
+	if axis < rank {
+		stride = t.strides[axis] * t.shape[axis]
+	}
+	shape := make([]int, 0, rank+1)
+	shape = append(append(append(shape, t.shape[:axis]...), 1), t.shape[axis:]...)
+	strides := make([]int, 0, rank+1)
+	strides = append(append(append(strides, t.strides[:axis]...), stride), t.strides[axis:]...)
+	return t.view(shape, strides, t.offset)
+}
+
+// Squeeze returns a view of the Tensor with the given dimension removed.
+// It panics if the dimension has not size 1.
+func (t *DenseTensor[T]) Squeeze(axis int) Tensor {
+	axis = normalizeAxis(axis, len(t.shape))
+	if t.shape[axis] != 1 {
+		panic(fmt.Sprintf("mat: cannot squeeze axis %d with size %d", axis, t.shape[axis]))
+	}
+	return t.Select(axis, 0)
+}
+
+// BroadcastTo returns a view of the Tensor expanded to the given shape.
+// Dimensions of size 1 can be expanded to any size, and new leading
+// dimensions can be added; expanded dimensions have stride zero.
+func (t *DenseTensor[T]) BroadcastTo(shape ...int) Tensor {
+	rank := len(t.shape)
+	if len(shape) < rank {
+		panic("mat: cannot broadcast to a shape with fewer dimensions")
+	}
+	strides := make([]int, len(shape))
+	lead := len(shape) - rank
+	for i, v := range shape {
+		if i < lead {
+			continue // new dimension, stride zero
+		}
+		switch k := i - lead; {
+		case t.shape[k] == v:
+			strides[i] = t.strides[k]
+		case t.shape[k] == 1:
+			// expanded dimension, stride zero
+		default:
+			panic(fmt.Sprintf("mat: cannot broadcast shape %v to %v", t.shape, shape))
+		}
+	}
+	return t.view(append([]int{}, shape...), strides, t.offset)
+}
+
+// Contiguous returns the receiver itself if it is contiguous, otherwise
+// a contiguous copy of it.
+func (t *DenseTensor[T]) Contiguous() Tensor {
+	if t.IsContiguous() {
+		return t
+	}
+	return newTensorFromDense(t.contiguousDense(), t.shape)
+}
+
+// SumAxis returns a new Tensor containing the sum of the values along
+// the given axis, which is removed from the resulting shape.
+func (t *DenseTensor[T]) SumAxis(axis int) Tensor {
+	return t.reduceAxis(axis, false, func(acc, v T) T { return acc + v })
+}
+
+// MeanAxis returns a new Tensor containing the mean of the values along
+// the given axis, which is removed from the resulting shape.
+func (t *DenseTensor[T]) MeanAxis(axis int) Tensor {
+	out := t.reduceAxis(axis, false, func(acc, v T) T { return acc + v })
+	if n := t.shape[normalizeAxis(axis, len(t.shape))]; n > 0 {
+		out.ProdScalarInPlace(1 / float64(n))
+	}
+	return out
+}
+
+// MaxAxis returns a new Tensor containing the maximum values along
+// the given axis, which is removed from the resulting shape.
+func (t *DenseTensor[T]) MaxAxis(axis int) Tensor {
+	return t.reduceAxis(axis, true, func(acc, v T) T {
+		if v > acc {
+			return v
+		}
+		return acc
+	})
+}
+
+// MinAxis returns a new Tensor containing the minimum values along
+// the given axis, which is removed from the resulting shape.
+func (t *DenseTensor[T]) MinAxis(axis int) Tensor {
+	return t.reduceAxis(axis, true, func(acc, v T) T {
+		if v < acc {
+			return v
+		}
+		return acc
+	})
+}
+
+// reduceAxis applies the reduce function to all values along the given axis.
+// If nonEmpty is true, it panics when the axis has size zero.
+func (t *DenseTensor[T]) reduceAxis(axis int, nonEmpty bool, reduce func(acc, v T) T) *DenseTensor[T] {
+	rank := len(t.shape)
+	axis = normalizeAxis(axis, rank)
+	n := t.shape[axis]
+	if nonEmpty && n == 0 {
+		panic("mat: cannot reduce an empty axis")
+	}
+
+	// Move the reduced axis to the last position, so that its values
+	// are visited consecutively.
+	axes := make([]int, 0, rank)
+	for i := 0; i < rank; i++ {
+		if i != axis {
+			axes = append(axes, i)
+		}
+	}
+	moved := t.Permute(append(axes, axis)...).(*DenseTensor[T])
+
+	outShape := append(t.Shape()[:axis], t.shape[axis+1:]...)
+	out := NewEmptyDenseTensor[T](outShape...)
+	outData := out.data
+	data := t.data
+	moved.forEachOffset(func(i, off int) {
+		o, j := i/n, i%n
+		if j == 0 {
+			outData[o] = data[off]
+			return
+		}
+		outData[o] = reduce(outData[o], data[off])
+	})
+	return out
+}
+
+// view creates a new tensor sharing the receiver's data.
+func (t *DenseTensor[T]) view(shape, strides []int, offset int) *DenseTensor[T] {
+	return &DenseTensor[T]{
+		data:    t.data,
+		shape:   shape,
+		strides: strides,
+		offset:  offset,
+	}
+}
+
+// offsetOf returns the position in the underlying data of the element at
+// the given multi-dimensional index.
+func (t *DenseTensor[T]) offsetOf(index []int) int {
+	if len(index) != len(t.shape) {
+		panic(fmt.Sprintf("mat: expected %d indices, got %d", len(t.shape), len(index)))
+	}
+	off := t.offset
+	for k, i := range index {
+		if i < 0 || i >= t.shape[k] {
+			panic("mat: index out of range")
+		}
+		off += i * t.strides[k]
+	}
+	return off
+}
+
+// flatOffset returns the position in the underlying data of the i-th
+// element in row-major order.
+func (t *DenseTensor[T]) flatOffset(i int) int {
+	off := t.offset
+	for k := len(t.shape) - 1; k >= 0; k-- {
+		n := t.shape[k]
+		off += (i % n) * t.strides[k]
+		i /= n
+	}
+	return off
+}
+
+// forEachOffset calls fn for each element of the tensor in row-major order,
+// passing the sequential index of the element and its position in the
+// underlying data.
+func (t *DenseTensor[T]) forEachOffset(fn func(i, off int)) {
+	size := t.Size()
+	if size == 0 {
+		return
+	}
+	if t.IsContiguous() {
+		for i := 0; i < size; i++ {
+			fn(i, t.offset+i)
+		}
+		return
+	}
+	shape := t.shape
+	strides := t.strides
+	rank := len(shape)
+	index := make([]int, rank)
+	off := t.offset
+	for i := 0; i < size; i++ {
+		fn(i, off)
+		for k := rank - 1; k >= 0; k-- {
+			index[k]++
+			off += strides[k]
+			if index[k] < shape[k] {
+				break
+			}
+			off -= index[k] * strides[k]
+			index[k] = 0
+		}
+	}
+}
+
+// contiguousDense returns a new pooled Dense matrix, having the dimensions
+// of the two-dimensional projection, filled with a row-major copy of
+// the tensor values.
+func (t *DenseTensor[T]) contiguousDense() *Dense[T] {
+	out := densePool[T]().Get(t.Dims())
+	outData := out.data
+	data := t.data
+	t.forEachOffset(func(i, off int) {
+		outData[i] = data[off]
+	})
+	return out
+}
+
+// dense returns the two-dimensional projection of the tensor as a Dense
+// matrix, sharing the same data if the tensor is contiguous, otherwise
+// a (non-pooled) copy.
+func (t *DenseTensor[T]) dense() *Dense[T] {
+	rows, cols := t.Dims()
+	if t.IsContiguous() {
+		return &Dense[T]{
+			rows:  rows,
+			cols:  cols,
+			flags: denseIsView,
+			data:  t.data[t.offset : t.offset+rows*cols],
+		}
+	}
+	data := make([]T, rows*cols)
+	t.forEachOffset(func(i, off int) {
+		data[i] = t.data[off]
+	})
+	return &Dense[T]{rows: rows, cols: cols, data: data}
+}
+
+// inPlace calls fn with the two-dimensional projection of the tensor,
+// ensuring that any modification is reflected on the tensor values.
+func (t *DenseTensor[T]) inPlace(fn func(d *Dense[T])) {
+	d := t.dense()
+	fn(d)
+	if t.IsContiguous() {
+		return
+	}
+	data := d.data
+	t.forEachOffset(func(i, off int) {
+		t.data[off] = data[i]
+	})
+}
+
+// like converts a Dense result of an element-wise operation to a tensor
+// with the same shape of the receiver. Any other value is returned as is.
+func (t *DenseTensor[T]) like(m Matrix) Matrix {
+	d, ok := m.(*Dense[T])
+	if !ok {
+		return m
+	}
+	if r, c := t.Dims(); d.rows != r || d.cols != c {
+		return m
+	}
+	return newTensorFromDense(d, t.shape)
+}
+
+// needsBroadcast reports whether the element-wise operations between the
+// receiver and the other matrix require broadcasting. A non-Tensor matrix
+// with the same dimensions of the two-dimensional projection is always
+// considered compatible.
+func (t *DenseTensor[T]) needsBroadcast(other Matrix) bool {
+	if _, ok := other.(Tensor); !ok {
+		return !SameDims(t, other)
+	}
+	return !SameShape(t, other)
+}
+
+// broadcastOp applies the element-wise function op to the values of the
+// receiver and the other matrix, broadcast together, returning a new tensor.
+func (t *DenseTensor[T]) broadcastOp(other Matrix, op func(x, y T) T) Matrix {
+	o := AsTensor(other)
+	shape := BroadcastShapes(t.shape, o.Shape())
+	aData := Data[T](t.BroadcastTo(shape...))
+	bData := Data[T](o.BroadcastTo(shape...))
+	out := NewEmptyDenseTensor[T](shape...)
+	for i := range out.data {
+		out.data[i] = op(aData[i], bData[i])
+	}
+	return out
+}
+
+// checkSameShape panics if the other matrix is a Tensor with a shape
+// different from the receiver's. Non-Tensor matrices are checked against
+// the two-dimensional projection of the receiver by the Dense methods.
+func (t *DenseTensor[T]) checkSameShape(other Matrix) {
+	if _, ok := other.(Tensor); ok && !SameShape(t, other) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+}
+
+// reset replaces the content of the receiver with the data of d,
+// using the given shape.
+func (t *DenseTensor[T]) reset(d *Dense[T], shape []int) {
+	t.release()
+	*t = *newTensorFromDense(d, shape)
+}
+
+// release gives the owned data, if any, back to the pool.
+func (t *DenseTensor[T]) release() {
+	if t.owner == nil {
+		return
+	}
+	densePool[T]().Put(t.owner)
+	t.owner = nil
+}
+
+// Rows returns the number of rows of the two-dimensional projection.
+func (t *DenseTensor[T]) Rows() int {
+	r, _ := projectedDims(t.shape)
+	return r
+}
+
+// Columns returns the number of columns of the two-dimensional projection.
+func (t *DenseTensor[T]) Columns() int {
+	_, c := projectedDims(t.shape)
+	return c
+}
+
+// Dims returns the number of rows and columns of the two-dimensional
+// projection.
+func (t *DenseTensor[T]) Dims() (r, c int) {
+	return projectedDims(t.shape)
+}
+
+// Size returns the total number of elements.
+func (t *DenseTensor[T]) Size() int {
+	return shapeSize(t.shape)
+}
+
+// Data returns the values of the tensor, as a raw one-dimensional slice in
+// row-major order.
+//
+// If the tensor is contiguous, the data slice IS NOT a copy; otherwise
+// a copy is returned.
+func (t *DenseTensor[T]) Data() float.Slice {
+	return t.dense().Data()
+}
+
+// SetData sets the content of the tensor, copying the given raw
+// data representation as one-dimensional slice in row-major order.
+func (t *DenseTensor[T]) SetData(data float.Slice) {
+	t.inPlace(func(d *Dense[T]) { d.SetData(data) })
+}
+
+// ZerosLike returns a new tensor with the same shape of the receiver,
+// initialized with zeroes.
+func (t *DenseTensor[T]) ZerosLike() Matrix {
+	return NewEmptyDenseTensor[T](t.shape...)
+}
+
+// OnesLike returns a new tensor with the same shape of the receiver,
+// initialized with ones.
+func (t *DenseTensor[T]) OnesLike() Matrix {
+	return NewInitDenseTensor[T](1, t.shape...)
+}
+
+// Scalar returns the scalar value.
+// It panics if the tensor does not contain exactly one element.
+func (t *DenseTensor[T]) Scalar() float.Float {
+	if t.Size() != 1 {
+		panic("mat: expected scalar but the matrix contains more elements")
+	}
+	return float.Interface(t.data[t.offset])
+}
+
+// Zeros sets all the values of the tensor to zero.
+func (t *DenseTensor[T]) Zeros() {
+	t.forEachOffset(func(_, off int) {
+		t.data[off] = 0
+	})
+}
+
+// Set sets the scalar value from a 1×1 matrix at row r and column c of
+// the two-dimensional projection.
+// It panics if the given matrix is not 1×1, or if indices are out of range.
+func (t *DenseTensor[T]) Set(r int, c int, m Matrix) {
+	t.data[t.projectedOffset(r, c)] = float.ValueOf[T](m.Scalar())
+}
+
+// At returns the value at row r and column c of the two-dimensional
+// projection as a 1×1 matrix.
+// It panics if the given indices are out of range.
+func (t *DenseTensor[T]) At(r int, c int) Matrix {
+	return NewScalar(t.data[t.projectedOffset(r, c)])
+}
+
+// SetScalar sets the value v at row r and column c of the two-dimensional
+// projection.
+// It panics if the given indices are out of range.
+func (t *DenseTensor[T]) SetScalar(r int, c int, v float.Float) {
+	t.data[t.projectedOffset(r, c)] = float.ValueOf[T](v)
+}
+
+// ScalarAt returns the value at row r and column c of the two-dimensional
+// projection.
+// It panics if the given indices are out of range.
+func (t *DenseTensor[T]) ScalarAt(r int, c int) float.Float {
+	return float.Interface(t.data[t.projectedOffset(r, c)])
+}
+
+// SetVec sets the scalar value from a 1×1 matrix at position i of a
+// vector. It panics if the receiver is not a vector, or the given matrix is
+// not 1×1, or the position is out of range.
+func (t *DenseTensor[T]) SetVec(i int, m Matrix) {
+	t.data[t.vecOffset(i)] = float.ValueOf[T](m.Scalar())
+}
+
+// AtVec returns the value at position i of a vector as a 1×1 matrix.
+// It panics if the receiver is not a vector or the position is out of range.
+func (t *DenseTensor[T]) AtVec(i int) Matrix {
+	return NewScalar(t.data[t.vecOffset(i)])
+}
+
+// SetVecScalar sets the value v at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (t *DenseTensor[T]) SetVecScalar(i int, v float.Float) {
+	t.data[t.vecOffset(i)] = float.ValueOf[T](v)
+}
+
+// ScalarAtVec returns the value at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (t *DenseTensor[T]) ScalarAtVec(i int) float.Float {
+	return float.Interface(t.data[t.vecOffset(i)])
+}
+
+func (t *DenseTensor[T]) projectedOffset(r, c int) int {
+	rows, cols := t.Dims()
+	if r < 0 || r >= rows {
+		panic("mat: 'r' argument out of range")
+	}
+	if c < 0 || c >= cols {
+		panic("mat: 'c' argument out of range")
+	}
+	return t.flatOffset(r*cols + c)
+}
+
+func (t *DenseTensor[T]) vecOffset(i int) int {
+	if !IsVector(t) {
+		panic("mat: expected vector")
+	}
+	if i < 0 || i >= t.Size() {
+		panic("mat: 'i' argument out of range")
+	}
+	return t.flatOffset(i)
+}
+
+// ExtractRow returns a copy of the i-th row of the two-dimensional
+// projection, as a row vector (1×cols).
+func (t *DenseTensor[T]) ExtractRow(i int) Matrix {
+	return t.dense().ExtractRow(i)
+}
+
+// ExtractColumn returns a copy of the i-th column of the two-dimensional
+// projection, as a column vector (rows×1).
+func (t *DenseTensor[T]) ExtractColumn(i int) Matrix {
+	return t.dense().ExtractColumn(i)
+}
+
+// View returns a new rank-2 tensor sharing the same underlying data,
+// if the receiver is contiguous, otherwise a copy.
+func (t *DenseTensor[T]) View(rows, cols int) Matrix {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	return t.ViewShape(rows, cols)
+}
+
+// Slice returns a new matrix obtained by slicing the two-dimensional
+// projection across the given positions. The parameters "fromRow" and
+// "fromCol" are inclusive, while "toRow" and "toCol" are exclusive.
+func (t *DenseTensor[T]) Slice(fromRow, fromCol, toRow, toCol int) Matrix {
+	return t.dense().Slice(fromRow, fromCol, toRow, toCol)
+}
+
+// Reshape returns a copy of the tensor as a rank-2 tensor.
+// It panics if the dimensions are incompatible.
+func (t *DenseTensor[T]) Reshape(rows, cols int) Matrix {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if rows*cols != t.Size() {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Size (rows*cols) must be: %d", t.Size()))
+	}
+	return newTensorFromDense(t.contiguousDense(), []int{rows, cols})
+}
+
+// ReshapeInPlace changes the shape of the tensor in place to rows×cols,
+// and returns the tensor itself. A non-contiguous tensor is made contiguous
+// first, detaching it from the data it was sharing.
+// It panics if the dimensions are incompatible.
+func (t *DenseTensor[T]) ReshapeInPlace(rows, cols int) Matrix {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if rows*cols != t.Size() {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Size (rows*cols) must be: %d", t.Size()))
+	}
+	t.setShapeInPlace([]int{rows, cols})
+	return t
+}
+
+// Flatten creates a new row vector (1×size) corresponding to the
+// "flattened" row-major ordered representation of the tensor.
+func (t *DenseTensor[T]) Flatten() Matrix {
+	return t.Reshape(1, t.Size())
+}
+
+// FlattenInPlace changes the shape of the tensor in place to 1×size,
+// and returns the tensor itself.
+func (t *DenseTensor[T]) FlattenInPlace() Matrix {
+	t.setShapeInPlace([]int{1, t.Size()})
+	return t
+}
+
+func (t *DenseTensor[T]) setShapeInPlace(shape []int) {
+	if !t.IsContiguous() {
+		t.reset(t.contiguousDense(), shape)
+		return
+	}
+	t.shape = shape
+	t.strides = contiguousStrides(shape)
+}
+
+// ResizeVector returns a resized copy of the vector.
+//
+// If the new size is smaller than the input vector, the remaining tail
+// elements are removed. If it's bigger, the additional tail elements
+// are set to zero.
+func (t *DenseTensor[T]) ResizeVector(newSize int) Matrix {
+	return t.dense().ResizeVector(newSize)
+}
+
+// T returns the transpose of the two-dimensional projection.
+func (t *DenseTensor[T]) T() Matrix {
+	return t.dense().T()
+}
+
+// TransposeInPlace transposes the two-dimensional projection of the tensor
+// in place, and returns the tensor itself. The resulting tensor has rank 2.
+func (t *DenseTensor[T]) TransposeInPlace() Matrix {
+	rows, cols := t.Dims()
+	t.reset(t.dense().T().(*Dense[T]), []int{cols, rows})
+	return t
+}
+
+// Add returns the addition between the receiver and another matrix.
+func (t *DenseTensor[T]) Add(other Matrix) Matrix {
+	if t.needsBroadcast(other) {
+		return t.broadcastOp(other, addOp[T])
+	}
+	return t.like(t.dense().Add(other))
+}
+
+// AddInPlace performs the in-place addition with the other matrix.
+func (t *DenseTensor[T]) AddInPlace(other Matrix) Matrix {
+	t.checkSameShape(other)
+	t.inPlace(func(d *Dense[T]) { d.AddInPlace(other) })
+	return t
+}
+
+// AddScalar performs the addition between the tensor and the given value.
+func (t *DenseTensor[T]) AddScalar(n float64) Matrix {
+	return t.like(t.dense().AddScalar(n))
+}
+
+// AddScalarInPlace adds the scalar to all values of the tensor.
+func (t *DenseTensor[T]) AddScalarInPlace(n float64) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.AddScalarInPlace(n) })
+	return t
+}
+
+// Sub returns the subtraction of the other matrix from the receiver.
+func (t *DenseTensor[T]) Sub(other Matrix) Matrix {
+	if t.needsBroadcast(other) {
+		return t.broadcastOp(other, subOp[T])
+	}
+	return t.like(t.dense().Sub(other))
+}
+
+// SubInPlace performs the in-place subtraction with the other matrix.
+func (t *DenseTensor[T]) SubInPlace(other Matrix) Matrix {
+	t.checkSameShape(other)
+	t.inPlace(func(d *Dense[T]) { d.SubInPlace(other) })
+	return t
+}
+
+// SubScalar performs a subtraction between the tensor and the given value.
+func (t *DenseTensor[T]) SubScalar(n float64) Matrix {
+	return t.like(t.dense().SubScalar(n))
+}
+
+// SubScalarInPlace subtracts the scalar from the receiver's values.
+func (t *DenseTensor[T]) SubScalarInPlace(n float64) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.SubScalarInPlace(n) })
+	return t
+}
+
+// Prod performs the element-wise product between the receiver and the other matrix.
+func (t *DenseTensor[T]) Prod(other Matrix) Matrix {
+	if t.needsBroadcast(other) {
+		return t.broadcastOp(other, prodOp[T])
+	}
+	return t.like(t.dense().Prod(other))
+}
+
+// ProdInPlace performs the in-place element-wise product with the other matrix.
+func (t *DenseTensor[T]) ProdInPlace(other Matrix) Matrix {
+	t.checkSameShape(other)
+	t.inPlace(func(d *Dense[T]) { d.ProdInPlace(other) })
+	return t
+}
+
+// ProdScalar returns the multiplication between the tensor and the given value.
+func (t *DenseTensor[T]) ProdScalar(n float64) Matrix {
+	return t.like(t.dense().ProdScalar(n))
+}
+
+// ProdScalarInPlace performs the in-place multiplication between the
+// tensor and the given value.
+func (t *DenseTensor[T]) ProdScalarInPlace(n float64) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.ProdScalarInPlace(n) })
+	return t
+}
+
+// ProdMatrixScalarInPlace multiplies the given matrix with the value,
+// storing the result in the receiver.
+func (t *DenseTensor[T]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.ProdMatrixScalarInPlace(m, n) })
+	return t
+}
+
+// Div returns the result of the element-wise division of the receiver by the other matrix.
+func (t *DenseTensor[T]) Div(other Matrix) Matrix {
+	if t.needsBroadcast(other) {
+		return t.broadcastOp(other, divOp[T])
+	}
+	return t.like(t.dense().Div(other))
+}
+
+// DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
+func (t *DenseTensor[T]) DivInPlace(other Matrix) Matrix {
+	t.checkSameShape(other)
+	t.inPlace(func(d *Dense[T]) { d.DivInPlace(other) })
+	return t
+}
+
+// Mul performs the multiplication row by column of the two-dimensional
+// projection with the other matrix.
+func (t *DenseTensor[T]) Mul(other Matrix) Matrix {
+	return t.dense().Mul(other)
+}
+
+// MulT performs the matrix multiplication row by column of the transposed
+// two-dimensional projection with the other matrix.
+func (t *DenseTensor[T]) MulT(other Matrix) Matrix {
+	return t.dense().MulT(other)
+}
+
+// DotUnitary returns the dot product of two vectors as a scalar Matrix.
+func (t *DenseTensor[T]) DotUnitary(other Matrix) Matrix {
+	return t.dense().DotUnitary(other)
+}
+
+// ClipInPlace clips in place each value of the tensor.
+func (t *DenseTensor[T]) ClipInPlace(min, max float64) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.ClipInPlace(min, max) })
+	return t
+}
+
+// Maximum returns a new tensor containing the element-wise maxima.
+func (t *DenseTensor[T]) Maximum(other Matrix) Matrix {
+	return t.like(t.dense().Maximum(other))
+}
+
+// Minimum returns a new tensor containing the element-wise minima.
+func (t *DenseTensor[T]) Minimum(other Matrix) Matrix {
+	return t.like(t.dense().Minimum(other))
+}
+
+// Abs returns a new tensor applying the absolute value function to all elements.
+func (t *DenseTensor[T]) Abs() Matrix {
+	return t.like(t.dense().Abs())
+}
+
+// Pow returns a new tensor, applying the power function with given exponent
+// to all elements of the tensor.
+func (t *DenseTensor[T]) Pow(power float64) Matrix {
+	return t.like(t.dense().Pow(power))
+}
+
+// Sqrt returns a new tensor applying the square root function to all elements.
+func (t *DenseTensor[T]) Sqrt() Matrix {
+	return t.like(t.dense().Sqrt())
+}
+
+// Log returns a new tensor applying the natural logarithm function to each element.
+func (t *DenseTensor[T]) Log() Matrix {
+	return t.like(t.dense().Log())
+}
+
+// Exp returns a new tensor applying the base-e exponential function to each element.
+func (t *DenseTensor[T]) Exp() Matrix {
+	return t.like(t.dense().Exp())
+}
+
+// Sigmoid returns a new tensor applying the sigmoid function to each element.
+func (t *DenseTensor[T]) Sigmoid() Matrix {
+	return t.like(t.dense().Sigmoid())
+}
+
+// Sum returns the sum of all values of the tensor as a scalar Matrix.
+func (t *DenseTensor[T]) Sum() Matrix {
+	return t.dense().Sum()
+}
+
+// Max returns the maximum value of the tensor as a scalar Matrix.
+func (t *DenseTensor[T]) Max() Matrix {
+	return t.dense().Max()
+}
+
+// Min returns the minimum value of the tensor as a scalar Matrix.
+func (t *DenseTensor[T]) Min() Matrix {
+	return t.dense().Min()
+}
+
+// ArgMax returns the index of the vector's element with the maximum value.
+func (t *DenseTensor[T]) ArgMax() int {
+	return t.dense().ArgMax()
+}
+
+// Softmax applies the softmax function to the vector, returning the
+// result as a new column vector.
+func (t *DenseTensor[T]) Softmax() Matrix {
+	return t.dense().Softmax()
+}
+
+// CumSum computes the cumulative sum of the vector's elements, returning
+// the result as a new column vector.
+func (t *DenseTensor[T]) CumSum() Matrix {
+	return t.dense().CumSum()
+}
+
+// Range creates a new vector initialized with data extracted from the
+// tensor values, from start (inclusive) to end (exclusive).
+func (t *DenseTensor[T]) Range(start, end int) Matrix {
+	return t.dense().Range(start, end)
+}
+
+// SplitV splits the vector in N chunks of given sizes,
+// so that N[i] has size sizes[i].
+func (t *DenseTensor[T]) SplitV(sizes ...int) []Matrix {
+	return t.dense().SplitV(sizes...)
+}
+
+// Augment places the identity matrix at the end of the two-dimensional
+// projection.
+func (t *DenseTensor[T]) Augment() Matrix {
+	return t.dense().Augment()
+}
+
+// SwapInPlace swaps two rows of the two-dimensional projection in place.
+func (t *DenseTensor[T]) SwapInPlace(r1, r2 int) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.SwapInPlace(r1, r2) })
+	return t
+}
+
+// PadRows returns a copy of the two-dimensional projection with n
+// additional tail rows. The additional elements are set to zero.
+func (t *DenseTensor[T]) PadRows(n int) Matrix {
+	return t.dense().PadRows(n)
+}
+
+// PadColumns returns a copy of the two-dimensional projection with n
+// additional tail columns. The additional elements are set to zero.
+func (t *DenseTensor[T]) PadColumns(n int) Matrix {
+	return t.dense().PadColumns(n)
+}
+
+// AppendRows returns a copy of the two-dimensional projection with len(vs)
+// additional tail rows, being each new row filled with the values of each
+// given vector.
+func (t *DenseTensor[T]) AppendRows(vs ...Matrix) Matrix {
+	return t.dense().AppendRows(vs...)
+}
+
+// Norm returns the vector's norm. Use pow = 2.0 to compute the Euclidean norm.
+// The result is a scalar Matrix.
+func (t *DenseTensor[T]) Norm(pow float64) Matrix {
+	return t.dense().Norm(pow)
+}
+
+// Pivoting returns the partial pivots of a square matrix to reorder rows.
+// Considerate square sub-matrix from element (offset, offset).
+func (t *DenseTensor[T]) Pivoting(row int) (Matrix, bool, [2]int) {
+	return t.dense().Pivoting(row)
+}
+
+// Normalize2 normalizes an array with the Euclidean norm.
+func (t *DenseTensor[T]) Normalize2() Matrix {
+	return t.like(t.dense().Normalize2())
+}
+
+// LU performs lower–upper (LU) decomposition of the two-dimensional
+// projection, which must be square.
+func (t *DenseTensor[T]) LU() (l, u, p Matrix) {
+	return t.dense().LU()
+}
+
+// Inverse returns the inverse of the two-dimensional projection, which
+// must be square.
+func (t *DenseTensor[T]) Inverse() Matrix {
+	return t.dense().Inverse()
+}
+
+// VecForEach calls fn for each element of the vector.
+// It panics if the receiver is not a vector.
+func (t *DenseTensor[T]) VecForEach(fn func(i int, v float64)) {
+	t.dense().VecForEach(fn)
+}
+
+// Apply creates a new tensor executing the unary function fn over the
+// elements of the two-dimensional projection.
+func (t *DenseTensor[T]) Apply(fn func(r, c int, v float64) float64) Matrix {
+	return t.like(t.dense().Apply(fn))
+}
+
+// ApplyInPlace executes the unary function fn over the matrix a,
+// and stores the result in the receiver, returning the receiver itself.
+func (t *DenseTensor[T]) ApplyInPlace(fn func(r, c int, v float64) float64, a Matrix) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.ApplyInPlace(fn, a) })
+	return t
+}
+
+// ApplyWithAlpha creates a new tensor executing the unary function fn,
+// taking additional parameters alpha.
+func (t *DenseTensor[T]) ApplyWithAlpha(fn func(r, c int, v float64, alpha ...float64) float64, alpha ...float64) Matrix {
+	return t.like(t.dense().ApplyWithAlpha(fn, alpha...))
+}
+
+// ApplyWithAlphaInPlace executes the unary function fn over the matrix a,
+// taking additional parameters alpha, and stores the result in the
+// receiver, returning the receiver itself.
+func (t *DenseTensor[T]) ApplyWithAlphaInPlace(fn func(r, c int, v float64, alpha ...float64) float64, a Matrix, alpha ...float64) Matrix {
+	t.inPlace(func(d *Dense[T]) { d.ApplyWithAlphaInPlace(fn, a, alpha...) })
+	return t
+}
+
+// DoNonZero calls a function for each non-zero element of the
+// two-dimensional projection.
+// The parameters of the function are the element's indices and value.
+func (t *DenseTensor[T]) DoNonZero(fn func(r, c int, v float64)) {
+	t.dense().DoNonZero(fn)
+}
+
+// DoVecNonZero calls a function for each non-zero element of the vector.
+// The parameters of the function are the element's index and value.
+func (t *DenseTensor[T]) DoVecNonZero(fn func(i int, v float64)) {
+	t.dense().DoVecNonZero(fn)
+}
+
+// Clone returns a new contiguous tensor with the same shape of the
+// receiver, copying all its values.
+func (t *DenseTensor[T]) Clone() Matrix {
+	return newTensorFromDense(t.contiguousDense(), t.shape)
+}
+
+// Copy copies the data from the other matrix to the receiver.
+// It panics if the matrices have different dimensions.
+func (t *DenseTensor[T]) Copy(other Matrix) {
+	t.inPlace(func(d *Dense[T]) { d.Copy(other) })
+}
+
+// String returns a string representation of the tensor.
+func (t *DenseTensor[T]) String() string {
+	dims := make([]string, len(t.shape))
+	for i, v := range t.shape {
+		dims[i] = fmt.Sprint(v)
+	}
+	return fmt.Sprintf("Matrix|DenseTensor[%T](%s)%v", T(0), strings.Join(dims, "×"), t.dense().data)
+}
+
+// NewMatrix creates a new Dense matrix, of the same data type of the
+// receiver, of size rows×cols, initialized with a copy of raw data.
+func (t *DenseTensor[T]) NewMatrix(rows, cols int, data float.Slice) Matrix {
+	return NewDense[T](rows, cols, float.SliceValueOf[T](data))
+}
+
+// NewVec creates a new column vector (len(data)×1), of the same data type of
+// the receiver, initialized with a copy of raw data.
+func (t *DenseTensor[T]) NewVec(data float.Slice) Matrix {
+	return NewVecDense[T](float.SliceValueOf[T](data))
+}
+
+// NewScalar creates a new 1×1 matrix, of the same data type of the receiver,
+// containing the given value.
+func (t *DenseTensor[T]) NewScalar(v float64) Matrix {
+	return NewScalar(T(v))
+}
+
+// NewEmptyVec creates a new vector, of the same data type of the receiver,
+// with dimensions size×1, initialized with zeros.
+func (t *DenseTensor[T]) NewEmptyVec(size int) Matrix {
+	return NewEmptyVecDense[T](size)
+}
+
+// NewEmptyMatrix creates a new rows×cols matrix, of the same data type of
+// the receiver, initialized with zeros.
+func (t *DenseTensor[T]) NewEmptyMatrix(rows, cols int) Matrix {
+	return NewEmptyDense[T](rows, cols)
+}
+
+// NewInitMatrix creates a new rows×cols dense matrix, of the same data type
+// of the receiver, initialized with a constant value.
+func (t *DenseTensor[T]) NewInitMatrix(rows, cols int, v float64) Matrix {
+	return NewInitDense(rows, cols, T(v))
+}
+
+// NewInitFuncMatrix creates a new rows×cols dense matrix, of the same data
+// type of the receiver, initialized with the values returned from the
+// callback function.
+func (t *DenseTensor[T]) NewInitFuncMatrix(rows, cols int, fn func(r, c int) float64) Matrix {
+	return NewInitFuncDense(rows, cols, func(r, c int) T {
+		return T(fn(r, c))
+	})
+}
+
+// NewInitVec creates a new column vector (size×1), of the same data type of
+// the receiver, initialized with a constant value.
+func (t *DenseTensor[T]) NewInitVec(size int, v float64) Matrix {
+	return NewInitVecDense(size, T(v))
+}
+
+// NewIdentityMatrix creates a new square identity matrix (size×size), of
+// the same data type of the receiver.
+func (t *DenseTensor[T]) NewIdentityMatrix(size int) Matrix {
+	return NewIdentityDense[T](size)
+}
+
+// NewOneHotVec creates a new one-hot column vector (size×1), of the same
+// data type of the receiver.
+func (t *DenseTensor[T]) NewOneHotVec(size int, oneAt int) Matrix {
+	return NewOneHotVecDense[T](size, oneAt)
+}
+
+// NewConcatV creates a new column vector, of the same data type of the
+// receiver, concatenating two or more vectors "vertically".
+func (t *DenseTensor[T]) NewConcatV(vs ...Matrix) Matrix {
+	return ConcatV[T](vs...)
+}
+
+// NewStack creates a new matrix, of the same data type of the receiver,
+// stacking two or more vectors of the same size on top of each other.
+func (t *DenseTensor[T]) NewStack(vs ...Matrix) Matrix {
+	return Stack[T](vs...)
+}
@@ -0,0 +1,340 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Tensor = &DenseTensor[float32]{}
+var _ Tensor = &DenseTensor[float64]{}
+
+func TestNewDenseTensor(t *testing.T) {
+	t.Run("float32", testNewDenseTensor[float32])
+	t.Run("float64", testNewDenseTensor[float64])
+}
+
+func testNewDenseTensor[T float.DType](t *testing.T) {
+	t.Run("wrong data size", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewDenseTensor([]int{2, 3}, []T{1, 2, 3})
+		})
+	})
+
+	t.Run("negative dimension", func(t *testing.T) {
+		require.Panics(t, func() {
+			NewEmptyDenseTensor[T](2, -1)
+		})
+	})
+
+	testCases := []struct {
+		shape   []int
+		strides []int
+		rows    int
+		cols    int
+	}{
+		{[]int{}, []int{}, 1, 1},
+		{[]int{4}, []int{1}, 4, 1},
+		{[]int{2, 3}, []int{3, 1}, 2, 3},
+		{[]int{2, 3, 4}, []int{12, 4, 1}, 2, 12},
+		{[]int{0, 3}, []int{3, 1}, 0, 3},
+	}
+	for _, tc := range testCases {
+		d := NewEmptyDenseTensor[T](tc.shape...)
+		assert.Equal(t, tc.shape, d.Shape())
+		assert.Equal(t, tc.strides, d.Strides())
+		assert.Equal(t, len(tc.shape), d.Rank())
+		assert.Equal(t, tc.rows, d.Rows())
+		assert.Equal(t, tc.cols, d.Columns())
+		assert.Equal(t, tc.rows*tc.cols, d.Size())
+		assert.True(t, d.IsContiguous())
+	}
+
+	data := []T{1, 2, 3, 4, 5, 6}
+	d := NewDenseTensor([]int{3, 1, 2}, data)
+	data[0] = 42 // modifying data must not modify d
+	assert.Equal(t, []T{1, 2, 3, 4, 5, 6}, Data[T](d))
+	assert.Equal(t, T(6), float.ValueOf[T](d.Item(2, 0, 1)))
+}
+
+func TestDenseTensor_ItemAndSetItem(t *testing.T) {
+	t.Run("float32", testDenseTensorItemAndSetItem[float32])
+	t.Run("float64", testDenseTensorItemAndSetItem[float64])
+}
+
+func testDenseTensorItemAndSetItem[T float.DType](t *testing.T) {
+	d := NewEmptyDenseTensor[T](2, 3, 4)
+	d.SetItem(float.Interface(T(7)), 1, 2, 3)
+	assert.Equal(t, T(7), float.ValueOf[T](d.Item(1, 2, 3)))
+	assert.Equal(t, T(7), Data[T](d)[23])
+
+	require.Panics(t, func() { d.Item(1, 2) })
+	require.Panics(t, func() { d.Item(2, 0, 0) })
+	require.Panics(t, func() { d.SetItem(float.Interface(T(1)), 0, -1, 0) })
+}
+
+func TestDenseTensor_ViewShape(t *testing.T) {
+	t.Run("float32", testDenseTensorViewShape[float32])
+	t.Run("float64", testDenseTensorViewShape[float64])
+}
+
+func testDenseTensorViewShape[T float.DType](t *testing.T) {
+	d := NewDenseTensor([]int{2, 6}, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+
+	v := d.ViewShape(3, -1, 2)
+	assert.Equal(t, []int{3, 2, 2}, v.Shape())
+	v.SetItem(float.Interface(T(42)), 0, 0, 0)
+	assert.Equal(t, T(42), float.ValueOf[T](d.Item(0, 0)), "the view must share the data")
+
+	require.Panics(t, func() { d.ViewShape(5, -1) })
+	require.Panics(t, func() { d.ViewShape(-1, -1) })
+	require.Panics(t, func() { d.ViewShape(4, 4) })
+
+	p := d.TransposeAxes(0, 1)
+	c := p.ViewShape(12)
+	assert.Equal(t, []T{42, 7, 2, 8, 3, 9, 4, 10, 5, 11, 6, 12}, Data[T](c))
+	c.SetItem(float.Interface(T(0)), 0)
+	assert.Equal(t, T(42), float.ValueOf[T](d.Item(0, 0)), "a non-contiguous tensor is copied")
+}
+
+func TestDenseTensor_Permute(t *testing.T) {
+	t.Run("float32", testDenseTensorPermute[float32])
+	t.Run("float64", testDenseTensorPermute[float64])
+}
+
+func testDenseTensorPermute[T float.DType](t *testing.T) {
+	d := NewDenseTensor([]int{2, 3, 2}, []T{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	})
+
+	p := d.Permute(2, 0, 1)
+	assert.Equal(t, []int{2, 2, 3}, p.Shape())
+	assert.Equal(t, []int{1, 6, 2}, p.Strides())
+	assert.False(t, p.IsContiguous())
+	assert.Equal(t, []T{
+		1, 3, 5, 7, 9, 11,
+		2, 4, 6, 8, 10, 12,
+	}, Data[T](p))
+	assert.Equal(t, 2, p.Rows())
+	assert.Equal(t, 6, p.Columns())
+
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 2; k++ {
+				assert.Equal(t, d.Item(i, j, k), p.Item(k, i, j))
+			}
+		}
+	}
+
+	c := p.Contiguous()
+	assert.True(t, c.IsContiguous())
+	assert.Equal(t, Data[T](p), Data[T](c))
+	assert.Same(t, c, c.Contiguous())
+
+	back := p.Permute(1, 2, 0)
+	assert.Equal(t, d.Shape(), back.Shape())
+	assert.Equal(t, Data[T](d), Data[T](back))
+
+	require.Panics(t, func() { d.Permute(0, 1) })
+	require.Panics(t, func() { d.Permute(0, 1, 1) })
+	require.Panics(t, func() { d.Permute(0, 1, 3) })
+}
+
+func TestDenseTensor_NarrowAndSelect(t *testing.T) {
+	t.Run("float32", testDenseTensorNarrowAndSelect[float32])
+	t.Run("float64", testDenseTensorNarrowAndSelect[float64])
+}
+
+func testDenseTensorNarrowAndSelect[T float.DType](t *testing.T) {
+	d := NewDenseTensor([]int{3, 4}, []T{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+	})
+
+	n := d.Narrow(1, 1, 3)
+	assert.Equal(t, []int{3, 2}, n.Shape())
+	assert.Equal(t, []T{2, 3, 6, 7, 10, 11}, Data[T](n))
+
+	s := d.Select(0, 1)
+	assert.Equal(t, []int{4}, s.Shape())
+	assert.Equal(t, []T{5, 6, 7, 8}, Data[T](s))
+
+	s = d.Select(-1, 2)
+	assert.Equal(t, []int{3}, s.Shape())
+	assert.Equal(t, []T{3, 7, 11}, Data[T](s))
+
+	// In-place operations on views are reflected on the original tensor.
+	n.ProdScalarInPlace(10)
+	assert.Equal(t, []T{
+		1, 20, 30, 4,
+		5, 60, 70, 8,
+		9, 100, 110, 12,
+	}, Data[T](d))
+
+	require.Panics(t, func() { d.Narrow(0, 2, 4) })
+	require.Panics(t, func() { d.Select(1, 4) })
+}
+
+func TestDenseTensor_UnsqueezeAndSqueeze(t *testing.T) {
+	t.Run("float32", testDenseTensorUnsqueezeAndSqueeze[float32])
+	t.Run("float64", testDenseTensorUnsqueezeAndSqueeze[float64])
+}
+
+func testDenseTensorUnsqueezeAndSqueeze[T float.DType](t *testing.T) {
+	d := NewDenseTensor([]int{2, 3}, []T{1, 2, 3, 4, 5, 6})
+
+	assert.Equal(t, []int{1, 2, 3}, d.Unsqueeze(0).Shape())
+	assert.Equal(t, []int{2, 1, 3}, d.Unsqueeze(1).Shape())
+	assert.Equal(t, []int{2, 3, 1}, d.Unsqueeze(-1).Shape())
+	assert.True(t, d.Unsqueeze(1).IsContiguous())
+
+	s := d.Unsqueeze(1).Squeeze(1)
+	assert.Equal(t, []int{2, 3}, s.Shape())
+	assert.Equal(t, Data[T](d), Data[T](s))
+
+	require.Panics(t, func() { d.Squeeze(0) })
+}
+
+func TestDenseTensor_BroadcastTo(t *testing.T) {
+	t.Run("float32", testDenseTensorBroadcastTo[float32])
+	t.Run("float64", testDenseTensorBroadcastTo[float64])
+}
+
+func testDenseTensorBroadcastTo[T float.DType](t *testing.T) {
+	d := NewDenseTensor([]int{3, 1}, []T{1, 2, 3})
+
+	b := d.BroadcastTo(2, 3, 2)
+	assert.Equal(t, []int{2, 3, 2}, b.Shape())
+	assert.Equal(t, []int{0, 1, 0}, b.Strides())
+	assert.Equal(t, []T{
+		1, 1, 2, 2, 3, 3,
+		1, 1, 2, 2, 3, 3,
+	}, Data[T](b))
+
+	require.Panics(t, func() { d.BroadcastTo(2, 2) })
+	require.Panics(t, func() { d.BroadcastTo(3) })
+}
+
+func TestDenseTensor_ReduceAxis(t *testing.T) {
+	t.Run("float32", testDenseTensorReduceAxis[float32])
+	t.Run("float64", testDenseTensorReduceAxis[float64])
+}
+
+func testDenseTensorReduceAxis[T float.DType](t *testing.T) {
+	d := NewDenseTensor([]int{2, 3, 2}, []T{
+		1, 2, 3, 4, 5, 6,
+		7, 8, 9, 10, 11, 12,
+	})
+
+	testCases := []struct {
+		name     string
+		axis     int
+		fn       func(axis int) Tensor
+		shape    []int
+		expected []T
+	}{
+		{"sum 0", 0, d.SumAxis, []int{3, 2}, []T{8, 10, 12, 14, 16, 18}},
+		{"sum 1", 1, d.SumAxis, []int{2, 2}, []T{9, 12, 27, 30}},
+		{"sum -1", -1, d.SumAxis, []int{2, 3}, []T{3, 7, 11, 15, 19, 23}},
+		{"mean 1", 1, d.MeanAxis, []int{2, 2}, []T{3, 4, 9, 10}},
+		{"max 2", 2, d.MaxAxis, []int{2, 3}, []T{2, 4, 6, 8, 10, 12}},
+		{"min 0", 0, d.MinAxis, []int{3, 2}, []T{1, 2, 3, 4, 5, 6}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			y := tc.fn(tc.axis)
+			assert.Equal(t, tc.shape, y.Shape())
+			assert.InDeltaSlice(t, tc.expected, Data[T](y), 1e-6)
+		})
+	}
+
+	t.Run("reduce a view", func(t *testing.T) {
+		y := d.Permute(2, 1, 0).SumAxis(2)
+		assert.Equal(t, []int{2, 3}, y.Shape())
+		assert.Equal(t, []T{8, 12, 16, 10, 14, 18}, Data[T](y))
+	})
+
+	t.Run("rank 1 to scalar", func(t *testing.T) {
+		y := NewDenseTensor([]int{3}, []T{1, 2, 3}).SumAxis(0)
+		assert.Equal(t, []int{}, y.Shape())
+		assert.Equal(t, T(6), float.ValueOf[T](y.Scalar()))
+	})
+
+	t.Run("max of empty axis", func(t *testing.T) {
+		require.Panics(t, func() { NewEmptyDenseTensor[T](0, 2).MaxAxis(0) })
+	})
+}
+
+func TestDenseTensor_MatrixMethods(t *testing.T) {
+	t.Run("float32", testDenseTensorMatrixMethods[float32])
+	t.Run("float64", testDenseTensorMatrixMethods[float64])
+}
+
+func testDenseTensorMatrixMethods[T float.DType](t *testing.T) {
+	d := NewDenseTensor([]int{2, 2, 2}, []T{1, 2, 3, 4, 5, 6, 7, 8})
+
+	t.Run("element-wise results keep the shape", func(t *testing.T) {
+		y := d.Add(d).(Tensor)
+		assert.Equal(t, []int{2, 2, 2}, y.Shape())
+		assert.Equal(t, []T{2, 4, 6, 8, 10, 12, 14, 16}, Data[T](y))
+
+		y = d.ProdScalar(2).(Tensor)
+		assert.Equal(t, []int{2, 2, 2}, y.Shape())
+
+		y = d.Clone().(Tensor)
+		assert.Equal(t, []int{2, 2, 2}, y.Shape())
+		assert.Equal(t, Data[T](d), Data[T](y))
+	})
+
+	t.Run("two-dimensional projection", func(t *testing.T) {
+		assert.Equal(t, T(7), float.ValueOf[T](d.ScalarAt(1, 2)))
+		assert.Equal(t, T(36), float.ValueOf[T](d.Sum().Scalar()))
+
+		y := d.Mul(NewDense(4, 1, []T{1, 1, 1, 1}))
+		assert.Equal(t, []T{10, 26}, Data[T](y))
+	})
+
+	t.Run("non-contiguous in-place operations", func(t *testing.T) {
+		c := d.Clone().(Tensor)
+		p := c.Permute(2, 1, 0)
+		p.AddInPlace(NewDenseTensor([]int{2, 2, 2}, []T{1, 0, 0, 0, 0, 0, 0, 0}))
+		assert.Equal(t, T(2), float.ValueOf[T](c.Item(0, 0, 0)))
+		p.Zeros()
+		assert.Equal(t, T(0), float.ValueOf[T](c.Sum().Scalar()))
+	})
+
+	t.Run("release", func(t *testing.T) {
+		c := d.Clone()
+		v := c.(Tensor).Permute(2, 1, 0)
+		ReleaseMatrix(v) // views are ignored
+		ReleaseMatrix(c)
+	})
+}
+
+func TestAsTensor(t *testing.T) {
+	t.Run("float32", testAsTensor[float32])
+	t.Run("float64", testAsTensor[float64])
+}
+
+func testAsTensor[T float.DType](t *testing.T) {
+	d := NewDense(2, 3, []T{1, 2, 3, 4, 5, 6})
+	x := AsTensor(d)
+	assert.Equal(t, []int{2, 3}, x.Shape())
+	assert.Equal(t, []int{2, 3}, Shape(d))
+	x.SetItem(float.Interface(T(42)), 0, 0)
+	assert.Equal(t, T(42), d.data[0], "the tensor must share the data")
+	assert.Same(t, x, AsTensor(x))
+
+	w := WithShape(d, 3, 2, 1)
+	assert.Equal(t, []int{3, 2, 1}, w.Shape())
+	assert.True(t, SameShape(w, NewEmptyDenseTensor[T](3, 2, 1)))
+	assert.False(t, SameShape(w, NewEmptyDense[T](3, 2)))
+}
@@ -5,6 +5,11 @@
 package float
 
 // DType is the primary type constraint for matrices defined in this package.
+//
+// The 16-bit floating point types (see Half) are not part of DType, since
+// they are represented as bit patterns in a uint16, on which the generic
+// arithmetic of DType values would be wrong. Matrices storing 16-bit values
+// are provided by mat.HalfDense, which computes in float32.
 type DType interface {
 	float32 | float64
 }
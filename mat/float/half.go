@@ -0,0 +1,237 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package float
+
+import (
+	"fmt"
+	"math"
+)
+
+// Half is the type constraint for the 16-bit floating point types.
+//
+// Half types are storage formats: they are not part of DType, and any
+// computation is performed on float32 or float64 values, converting from
+// and to the 16-bit representation as needed (see mat.HalfDense).
+type Half interface {
+	Float16 | BFloat16
+	Float
+	// Float32 returns the value converted to float32.
+	Float32() float32
+}
+
+// Float16 is an IEEE 754 half-precision binary floating-point value
+// (1 sign bit, 5 exponent bits, 10 significand bits).
+type Float16 uint16
+
+// Float16FromFloat32 converts a float32 value to Float16, rounding to the
+// nearest even value. Values out of range become infinities.
+func Float16FromFloat32(f float32) Float16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xff
+	mant := b & 0x7fffff
+
+	if exp == 0xff { // Inf or NaN
+		if mant != 0 {
+			return Float16(sign | 0x7e00)
+		}
+		return Float16(sign | 0x7c00)
+	}
+
+	e := exp - 127 + 15
+	switch {
+	case e >= 0x1f: // overflow
+		return Float16(sign | 0x7c00)
+	case e <= 0: // subnormal or zero
+		if e < -10 {
+			return Float16(sign)
+		}
+		mant |= 0x800000
+		shift := uint(14 - e)
+		h := mant >> shift
+		rem := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rem > halfway || (rem == halfway && h&1 == 1) {
+			h++
+		}
+		return Float16(sign | uint16(h))
+	default:
+		h := uint32(e)<<10 | mant>>13
+		rem := mant & 0x1fff
+		if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+			h++ // a carry into the exponent is still correct, up to infinity
+		}
+		return Float16(sign | uint16(h))
+	}
+}
+
+// Float32 returns the value converted to float32. The conversion is exact.
+func (h Float16) Float32() float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h) & 0x3ff
+
+	switch exp {
+	case 0x1f: // Inf or NaN
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case 0: // subnormal or zero
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
+
+// F32 returns the value as float32.
+func (h Float16) F32() float32 {
+	return h.Float32()
+}
+
+// F64 returns the value as float64.
+func (h Float16) F64() float64 {
+	return float64(h.Float32())
+}
+
+// BitSize returns the size in bits of the value type.
+func (h Float16) BitSize() int {
+	return 16
+}
+
+// String returns the value as a string.
+func (h Float16) String() string {
+	return fmt.Sprint(h.Float32())
+}
+
+// BFloat16 is a "brain floating point" value: a float32 truncated to its
+// 16 most significant bits (1 sign bit, 8 exponent bits, 7 significand bits).
+type BFloat16 uint16
+
+// BFloat16FromFloat32 converts a float32 value to BFloat16, rounding to the
+// nearest even value.
+func BFloat16FromFloat32(f float32) BFloat16 {
+	b := math.Float32bits(f)
+	if b&0x7fffffff > 0x7f800000 { // NaN: keep it quiet
+		return BFloat16(b>>16 | 0x40)
+	}
+	b += 0x7fff + (b>>16)&1
+	return BFloat16(b >> 16)
+}
+
+// Float32 returns the value converted to float32. The conversion is exact.
+func (h BFloat16) Float32() float32 {
+	return math.Float32frombits(uint32(h) << 16)
+}
+
+// F32 returns the value as float32.
+func (h BFloat16) F32() float32 {
+	return h.Float32()
+}
+
+// F64 returns the value as float64.
+func (h BFloat16) F64() float64 {
+	return float64(h.Float32())
+}
+
+// BitSize returns the size in bits of the value type.
+func (h BFloat16) BitSize() int {
+	return 16
+}
+
+// String returns the value as a string.
+func (h BFloat16) String() string {
+	return fmt.Sprint(h.Float32())
+}
+
+// HalfFromFloat32 converts a float32 value to the Half type H.
+func HalfFromFloat32[H Half](f float32) H {
+	switch any(H(0)).(type) {
+	case Float16:
+		return H(Float16FromFloat32(f))
+	case BFloat16:
+		return H(BFloat16FromFloat32(f))
+	default:
+		panic(fmt.Errorf("mat: unexpected value type %T", H(0)))
+	}
+}
+
+// HalfSliceInterface converts a concrete slice of Half values to an
+// internal representation compatible with Slice. Its F32 and F64 methods
+// always return new converted slices.
+func HalfSliceInterface[H Half](v []H) Slice {
+	return halfSlice[H](v)
+}
+
+// HalfSliceValueOf converts a Slice value to a new slice of Half values.
+func HalfSliceValueOf[H Half](v Slice) []H {
+	if hs, ok := v.(halfSlice[H]); ok {
+		return append([]H(nil), hs...)
+	}
+	f32 := v.F32()
+	if f32 == nil {
+		return nil
+	}
+	out := make([]H, len(f32))
+	for i, f := range f32 {
+		out[i] = HalfFromFloat32[H](f)
+	}
+	return out
+}
+
+// halfSlice is the built-in implementation of a Slice of Half values.
+type halfSlice[H Half] []H
+
+// F32 returns the values converted to a new []float32.
+func (hs halfSlice[_]) F32() []float32 {
+	if hs == nil {
+		return nil
+	}
+	out := make([]float32, len(hs))
+	for i, h := range hs {
+		out[i] = h.Float32()
+	}
+	return out
+}
+
+// F64 returns the values converted to a new []float64.
+func (hs halfSlice[_]) F64() []float64 {
+	if hs == nil {
+		return nil
+	}
+	out := make([]float64, len(hs))
+	for i, h := range hs {
+		out[i] = float64(h.Float32())
+	}
+	return out
+}
+
+// BitSize returns the size in bits of the internal value type.
+func (hs halfSlice[_]) BitSize() int {
+	return 16
+}
+
+// Len returns the length of the slice.
+func (hs halfSlice[_]) Len() int {
+	return len(hs)
+}
+
+// Equals reports whether the content of the receiver is equal to the
+// content of the other slice, comparing the values as float32.
+func (hs halfSlice[_]) Equals(other Slice) bool {
+	return SliceInterface(hs.F32()).Equals(other)
+}
+
+// InDelta reports whether the receiver and the other slice have the same
+// length and all their values at the same positions are within delta,
+// comparing the values as float32.
+func (hs halfSlice[_]) InDelta(other Slice, delta float64) bool {
+	return SliceInterface(hs.F32()).InDelta(other, delta)
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package float_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloat16FromFloat32(t *testing.T) {
+	testCases := []struct {
+		f float32
+		h float.Float16
+	}{
+		{0, 0x0000},
+		{float32(math.Copysign(0, -1)), 0x8000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{0.1, 0x2e66},
+		{65504, 0x7bff},
+		{65520, 0x7c00}, // rounds to infinity
+		{1e10, 0x7c00},
+		{-1e10, 0xfc00},
+		{float32(math.Inf(1)), 0x7c00},
+		{float32(math.Inf(-1)), 0xfc00},
+		{6.103515625e-05, 0x0400},        // smallest normal
+		{5.960464477539063e-08, 0x0001},  // smallest subnormal
+		{2.9802322387695312e-08, 0x0000}, // halfway to the smallest subnormal, rounds to even
+		{1e-10, 0x0000},
+		{1 + 1.0/2048, 0x3c00}, // halfway, rounds to even
+		{1 + 3.0/2048, 0x3c02}, // halfway, rounds to even
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%g", tc.f), func(t *testing.T) {
+			assert.Equal(t, tc.h, float.Float16FromFloat32(tc.f))
+		})
+	}
+
+	t.Run("NaN", func(t *testing.T) {
+		h := float.Float16FromFloat32(float32(math.NaN()))
+		assert.True(t, math.IsNaN(float64(h.Float32())))
+	})
+}
+
+func TestFloat16_RoundTrip(t *testing.T) {
+	for i := 0; i <= math.MaxUint16; i++ {
+		h := float.Float16(i)
+		f := h.Float32()
+		if math.IsNaN(float64(f)) {
+			continue
+		}
+		if got := float.Float16FromFloat32(f); got != h {
+			t.Fatalf("%#04x: expected round trip, got %#04x (%g)", i, uint16(got), f)
+		}
+	}
+}
+
+func TestBFloat16FromFloat32(t *testing.T) {
+	testCases := []struct {
+		f float32
+		h float.BFloat16
+	}{
+		{0, 0x0000},
+		{1, 0x3f80},
+		{-2, 0xc000},
+		{3.140625, 0x4049},
+		{1 + 1.0/256, 0x3f80}, // halfway, rounds to even
+		{1 + 3.0/256, 0x3f82}, // halfway, rounds to even
+		{float32(math.Inf(1)), 0x7f80},
+		{math.MaxFloat32, 0x7f80}, // rounds to infinity
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("%g", tc.f), func(t *testing.T) {
+			assert.Equal(t, tc.h, float.BFloat16FromFloat32(tc.f))
+		})
+	}
+
+	t.Run("NaN", func(t *testing.T) {
+		h := float.BFloat16FromFloat32(float32(math.NaN()))
+		assert.True(t, math.IsNaN(float64(h.Float32())))
+	})
+}
+
+func TestBFloat16_RoundTrip(t *testing.T) {
+	for i := 0; i <= math.MaxUint16; i++ {
+		h := float.BFloat16(i)
+		f := h.Float32()
+		if math.IsNaN(float64(f)) {
+			continue
+		}
+		if got := float.BFloat16FromFloat32(f); got != h {
+			t.Fatalf("%#04x: expected round trip, got %#04x (%g)", i, uint16(got), f)
+		}
+	}
+}
+
+func TestHalf_Float(t *testing.T) {
+	t.Run("Float16", testHalfFloat[float.Float16])
+	t.Run("BFloat16", testHalfFloat[float.BFloat16])
+}
+
+func testHalfFloat[H float.Half](t *testing.T) {
+	var v float.Float = float.HalfFromFloat32[H](1.5)
+	assert.Equal(t, float32(1.5), v.F32())
+	assert.Equal(t, 1.5, v.F64())
+	assert.Equal(t, 16, v.BitSize())
+	assert.Equal(t, float32(1.5), float.ValueOf[float32](v))
+}
+
+func TestHalfSlice(t *testing.T) {
+	t.Run("Float16", testHalfSlice[float.Float16])
+	t.Run("BFloat16", testHalfSlice[float.BFloat16])
+}
+
+func testHalfSlice[H float.Half](t *testing.T) {
+	t.Run("nil", func(t *testing.T) {
+		s := float.HalfSliceInterface[H](nil)
+		assert.Nil(t, s.F32())
+		assert.Nil(t, s.F64())
+		assert.Equal(t, 0, s.Len())
+		assert.Nil(t, float.HalfSliceValueOf[H](float.SliceInterface[float32](nil)))
+	})
+
+	t.Run("conversions", func(t *testing.T) {
+		h := float.HalfSliceValueOf[H](float.SliceInterface([]float64{1, -2, 0.5}))
+		assert.Len(t, h, 3)
+
+		s := float.HalfSliceInterface(h)
+		assert.Equal(t, 16, s.BitSize())
+		assert.Equal(t, 3, s.Len())
+		assert.Equal(t, []float32{1, -2, 0.5}, s.F32())
+		assert.Equal(t, []float64{1, -2, 0.5}, s.F64())
+		assert.Equal(t, []float32{1, -2, 0.5}, float.SliceValueOf[float32](s))
+
+		assert.True(t, s.Equals(float.SliceInterface([]float32{1, -2, 0.5})))
+		assert.False(t, s.Equals(float.SliceInterface([]float32{1, -2, 0.6})))
+		assert.True(t, s.InDelta(float.SliceInterface([]float64{1, -2, 0.51}), 0.1))
+
+		c := float.HalfSliceValueOf[H](s)
+		assert.Equal(t, h, c)
+		c[0] = 0
+		assert.NotEqual(t, h[0], c[0])
+	})
+}
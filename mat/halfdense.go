@@ -0,0 +1,788 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// HalfDense is a matrix storing its values as 16-bit floating point
+// numbers of type H (see float.Half), in row-major order.
+//
+// Since the 16-bit types are not part of float.DType, computations are
+// performed in float32: HalfDense implements the Matrix interface upcasting
+// its values to a float32 Dense matrix, so it can replace the float32 value
+// of a parameter, halving its memory footprint. Matrix products involving a
+// HalfDense matrix and a float32 Dense one, in any order, convert the half
+// values on the fly, without upcasting the whole receiver. Most of the
+// other operations are performed on an upcast copy, returning a float32
+// Dense matrix; in-place operations round the result again.
+//
+// HalfDense matrices are not pooled: ReleaseMatrix has no effect on them.
+type HalfDense[H float.Half] struct {
+	rows int
+	cols int
+	// data contains the half-precision values in row-major order.
+	data []H
+}
+
+// halfDenseMatrix is implemented by the HalfDense matrices of any 16-bit
+// type, allowing float32 Dense matrices to multiply them directly.
+type halfDenseMatrix interface {
+	denseMul(d *Dense[float32]) *Dense[float32]
+	denseMulT(d *Dense[float32]) *Dense[float32]
+}
+
+// HalfData returns the 16-bit values of the matrix, in row-major order.
+// The returned slice IS NOT a copy and must not be modified.
+func (h *HalfDense[H]) HalfData() []H {
+	return h.data
+}
+
+// Upcast returns a new float32 Dense matrix with the values of the receiver.
+// The result is not taken from the pool, so it can be safely discarded.
+func (h *HalfDense[H]) Upcast() *Dense[float32] {
+	d := &Dense[float32]{rows: h.rows, cols: h.cols, data: make([]float32, len(h.data))}
+	for i, v := range h.data {
+		d.data[i] = v.Float32()
+	}
+	return d
+}
+
+// setFromData replaces the content of the matrix rounding the given
+// values, interpreted in row-major order.
+func (h *HalfDense[H]) setFromData(data []float32) {
+	if len(data) != h.rows*h.cols {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", h.rows*h.cols))
+	}
+	h.data = make([]H, len(data))
+	for i, v := range data {
+		h.data[i] = float.HalfFromFloat32[H](v)
+	}
+}
+
+// get returns the value at row r and column c.
+func (h *HalfDense[H]) get(r, c int) float32 {
+	if r < 0 || r >= h.rows {
+		panic("mat: 'r' argument out of range")
+	}
+	if c < 0 || c >= h.cols {
+		panic("mat: 'c' argument out of range")
+	}
+	return h.data[r*h.cols+c].Float32()
+}
+
+// set sets the value v, rounded to the type H, at row r and column c.
+func (h *HalfDense[H]) set(r, c int, v float32) {
+	if r < 0 || r >= h.rows {
+		panic("mat: 'r' argument out of range")
+	}
+	if c < 0 || c >= h.cols {
+		panic("mat: 'c' argument out of range")
+	}
+	h.data[r*h.cols+c] = float.HalfFromFloat32[H](v)
+}
+
+// inPlace calls fn with an upcast copy of the matrix, then rounds the
+// modified values back into the receiver.
+func (h *HalfDense[H]) inPlace(fn func(d *Dense[float32])) {
+	d := h.Upcast()
+	fn(d)
+	h.rows, h.cols = d.rows, d.cols
+	h.setFromData(d.data)
+}
+
+// Rows returns the number of rows of the matrix.
+func (h *HalfDense[H]) Rows() int {
+	return h.rows
+}
+
+// Columns returns the number of columns of the matrix.
+func (h *HalfDense[H]) Columns() int {
+	return h.cols
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (h *HalfDense[H]) Dims() (r, c int) {
+	return h.rows, h.cols
+}
+
+// The Size of the matrix (rows*columns).
+func (h *HalfDense[H]) Size() int {
+	return h.rows * h.cols
+}
+
+// Data returns a copy of the values of the matrix, upcast to float32, as a
+// raw one-dimensional slice of values in row-major order.
+func (h *HalfDense[H]) Data() float.Slice {
+	return float.SliceInterface(h.Upcast().data)
+}
+
+// SetData sets the content of the matrix, rounding the given raw data
+// representation as one-dimensional slice.
+func (h *HalfDense[H]) SetData(data float.Slice) {
+	h.setFromData(float.SliceValueOf[float32](data))
+}
+
+// ZerosLike returns a new HalfDense matrix with the same dimensions of the
+// receiver, initialized with zeros.
+func (h *HalfDense[H]) ZerosLike() Matrix {
+	return NewEmptyHalfDense[H](h.rows, h.cols)
+}
+
+// OnesLike returns a new Dense matrix with the same dimensions of the
+// receiver, initialized with ones.
+func (h *HalfDense[H]) OnesLike() Matrix {
+	return NewInitDense[float32](h.rows, h.cols, 1)
+}
+
+// Scalar returns the scalar value.
+// It panics if the matrix does not contain exactly one element.
+func (h *HalfDense[H]) Scalar() float.Float {
+	if h.Size() != 1 {
+		panic("mat: expected scalar but the matrix contains more elements")
+	}
+	return float.Interface(h.get(0, 0))
+}
+
+// Zeros sets all the values of the matrix to zero.
+func (h *HalfDense[H]) Zeros() {
+	for i := range h.data {
+		h.data[i] = 0
+	}
+}
+
+// Set sets the scalar value from a 1×1 matrix at row r and column c.
+// It panics if the given matrix is not 1×1, or if indices are out of range.
+func (h *HalfDense[H]) Set(r int, c int, m Matrix) {
+	h.set(r, c, float.ValueOf[float32](m.Scalar()))
+}
+
+// At returns the value at row r and column c as a 1×1 matrix.
+// It panics if the given indices are out of range.
+func (h *HalfDense[H]) At(r int, c int) Matrix {
+	return NewScalar(h.get(r, c))
+}
+
+// SetScalar sets the value v at row r and column c.
+// It panics if the given indices are out of range.
+func (h *HalfDense[H]) SetScalar(r int, c int, v float.Float) {
+	h.set(r, c, float.ValueOf[float32](v))
+}
+
+// ScalarAt returns the value at row r and column c.
+// It panics if the given indices are out of range.
+func (h *HalfDense[H]) ScalarAt(r int, c int) float.Float {
+	return float.Interface(h.get(r, c))
+}
+
+// SetVec sets the scalar value from a 1×1 matrix at position i of a
+// vector. It panics if the receiver is not a vector, or the given matrix is
+// not 1×1, or the position is out of range.
+func (h *HalfDense[H]) SetVec(i int, m Matrix) {
+	r, c := h.vecIndices(i)
+	h.set(r, c, float.ValueOf[float32](m.Scalar()))
+}
+
+// AtVec returns the value at position i of a vector as a 1×1 matrix.
+// It panics if the receiver is not a vector or the position is out of range.
+func (h *HalfDense[H]) AtVec(i int) Matrix {
+	return NewScalar(h.get(h.vecIndices(i)))
+}
+
+// SetVecScalar sets the value v at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (h *HalfDense[H]) SetVecScalar(i int, v float.Float) {
+	r, c := h.vecIndices(i)
+	h.set(r, c, float.ValueOf[float32](v))
+}
+
+// ScalarAtVec returns the value at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (h *HalfDense[H]) ScalarAtVec(i int) float.Float {
+	return float.Interface(h.get(h.vecIndices(i)))
+}
+
+func (h *HalfDense[H]) vecIndices(i int) (r, c int) {
+	if !IsVector(h) {
+		panic("mat: expected vector")
+	}
+	if i < 0 || i >= h.Size() {
+		panic("mat: 'i' argument out of range")
+	}
+	if h.cols == 1 {
+		return i, 0
+	}
+	return 0, i
+}
+
+// ExtractRow returns a copy of the i-th row of the matrix,
+// as a Dense row vector (1×cols).
+func (h *HalfDense[H]) ExtractRow(i int) Matrix {
+	return h.Upcast().ExtractRow(i)
+}
+
+// ExtractColumn returns a copy of the i-th column of the matrix,
+// as a Dense column vector (rows×1).
+func (h *HalfDense[H]) ExtractColumn(i int) Matrix {
+	return h.Upcast().ExtractColumn(i)
+}
+
+// View returns a Dense copy of the matrix with the given dimensions, since
+// the half-precision representation cannot be shared.
+func (h *HalfDense[H]) View(rows, cols int) Matrix {
+	return h.Upcast().Reshape(rows, cols)
+}
+
+// Slice returns a new Dense matrix obtained by slicing the receiver across
+// the given positions. The parameters "fromRow" and "fromCol" are inclusive,
+// while "toRow" and "toCol" are exclusive.
+func (h *HalfDense[H]) Slice(fromRow, fromCol, toRow, toCol int) Matrix {
+	return h.Upcast().Slice(fromRow, fromCol, toRow, toCol)
+}
+
+// Reshape returns a Dense copy of the matrix with the given dimensions.
+// It panics if the dimensions are incompatible.
+func (h *HalfDense[H]) Reshape(rows, cols int) Matrix {
+	return h.Upcast().Reshape(rows, cols)
+}
+
+// ReshapeInPlace changes the dimensions of the matrix in place, rounding
+// again its values, and returns the matrix itself.
+// It panics if the dimensions are incompatible.
+func (h *HalfDense[H]) ReshapeInPlace(rows, cols int) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.ReshapeInPlace(rows, cols) })
+	return h
+}
+
+// Flatten creates a new Dense row vector (1×size) corresponding to the
+// "flattened" row-major ordered representation of the initial matrix.
+func (h *HalfDense[H]) Flatten() Matrix {
+	return h.Upcast().Flatten()
+}
+
+// FlattenInPlace transforms the matrix in place, changing its dimensions,
+// obtaining a row vector (1×size) containing the "flattened" row-major
+// ordered representation of the initial value.
+// It returns the matrix itself.
+func (h *HalfDense[H]) FlattenInPlace() Matrix {
+	return h.ReshapeInPlace(1, h.Size())
+}
+
+// ResizeVector returns a resized Dense copy of the vector.
+//
+// If the new size is smaller than the input vector, the remaining tail
+// elements are removed. If it's bigger, the additional tail elements
+// are set to zero.
+func (h *HalfDense[H]) ResizeVector(newSize int) Matrix {
+	return h.Upcast().ResizeVector(newSize)
+}
+
+// T returns the transpose of the matrix, as a new float32 Dense matrix.
+func (h *HalfDense[H]) T() Matrix {
+	return h.Upcast().T()
+}
+
+// TransposeInPlace transposes the matrix in place, rounding again its
+// values, and returns the matrix itself.
+func (h *HalfDense[H]) TransposeInPlace() Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.TransposeInPlace() })
+	return h
+}
+
+// Add returns the addition between the receiver and another matrix,
+// as a new float32 Dense matrix.
+func (h *HalfDense[H]) Add(other Matrix) Matrix {
+	return h.Upcast().Add(other)
+}
+
+// AddInPlace performs the in-place addition with the other matrix.
+func (h *HalfDense[H]) AddInPlace(other Matrix) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.AddInPlace(other) })
+	return h
+}
+
+// AddScalar performs the addition between the matrix and the given value,
+// returning a new float32 Dense matrix.
+func (h *HalfDense[H]) AddScalar(n float64) Matrix {
+	return h.Upcast().AddScalar(n)
+}
+
+// AddScalarInPlace adds the scalar to all values of the matrix.
+func (h *HalfDense[H]) AddScalarInPlace(n float64) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.AddScalarInPlace(n) })
+	return h
+}
+
+// Sub returns the subtraction of the other matrix from the receiver,
+// as a new float32 Dense matrix.
+func (h *HalfDense[H]) Sub(other Matrix) Matrix {
+	return h.Upcast().Sub(other)
+}
+
+// SubInPlace performs the in-place subtraction with the other matrix.
+func (h *HalfDense[H]) SubInPlace(other Matrix) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.SubInPlace(other) })
+	return h
+}
+
+// SubScalar performs a subtraction between the matrix and the given value,
+// returning a new float32 Dense matrix.
+func (h *HalfDense[H]) SubScalar(n float64) Matrix {
+	return h.Upcast().SubScalar(n)
+}
+
+// SubScalarInPlace subtracts the scalar from the receiver's values.
+func (h *HalfDense[H]) SubScalarInPlace(n float64) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.SubScalarInPlace(n) })
+	return h
+}
+
+// Prod performs the element-wise product between the receiver and the
+// other matrix, returning a new float32 Dense matrix.
+func (h *HalfDense[H]) Prod(other Matrix) Matrix {
+	return h.Upcast().Prod(other)
+}
+
+// ProdInPlace performs the in-place element-wise product with the other matrix.
+func (h *HalfDense[H]) ProdInPlace(other Matrix) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.ProdInPlace(other) })
+	return h
+}
+
+// ProdScalar returns the multiplication between the matrix and the given
+// value, as a new float32 Dense matrix.
+func (h *HalfDense[H]) ProdScalar(n float64) Matrix {
+	return h.Upcast().ProdScalar(n)
+}
+
+// ProdScalarInPlace performs the in-place multiplication between the
+// matrix and the given value.
+func (h *HalfDense[H]) ProdScalarInPlace(n float64) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.ProdScalarInPlace(n) })
+	return h
+}
+
+// ProdMatrixScalarInPlace multiplies the given matrix with the value,
+// storing the result in the receiver.
+func (h *HalfDense[H]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.ProdMatrixScalarInPlace(m, n) })
+	return h
+}
+
+// Div returns the result of the element-wise division of the receiver by
+// the other matrix, as a new float32 Dense matrix.
+func (h *HalfDense[H]) Div(other Matrix) Matrix {
+	return h.Upcast().Div(other)
+}
+
+// DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
+func (h *HalfDense[H]) DivInPlace(other Matrix) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.DivInPlace(other) })
+	return h
+}
+
+// Mul performs the multiplication row by column.
+// If A is an i×j Matrix, and B is j×k, then the resulting Matrix
+// C = AB will be i×k.
+//
+// The result is a new float32 Dense matrix. The half values are upcast one
+// row at a time, without converting the whole receiver.
+func (h *HalfDense[H]) Mul(other Matrix) Matrix {
+	if h.cols != other.Rows() {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	outCols := other.Columns()
+	out := densePoolFloat32.GetEmpty(h.rows, outCols)
+	oData := Data[float32](other)
+	row := make([]float32, h.cols)
+	for r := 0; r < h.rows; r++ {
+		h.upcastRow(r, row)
+		outRow := out.data[r*outCols : (r+1)*outCols]
+		for k, v := range row {
+			if v != 0 {
+				axpy(v, oData[k*outCols:(k+1)*outCols], outRow)
+			}
+		}
+	}
+	return out
+}
+
+// MulT performs the matrix multiplication row by column.
+// ATB = C, where AT is the transpose of A
+// if A is an r x c Matrix, and B is j x k, r = j the resulting
+// Matrix C will be c x k.
+//
+// The result is a new float32 Dense matrix.
+func (h *HalfDense[H]) MulT(other Matrix) Matrix {
+	if h.rows != other.Rows() {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	outCols := other.Columns()
+	out := densePoolFloat32.GetEmpty(h.cols, outCols)
+	oData := Data[float32](other)
+	row := make([]float32, h.cols)
+	for r := 0; r < h.rows; r++ {
+		h.upcastRow(r, row)
+		oRow := oData[r*outCols : (r+1)*outCols]
+		for c, w := range row {
+			if w != 0 {
+				axpy(w, oRow, out.data[c*outCols:(c+1)*outCols])
+			}
+		}
+	}
+	return out
+}
+
+// denseMul returns the product d×h as a new float32 Dense matrix.
+func (h *HalfDense[H]) denseMul(d *Dense[float32]) *Dense[float32] {
+	out := densePoolFloat32.GetEmpty(d.rows, h.cols)
+	row := make([]float32, h.cols)
+	for k := 0; k < h.rows; k++ {
+		h.upcastRow(k, row)
+		for i := 0; i < d.rows; i++ {
+			if coeff := d.data[i*d.cols+k]; coeff != 0 {
+				axpy(coeff, row, out.data[i*h.cols:(i+1)*h.cols])
+			}
+		}
+	}
+	return out
+}
+
+// denseMulT returns the product dᵀ×h as a new float32 Dense matrix.
+func (h *HalfDense[H]) denseMulT(d *Dense[float32]) *Dense[float32] {
+	out := densePoolFloat32.GetEmpty(d.cols, h.cols)
+	row := make([]float32, h.cols)
+	for k := 0; k < h.rows; k++ {
+		h.upcastRow(k, row)
+		for i, coeff := range d.data[k*d.cols : (k+1)*d.cols] {
+			if coeff != 0 {
+				axpy(coeff, row, out.data[i*h.cols:(i+1)*h.cols])
+			}
+		}
+	}
+	return out
+}
+
+// upcastRow stores in dst the values of the r-th row.
+func (h *HalfDense[H]) upcastRow(r int, dst []float32) {
+	for i, v := range h.data[r*h.cols : (r+1)*h.cols] {
+		dst[i] = v.Float32()
+	}
+}
+
+// DotUnitary returns the dot product of two vectors as a scalar Matrix.
+func (h *HalfDense[H]) DotUnitary(other Matrix) Matrix {
+	return h.Upcast().DotUnitary(other)
+}
+
+// ClipInPlace clips in place each value of the matrix.
+func (h *HalfDense[H]) ClipInPlace(min, max float64) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.ClipInPlace(min, max) })
+	return h
+}
+
+// Maximum returns a new Dense matrix containing the element-wise maxima.
+func (h *HalfDense[H]) Maximum(other Matrix) Matrix {
+	return h.Upcast().Maximum(other)
+}
+
+// Minimum returns a new Dense matrix containing the element-wise minima.
+func (h *HalfDense[H]) Minimum(other Matrix) Matrix {
+	return h.Upcast().Minimum(other)
+}
+
+// Abs returns a new Dense matrix applying the absolute value function to
+// all elements.
+func (h *HalfDense[H]) Abs() Matrix {
+	return h.Upcast().Abs()
+}
+
+// Pow returns a new Dense matrix, applying the power function with given
+// exponent to all elements of the matrix.
+func (h *HalfDense[H]) Pow(power float64) Matrix {
+	return h.Upcast().Pow(power)
+}
+
+// Sqrt returns a new Dense matrix applying the square root function to
+// all elements.
+func (h *HalfDense[H]) Sqrt() Matrix {
+	return h.Upcast().Sqrt()
+}
+
+// Log returns a new Dense matrix applying the natural logarithm function to
+// each element.
+func (h *HalfDense[H]) Log() Matrix {
+	return h.Upcast().Log()
+}
+
+// Exp returns a new Dense matrix applying the base-e exponential function
+// to each element.
+func (h *HalfDense[H]) Exp() Matrix {
+	return h.Upcast().Exp()
+}
+
+// Sigmoid returns a new Dense matrix applying the sigmoid function to each
+// element.
+func (h *HalfDense[H]) Sigmoid() Matrix {
+	return h.Upcast().Sigmoid()
+}
+
+// Sum returns the sum of all values of the matrix as a scalar Matrix.
+func (h *HalfDense[H]) Sum() Matrix {
+	return h.Upcast().Sum()
+}
+
+// Max returns the maximum value of the matrix as a scalar Matrix.
+func (h *HalfDense[H]) Max() Matrix {
+	return h.Upcast().Max()
+}
+
+// Min returns the minimum value of the matrix as a scalar Matrix.
+func (h *HalfDense[H]) Min() Matrix {
+	return h.Upcast().Min()
+}
+
+// ArgMax returns the index of the vector's element with the maximum value.
+func (h *HalfDense[H]) ArgMax() int {
+	return h.Upcast().ArgMax()
+}
+
+// Softmax applies the softmax function to the vector, returning the
+// result as a new float32 Dense column vector.
+func (h *HalfDense[H]) Softmax() Matrix {
+	return h.Upcast().Softmax()
+}
+
+// CumSum computes the cumulative sum of the vector's elements, returning
+// the result as a new float32 Dense column vector.
+func (h *HalfDense[H]) CumSum() Matrix {
+	return h.Upcast().CumSum()
+}
+
+// Range creates a new Dense vector initialized with data extracted from the
+// matrix values, from start (inclusive) to end (exclusive).
+func (h *HalfDense[H]) Range(start, end int) Matrix {
+	return h.Upcast().Range(start, end)
+}
+
+// SplitV splits the vector in N Dense chunks of given sizes,
+// so that N[i] has size sizes[i].
+func (h *HalfDense[H]) SplitV(sizes ...int) []Matrix {
+	return h.Upcast().SplitV(sizes...)
+}
+
+// Augment places the identity matrix at the end of the original matrix,
+// returning a new float32 Dense matrix.
+func (h *HalfDense[H]) Augment() Matrix {
+	return h.Upcast().Augment()
+}
+
+// SwapInPlace swaps two rows of the matrix in place.
+func (h *HalfDense[H]) SwapInPlace(r1, r2 int) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.SwapInPlace(r1, r2) })
+	return h
+}
+
+// PadRows returns a Dense copy of the matrix with n additional tail rows.
+// The additional elements are set to zero.
+func (h *HalfDense[H]) PadRows(n int) Matrix {
+	return h.Upcast().PadRows(n)
+}
+
+// PadColumns returns a Dense copy of the matrix with n additional tail
+// columns. The additional elements are set to zero.
+func (h *HalfDense[H]) PadColumns(n int) Matrix {
+	return h.Upcast().PadColumns(n)
+}
+
+// AppendRows returns a Dense copy of the matrix with len(vs) additional
+// tail rows, being each new row filled with the values of each given vector.
+//
+// It accepts row or column vectors indifferently, virtually treating all of
+// them as row vectors.
+func (h *HalfDense[H]) AppendRows(vs ...Matrix) Matrix {
+	return h.Upcast().AppendRows(vs...)
+}
+
+// Norm returns the vector's norm. Use pow = 2.0 to compute the Euclidean norm.
+// The result is a scalar Matrix.
+func (h *HalfDense[H]) Norm(pow float64) Matrix {
+	return h.Upcast().Norm(pow)
+}
+
+// Pivoting returns the partial pivots of a square matrix to reorder rows.
+// Considerate square sub-matrix from element (offset, offset).
+func (h *HalfDense[H]) Pivoting(row int) (Matrix, bool, [2]int) {
+	return h.Upcast().Pivoting(row)
+}
+
+// Normalize2 normalizes an array with the Euclidean norm, returning a new
+// Dense matrix.
+func (h *HalfDense[H]) Normalize2() Matrix {
+	return h.Upcast().Normalize2()
+}
+
+// LU performs lower–upper (LU) decomposition of a square matrix D such as
+// PLU = D, L is lower diagonal and U is upper diagonal, p are pivots.
+func (h *HalfDense[H]) LU() (l, u, p Matrix) {
+	return h.Upcast().LU()
+}
+
+// Inverse returns the inverse of the Matrix, as a new float32 Dense matrix.
+func (h *HalfDense[H]) Inverse() Matrix {
+	return h.Upcast().Inverse()
+}
+
+// VecForEach calls fn for each element of the vector.
+// It panics if the receiver is not a vector.
+func (h *HalfDense[H]) VecForEach(fn func(i int, v float64)) {
+	h.Upcast().VecForEach(fn)
+}
+
+// Apply creates a new Dense matrix executing the unary function fn.
+func (h *HalfDense[H]) Apply(fn func(r, c int, v float64) float64) Matrix {
+	return h.Upcast().Apply(fn)
+}
+
+// ApplyInPlace executes the unary function fn over the matrix a,
+// and stores the result in the receiver, returning the receiver itself.
+func (h *HalfDense[H]) ApplyInPlace(fn func(r, c int, v float64) float64, a Matrix) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.ApplyInPlace(fn, a) })
+	return h
+}
+
+// ApplyWithAlpha creates a new Dense matrix executing the unary function
+// fn, taking additional parameters alpha.
+func (h *HalfDense[H]) ApplyWithAlpha(fn func(r, c int, v float64, alpha ...float64) float64, alpha ...float64) Matrix {
+	return h.Upcast().ApplyWithAlpha(fn, alpha...)
+}
+
+// ApplyWithAlphaInPlace executes the unary function fn over the matrix a,
+// taking additional parameters alpha, and stores the result in the
+// receiver, returning the receiver itself.
+func (h *HalfDense[H]) ApplyWithAlphaInPlace(fn func(r, c int, v float64, alpha ...float64) float64, a Matrix, alpha ...float64) Matrix {
+	h.inPlace(func(d *Dense[float32]) { d.ApplyWithAlphaInPlace(fn, a, alpha...) })
+	return h
+}
+
+// DoNonZero calls a function for each non-zero element of the matrix.
+// The parameters of the function are the element's indices and value.
+func (h *HalfDense[H]) DoNonZero(fn func(r, c int, v float64)) {
+	h.Upcast().DoNonZero(fn)
+}
+
+// DoVecNonZero calls a function for each non-zero element of the vector.
+// The parameters of the function are the element's index and value.
+func (h *HalfDense[H]) DoVecNonZero(fn func(i int, v float64)) {
+	h.Upcast().DoVecNonZero(fn)
+}
+
+// Clone returns a new HalfDense matrix, copying all its values from the
+// receiver.
+func (h *HalfDense[H]) Clone() Matrix {
+	return &HalfDense[H]{
+		rows: h.rows,
+		cols: h.cols,
+		data: append([]H(nil), h.data...),
+	}
+}
+
+// Copy copies the data from the other matrix to the receiver, rounding it
+// to the type H if necessary.
+// It panics if the matrices have different dimensions.
+func (h *HalfDense[H]) Copy(other Matrix) {
+	if !SameDims(h, other) {
+		panic("mat: incompatible matrix dimensions")
+	}
+	if o, ok := other.(*HalfDense[H]); ok {
+		copy(h.data, o.data)
+		return
+	}
+	h.setFromData(Data[float32](other))
+}
+
+// String returns a string representation of the matrix.
+func (h *HalfDense[H]) String() string {
+	return fmt.Sprintf("Matrix|HalfDense[%T](%d×%d)%v", H(0), h.rows, h.cols, h.data)
+}
+
+// NewMatrix creates a new Dense matrix, of the same data type of the
+// receiver, of size rows×cols, initialized with a copy of raw data.
+func (h *HalfDense[H]) NewMatrix(rows, cols int, data float.Slice) Matrix {
+	return NewDense[float32](rows, cols, float.SliceValueOf[float32](data))
+}
+
+// NewVec creates a new Dense column vector (len(data)×1), of the same data
+// type of the receiver, initialized with a copy of raw data.
+func (h *HalfDense[H]) NewVec(data float.Slice) Matrix {
+	return NewVecDense[float32](float.SliceValueOf[float32](data))
+}
+
+// NewScalar creates a new 1×1 matrix, of the same data type of the receiver,
+// containing the given value.
+func (h *HalfDense[H]) NewScalar(v float64) Matrix {
+	return NewScalar(float32(v))
+}
+
+// NewEmptyVec creates a new Dense vector, of the same data type of the
+// receiver, with dimensions size×1, initialized with zeros.
+func (h *HalfDense[H]) NewEmptyVec(size int) Matrix {
+	return NewEmptyVecDense[float32](size)
+}
+
+// NewEmptyMatrix creates a new rows×cols Dense matrix, of the same data
+// type of the receiver, initialized with zeros.
+func (h *HalfDense[H]) NewEmptyMatrix(rows, cols int) Matrix {
+	return NewEmptyDense[float32](rows, cols)
+}
+
+// NewInitMatrix creates a new rows×cols dense matrix, of the same data type
+// of the receiver, initialized with a constant value.
+func (h *HalfDense[H]) NewInitMatrix(rows, cols int, v float64) Matrix {
+	return NewInitDense(rows, cols, float32(v))
+}
+
+// NewInitFuncMatrix creates a new rows×cols dense matrix, of the same data
+// type of the receiver, initialized with the values returned from the
+// callback function.
+func (h *HalfDense[H]) NewInitFuncMatrix(rows, cols int, fn func(r, c int) float64) Matrix {
+	return NewInitFuncDense(rows, cols, func(r, c int) float32 {
+		return float32(fn(r, c))
+	})
+}
+
+// NewInitVec creates a new Dense column vector (size×1), of the same data
+// type of the receiver, initialized with a constant value.
+func (h *HalfDense[H]) NewInitVec(size int, v float64) Matrix {
+	return NewInitVecDense(size, float32(v))
+}
+
+// NewIdentityMatrix creates a new square Dense identity matrix (size×size),
+// of the same data type of the receiver.
+func (h *HalfDense[H]) NewIdentityMatrix(size int) Matrix {
+	return NewIdentityDense[float32](size)
+}
+
+// NewOneHotVec creates a new Dense one-hot column vector (size×1), of the
+// same data type of the receiver.
+func (h *HalfDense[H]) NewOneHotVec(size int, oneAt int) Matrix {
+	return NewOneHotVecDense[float32](size, oneAt)
+}
+
+// NewConcatV creates a new Dense column vector, of the same data type of
+// the receiver, concatenating two or more vectors "vertically".
+func (h *HalfDense[H]) NewConcatV(vs ...Matrix) Matrix {
+	return ConcatV[float32](vs...)
+}
+
+// NewStack creates a new Dense matrix, of the same data type of the
+// receiver, stacking two or more vectors of the same size on top of each
+// other.
+func (h *HalfDense[H]) NewStack(vs ...Matrix) Matrix {
+	return Stack[float32](vs...)
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+func init() {
+	gob.Register(&HalfDense[float.Float16]{})
+	gob.Register(&HalfDense[float.BFloat16]{})
+}
+
+// HalfDense matrices are marshaled in the same binary form produced by
+// Dense.MarshalBinaryFloat16 and Dense.MarshalBinaryBFloat16, so that they
+// can be unmarshaled into Dense matrices too, and vice versa.
+
+// MarshalBinary marshals a HalfDense matrix into binary form.
+func (h HalfDense[H]) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 17+len(h.data)*2)
+	data[0] = halfBinaryIdentifier[H]()
+	binary.LittleEndian.PutUint64(data[1:], uint64(h.rows))
+	binary.LittleEndian.PutUint64(data[9:], uint64(h.cols))
+
+	s := data[17:]
+	for _, v := range h.data {
+		binary.LittleEndian.PutUint16(s, uint16(v))
+		s = s[2:]
+	}
+	return data, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of a HalfDense matrix.
+// The representation can only be unmarshaled into a matrix of the same
+// 16-bit type.
+func (h *HalfDense[H]) UnmarshalBinary(data []byte) error {
+	if len(data) < 17 || data[0] != halfBinaryIdentifier[H]() {
+		return fmt.Errorf("mat: cannot unmarshal HalfDense[%T]: invalid identifier", H(0))
+	}
+	rows := int(binary.LittleEndian.Uint64(data[1:]))
+	cols := int(binary.LittleEndian.Uint64(data[9:]))
+	if len(data) != 17+rows*cols*2 {
+		return fmt.Errorf("mat: cannot unmarshal HalfDense[%T]: invalid data length", H(0))
+	}
+
+	h.rows, h.cols = rows, cols
+	h.data = make([]H, rows*cols)
+	s := data[17:]
+	for i := range h.data {
+		h.data[i] = H(binary.LittleEndian.Uint16(s))
+		s = s[2:]
+	}
+	return nil
+}
+
+// halfBinaryIdentifier returns the identifier of the binary form of a
+// matrix of 16-bit values of type H.
+func halfBinaryIdentifier[H float.Half]() byte {
+	switch any(H(0)).(type) {
+	case float.Float16:
+		return binaryDenseFloat16
+	case float.BFloat16:
+		return binaryDenseBFloat16
+	default:
+		panic(fmt.Sprintf("mat: unexpected half type %T", H(0)))
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// NewHalfDense returns a new rows×cols HalfDense matrix, initialized with a
+// copy of the given 16-bit values, interpreted in row-major order.
+// It panics if the length of data is not rows*cols.
+func NewHalfDense[H float.Half](rows, cols int, data []H) *HalfDense[H] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if len(data) != rows*cols {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", rows*cols))
+	}
+	return &HalfDense[H]{rows: rows, cols: cols, data: append([]H(nil), data...)}
+}
+
+// NewEmptyHalfDense returns a new rows×cols HalfDense matrix,
+// initialized with zeros.
+func NewEmptyHalfDense[H float.Half](rows, cols int) *HalfDense[H] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	return &HalfDense[H]{rows: rows, cols: cols, data: make([]H, rows*cols)}
+}
+
+// NewHalfDenseFromMatrix returns a new HalfDense matrix with the same
+// dimensions of m, and its values rounded to the type H.
+func NewHalfDenseFromMatrix[H float.Half](m Matrix) *HalfDense[H] {
+	if h, ok := m.(*HalfDense[H]); ok {
+		return h.Clone().(*HalfDense[H])
+	}
+	return &HalfDense[H]{
+		rows: m.Rows(),
+		cols: m.Columns(),
+		data: float.HalfSliceValueOf[H](m.Data()),
+	}
+}
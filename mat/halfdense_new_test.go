@@ -0,0 +1,63 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHalfDense(t *testing.T) {
+	t.Run("Float16", testNewHalfDense[float.Float16])
+	t.Run("BFloat16", testNewHalfDense[float.BFloat16])
+}
+
+func testNewHalfDense[H float.Half](t *testing.T) {
+	data := float.HalfSliceValueOf[H](float.SliceInterface([]float32{1, 2, 3, 4, 5, 6}))
+	h := NewHalfDense[H](2, 3, data)
+	assert.Equal(t, 2, h.Rows())
+	assert.Equal(t, 3, h.Columns())
+	assert.Equal(t, []float32{1, 2, 3, 4, 5, 6}, Data[float32](h))
+
+	data[0] = 0
+	assert.Equal(t, float32(1), h.ScalarAt(0, 0).F32(), "data must be copied")
+
+	require.Panics(t, func() { NewHalfDense[H](-1, 2, nil) })
+	require.Panics(t, func() { NewHalfDense[H](2, 2, data) })
+}
+
+func TestNewEmptyHalfDense(t *testing.T) {
+	t.Run("Float16", testNewEmptyHalfDense[float.Float16])
+	t.Run("BFloat16", testNewEmptyHalfDense[float.BFloat16])
+}
+
+func testNewEmptyHalfDense[H float.Half](t *testing.T) {
+	h := NewEmptyHalfDense[H](2, 3)
+	assert.Equal(t, []float32{0, 0, 0, 0, 0, 0}, Data[float32](h))
+	require.Panics(t, func() { NewEmptyHalfDense[H](2, -1) })
+}
+
+func TestNewHalfDenseFromMatrix(t *testing.T) {
+	t.Run("Float16", testNewHalfDenseFromMatrix[float.Float16])
+	t.Run("BFloat16", testNewHalfDenseFromMatrix[float.BFloat16])
+}
+
+func testNewHalfDenseFromMatrix[H float.Half](t *testing.T) {
+	d := NewDense[float64](2, 2, []float64{-1, 0, 0.1, 2})
+	h := NewHalfDenseFromMatrix[H](d)
+	assert.Equal(t, []H{
+		float.HalfFromFloat32[H](-1),
+		0,
+		float.HalfFromFloat32[H](0.1),
+		float.HalfFromFloat32[H](2),
+	}, h.HalfData())
+
+	c := NewHalfDenseFromMatrix[H](h)
+	assert.NotSame(t, h, c)
+	assert.Equal(t, h, c)
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"testing"
+	"unsafe"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Matrix = &HalfDense[float.Float16]{}
+var _ Matrix = &HalfDense[float.BFloat16]{}
+
+func newTestHalfDense[H float.Half]() *HalfDense[H] {
+	return NewHalfDenseFromMatrix[H](NewDense[float32](3, 4, []float32{
+		0.5, -1.25, 0.0, 2.5,
+		0.0, 0.0, 0.0, 0.0,
+		3.0, 3.0, -3.0, 3.0,
+	}))
+}
+
+func TestHalfDense_Storage(t *testing.T) {
+	t.Run("Float16", testHalfDenseStorage[float.Float16])
+	t.Run("BFloat16", testHalfDenseStorage[float.BFloat16])
+}
+
+func testHalfDenseStorage[H float.Half](t *testing.T) {
+	h := newTestHalfDense[H]()
+	assert.Len(t, h.HalfData(), 12)
+	assert.Equal(t, uintptr(2), unsafe.Sizeof(h.HalfData()[0]))
+
+	// The test values are exactly representable in both formats.
+	d := h.Upcast()
+	assertDenseDims(t, 3, 4, d)
+	assert.Equal(t, []float32{
+		0.5, -1.25, 0.0, 2.5,
+		0.0, 0.0, 0.0, 0.0,
+		3.0, 3.0, -3.0, 3.0,
+	}, d.data)
+	assert.Equal(t, d.data, Data[float32](h))
+	assert.Equal(t, 32, h.Data().BitSize())
+
+	h.SetScalar(1, 2, float.Interface(float32(0.1)))
+	assert.InDelta(t, 0.1, h.ScalarAt(1, 2).F64(), 1.0e-3)
+	assert.Equal(t, float.HalfFromFloat32[H](0.1), h.HalfData()[6])
+	require.Panics(t, func() { h.ScalarAt(3, 0) })
+}
+
+func TestHalfDense_Mul(t *testing.T) {
+	t.Run("Float16", testHalfDenseMul[float.Float16])
+	t.Run("BFloat16", testHalfDenseMul[float.BFloat16])
+}
+
+func testHalfDenseMul[H float.Half](t *testing.T) {
+	h := newTestHalfDense[H]()
+	d := h.Upcast()
+
+	t.Run("half × dense", func(t *testing.T) {
+		b := NewDense[float32](4, 2, []float32{
+			1, 2,
+			3, 4,
+			5, 6,
+			7, 8,
+		})
+		y := h.Mul(b)
+		assertDenseDims(t, 3, 2, y.(*Dense[float32]))
+		assert.Equal(t, Data[float32](d.Mul(b)), Data[float32](y))
+
+		x := NewVecDense([]float32{1, -1, 0.5, 2})
+		assert.Equal(t, Data[float32](d.Mul(x)), Data[float32](h.Mul(x)))
+	})
+
+	t.Run("half transposed × dense", func(t *testing.T) {
+		b := NewDense[float32](3, 2, []float32{
+			1, 2,
+			3, 4,
+			5, 6,
+		})
+		y := h.MulT(b)
+		assertDenseDims(t, 4, 2, y.(*Dense[float32]))
+		assert.Equal(t, Data[float32](d.T().Mul(b)), Data[float32](y))
+	})
+
+	t.Run("dense × half", func(t *testing.T) {
+		a := NewDense[float32](2, 3, []float32{
+			1, 2, 3,
+			4, 5, 6,
+		})
+		y := a.Mul(h)
+		assertDenseDims(t, 2, 4, y.(*Dense[float32]))
+		assert.Equal(t, Data[float32](a.Mul(d)), Data[float32](y))
+	})
+
+	t.Run("dense transposed × half", func(t *testing.T) {
+		a := NewDense[float32](3, 2, []float32{
+			1, 2,
+			3, 4,
+			5, 6,
+		})
+		y := a.MulT(h)
+		assertDenseDims(t, 2, 4, y.(*Dense[float32]))
+		assert.Equal(t, Data[float32](a.T().Mul(d)), Data[float32](y))
+	})
+
+	t.Run("incompatible dimensions", func(t *testing.T) {
+		require.Panics(t, func() { h.Mul(NewEmptyDense[float32](3, 1)) })
+		require.Panics(t, func() { NewEmptyDense[float32](2, 2).Mul(h) })
+	})
+}
+
+func TestHalfDense_InPlace(t *testing.T) {
+	t.Run("Float16", testHalfDenseInPlace[float.Float16])
+	t.Run("BFloat16", testHalfDenseInPlace[float.BFloat16])
+}
+
+func testHalfDenseInPlace[H float.Half](t *testing.T) {
+	h := NewHalfDenseFromMatrix[H](NewDense[float32](2, 2, []float32{1, 2, 3, 4}))
+
+	y := h.Clone().(*HalfDense[H])
+	y.AddInPlace(NewInitDense[float32](2, 2, 1))
+	assert.Equal(t, []float32{2, 3, 4, 5}, Data[float32](y))
+	assert.Equal(t, []float32{1, 2, 3, 4}, Data[float32](h))
+
+	// The results are rounded to the 16-bit type.
+	y.ProdScalarInPlace(0.1)
+	assert.Equal(t, float.HalfFromFloat32[H](0.2), y.HalfData()[0])
+
+	z := h.Add(h)
+	assertDenseDims(t, 2, 2, z.(*Dense[float32]))
+	assert.Equal(t, []float32{2, 4, 6, 8}, Data[float32](z))
+
+	require.NotPanics(t, func() { ReleaseMatrix(h) })
+}
+
+func TestHalfDense_Marshaling(t *testing.T) {
+	t.Run("Float16", testHalfDenseMarshaling[float.Float16])
+	t.Run("BFloat16", testHalfDenseMarshaling[float.BFloat16])
+}
+
+func testHalfDenseMarshaling[H float.Half](t *testing.T) {
+	h := newTestHalfDense[H]()
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := h.MarshalBinary()
+		require.NoError(t, err)
+		assert.Len(t, data, 17+12*2)
+
+		y := new(HalfDense[H])
+		require.NoError(t, y.UnmarshalBinary(data))
+		assert.Equal(t, h, y)
+
+		assert.Error(t, y.UnmarshalBinary(data[:len(data)-1]))
+
+		// The binary form is the same of the half-precision Dense one.
+		d := new(Dense[float32])
+		require.NoError(t, d.UnmarshalBinary(data))
+		assert.Equal(t, Data[float32](h), d.data)
+	})
+
+	t.Run("matrix", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, MarshalBinaryMatrix(h, &buf))
+
+		y, err := UnmarshalBinaryMatrix(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, h, y)
+	})
+}
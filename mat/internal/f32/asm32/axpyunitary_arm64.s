@@ -0,0 +1,57 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && !gccgo && !safe
+// +build !noasm,!gccgo,!safe
+
+#include "textflag.h"
+
+// func axpyUnitaryNEON(alpha float32, x, y []float32)
+TEXT ·axpyUnitaryNEON(SB), NOSPLIT, $0-56
+	MOVD x_base+8(FP), R0  // R0 = &x
+	MOVD y_base+32(FP), R1 // R1 = &y
+	MOVD x_len+16(FP), R2  // R2 = min( len(x), len(y) )
+	MOVD y_len+40(FP), R3
+	CMP  R2, R3
+	CSEL LT, R3, R2, R2
+	CBZ  R2, axpy_end      // if R2 == 0 { return }
+
+	FMOVS alpha+0(FP), F31
+	VDUP  V31.S[0], V31.S4 // V31 = { alpha, ... }
+
+axpy_loop_unrolled: // Loop unrolled 16x
+	CMP    $16, R2
+	BLT    axpy_loop
+	VLD1.P 64(R0), [V0.S4, V1.S4, V2.S4, V3.S4]
+	VLD1   (R1), [V4.S4, V5.S4, V6.S4, V7.S4]
+	VFMLA  V31.S4, V0.S4, V4.S4 // y[i] += alpha * x[i]
+	VFMLA  V31.S4, V1.S4, V5.S4
+	VFMLA  V31.S4, V2.S4, V6.S4
+	VFMLA  V31.S4, V3.S4, V7.S4
+	VST1.P [V4.S4, V5.S4, V6.S4, V7.S4], 64(R1)
+	SUB    $16, R2
+	B      axpy_loop_unrolled
+
+axpy_loop: // Loop over a single register
+	CMP    $4, R2
+	BLT    axpy_tail
+	VLD1.P 16(R0), [V0.S4]
+	VLD1   (R1), [V4.S4]
+	VFMLA  V31.S4, V0.S4, V4.S4
+	VST1.P [V4.S4], 16(R1)
+	SUB    $4, R2
+	B      axpy_loop
+
+axpy_tail: // Scalar tail
+	CBZ      R2, axpy_end
+	FMOVS.P 4(R0), F0
+	FMOVS   (R1), F1
+	FMULS   F31, F0
+	FADDS   F0, F1
+	FMOVS.P F1, 4(R1)
+	SUB      $1, R2
+	B        axpy_tail
+
+axpy_end:
+	RET
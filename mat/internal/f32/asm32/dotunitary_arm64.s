@@ -0,0 +1,72 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && !gccgo && !safe
+// +build !noasm,!gccgo,!safe
+
+#include "textflag.h"
+
+// func dotUnitaryNEON(x, y []float32) (sum float32)
+TEXT ·dotUnitaryNEON(SB), NOSPLIT, $0-52
+	MOVD x_base+0(FP), R0  // R0 = &x
+	MOVD y_base+24(FP), R1 // R1 = &y
+	MOVD x_len+8(FP), R2   // R2 = min( len(x), len(y) )
+	MOVD y_len+32(FP), R3
+	CMP  R2, R3
+	CSEL LT, R3, R2, R2
+
+	VEOR V16.B16, V16.B16, V16.B16 // Partial sums
+	VEOR V17.B16, V17.B16, V17.B16
+	VEOR V18.B16, V18.B16, V18.B16
+	VEOR V19.B16, V19.B16, V19.B16
+
+dot_loop_unrolled: // Loop unrolled 16x
+	CMP    $16, R2
+	BLT    dot_loop
+	VLD1.P 64(R0), [V0.S4, V1.S4, V2.S4, V3.S4]
+	VLD1.P 64(R1), [V4.S4, V5.S4, V6.S4, V7.S4]
+	VFMLA  V4.S4, V0.S4, V16.S4 // sum += x[i] * y[i]
+	VFMLA  V5.S4, V1.S4, V17.S4
+	VFMLA  V6.S4, V2.S4, V18.S4
+	VFMLA  V7.S4, V3.S4, V19.S4
+	SUB    $16, R2
+	B      dot_loop_unrolled
+
+dot_loop: // Loop over a single register
+	CMP    $4, R2
+	BLT    dot_reduce
+	VLD1.P 16(R0), [V0.S4]
+	VLD1.P 16(R1), [V4.S4]
+	VFMLA  V4.S4, V0.S4, V16.S4
+	SUB    $4, R2
+	B      dot_loop
+
+dot_reduce: // Fold the partial sums into F16
+	MOVW  $0x3f800000, R3
+	VDUP  R3, V31.S4
+	VFMLA V31.S4, V17.S4, V16.S4
+	VFMLA V31.S4, V18.S4, V16.S4
+	VFMLA V31.S4, V19.S4, V16.S4
+	VMOV  V16.S[1], R3
+	VMOV  V16.S[2], R4
+	VMOV  V16.S[3], R5
+	FMOVS R3, F1
+	FMOVS R4, F2
+	FMOVS R5, F3
+	FADDS F1, F16
+	FADDS F2, F16
+	FADDS F3, F16
+
+dot_tail: // Scalar tail
+	CBZ      R2, dot_end
+	FMOVS.P 4(R0), F0
+	FMOVS.P 4(R1), F1
+	FMULS   F0, F1
+	FADDS   F1, F16
+	SUB      $1, R2
+	B        dot_tail
+
+dot_end:
+	FMOVS F16, sum+48(FP)
+	RET
@@ -0,0 +1,64 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && !gccgo && !safe
+// +build !noasm,!gccgo,!safe
+
+package asm32
+
+import "github.com/nlpodyssey/spago/mat/internal/matfuncs/cpu"
+
+var hasASIMD = cpu.ARM64.HasASIMD
+
+// AxpyUnitary is
+//  for i, v := range x {
+//  	y[i] += alpha * v
+//  }
+func AxpyUnitary(alpha float32, x, y []float32) {
+	if hasASIMD {
+		axpyUnitaryNEON(alpha, x, y)
+		return
+	}
+	for i, v := range x {
+		y[i] += alpha * v
+	}
+}
+
+// AxpyUnitaryTo is
+//  for i, v := range x {
+//  	dst[i] = alpha*v + y[i]
+//  }
+func AxpyUnitaryTo(dst []float32, alpha float32, x, y []float32) {
+	if hasASIMD {
+		axpyUnitaryToNEON(dst, alpha, x, y)
+		return
+	}
+	for i, v := range x {
+		dst[i] = alpha*v + y[i]
+	}
+}
+
+// DotUnitary is
+//  for i, v := range x {
+//  	sum += y[i] * v
+//  }
+//  return sum
+func DotUnitary(x, y []float32) (sum float32) {
+	if hasASIMD {
+		return dotUnitaryNEON(x, y)
+	}
+	for i, v := range x {
+		sum += y[i] * v
+	}
+	return sum
+}
+
+//go:noescape
+func axpyUnitaryNEON(alpha float32, x, y []float32)
+
+//go:noescape
+func axpyUnitaryToNEON(dst []float32, alpha float32, x, y []float32)
+
+//go:noescape
+func dotUnitaryNEON(x, y []float32) (sum float32)
@@ -7,26 +7,6 @@
 
 package asm32
 
-// AxpyUnitary is
-//  for i, v := range x {
-//  	y[i] += alpha * v
-//  }
-func AxpyUnitary(alpha float32, x, y []float32) {
-	for i, v := range x {
-		y[i] += alpha * v
-	}
-}
-
-// AxpyUnitaryTo is
-//  for i, v := range x {
-//  	dst[i] = alpha*v + y[i]
-//  }
-func AxpyUnitaryTo(dst []float32, alpha float32, x, y []float32) {
-	for i, v := range x {
-		dst[i] = alpha*v + y[i]
-	}
-}
-
 // AxpyInc is
 //  for i := 0; i < int(n); i++ {
 //  	y[iy] += alpha * x[ix]
@@ -57,18 +37,6 @@ func AxpyIncTo(dst []float32, incDst, idst uintptr, alpha float32, x, y []float3
 	}
 }
 
-// DotUnitary is
-//  for i, v := range x {
-//  	sum += y[i] * v
-//  }
-//  return sum
-func DotUnitary(x, y []float32) (sum float32) {
-	for i, v := range x {
-		sum += y[i] * v
-	}
-	return sum
-}
-
 // DotInc is
 //  for i := 0; i < int(n); i++ {
 //  	sum += y[iy] * x[ix]
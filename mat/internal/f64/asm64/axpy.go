@@ -7,26 +7,6 @@
 
 package asm64
 
-// AxpyUnitary is
-//  for i, v := range x {
-//  	y[i] += alpha * v
-//  }
-func AxpyUnitary(alpha float64, x, y []float64) {
-	for i, v := range x {
-		y[i] += alpha * v
-	}
-}
-
-// AxpyUnitaryTo is
-//  for i, v := range x {
-//  	dst[i] = alpha*v + y[i]
-//  }
-func AxpyUnitaryTo(dst []float64, alpha float64, x, y []float64) {
-	for i, v := range x {
-		dst[i] = alpha*v + y[i]
-	}
-}
-
 // AxpyInc is
 //  for i := 0; i < int(n); i++ {
 //  	y[iy] += alpha * x[ix]
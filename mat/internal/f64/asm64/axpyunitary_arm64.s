@@ -0,0 +1,57 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && !gccgo && !safe
+// +build !noasm,!gccgo,!safe
+
+#include "textflag.h"
+
+// func axpyUnitaryNEON(alpha float64, x, y []float64)
+TEXT ·axpyUnitaryNEON(SB), NOSPLIT, $0-56
+	MOVD x_base+8(FP), R0  // R0 = &x
+	MOVD y_base+32(FP), R1 // R1 = &y
+	MOVD x_len+16(FP), R2  // R2 = min( len(x), len(y) )
+	MOVD y_len+40(FP), R3
+	CMP  R2, R3
+	CSEL LT, R3, R2, R2
+	CBZ  R2, axpy_end      // if R2 == 0 { return }
+
+	FMOVD alpha+0(FP), F31
+	VDUP  V31.D[0], V31.D2 // V31 = { alpha, ... }
+
+axpy_loop_unrolled: // Loop unrolled 8x
+	CMP    $8, R2
+	BLT    axpy_loop
+	VLD1.P 64(R0), [V0.D2, V1.D2, V2.D2, V3.D2]
+	VLD1   (R1), [V4.D2, V5.D2, V6.D2, V7.D2]
+	VFMLA  V31.D2, V0.D2, V4.D2 // y[i] += alpha * x[i]
+	VFMLA  V31.D2, V1.D2, V5.D2
+	VFMLA  V31.D2, V2.D2, V6.D2
+	VFMLA  V31.D2, V3.D2, V7.D2
+	VST1.P [V4.D2, V5.D2, V6.D2, V7.D2], 64(R1)
+	SUB    $8, R2
+	B      axpy_loop_unrolled
+
+axpy_loop: // Loop over a single register
+	CMP    $2, R2
+	BLT    axpy_tail
+	VLD1.P 16(R0), [V0.D2]
+	VLD1   (R1), [V4.D2]
+	VFMLA  V31.D2, V0.D2, V4.D2
+	VST1.P [V4.D2], 16(R1)
+	SUB    $2, R2
+	B      axpy_loop
+
+axpy_tail: // Scalar tail
+	CBZ      R2, axpy_end
+	FMOVD.P 8(R0), F0
+	FMOVD   (R1), F1
+	FMULD   F31, F0
+	FADDD   F0, F1
+	FMOVD.P F1, 8(R1)
+	SUB      $1, R2
+	B        axpy_tail
+
+axpy_end:
+	RET
@@ -0,0 +1,61 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && !gccgo && !safe
+// +build !noasm,!gccgo,!safe
+
+#include "textflag.h"
+
+// func axpyUnitaryToNEON(dst []float64, alpha float64, x, y []float64)
+TEXT ·axpyUnitaryToNEON(SB), NOSPLIT, $0-80
+	MOVD dst_base+0(FP), R0 // R0 = &dst
+	MOVD x_base+32(FP), R1  // R1 = &x
+	MOVD y_base+56(FP), R2  // R2 = &y
+	MOVD x_len+40(FP), R3   // R3 = min( len(x), len(y), len(dst) )
+	MOVD y_len+64(FP), R4
+	CMP  R3, R4
+	CSEL LT, R4, R3, R3
+	MOVD dst_len+8(FP), R4
+	CMP  R3, R4
+	CSEL LT, R4, R3, R3
+	CBZ  R3, axpy_end       // if R3 == 0 { return }
+
+	FMOVD alpha+24(FP), F31
+	VDUP  V31.D[0], V31.D2 // V31 = { alpha, ... }
+
+axpy_loop_unrolled: // Loop unrolled 8x
+	CMP    $8, R3
+	BLT    axpy_loop
+	VLD1.P 64(R1), [V0.D2, V1.D2, V2.D2, V3.D2]
+	VLD1.P 64(R2), [V4.D2, V5.D2, V6.D2, V7.D2]
+	VFMLA  V31.D2, V0.D2, V4.D2 // dst[i] = alpha * x[i] + y[i]
+	VFMLA  V31.D2, V1.D2, V5.D2
+	VFMLA  V31.D2, V2.D2, V6.D2
+	VFMLA  V31.D2, V3.D2, V7.D2
+	VST1.P [V4.D2, V5.D2, V6.D2, V7.D2], 64(R0)
+	SUB    $8, R3
+	B      axpy_loop_unrolled
+
+axpy_loop: // Loop over a single register
+	CMP    $2, R3
+	BLT    axpy_tail
+	VLD1.P 16(R1), [V0.D2]
+	VLD1.P 16(R2), [V4.D2]
+	VFMLA  V31.D2, V0.D2, V4.D2
+	VST1.P [V4.D2], 16(R0)
+	SUB    $2, R3
+	B      axpy_loop
+
+axpy_tail: // Scalar tail
+	CBZ      R3, axpy_end
+	FMOVD.P 8(R1), F0
+	FMOVD.P 8(R2), F1
+	FMULD   F31, F0
+	FADDD   F0, F1
+	FMOVD.P F1, 8(R0)
+	SUB      $1, R3
+	B        axpy_tail
+
+axpy_end:
+	RET
@@ -7,18 +7,6 @@
 
 package asm64
 
-// DotUnitary is
-//  for i, v := range x {
-//  	sum += y[i] * v
-//  }
-//  return sum
-func DotUnitary(x, y []float64) (sum float64) {
-	for i, v := range x {
-		sum += y[i] * v
-	}
-	return sum
-}
-
 // DotInc is
 //  for i := 0; i < int(n); i++ {
 //  	sum += y[iy] * x[ix]
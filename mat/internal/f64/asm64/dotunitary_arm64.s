@@ -0,0 +1,66 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && !gccgo && !safe
+// +build !noasm,!gccgo,!safe
+
+#include "textflag.h"
+
+// func dotUnitaryNEON(x, y []float64) (sum float64)
+TEXT ·dotUnitaryNEON(SB), NOSPLIT, $0-56
+	MOVD x_base+0(FP), R0  // R0 = &x
+	MOVD y_base+24(FP), R1 // R1 = &y
+	MOVD x_len+8(FP), R2   // R2 = min( len(x), len(y) )
+	MOVD y_len+32(FP), R3
+	CMP  R2, R3
+	CSEL LT, R3, R2, R2
+
+	VEOR V16.B16, V16.B16, V16.B16 // Partial sums
+	VEOR V17.B16, V17.B16, V17.B16
+	VEOR V18.B16, V18.B16, V18.B16
+	VEOR V19.B16, V19.B16, V19.B16
+
+dot_loop_unrolled: // Loop unrolled 8x
+	CMP    $8, R2
+	BLT    dot_loop
+	VLD1.P 64(R0), [V0.D2, V1.D2, V2.D2, V3.D2]
+	VLD1.P 64(R1), [V4.D2, V5.D2, V6.D2, V7.D2]
+	VFMLA  V4.D2, V0.D2, V16.D2 // sum += x[i] * y[i]
+	VFMLA  V5.D2, V1.D2, V17.D2
+	VFMLA  V6.D2, V2.D2, V18.D2
+	VFMLA  V7.D2, V3.D2, V19.D2
+	SUB    $8, R2
+	B      dot_loop_unrolled
+
+dot_loop: // Loop over a single register
+	CMP    $2, R2
+	BLT    dot_reduce
+	VLD1.P 16(R0), [V0.D2]
+	VLD1.P 16(R1), [V4.D2]
+	VFMLA  V4.D2, V0.D2, V16.D2
+	SUB    $2, R2
+	B      dot_loop
+
+dot_reduce: // Fold the partial sums into F16
+	MOVD  $0x3ff0000000000000, R3
+	VDUP  R3, V31.D2
+	VFMLA V31.D2, V17.D2, V16.D2
+	VFMLA V31.D2, V18.D2, V16.D2
+	VFMLA V31.D2, V19.D2, V16.D2
+	VMOV  V16.D[1], R3
+	FMOVD R3, F1
+	FADDD F1, F16
+
+dot_tail: // Scalar tail
+	CBZ      R2, dot_end
+	FMOVD.P 8(R0), F0
+	FMOVD.P 8(R1), F1
+	FMULD   F0, F1
+	FADDD   F1, F16
+	SUB      $1, R2
+	B        dot_tail
+
+dot_end:
+	FMOVD F16, sum+48(FP)
+	RET
@@ -0,0 +1,125 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !noasm && !gccgo && !safe
+// +build !noasm,!gccgo,!safe
+
+package asm64
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// neonMaxLen is the maximum length of the slices given to the NEON kernels
+// by the tests below, covering every tail length of the unrolled loops.
+const neonMaxLen = 33
+
+// neonGuard is the value stored right after the end of the output slices,
+// to detect the kernels writing out of bounds.
+const neonGuard = -12345
+
+// forEachNEONCase calls f with every length from 0 to neonMaxLen, and with
+// every offset from 0 to 3, so that the slices start at unaligned addresses.
+func forEachNEONCase(t *testing.T, f func(t *testing.T, size, offset int)) {
+	if !hasASIMD {
+		t.Skip("ASIMD not available")
+	}
+	for size := 0; size <= neonMaxLen; size++ {
+		for offset := 0; offset < 4; offset++ {
+			t.Run(fmt.Sprintf("size %d offset %d", size, offset), func(t *testing.T) {
+				f(t, size, offset)
+			})
+		}
+	}
+}
+
+// newNEONVec returns a random slice of the given size, starting at the given
+// offset of its backing array, followed by a guard value.
+func newNEONVec(size, offset int) []float64 {
+	backing := make([]float64, offset+size+1)
+	for i := range backing {
+		backing[i] = float64(rand.NormFloat64())
+	}
+	backing[offset+size] = neonGuard
+	return backing[offset : offset+size]
+}
+
+// requireNEONSlice compares the values within a tolerance, since the kernels
+// use fused multiply-add instructions, which the compiler may or may not use
+// for the reference loops.
+func requireNEONSlice(t *testing.T, expected, actual []float64) {
+	for i, e := range expected {
+		if d := math.Abs(float64(e - actual[i])); d > 1e-14 {
+			t.Fatalf("value at index %d: expected %G, actual %G", i, e, actual[i])
+		}
+	}
+	if g := actual[:len(actual)+1][len(actual)]; g != neonGuard {
+		t.Fatalf("value after the end of the output overwritten: %G", g)
+	}
+}
+
+func TestAxpyUnitaryNEON(t *testing.T) {
+	forEachNEONCase(t, func(t *testing.T, size, offset int) {
+		x := newNEONVec(size, offset)
+		y := newNEONVec(size, (offset+1)%4)
+		expected := append([]float64(nil), y...)
+		for i, v := range x {
+			expected[i] += 3 * v
+		}
+
+		axpyUnitaryNEON(3, x, y)
+
+		requireNEONSlice(t, expected, y)
+	})
+
+	t.Run("nil slices", func(t *testing.T) {
+		axpyUnitaryNEON(3, nil, nil)
+	})
+}
+
+func TestAxpyUnitaryToNEON(t *testing.T) {
+	forEachNEONCase(t, func(t *testing.T, size, offset int) {
+		x := newNEONVec(size, offset)
+		y := newNEONVec(size, (offset+1)%4)
+		expected := make([]float64, size)
+		for i, v := range x {
+			expected[i] = 3*v + y[i]
+		}
+
+		dst := newNEONVec(size, (offset+2)%4)
+		axpyUnitaryToNEON(dst, 3, x, y)
+
+		requireNEONSlice(t, expected, dst)
+	})
+
+	t.Run("nil slices", func(t *testing.T) {
+		axpyUnitaryToNEON(nil, 3, nil, nil)
+	})
+}
+
+func TestDotUnitaryNEON(t *testing.T) {
+	forEachNEONCase(t, func(t *testing.T, size, offset int) {
+		x := newNEONVec(size, offset)
+		y := newNEONVec(size, (offset+1)%4)
+		var expected float64
+		for i, v := range x {
+			expected += y[i] * v
+		}
+
+		actual := dotUnitaryNEON(x, y)
+
+		if d := math.Abs(float64(expected - actual)); d > 1e-12*math.Max(1, float64(size)) {
+			t.Fatalf("expected %G, actual %G", expected, actual)
+		}
+	})
+
+	t.Run("nil slices", func(t *testing.T) {
+		if actual := dotUnitaryNEON(nil, nil); actual != 0 {
+			t.Fatalf("expected 0, actual %G", actual)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gemm provides a cache-blocked, multi-threaded implementation of
+// the general matrix-matrix multiplication.
+package gemm
+
+import (
+	"fmt"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/nlpodyssey/spago/mat/internal/f32/asm32"
+	"github.com/nlpodyssey/spago/mat/internal/f64/asm64"
+)
+
+const (
+	// blockM is the number of rows of C assigned to a worker at a time.
+	blockM = 32
+	// blockK is the number of rows of a panel of B processed at a time.
+	blockK = 256
+	// blockN is the number of columns of a panel of B. A blockK×blockN
+	// block of B is expected to fit in the L2 cache, and a row of blockN
+	// values of C in the L1 cache.
+	blockN = 512
+	// minParallelWork is the minimum number of multiply-add operations
+	// which justify the use of more than one goroutine.
+	minParallelWork = 1 << 18
+)
+
+// Mul computes the matrix-matrix multiplication C += A * B, where A is
+// m×k, B is k×n and C is m×n, all stored in row-major order.
+//
+// The columns of B are packed in contiguous panels, which are visited in
+// cache-sized blocks. The rows of C are split in blocks, which are
+// distributed among at most "parallelism" goroutines, including the
+// calling one; if parallelism is less than 2, or the matrices are small,
+// the multiplication is computed on the calling goroutine only.
+//
+// The goroutines added to the calling ones by all the concurrent
+// multiplications are at most runtime.GOMAXPROCS(0)-1 (see reserveWorkers),
+// so that many multiplications running at once, as the operators of a
+// graph do, are mostly computed on their own goroutines, without
+// oversubscribing the CPUs.
+//
+// The innermost loop uses the AxpyUnitary kernels from the asm32 and asm64
+// packages, which have a pure-Go fallback on non-amd64 architectures.
+func Mul[F float32 | float64](parallelism, m, k, n int, a, b, c []F) {
+	if m == 0 || k == 0 || n == 0 {
+		return
+	}
+	axpy := axpyKernel[F]()
+
+	panels := b
+	if n > blockN {
+		packed := getPacked[F](k * n)
+		defer putPacked(packed)
+		panels = *packed
+		pack(k, n, b, panels)
+	}
+
+	compute := func(i0, i1 int) {
+		for j0 := 0; j0 < n; j0 += blockN {
+			nb := min(blockN, n-j0)
+			panel := panels[j0*k : j0*k+k*nb]
+			for k0 := 0; k0 < k; k0 += blockK {
+				k1 := min(k0+blockK, k)
+				for i := i0; i < i1; i++ {
+					cRow := c[i*n+j0 : i*n+j0+nb]
+					aRow := a[i*k : i*k+k]
+					for l := k0; l < k1; l++ {
+						if v := aRow[l]; v != 0 {
+							axpy(v, panel[l*nb:l*nb+nb], cRow)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	blocks := (m + blockM - 1) / blockM
+	workers := min(parallelism, blocks)
+	if workers < 2 || m*k*n < minParallelWork {
+		compute(0, m)
+		return
+	}
+	extra := reserveWorkers(workers - 1)
+	if extra == 0 {
+		compute(0, m)
+		return
+	}
+
+	var next int32 = -1
+	work := func() {
+		for {
+			block := int(atomic.AddInt32(&next, 1))
+			if block >= blocks {
+				return
+			}
+			i0 := block * blockM
+			compute(i0, min(i0+blockM, m))
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(extra)
+	for w := 0; w < extra; w++ {
+		go func() {
+			defer wg.Done()
+			defer atomic.AddInt32(&extraWorkers, -1)
+			work()
+		}()
+	}
+	work()
+	wg.Wait()
+}
+
+// extraWorkers is the number of goroutines currently computing a share of
+// a multiplication on behalf of another goroutine.
+var extraWorkers int32
+
+// reserveWorkers reserves up to n extra workers, so that the extra workers
+// of all the concurrent multiplications are at most GOMAXPROCS-1, and
+// returns the number of workers reserved. Each of them must decrement
+// extraWorkers when done.
+func reserveWorkers(n int) int {
+	limit := int32(runtime.GOMAXPROCS(0) - 1)
+	for {
+		busy := atomic.LoadInt32(&extraWorkers)
+		r := int32(min(n, int(limit-busy)))
+		if r <= 0 {
+			return 0
+		}
+		if atomic.CompareAndSwapInt32(&extraWorkers, busy, busy+r) {
+			return int(r)
+		}
+	}
+}
+
+// packPools hold the slices used for packing B, recycled across the
+// multiplications. Each pool holds the slices whose capacity has the
+// same bit length.
+var (
+	packPoolFloat32 [64]sync.Pool
+	packPoolFloat64 [64]sync.Pool
+)
+
+// getPacked returns a slice of the given size from the pools, allocating
+// a new one if none is available.
+func getPacked[F float32 | float64](size int) *[]F {
+	bucket := bits.Len(uint(size))
+	if p, ok := packPools[F]()[bucket].Get().(*[]F); ok {
+		*p = (*p)[:size]
+		return p
+	}
+	s := make([]F, size, 1<<bucket-1)
+	return &s
+}
+
+// putPacked returns a slice obtained with getPacked to the pools.
+func putPacked[F float32 | float64](p *[]F) {
+	packPools[F]()[bits.Len(uint(cap(*p)))].Put(p)
+}
+
+func packPools[F float32 | float64]() *[64]sync.Pool {
+	switch any(F(0)).(type) {
+	case float32:
+		return &packPoolFloat32
+	case float64:
+		return &packPoolFloat64
+	default:
+		panic(fmt.Sprintf("gemm: unexpected type %T", F(0)))
+	}
+}
+
+// pack copies the k×n matrix B into the packed slice, of size k*n,
+// arranged in panels of blockN columns (the last one possibly narrower).
+// Each panel is stored in row-major order, and the panel starting at
+// column j begins at offset j*k.
+func pack[F float32 | float64](k, n int, b, packed []F) {
+	for j0 := 0; j0 < n; j0 += blockN {
+		nb := min(blockN, n-j0)
+		panel := packed[j0*k : j0*k+k*nb]
+		for l := 0; l < k; l++ {
+			copy(panel[l*nb:l*nb+nb], b[l*n+j0:l*n+j0+nb])
+		}
+	}
+}
+
+// axpyKernel returns the AxpyUnitary implementation for the type F,
+// computing y += alpha * x.
+func axpyKernel[F float32 | float64]() func(alpha F, x, y []F) {
+	switch any(F(0)).(type) {
+	case float32:
+		return any(asm32.AxpyUnitary).(func(F, []F, []F))
+	case float64:
+		return any(asm64.AxpyUnitary).(func(F, []F, []F))
+	default:
+		panic(fmt.Sprintf("gemm: unexpected type %T", F(0)))
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gemm
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/internal/f32"
+	"github.com/nlpodyssey/spago/mat/internal/f64"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMul(t *testing.T) {
+	t.Run("float32", testMul[float32])
+	t.Run("float64", testMul[float64])
+}
+
+func testMul[F float32 | float64](t *testing.T) {
+	sizes := [][3]int{
+		{0, 0, 0},
+		{1, 1, 1},
+		{3, 5, 2},
+		{33, 129, 257},
+		{70, 300, 600},
+		{100, 1, 513},
+	}
+	for _, size := range sizes {
+		m, k, n := size[0], size[1], size[2]
+		for _, parallelism := range []int{0, 1, 3, 8} {
+			name := fmt.Sprintf("%d×%d×%d parallelism %d", m, k, n, parallelism)
+			t.Run(name, func(t *testing.T) {
+				r := rand.New(rand.NewSource(42))
+				a, b := randomSlice[F](r, m*k), randomSlice[F](r, k*n)
+				c := randomSlice[F](r, m*n)
+				expected := append([]F(nil), c...)
+				naiveMul(m, k, n, a, b, expected)
+
+				Mul(parallelism, m, k, n, a, b, c)
+				assert.InDeltaSlice(t, expected, c, 1.0e-3)
+			})
+		}
+	}
+}
+
+func TestMul_ReusesPackedPanels(t *testing.T) {
+	if raceEnabled {
+		t.Skip("sync.Pool drops items at random with the race detector")
+	}
+	r := rand.New(rand.NewSource(42))
+	m, k, n := 4, 8, 2*blockN+1
+	a, b := randomSlice[float32](r, m*k), randomSlice[float32](r, k*n)
+	c := make([]float32, m*n)
+	Mul(1, m, k, n, a, b, c) // warm-up: fills the pool
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := 0; i < 10; i++ {
+		Mul(1, m, k, n, a, b, c)
+	}
+	runtime.ReadMemStats(&after)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(k*n*4), "B is packed in a recycled slice")
+}
+
+func TestReserveWorkers(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	assert.Equal(t, 2, reserveWorkers(2))
+	assert.Equal(t, 1, reserveWorkers(5), "at most GOMAXPROCS-1 extra workers")
+	assert.Equal(t, 0, reserveWorkers(1))
+	atomic.AddInt32(&extraWorkers, -3)
+	assert.Equal(t, 3, reserveWorkers(8))
+	atomic.AddInt32(&extraWorkers, -3)
+}
+
+func naiveMul[F float32 | float64](m, k, n int, a, b, c []F) {
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			var sum F
+			for l := 0; l < k; l++ {
+				sum += a[i*k+l] * b[l*n+j]
+			}
+			c[i*n+j] += sum
+		}
+	}
+}
+
+func randomSlice[F float32 | float64](r *rand.Rand, size int) []F {
+	s := make([]F, size)
+	for i := range s {
+		s[i] = F(r.Float64()*2 - 1)
+	}
+	return s
+}
+
+var benchSizes = []int{64, 256, 512, 1024}
+
+func BenchmarkMul(b *testing.B) {
+	for _, size := range benchSizes {
+		b.Run(fmt.Sprintf("float32/%d/reference", size), func(b *testing.B) {
+			benchmarkMul(b, size, f32.MatrixMul)
+		})
+		b.Run(fmt.Sprintf("float32/%d/serial", size), func(b *testing.B) {
+			benchmarkMul(b, size, withParallelism[float32](1))
+		})
+		b.Run(fmt.Sprintf("float32/%d/parallel", size), func(b *testing.B) {
+			benchmarkMul(b, size, withParallelism[float32](runtime.GOMAXPROCS(0)))
+		})
+		b.Run(fmt.Sprintf("float64/%d/reference", size), func(b *testing.B) {
+			benchmarkMul(b, size, f64.MatrixMul)
+		})
+		b.Run(fmt.Sprintf("float64/%d/serial", size), func(b *testing.B) {
+			benchmarkMul(b, size, withParallelism[float64](1))
+		})
+		b.Run(fmt.Sprintf("float64/%d/parallel", size), func(b *testing.B) {
+			benchmarkMul(b, size, withParallelism[float64](runtime.GOMAXPROCS(0)))
+		})
+	}
+}
+
+func withParallelism[F float32 | float64](parallelism int) func(m, k, n int, a, b, c []F) {
+	return func(m, k, n int, a, b, c []F) {
+		Mul(parallelism, m, k, n, a, b, c)
+	}
+}
+
+func benchmarkMul[F float32 | float64](b *testing.B, size int, mul func(m, k, n int, a, b, c []F)) {
+	r := rand.New(rand.NewSource(42))
+	x, y := randomSlice[F](r, size*size), randomSlice[F](r, size*size)
+	z := make([]F, size*size)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mul(size, size, size, x, y, z)
+	}
+}
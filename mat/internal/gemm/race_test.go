@@ -0,0 +1,11 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build race
+
+package gemm
+
+// raceEnabled reports whether the race detector is enabled, which makes
+// sync.Pool drop items at random.
+const raceEnabled = true
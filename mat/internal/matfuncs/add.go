@@ -0,0 +1,16 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package matfuncs
+
+func add[F float32 | float64](x1, x2, y []F) {
+	if len(x1) == 0 {
+		return
+	}
+	_ = y[len(x1)-1]
+	_ = x2[len(x1)-1]
+	for i, x1v := range x1 {
+		y[i] = x1v + x2[i]
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+#include "textflag.h"
+
+// The kernels below only rely on VFMLA/VFMLS for vector floating-point
+// arithmetic, so that they assemble with older Go toolchains too.
+// A sum x1+x2 is computed as x1 + x2*1, which is exact in the product
+// and rounds only once, just like a plain addition.
+
+// func AddNEON32(x1 []float32, x2 []float32, y []float32)
+// Requires: ASIMD
+TEXT ·AddNEON32(SB), NOSPLIT, $0-72
+	MOVD x1_base+0(FP), R0
+	MOVD x2_base+24(FP), R1
+	MOVD y_base+48(FP), R2
+	MOVD x1_len+8(FP), R3
+
+	MOVW $0x3f800000, R4
+	VDUP R4, V31.S4
+
+unrolledLoop:
+	CMP   $16, R3
+	BLT   singleRegisterLoop
+	VLD1.P 64(R0), [V0.S4, V1.S4, V2.S4, V3.S4]
+	VLD1.P 64(R1), [V4.S4, V5.S4, V6.S4, V7.S4]
+	VFMLA V31.S4, V4.S4, V0.S4
+	VFMLA V31.S4, V5.S4, V1.S4
+	VFMLA V31.S4, V6.S4, V2.S4
+	VFMLA V31.S4, V7.S4, V3.S4
+	VST1.P [V0.S4, V1.S4, V2.S4, V3.S4], 64(R2)
+	SUB   $16, R3
+	B     unrolledLoop
+
+singleRegisterLoop:
+	CMP   $4, R3
+	BLT   tailLoop
+	VLD1.P 16(R0), [V0.S4]
+	VLD1.P 16(R1), [V4.S4]
+	VFMLA V31.S4, V4.S4, V0.S4
+	VST1.P [V0.S4], 16(R2)
+	SUB   $4, R3
+	B     singleRegisterLoop
+
+tailLoop:
+	CBZ     R3, end
+	FMOVS.P 4(R0), F0
+	FMOVS.P 4(R1), F1
+	FADDS   F1, F0
+	FMOVS.P F0, 4(R2)
+	SUB     $1, R3
+	B       tailLoop
+
+end:
+	RET
+
+// func AddNEON64(x1 []float64, x2 []float64, y []float64)
+// Requires: ASIMD
+TEXT ·AddNEON64(SB), NOSPLIT, $0-72
+	MOVD x1_base+0(FP), R0
+	MOVD x2_base+24(FP), R1
+	MOVD y_base+48(FP), R2
+	MOVD x1_len+8(FP), R3
+
+	MOVD $0x3ff0000000000000, R4
+	VDUP R4, V31.D2
+
+unrolledLoop:
+	CMP   $8, R3
+	BLT   singleRegisterLoop
+	VLD1.P 64(R0), [V0.D2, V1.D2, V2.D2, V3.D2]
+	VLD1.P 64(R1), [V4.D2, V5.D2, V6.D2, V7.D2]
+	VFMLA V31.D2, V4.D2, V0.D2
+	VFMLA V31.D2, V5.D2, V1.D2
+	VFMLA V31.D2, V6.D2, V2.D2
+	VFMLA V31.D2, V7.D2, V3.D2
+	VST1.P [V0.D2, V1.D2, V2.D2, V3.D2], 64(R2)
+	SUB   $8, R3
+	B     unrolledLoop
+
+singleRegisterLoop:
+	CMP   $2, R3
+	BLT   tailLoop
+	VLD1.P 16(R0), [V0.D2]
+	VLD1.P 16(R1), [V4.D2]
+	VFMLA V31.D2, V4.D2, V0.D2
+	VST1.P [V0.D2], 16(R2)
+	SUB   $2, R3
+	B     singleRegisterLoop
+
+tailLoop:
+	CBZ     R3, end
+	FMOVD.P 8(R0), F0
+	FMOVD.P 8(R1), F1
+	FADDD   F1, F0
+	FMOVD.P F0, 8(R2)
+	SUB     $1, R3
+	B       tailLoop
+
+end:
+	RET
@@ -0,0 +1,17 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+package matfuncs
+
+// AddNEON32 adds x1 and x2 element-wise, storing the result in y (32 bits, NEON required).
+//
+//go:noescape
+func AddNEON32(x1 []float32, x2 []float32, y []float32)
+
+// AddNEON64 adds x1 and x2 element-wise, storing the result in y (64 bits, NEON required).
+//
+//go:noescape
+func AddNEON64(x1 []float64, x2 []float64, y []float64)
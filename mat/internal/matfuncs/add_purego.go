@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build !amd64 || !gc || purego
+//go:build !(amd64 || arm64) || !gc || purego
 
 package matfuncs
 
@@ -15,14 +15,3 @@ func Add32(x1, x2, y []float32) {
 func Add64(x1, x2, y []float64) {
 	add(x1, x2, y)
 }
-
-func add[F float32 | float64](x1, x2, y []F) {
-	if len(x1) == 0 {
-		return
-	}
-	_ = y[len(x1)-1]
-	_ = x2[len(x1)-1]
-	for i, x1v := range x1 {
-		y[i] = x1v + x2[i]
-	}
-}
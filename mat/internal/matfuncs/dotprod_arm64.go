@@ -0,0 +1,23 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+package matfuncs
+
+// DotProd32 returns the dot product between x1 and x2 (32 bits).
+func DotProd32(x1, x2 []float32) float32 {
+	if hasASIMD {
+		return DotProdNEON32(x1, x2)
+	}
+	return dotProd(x1, x2)
+}
+
+// DotProd64 returns the dot product between x1 and x2 (64 bits).
+func DotProd64(x1, x2 []float64) float64 {
+	if hasASIMD {
+		return DotProdNEON64(x1, x2)
+	}
+	return dotProd(x1, x2)
+}
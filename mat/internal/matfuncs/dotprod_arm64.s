@@ -0,0 +1,127 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+#include "textflag.h"
+
+// func DotProdNEON32(x1 []float32, x2 []float32) float32
+// Requires: ASIMD
+TEXT ·DotProdNEON32(SB), NOSPLIT, $0-52
+	MOVD x1_base+0(FP), R0
+	MOVD x2_base+24(FP), R1
+	MOVD x1_len+8(FP), R2
+
+	VEOR V16.B16, V16.B16, V16.B16
+	VEOR V17.B16, V17.B16, V17.B16
+	VEOR V18.B16, V18.B16, V18.B16
+	VEOR V19.B16, V19.B16, V19.B16
+
+unrolledLoop:
+	CMP   $16, R2
+	BLT   singleRegisterLoop
+	VLD1.P 64(R0), [V0.S4, V1.S4, V2.S4, V3.S4]
+	VLD1.P 64(R1), [V4.S4, V5.S4, V6.S4, V7.S4]
+	VFMLA V4.S4, V0.S4, V16.S4
+	VFMLA V5.S4, V1.S4, V17.S4
+	VFMLA V6.S4, V2.S4, V18.S4
+	VFMLA V7.S4, V3.S4, V19.S4
+	SUB   $16, R2
+	B     unrolledLoop
+
+singleRegisterLoop:
+	CMP   $4, R2
+	BLT   reduce
+	VLD1.P 16(R0), [V0.S4]
+	VLD1.P 16(R1), [V4.S4]
+	VFMLA V4.S4, V0.S4, V16.S4
+	SUB   $4, R2
+	B     singleRegisterLoop
+
+reduce:
+	// Fold the four accumulators into V16, then sum its lanes into F16.
+	MOVW  $0x3f800000, R3
+	VDUP  R3, V31.S4
+	VFMLA V31.S4, V17.S4, V16.S4
+	VFMLA V31.S4, V18.S4, V16.S4
+	VFMLA V31.S4, V19.S4, V16.S4
+	VMOV  V16.S[1], R3
+	VMOV  V16.S[2], R4
+	VMOV  V16.S[3], R5
+	FMOVS R3, F1
+	FMOVS R4, F2
+	FMOVS R5, F3
+	FADDS F1, F16
+	FADDS F2, F16
+	FADDS F3, F16
+
+tailLoop:
+	CBZ     R2, end
+	FMOVS.P 4(R0), F0
+	FMOVS.P 4(R1), F1
+	FMULS   F0, F1
+	FADDS   F1, F16
+	SUB     $1, R2
+	B       tailLoop
+
+end:
+	FMOVS F16, ret+48(FP)
+	RET
+
+// func DotProdNEON64(x1 []float64, x2 []float64) float64
+// Requires: ASIMD
+TEXT ·DotProdNEON64(SB), NOSPLIT, $0-56
+	MOVD x1_base+0(FP), R0
+	MOVD x2_base+24(FP), R1
+	MOVD x1_len+8(FP), R2
+
+	VEOR V16.B16, V16.B16, V16.B16
+	VEOR V17.B16, V17.B16, V17.B16
+	VEOR V18.B16, V18.B16, V18.B16
+	VEOR V19.B16, V19.B16, V19.B16
+
+unrolledLoop:
+	CMP   $8, R2
+	BLT   singleRegisterLoop
+	VLD1.P 64(R0), [V0.D2, V1.D2, V2.D2, V3.D2]
+	VLD1.P 64(R1), [V4.D2, V5.D2, V6.D2, V7.D2]
+	VFMLA V4.D2, V0.D2, V16.D2
+	VFMLA V5.D2, V1.D2, V17.D2
+	VFMLA V6.D2, V2.D2, V18.D2
+	VFMLA V7.D2, V3.D2, V19.D2
+	SUB   $8, R2
+	B     unrolledLoop
+
+singleRegisterLoop:
+	CMP   $2, R2
+	BLT   reduce
+	VLD1.P 16(R0), [V0.D2]
+	VLD1.P 16(R1), [V4.D2]
+	VFMLA V4.D2, V0.D2, V16.D2
+	SUB   $2, R2
+	B     singleRegisterLoop
+
+reduce:
+	// Fold the four accumulators into V16, then sum its lanes into F16.
+	MOVD  $0x3ff0000000000000, R3
+	VDUP  R3, V31.D2
+	VFMLA V31.D2, V17.D2, V16.D2
+	VFMLA V31.D2, V18.D2, V16.D2
+	VFMLA V31.D2, V19.D2, V16.D2
+	VMOV  V16.D[1], R3
+	FMOVD R3, F1
+	FADDD F1, F16
+
+tailLoop:
+	CBZ     R2, end
+	FMOVD.P 8(R0), F0
+	FMOVD.P 8(R1), F1
+	FMULD   F0, F1
+	FADDD   F1, F16
+	SUB     $1, R2
+	B       tailLoop
+
+end:
+	FMOVD F16, ret+48(FP)
+	RET
@@ -0,0 +1,17 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+package matfuncs
+
+// DotProdNEON32 returns the dot product between x1 and x2 (32 bits, NEON required).
+//
+//go:noescape
+func DotProdNEON32(x1 []float32, x2 []float32) float32
+
+// DotProdNEON64 returns the dot product between x1 and x2 (64 bits, NEON required).
+//
+//go:noescape
+func DotProdNEON64(x1 []float64, x2 []float64) float64
@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-//go:build !amd64 || !gc || purego
+//go:build !(amd64 || arm64) || !gc || purego
 
 package matfuncs
 
@@ -15,14 +15,3 @@ func DotProd32(x1, x2 []float32) float32 {
 func DotProd64(x1, x2 []float64) float64 {
 	return dotProd(x1, x2)
 }
-
-func dotProd[F float32 | float64](x1, x2 []F) (y F) {
-	if len(x1) == 0 {
-		return
-	}
-	_ = x2[len(x1)-1]
-	for i, x1v := range x1 {
-		y += x1v * x2[i]
-	}
-	return
-}
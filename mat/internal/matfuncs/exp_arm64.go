@@ -0,0 +1,32 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+package matfuncs
+
+// Exp32 computes the base-e exponential of each element of x, storing the result in y (32 bits).
+func Exp32(x, y []float32) {
+	if len(x) == 0 {
+		return
+	}
+	if !hasASIMD {
+		exp(x, y)
+		return
+	}
+
+	_ = y[len(x)-1]
+	ExpNEON32(x, y)
+
+	mod := len(x) % 4
+	if mod > 0 {
+		tailStart := len(x) - mod
+		exp(x[tailStart:], y[tailStart:])
+	}
+}
+
+// Exp64 computes the base-e exponential of each element of x, storing the result in y (64 bits).
+func Exp64(x, y []float64) {
+	exp(x, y)
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+#include "textflag.h"
+
+// ExpNEON32 implements the same Cephes-style approximation used by the
+// amd64 kernels, four lanes at a time:
+//
+//	x = clamp(x, -88.37626, 88.37626)
+//	n = round(x * log2(e))
+//	r = x - n*ln(2)   (ln(2) split in two constants for extra precision)
+//	exp(x) = 2^n * (1 + r + r^2 * P(r))
+//
+// Only VFMLA/VFMLS are used for floating-point vector arithmetic, so that
+// the kernel assembles with older Go toolchains too. The clamp is done on
+// the magnitude bits with an unsigned integer minimum, and n is rounded by
+// adding and subtracting 1.5*2^23. The upper bound is one ulp below the
+// amd64 one so that n always stays within [-127, 127].
+
+// func ExpNEON32(x []float32, y []float32)
+// Requires: ASIMD
+TEXT ·ExpNEON32(SB), NOSPLIT, $0-48
+	MOVD x_base+0(FP), R0
+	MOVD y_base+24(FP), R1
+	MOVD x_len+8(FP), R2
+	LSR  $2, R2
+
+	MOVW $0x80000000, R3 // sign mask
+	VDUP R3, V17.S4
+	MOVW $0x7fffffff, R3 // magnitude mask
+	VDUP R3, V18.S4
+	MOVW $0x42b0c0a4, R3 // max |x|
+	VDUP R3, V19.S4
+	MOVW $0x3fb8aa3b, R3 // log2(e)
+	VDUP R3, V20.S4
+	MOVW $0x4b400000, R3 // 1.5 * 2^23
+	VDUP R3, V21.S4
+	MOVW $0xbf318000, R3 // -C1
+	VDUP R3, V22.S4
+	MOVW $0x395e8083, R3 // C2
+	VDUP R3, V23.S4
+	MOVW $0x39506967, R3 // P0
+	VDUP R3, V24.S4
+	MOVW $0x3ab743ce, R3 // P1
+	VDUP R3, V25.S4
+	MOVW $0x3c088908, R3 // P2
+	VDUP R3, V26.S4
+	MOVW $0x3d2aa9c1, R3 // P3
+	VDUP R3, V27.S4
+	MOVW $0x3e2aaaaa, R3 // P4
+	VDUP R3, V28.S4
+	MOVW $0x3f000000, R3 // P5 = 0.5
+	VDUP R3, V29.S4
+	MOVW $0x3f800000, R3 // 1.0
+	VDUP R3, V31.S4
+
+loop:
+	CBZ    R2, end
+	VLD1.P 16(R0), [V0.S4]
+
+	// x = clamp(x)
+	VAND  V17.B16, V0.B16, V1.B16
+	VAND  V18.B16, V0.B16, V0.B16
+	VUMIN V19.S4, V0.S4, V0.S4
+	VORR  V1.B16, V0.B16, V0.B16
+
+	// V2 = 1.5*2^23 + n, V3 = n
+	VMOV  V21.B16, V2.B16
+	VFMLA V20.S4, V0.S4, V2.S4
+	VMOV  V2.B16, V3.B16
+	VFMLS V31.S4, V21.S4, V3.S4
+
+	// V2 = n as integer
+	VSUB V21.S4, V2.S4, V2.S4
+
+	// x -= n * ln(2)
+	VFMLA V22.S4, V3.S4, V0.S4
+	VFMLA V23.S4, V3.S4, V0.S4
+
+	// V4 = x^2
+	VEOR  V4.B16, V4.B16, V4.B16
+	VFMLA V0.S4, V0.S4, V4.S4
+
+	// V5 = P(x)
+	VMOV  V25.B16, V5.B16
+	VFMLA V24.S4, V0.S4, V5.S4
+	VMOV  V26.B16, V6.B16
+	VFMLA V5.S4, V0.S4, V6.S4
+	VMOV  V27.B16, V5.B16
+	VFMLA V6.S4, V0.S4, V5.S4
+	VMOV  V28.B16, V6.B16
+	VFMLA V5.S4, V0.S4, V6.S4
+	VMOV  V29.B16, V5.B16
+	VFMLA V6.S4, V0.S4, V5.S4
+
+	// V6 = 1 + x + x^2 * P(x)
+	VMOV  V31.B16, V6.B16
+	VFMLA V31.S4, V0.S4, V6.S4
+	VFMLA V5.S4, V4.S4, V6.S4
+
+	// V2 = 2^n
+	VSHL $23, V2.S4, V2.S4
+	VADD V31.S4, V2.S4, V2.S4
+
+	// y = 2^n * (1 + x + x^2 * P(x))
+	VEOR   V7.B16, V7.B16, V7.B16
+	VFMLA  V2.S4, V6.S4, V7.S4
+	VST1.P [V7.S4], 16(R1)
+
+	SUB $1, R2
+	B   loop
+
+end:
+	RET
@@ -0,0 +1,13 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+package matfuncs
+
+// ExpNEON32 computes the base-e exponential of each element of x, storing the result in y (32 bits, NEON required).
+// Only the first len(x) rounded down to a multiple of 4 elements are processed.
+//
+//go:noescape
+func ExpNEON32(x []float32, y []float32)
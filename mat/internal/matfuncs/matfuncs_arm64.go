@@ -0,0 +1,11 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+package matfuncs
+
+import "github.com/nlpodyssey/spago/mat/internal/matfuncs/cpu"
+
+var hasASIMD = cpu.ARM64.HasASIMD
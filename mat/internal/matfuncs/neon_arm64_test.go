@@ -0,0 +1,128 @@
+// Copyright 2022 The NLP Odyssey Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build arm64 && gc && !purego
+
+package matfuncs
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// neonMaxLen is the maximum length of the slices given to the NEON kernels
+// by the tests below, covering every tail length of the unrolled loops.
+const neonMaxLen = 33
+
+// neonGuard is the value stored right after the end of the output slices,
+// to detect the kernels writing out of bounds.
+const neonGuard = -12345
+
+// forEachNEONCase calls f with every length from 0 to neonMaxLen, and with
+// every offset from 0 to 3, so that the slices start at unaligned addresses.
+func forEachNEONCase(t *testing.T, f func(t *testing.T, size, offset int)) {
+	if !hasASIMD {
+		t.Skip("ASIMD not available")
+	}
+	for size := 0; size <= neonMaxLen; size++ {
+		for offset := 0; offset < 4; offset++ {
+			t.Run(fmt.Sprintf("size %d offset %d", size, offset), func(t *testing.T) {
+				f(t, size, offset)
+			})
+		}
+	}
+}
+
+// newNEONVec returns a random slice of the given size, starting at the given
+// offset of its backing array, followed by a guard value.
+func newNEONVec[F Float](size, offset int) []F {
+	backing := NewRandVec[F](offset + size + 1)
+	backing[offset+size] = neonGuard
+	return backing[offset : offset+size]
+}
+
+func requireNEONGuard[F Float](t *testing.T, y []F) {
+	if g := y[:len(y)+1][len(y)]; g != neonGuard {
+		t.Fatalf("value after the end of the output overwritten: %G", g)
+	}
+}
+
+func TestAddNEON32(t *testing.T) {
+	testAddNEON(t, AddNEON32)
+}
+
+func TestAddNEON64(t *testing.T) {
+	testAddNEON(t, AddNEON64)
+}
+
+func testAddNEON[F Float](t *testing.T, fn func(x1, x2, y []F)) {
+	forEachNEONCase(t, func(t *testing.T, size, offset int) {
+		x1 := newNEONVec[F](size, offset)
+		x2 := newNEONVec[F](size, (offset+1)%4)
+		expected := make([]F, size)
+		add(x1, x2, expected)
+
+		actual := newNEONVec[F](size, (offset+2)%4)
+		fn(x1, x2, actual)
+
+		RequireSlicesInDelta(t, expected, actual, 0)
+		requireNEONGuard(t, actual)
+	})
+
+	t.Run("nil slices", func(t *testing.T) {
+		fn(nil, nil, nil)
+	})
+}
+
+func TestDotProdNEON32(t *testing.T) {
+	testDotProdNEON(t, DotProdNEON32, 1e-5)
+}
+
+func TestDotProdNEON64(t *testing.T) {
+	testDotProdNEON(t, DotProdNEON64, 1e-12)
+}
+
+func testDotProdNEON[F Float](t *testing.T, fn func(x1, x2 []F) F, eps float64) {
+	forEachNEONCase(t, func(t *testing.T, size, offset int) {
+		x1 := newNEONVec[F](size, offset)
+		x2 := newNEONVec[F](size, (offset+1)%4)
+		expected := dotProd(x1, x2)
+
+		actual := fn(x1, x2)
+
+		RequireValueInDelta(t, expected, actual, eps*math.Max(1, float64(size)))
+	})
+
+	t.Run("nil slices", func(t *testing.T) {
+		if actual := fn(nil, nil); actual != 0 {
+			t.Fatalf("expected 0, actual %G", actual)
+		}
+	})
+}
+
+func TestExpNEON32(t *testing.T) {
+	forEachNEONCase(t, func(t *testing.T, size, offset int) {
+		x := newNEONVec[float32](size, offset)
+		processed := size - size%4
+		expected := make([]float32, processed)
+		exp(x[:processed], expected)
+
+		actual := newNEONVec[float32](size, (offset+1)%4)
+		tail := append([]float32(nil), actual[processed:]...)
+		ExpNEON32(x, actual)
+
+		for i, e := range expected {
+			if d := math.Abs(float64(e - actual[i])); d > 1e-6*math.Max(1, float64(e)) {
+				t.Fatalf("value at index %d: expected %G, actual %G", i, e, actual[i])
+			}
+		}
+		RequireSlicesInDelta(t, tail, actual[processed:], 0)
+		requireNEONGuard(t, actual)
+	})
+
+	t.Run("nil slices", func(t *testing.T) {
+		ExpNEON32(nil, nil)
+	})
+}
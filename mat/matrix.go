@@ -100,24 +100,30 @@ type Matrix interface {
 	// matrix itself.
 	TransposeInPlace() Matrix
 	// Add returns the addition between the receiver and another matrix.
+	// The matrices are broadcast together if their dimensions differ.
 	Add(other Matrix) Matrix
 	// AddInPlace performs the in-place addition with the other matrix.
+	// It panics if the dimensions differ; see BroadcastLike.
 	AddInPlace(other Matrix) Matrix
 	// AddScalar performs the addition between the matrix and the given value.
 	AddScalar(n float64) Matrix
 	// AddScalarInPlace adds the scalar to all values of the matrix.
 	AddScalarInPlace(n float64) Matrix
 	// Sub returns the subtraction of the other matrix from the receiver.
+	// The matrices are broadcast together if their dimensions differ.
 	Sub(other Matrix) Matrix
 	// SubInPlace performs the in-place subtraction with the other matrix.
+	// It panics if the dimensions differ; see BroadcastLike.
 	SubInPlace(other Matrix) Matrix
 	// SubScalar performs a subtraction between the matrix and the given value.
 	SubScalar(n float64) Matrix
 	// SubScalarInPlace subtracts the scalar from the receiver's values.
 	SubScalarInPlace(n float64) Matrix
 	// Prod performs the element-wise product between the receiver and the other matrix.
+	// The matrices are broadcast together if their dimensions differ.
 	Prod(other Matrix) Matrix
 	// ProdInPlace performs the in-place element-wise product with the other matrix.
+	// It panics if the dimensions differ; see BroadcastLike.
 	ProdInPlace(other Matrix) Matrix
 	// ProdScalar returns the multiplication between the matrix and the given value.
 	ProdScalar(n float64) Matrix
@@ -128,8 +134,10 @@ type Matrix interface {
 	// storing the result in the receiver.
 	ProdMatrixScalarInPlace(m Matrix, n float64) Matrix
 	// Div returns the result of the element-wise division of the receiver by the other matrix.
+	// The matrices are broadcast together if their dimensions differ.
 	Div(other Matrix) Matrix
 	// DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
+	// It panics if the dimensions differ; see BroadcastLike.
 	DivInPlace(other Matrix) Matrix
 	// Mul performs the multiplication row by column.
 	// If A is an i×j Matrix, and B is j×k, then the resulting Matrix
@@ -290,11 +298,18 @@ type Matrix interface {
 // slice of values in row-major order.
 func Data[T float.DType](m Matrix) []T {
 	if d, ok := m.(*Dense[T]); ok {
+		d.checkReleased()
 		return d.data
 	}
 	return float.SliceValueOf[T](m.Data())
 }
 
+// HalfData returns a new one-dimensional slice containing the values of the
+// matrix in row-major order, converted to the 16-bit floating point type H.
+func HalfData[H float.Half](m Matrix) []H {
+	return float.HalfSliceValueOf[H](m.Data())
+}
+
 // SetData sets the content of the matrix, copying the given raw
 // data representation as one-dimensional slice.
 func SetData[T float.DType](m Matrix, data []T) {
@@ -380,6 +395,7 @@ func InDelta(a, b Matrix, delta float64) bool {
 
 func float32Data(m Matrix) []float32 {
 	if d, ok := m.(*Dense[float32]); ok {
+		d.checkReleased()
 		return d.data
 	}
 	return m.Data().F32()
@@ -387,6 +403,7 @@ func float32Data(m Matrix) []float32 {
 
 func float64Data(m Matrix) []float64 {
 	if d, ok := m.(*Dense[float64]); ok {
+		d.checkReleased()
 		return d.data
 	}
 	return m.Data().F64()
@@ -8,12 +8,18 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+
+	"github.com/nlpodyssey/spago/mat/float"
 )
 
 const (
 	binaryMatrixNil byte = iota
 	binaryMatrixDense32
 	binaryMatrixDense64
+	binaryMatrixQuantized32
+	binaryMatrixQuantized64
+	binaryMatrixHalfFloat16
+	binaryMatrixHalfBFloat16
 )
 
 // MarshalBinaryMatrix encodes a Matrix into binary form.
@@ -38,6 +44,30 @@ func MarshalBinaryMatrix(m Matrix, w io.Writer) error {
 		if err != nil {
 			return err
 		}
+	case *Quantized[float32]:
+		identifier = binaryMatrixQuantized32
+		data, err = mt.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	case *Quantized[float64]:
+		identifier = binaryMatrixQuantized64
+		data, err = mt.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	case *HalfDense[float.Float16]:
+		identifier = binaryMatrixHalfFloat16
+		data, err = mt.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	case *HalfDense[float.BFloat16]:
+		identifier = binaryMatrixHalfBFloat16
+		data, err = mt.MarshalBinary()
+		if err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("mat: unexpected matrix type %T", mt)
 	}
@@ -96,6 +126,34 @@ func UnmarshalBinaryMatrix(r io.Reader) (Matrix, error) {
 			return nil, err
 		}
 		return d, nil
+	case binaryMatrixQuantized32:
+		q := new(Quantized[float32])
+		err = q.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return q, nil
+	case binaryMatrixQuantized64:
+		q := new(Quantized[float64])
+		err = q.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return q, nil
+	case binaryMatrixHalfFloat16:
+		h := new(HalfDense[float.Float16])
+		err = h.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return h, nil
+	case binaryMatrixHalfBFloat16:
+		h := new(HalfDense[float.BFloat16])
+		err = h.UnmarshalBinary(data)
+		if err != nil {
+			return nil, err
+		}
+		return h, nil
 	default:
 		return nil, fmt.Errorf("mat: unexpected matrix identifier %d", identifier)
 	}
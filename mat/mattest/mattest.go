@@ -7,6 +7,7 @@ package mattest
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/nlpodyssey/spago/mat"
 )
@@ -99,3 +100,62 @@ func RequireMatrixInDelta(t T, expected, actual mat.Matrix, delta float64, args
 		t.FailNow()
 	}
 }
+
+// AssertNoLeaks calls f with the tracking of the Dense pools enabled (see
+// mat.SetPoolTracking), and tests whether all the matrices obtained from
+// the pools during the call have been released, and no matrix has been
+// released twice or used after its release; if not, T.Errorf is called,
+// providing the number of leaked matrices by pool bucket.
+//
+// Releasing a matrix twice or using it after its release makes the pools
+// panic (see mat.SetPoolTracking): AssertNoLeaks recovers such a panic
+// raised by f, and reports it as a failure. Any other panic is propagated.
+//
+// It returns whether the test succeeded.
+//
+// Matrices allocated once and then kept on purpose, such as the support
+// structures of the optimizers, are reported as leaked: a training step
+// should be checked after a first warm-up step.
+func AssertNoLeaks(t T, f func()) bool {
+	t.Helper()
+	defer mat.SetPoolTracking(mat.SetPoolTracking(true))
+
+	before := mat.ReadPoolStats()
+	r := callRecovering(f)
+	after := mat.ReadPoolStats()
+
+	leaked := after.Live - before.Live
+	doubleReleases := after.DoubleReleases - before.DoubleReleases
+	usesAfterRelease := after.UsesAfterRelease - before.UsesAfterRelease
+	if r != nil && doubleReleases == 0 && usesAfterRelease == 0 {
+		panic(r)
+	}
+	if leaked == 0 && doubleReleases == 0 && usesAfterRelease == 0 {
+		return true
+	}
+
+	var buckets strings.Builder
+	for _, b := range after.Buckets {
+		n := b.Live
+		for _, bb := range before.Buckets {
+			if bb.Capacity == b.Capacity {
+				n -= bb.Live
+			}
+		}
+		if n > 0 {
+			fmt.Fprintf(&buckets, "\n  capacity %d: %d", b.Capacity, n)
+		}
+	}
+	t.Errorf("Dense matrices are not released correctly:\nleaked: %d (%d bytes)%s\nreleased twice: %d\nused after release: %d",
+		leaked, after.LiveBytes-before.LiveBytes, buckets.String(), doubleReleases, usesAfterRelease)
+	return false
+}
+
+// callRecovering calls f, returning the value of its panic, if any.
+func callRecovering(f func()) (r any) {
+	defer func() {
+		r = recover()
+	}()
+	f()
+	return nil
+}
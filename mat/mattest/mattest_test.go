@@ -328,6 +328,64 @@ func matrixInDeltaTestCases[T float.DType]() []matrixInDeltaTestCase {
 	}
 }
 
+func TestAssertNoLeaks(t *testing.T) {
+	t.Run("no leaks", func(t *testing.T) {
+		dt := new(dummyT)
+		ok := mattest.AssertNoLeaks(dt, func() {
+			d := mat.NewEmptyDense[float64](2, 3)
+			mat.ReleaseMatrix(d.Add(d))
+			mat.ReleaseMatrix(d)
+		})
+		assert.True(t, ok)
+		assert.Positive(t, dt.helperCalls)
+		assert.Equal(t, 0, dt.errorfCalls+dt.errorCalls)
+	})
+
+	t.Run("leaks", func(t *testing.T) {
+		var leaked mat.Matrix
+		dt := new(dummyT)
+		dt.errorf = func(format string, args ...any) {
+			assert.Equal(t, "Dense matrices are not released correctly:\n"+
+				"leaked: 1 (56 bytes)\n  capacity 7: 1\n"+
+				"released twice: 1\n"+
+				"used after release: 0", fmt.Sprintf(format, args...))
+		}
+		ok := mattest.AssertNoLeaks(dt, func() {
+			d := mat.NewEmptyDense[float64](2, 3)
+			leaked = d.Add(d)
+			mat.ReleaseMatrix(d)
+			mat.ReleaseMatrix(d)
+		})
+		assert.False(t, ok)
+		assert.Equal(t, 1, dt.errorfCalls)
+		assert.False(t, mat.SetPoolTracking(false), "the tracking is restored")
+		mat.ReleaseMatrix(leaked)
+	})
+
+	t.Run("use after release", func(t *testing.T) {
+		dt := new(dummyT)
+		dt.errorf = func(format string, args ...any) {
+			assert.Equal(t, "Dense matrices are not released correctly:\n"+
+				"leaked: 0 (0 bytes)\n"+
+				"released twice: 0\n"+
+				"used after release: 1", fmt.Sprintf(format, args...))
+		}
+		ok := mattest.AssertNoLeaks(dt, func() {
+			d := mat.NewEmptyDense[float64](2, 3)
+			mat.ReleaseMatrix(d)
+			d.Sum()
+		})
+		assert.False(t, ok)
+		assert.Equal(t, 1, dt.errorfCalls)
+	})
+
+	t.Run("other panics are propagated", func(t *testing.T) {
+		assert.PanicsWithValue(t, "foo", func() {
+			mattest.AssertNoLeaks(new(dummyT), func() { panic("foo") })
+		})
+	})
+}
+
 type dummyT struct {
 	helper      func()
 	helperCalls int
@@ -0,0 +1,376 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// npyMagic is the magic string at the beginning of every .npy file.
+const npyMagic = "\x93NUMPY"
+
+// npyHeaderAlignment is the alignment of the header, including the magic
+// string, the version and the header length, as required by NumPy.
+const npyHeaderAlignment = 64
+
+var (
+	npyDescrRegexp        = regexp.MustCompile(`['"]descr['"]\s*:\s*['"]([^'"]*)['"]`)
+	npyFortranOrderRegexp = regexp.MustCompile(`['"]fortran_order['"]\s*:\s*(True|False)`)
+	npyShapeRegexp        = regexp.MustCompile(`['"]shape['"]\s*:\s*\(([^)]*)\)`)
+)
+
+// WriteNPY encodes a Matrix in NumPy .npy format.
+//
+// The array is always written in C (row-major) order, with dtype "<f4" or
+// "<f8" according to the bit size of the matrix values. Its shape is the
+// one reported by Shape, so that Tensors retain all their dimensions.
+func WriteNPY(w io.Writer, m Matrix) error {
+	if m == nil {
+		return fmt.Errorf("mat: cannot encode a nil matrix to npy")
+	}
+
+	data := m.Data()
+	var descr string
+	switch data.BitSize() {
+	case 32:
+		descr = "<f4"
+	case 64:
+		descr = "<f8"
+	default:
+		return fmt.Errorf("mat: unsupported bit size %d for npy encoding", data.BitSize())
+	}
+
+	if err := writeNPYHeader(w, descr, Shape(m)); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	var buf [8]byte
+	switch descr {
+	case "<f4":
+		for _, v := range data.F32() {
+			binary.LittleEndian.PutUint32(buf[:4], math.Float32bits(v))
+			if _, err := bw.Write(buf[:4]); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, v := range data.F64() {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+			if _, err := bw.Write(buf[:]); err != nil {
+				return err
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// writeNPYHeader writes the magic string, version and header dictionary
+// of a .npy file, using version 1.0 unless the header is too long for it.
+func writeNPYHeader(w io.Writer, descr string, shape []int) error {
+	dims := make([]string, len(shape))
+	for i, v := range shape {
+		dims[i] = strconv.Itoa(v)
+	}
+	shapeStr := strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shapeStr += ","
+	}
+	dict := fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%s), }", descr, shapeStr)
+
+	major, lenSize := byte(1), 2
+	if padNPYHeader(dict, 2) > math.MaxUint16 {
+		major, lenSize = 2, 4
+	}
+	headerLen := padNPYHeader(dict, lenSize)
+
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if lenSize == 2 {
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(headerLen))
+	} else {
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(headerLen))
+	}
+	buf.WriteString(dict)
+	buf.WriteString(strings.Repeat(" ", headerLen-len(dict)-1))
+	buf.WriteByte('\n')
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// padNPYHeader returns the length of the header dictionary, once padded
+// with spaces and a final newline to the required alignment.
+func padNPYHeader(dict string, lenSize int) int {
+	preamble := len(npyMagic) + 2 + lenSize
+	total := preamble + len(dict) + 1
+	if rem := total % npyHeaderAlignment; rem != 0 {
+		total += npyHeaderAlignment - rem
+	}
+	return total - preamble
+}
+
+// ReadNPY decodes a Matrix from NumPy .npy format.
+//
+// Only float32 and float64 arrays are supported, in either byte order and
+// in either C or Fortran order. Arrays with zero, one or two dimensions
+// are returned as Dense matrices: a scalar becomes a 1×1 matrix and a
+// one-dimensional array becomes a column vector. Arrays with more than two
+// dimensions are returned as DenseTensor values.
+func ReadNPY(r io.Reader) (Matrix, error) {
+	descr, fortranOrder, shape, err := readNPYHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder
+	switch descr[0] {
+	case '<', '=':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	}
+
+	switch descr[1:] {
+	case "f4":
+		return readNPYData[float32](r, order, fortranOrder, shape)
+	default:
+		return readNPYData[float64](r, order, fortranOrder, shape)
+	}
+}
+
+// readNPYHeader reads and parses the header of a .npy file.
+func readNPYHeader(r io.Reader) (descr string, fortranOrder bool, shape []int, err error) {
+	preamble := make([]byte, len(npyMagic)+2)
+	if _, err = io.ReadFull(r, preamble); err != nil {
+		return "", false, nil, fmt.Errorf("mat: failed to read npy header: %w", err)
+	}
+	if string(preamble[:len(npyMagic)]) != npyMagic {
+		return "", false, nil, fmt.Errorf("mat: invalid npy magic string")
+	}
+
+	var headerLen int
+	switch major := preamble[len(npyMagic)]; major {
+	case 1:
+		var n uint16
+		err = binary.Read(r, binary.LittleEndian, &n)
+		headerLen = int(n)
+	case 2, 3:
+		var n uint32
+		err = binary.Read(r, binary.LittleEndian, &n)
+		headerLen = int(n)
+	default:
+		return "", false, nil, fmt.Errorf("mat: unsupported npy version %d.%d", major, preamble[len(npyMagic)+1])
+	}
+	if err != nil {
+		return "", false, nil, fmt.Errorf("mat: failed to read npy header: %w", err)
+	}
+
+	header := make([]byte, headerLen)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return "", false, nil, fmt.Errorf("mat: failed to read npy header: %w", err)
+	}
+	return parseNPYHeader(string(header))
+}
+
+// parseNPYHeader parses the dictionary literal of a .npy header.
+func parseNPYHeader(header string) (descr string, fortranOrder bool, shape []int, err error) {
+	m := npyDescrRegexp.FindStringSubmatch(header)
+	if m == nil {
+		return "", false, nil, fmt.Errorf("mat: npy header is missing 'descr'")
+	}
+	descr = m[1]
+	switch descr {
+	case "<f4", "<f8", ">f4", ">f8", "=f4", "=f8":
+	default:
+		return "", false, nil, fmt.Errorf("mat: unsupported npy dtype %q", descr)
+	}
+
+	m = npyFortranOrderRegexp.FindStringSubmatch(header)
+	if m == nil {
+		return "", false, nil, fmt.Errorf("mat: npy header is missing 'fortran_order'")
+	}
+	fortranOrder = m[1] == "True"
+
+	m = npyShapeRegexp.FindStringSubmatch(header)
+	if m == nil {
+		return "", false, nil, fmt.Errorf("mat: npy header is missing 'shape'")
+	}
+	shape = []int{}
+	for _, s := range strings.Split(m[1], ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 {
+			return "", false, nil, fmt.Errorf("mat: invalid npy shape %q", m[1])
+		}
+		shape = append(shape, v)
+	}
+	return descr, fortranOrder, shape, nil
+}
+
+// npyDataSize returns the number of values of an array with the given
+// shape, and whether its size in bytes fits in an int.
+func npyDataSize(shape []int, elemSize int) (int, bool) {
+	size := 1
+	for _, v := range shape {
+		if v < 0 {
+			return 0, false
+		}
+		if v != 0 && size > math.MaxInt/elemSize/v {
+			return 0, false
+		}
+		size *= v
+	}
+	return size, true
+}
+
+// npyChunkSize is the maximum number of values read from a .npy file at
+// once. The data are read in chunks, so that the memory allocated is
+// bounded by the data actually present, rather than by the shape declared
+// by the (untrusted) header.
+const npyChunkSize = 1 << 16
+
+// readNPYData reads the array values following a .npy header and builds
+// the resulting matrix.
+func readNPYData[T float.DType](r io.Reader, order binary.ByteOrder, fortranOrder bool, shape []int) (Matrix, error) {
+	elemSize := int(unsafe.Sizeof(T(0)))
+	size, ok := npyDataSize(shape, elemSize)
+	if !ok {
+		return nil, fmt.Errorf("mat: npy shape %v is too large", shape)
+	}
+
+	data := make([]T, 0, minInt(size, npyChunkSize))
+	raw := make([]byte, minInt(size, npyChunkSize)*elemSize)
+	for len(data) < size {
+		n := minInt(size-len(data), npyChunkSize)
+		chunk := raw[:n*elemSize]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return nil, fmt.Errorf("mat: failed to read npy data: %w", err)
+		}
+		for i := 0; i < n; i++ {
+			switch elemSize {
+			case 4:
+				data = append(data, T(math.Float32frombits(order.Uint32(chunk[i*4:]))))
+			default:
+				data = append(data, T(math.Float64frombits(order.Uint64(chunk[i*8:]))))
+			}
+		}
+	}
+	if fortranOrder && len(shape) > 1 {
+		data = fortranToRowMajor(data, shape)
+	}
+
+	switch len(shape) {
+	case 0:
+		return NewScalar(data[0]), nil
+	case 1:
+		return NewVecDense(data), nil
+	case 2:
+		return NewDense(shape[0], shape[1], data), nil
+	default:
+		return NewDenseTensor(shape, data), nil
+	}
+}
+
+// fortranToRowMajor returns a copy of data, laid out in column-major order
+// according to shape, rearranged in row-major order.
+func fortranToRowMajor[T float.DType](data []T, shape []int) []T {
+	out := make([]T, len(data))
+	strides := make([]int, len(shape))
+	stride := 1
+	for i, v := range shape {
+		strides[i] = stride
+		stride *= v
+	}
+	index := make([]int, len(shape))
+	for i := range out {
+		offset := 0
+		for j, v := range index {
+			offset += v * strides[j]
+		}
+		out[i] = data[offset]
+		for j := len(index) - 1; j >= 0; j-- {
+			index[j]++
+			if index[j] < shape[j] {
+				break
+			}
+			index[j] = 0
+		}
+	}
+	return out
+}
+
+// WriteNPZ encodes a collection of named matrices as an uncompressed NumPy
+// .npz archive. Each matrix is stored as a "<name>.npy" entry, in
+// lexicographical order of names.
+func WriteNPZ(w io.Writer, arrays map[string]Matrix) error {
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:   name + ".npy",
+			Method: zip.Store,
+		})
+		if err != nil {
+			return err
+		}
+		if err = WriteNPY(fw, arrays[name]); err != nil {
+			return fmt.Errorf("mat: failed to encode npz entry %q: %w", name, err)
+		}
+	}
+	return zw.Close()
+}
+
+// ReadNPZ decodes all the arrays of a NumPy .npz archive, either compressed
+// or not, as done by ReadNPY. The resulting map is keyed by entry names,
+// without the ".npy" extension.
+func ReadNPZ(r io.ReaderAt, size int64) (map[string]Matrix, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	arrays := make(map[string]Matrix, len(zr.File))
+	for _, f := range zr.File {
+		m, err := readNPZEntry(f)
+		if err != nil {
+			return nil, fmt.Errorf("mat: failed to decode npz entry %q: %w", f.Name, err)
+		}
+		arrays[strings.TrimSuffix(f.Name, ".npy")] = m
+	}
+	return arrays, nil
+}
+
+func readNPZEntry(f *zip.File) (Matrix, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ReadNPY(rc)
+}
@@ -0,0 +1,271 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNPY(t *testing.T) {
+	t.Run("float32", testNPY[float32])
+	t.Run("float64", testNPY[float64])
+
+	t.Run("header", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteNPY(&buf, NewEmptyDense[float64](2, 3)))
+
+		b := buf.Bytes()
+		headerLen := int(binary.LittleEndian.Uint16(b[8:10]))
+		assert.Equal(t, npyMagic+"\x01\x00", string(b[:8]))
+		assert.Zero(t, (10+headerLen)%64)
+		assert.Equal(t, byte('\n'), b[10+headerLen-1])
+
+		header := string(b[10 : 10+headerLen])
+		assert.Contains(t, header, "{'descr': '<f8', 'fortran_order': False, 'shape': (2, 3), }")
+		assert.Len(t, b, 10+headerLen+6*8)
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Error(t, WriteNPY(&buf, nil))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := map[string][]byte{
+			"empty":             {},
+			"bad magic":         []byte("\x93NUMPZ\x01\x00\x00\x00"),
+			"bad version":       npyFixture(4, "{'descr': '<f8', 'fortran_order': False, 'shape': (1,), }", make([]byte, 8)),
+			"unsupported dtype": npyFixture(1, "{'descr': '<i4', 'fortran_order': False, 'shape': (1,), }", make([]byte, 4)),
+			"missing shape":     npyFixture(1, "{'descr': '<f8', 'fortran_order': False, }", make([]byte, 8)),
+			"invalid shape":     npyFixture(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (-1,), }", make([]byte, 8)),
+			"truncated data":    npyFixture(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (2,), }", make([]byte, 8)),
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				m, err := ReadNPY(bytes.NewReader(tc))
+				assert.Error(t, err)
+				assert.Nil(t, m)
+			})
+		}
+	})
+}
+
+func testNPY[T float.DType](t *testing.T) {
+	descr := fmt.Sprintf("<f%d", float.Interface(T(0)).BitSize()/8)
+
+	t.Run("Dense round trip", func(t *testing.T) {
+		testCases := []Matrix{
+			NewEmptyDense[T](0, 0),
+			NewEmptyDense[T](0, 3),
+			NewDense[T](1, 1, []T{1}),
+			NewDense[T](1, 3, []T{1, 2, 3}),
+			NewDense[T](3, 1, []T{1, 2, 3}),
+			NewDense[T](2, 3, []T{-1, 2, -3, 4, -5, 6}),
+		}
+		for _, tc := range testCases {
+			t.Run(fmt.Sprintf("%d x %d", tc.Rows(), tc.Columns()), func(t *testing.T) {
+				var buf bytes.Buffer
+				require.NoError(t, WriteNPY(&buf, tc))
+
+				m, err := ReadNPY(&buf)
+				require.NoError(t, err)
+				assertDenseDims(t, tc.Rows(), tc.Columns(), m.(*Dense[T]))
+				assert.Equal(t, tc.Data(), m.Data())
+			})
+		}
+	})
+
+	t.Run("DenseTensor round trip", func(t *testing.T) {
+		tc := NewDenseTensor[T]([]int{2, 3, 2}, []T{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12})
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteNPY(&buf, tc))
+
+		m, err := ReadNPY(&buf)
+		require.NoError(t, err)
+		require.IsType(t, &DenseTensor[T]{}, m)
+		assert.Equal(t, []int{2, 3, 2}, Shape(m))
+		assert.Equal(t, tc.Data(), m.Data())
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (), }", descr), npyBytes[T](binary.LittleEndian, 42))
+		m, err := ReadNPY(bytes.NewReader(b))
+		require.NoError(t, err)
+		assertDenseDims(t, 1, 1, m.(*Dense[T]))
+		assert.Equal(t, []T{42}, Data[T](m))
+	})
+
+	t.Run("one-dimensional", func(t *testing.T) {
+		b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (3,), }", descr), npyBytes[T](binary.LittleEndian, 1, 2, 3))
+		m, err := ReadNPY(bytes.NewReader(b))
+		require.NoError(t, err)
+		assertDenseDims(t, 3, 1, m.(*Dense[T]))
+		assert.Equal(t, []T{1, 2, 3}, Data[T](m))
+	})
+
+	t.Run("Fortran order", func(t *testing.T) {
+		b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': True, 'shape': (2, 3), }", descr), npyBytes[T](binary.LittleEndian, 1, 4, 2, 5, 3, 6))
+		m, err := ReadNPY(bytes.NewReader(b))
+		require.NoError(t, err)
+		assertDenseDims(t, 2, 3, m.(*Dense[T]))
+		assert.Equal(t, []T{1, 2, 3, 4, 5, 6}, Data[T](m))
+	})
+
+	t.Run("Fortran order tensor", func(t *testing.T) {
+		// a[i, j, k] = 100*i + 10*j + k, stored with i varying fastest
+		var values []T
+		for k := 0; k < 4; k++ {
+			for j := 0; j < 3; j++ {
+				for i := 0; i < 2; i++ {
+					values = append(values, T(100*i+10*j+k))
+				}
+			}
+		}
+		b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': True, 'shape': (2, 3, 4), }", descr), npyBytes[T](binary.LittleEndian, values...))
+		m, err := ReadNPY(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Equal(t, []int{2, 3, 4}, Shape(m))
+
+		data := Data[T](m)
+		for i := 0; i < 2; i++ {
+			for j := 0; j < 3; j++ {
+				for k := 0; k < 4; k++ {
+					assert.Equal(t, T(100*i+10*j+k), data[i*12+j*4+k])
+				}
+			}
+		}
+	})
+
+	t.Run("big endian", func(t *testing.T) {
+		bigDescr := ">" + descr[1:]
+		b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (1, 2), }", bigDescr), npyBytes[T](binary.BigEndian, 1.5, -2))
+		m, err := ReadNPY(bytes.NewReader(b))
+		require.NoError(t, err)
+		assertDenseDims(t, 1, 2, m.(*Dense[T]))
+		assert.Equal(t, []T{1.5, -2}, Data[T](m))
+	})
+
+	t.Run("version 2.0", func(t *testing.T) {
+		b := npyFixture(2, fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (2,), }", descr), npyBytes[T](binary.LittleEndian, 1, 2))
+		m, err := ReadNPY(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Equal(t, []T{1, 2}, Data[T](m))
+	})
+
+	t.Run("shape overflow", func(t *testing.T) {
+		for _, shape := range []string{"(9223372036854775807, 3)", "(4294967296, 4294967296)"} {
+			b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': %s, }", descr, shape), nil)
+			_, err := ReadNPY(bytes.NewReader(b))
+			assert.Error(t, err, shape)
+		}
+	})
+
+	t.Run("truncated data", func(t *testing.T) {
+		// The declared shape is not allocated upfront.
+		b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (1000000000, 8), }", descr), npyBytes[T](binary.LittleEndian, 1, 2))
+		_, err := ReadNPY(bytes.NewReader(b))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("larger than a chunk", func(t *testing.T) {
+		values := make([]T, npyChunkSize+3)
+		for i := range values {
+			values[i] = T(i % 100)
+		}
+		b := npyFixture(1, fmt.Sprintf("{'descr': '%s', 'fortran_order': False, 'shape': (%d,), }", descr, len(values)), npyBytes[T](binary.LittleEndian, values...))
+		m, err := ReadNPY(bytes.NewReader(b))
+		require.NoError(t, err)
+		assert.Equal(t, values, Data[T](m))
+	})
+}
+
+func TestNPZ(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		arrays := map[string]Matrix{
+			"weights": NewDense[float32](2, 2, []float32{1, 2, 3, 4}),
+			"bias":    NewVecDense[float64]([]float64{-1, 1}),
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteNPZ(&buf, arrays))
+
+		actual, err := ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, arrays["weights"].Data(), actual["weights"].Data())
+		assert.Equal(t, arrays["bias"].Data(), actual["bias"].Data())
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: "x.npy", Method: zip.Deflate})
+		require.NoError(t, err)
+		_, err = fw.Write(npyFixture(1, "{'descr': '<f8', 'fortran_order': False, 'shape': (3,), }", npyBytes[float64](binary.LittleEndian, 1, 2, 3)))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		actual, err := ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		require.Contains(t, actual, "x")
+		assert.Equal(t, []float64{1, 2, 3}, Data[float64](actual["x"]))
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		fw, err := zw.Create("x.npy")
+		require.NoError(t, err)
+		_, err = fw.Write([]byte("foo"))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		actual, err := ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		assert.Error(t, err)
+		assert.Nil(t, actual)
+	})
+}
+
+// npyFixture builds the content of a .npy file with the given major version,
+// header dictionary and raw data.
+func npyFixture(major byte, dict string, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if major == 1 {
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(len(dict)+1))
+	} else {
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(len(dict)+1))
+	}
+	buf.WriteString(dict)
+	buf.WriteByte('\n')
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+func npyBytes[T float.DType](order binary.ByteOrder, values ...T) []byte {
+	var buf bytes.Buffer
+	for _, v := range values {
+		switch any(v).(type) {
+		case float32:
+			_ = binary.Write(&buf, order, math.Float32bits(float32(v)))
+		default:
+			_ = binary.Write(&buf, order, math.Float64bits(float64(v)))
+		}
+	}
+	return buf.Bytes()
+}
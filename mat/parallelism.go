@@ -0,0 +1,47 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// mulParallelism is the maximum number of goroutines used by the product
+// of two Dense matrices. Zero or negative values stand for the default.
+var mulParallelism int32
+
+// SetMulParallelism sets the maximum number of goroutines used to compute
+// the product of two Dense matrices (see Dense.Mul), and returns the
+// previous setting (0 if the default was in use), so that it can be
+// restored later.
+//
+// A value of 1 makes the multiplication run on the calling goroutine.
+// A value less than 1 restores the default, which is the value of
+// runtime.GOMAXPROCS at the time of each multiplication.
+//
+// Whatever the setting, the goroutines added to the calling ones by all
+// the multiplications running at once are at most runtime.GOMAXPROCS-1.
+// The operators of a graph, which are evaluated concurrently on their own
+// goroutines, thus don't oversubscribe the CPUs: when many products are
+// computed at once, most of them run on the calling goroutine only.
+//
+// It is safe to call SetMulParallelism concurrently with the
+// multiplications, which will use either the old or the new value.
+func SetMulParallelism(n int) int {
+	if n < 0 {
+		n = 0
+	}
+	return int(atomic.SwapInt32(&mulParallelism, int32(n)))
+}
+
+// MulParallelism returns the maximum number of goroutines currently used
+// to compute the product of two Dense matrices (see SetMulParallelism).
+func MulParallelism() int {
+	if n := atomic.LoadInt32(&mulParallelism); n > 0 {
+		return int(n)
+	}
+	return runtime.GOMAXPROCS(0)
+}
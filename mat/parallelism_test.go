@@ -0,0 +1,71 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetMulParallelism(t *testing.T) {
+	defer SetMulParallelism(SetMulParallelism(0))
+
+	assert.Equal(t, runtime.GOMAXPROCS(0), MulParallelism())
+
+	assert.Equal(t, 0, SetMulParallelism(3))
+	assert.Equal(t, 3, MulParallelism())
+
+	assert.Equal(t, 3, SetMulParallelism(-1))
+	assert.Equal(t, runtime.GOMAXPROCS(0), MulParallelism())
+}
+
+func TestDense_MulParallel(t *testing.T) {
+	t.Run("float32", testDenseMulParallel[float32])
+	t.Run("float64", testDenseMulParallel[float64])
+}
+
+func testDenseMulParallel[T float.DType](t *testing.T) {
+	defer SetMulParallelism(SetMulParallelism(0))
+
+	a := NewInitFuncDense[T](130, 70, func(r, c int) T {
+		return T((r*7+c*3)%11) - 5
+	})
+	b := NewInitFuncDense[T](70, 600, func(r, c int) T {
+		return T((r*5+c*2)%13) - 6
+	})
+
+	SetMulParallelism(1)
+	expected := a.Mul(b)
+
+	for _, n := range []int{2, 4, 16} {
+		SetMulParallelism(n)
+		assert.Equal(t, Data[T](expected), Data[T](a.Mul(b)), fmt.Sprintf("parallelism %d", n))
+	}
+}
+
+func BenchmarkDense_Mul(b *testing.B) {
+	for _, size := range []int{64, 256, 1024} {
+		for _, mode := range []string{"serial", "parallel"} {
+			parallelism := 1
+			if mode == "parallel" {
+				parallelism = runtime.GOMAXPROCS(0)
+			}
+			b.Run(fmt.Sprintf("float32/%d/%s", size, mode), func(b *testing.B) {
+				defer SetMulParallelism(SetMulParallelism(parallelism))
+				x := NewInitDense[float32](size, size, 0.5)
+				y := NewInitDense[float32](size, size, 0.25)
+				b.ReportAllocs()
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					ReleaseMatrix(x.Mul(y))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,880 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Quantized is a matrix storing its values as 8-bit integers, using an
+// asymmetric per-row quantization: each row has its own scale and
+// zero-point, and the real value of an element q is scale*(q-zeroPoint).
+// The zero value is always represented exactly. A column vector is
+// quantized as a whole, with a single scale and zero-point.
+//
+// Quantized implements the Matrix interface, so it can replace the Dense
+// value of a parameter, reducing its memory footprint by about four (or
+// eight) times. Matrix products involving a Quantized matrix and a Dense
+// one, in any order, operate directly on the quantized values without
+// converting the receiver to a Dense matrix. Most of the other operations
+// are performed on a dequantized copy, returning a Dense matrix; in-place
+// operations quantize the result again.
+//
+// Quantized matrices are not pooled: ReleaseMatrix has no effect on them.
+type Quantized[T float.DType] struct {
+	rows int
+	cols int
+	// data contains the quantized values in row-major order.
+	data []int8
+	// scales and zeroPoints contain the quantization parameters of each
+	// row, or a single pair of values for a column vector.
+	scales     []T
+	zeroPoints []int8
+}
+
+// Quantization returns the quantized values, in row-major order, and the
+// quantization parameters of each row.
+// The returned slices ARE NOT copies and must not be modified.
+func (q *Quantized[T]) Quantization() (data []int8, scales []T, zeroPoints []int8) {
+	return q.data, q.scales, q.zeroPoints
+}
+
+// Dequantize returns a new Dense matrix with the real values represented
+// by the receiver. The result is not taken from the pool, so it can be
+// safely discarded.
+func (q *Quantized[T]) Dequantize() *Dense[T] {
+	d := &Dense[T]{rows: q.rows, cols: q.cols, data: make([]T, len(q.data))}
+	for r := 0; r < q.rows; r++ {
+		scale, zp := q.rowParams(r)
+		dRow := d.data[r*q.cols : (r+1)*q.cols]
+		for i, v := range q.data[r*q.cols : (r+1)*q.cols] {
+			dRow[i] = scale * (T(v) - zp)
+		}
+	}
+	return d
+}
+
+// rowParams returns the scale and the zero-point of the r-th row.
+func (q *Quantized[T]) rowParams(r int) (scale, zeroPoint T) {
+	if len(q.scales) != q.rows {
+		r = 0
+	}
+	return q.scales[r], T(q.zeroPoints[r])
+}
+
+// setFromData replaces the content of the matrix quantizing the given
+// values, interpreted in row-major order.
+func (q *Quantized[T]) setFromData(data []T) {
+	if len(data) != q.rows*q.cols {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", q.rows*q.cols))
+	}
+	groups, groupSize := q.rows, q.cols
+	if q.cols == 1 && q.rows > 1 {
+		groups, groupSize = 1, q.rows
+	}
+	q.data = make([]int8, len(data))
+	q.scales = make([]T, groups)
+	q.zeroPoints = make([]int8, groups)
+	for g := 0; g < groups; g++ {
+		from, to := g*groupSize, (g+1)*groupSize
+		q.scales[g], q.zeroPoints[g] = quantize(data[from:to], q.data[from:to])
+	}
+}
+
+// quantize stores in dst the quantized representation of src, returning
+// the scale and zero-point in use. The quantization range always includes
+// zero, so that it can be represented exactly.
+func quantize[T float.DType](src []T, dst []int8) (scale T, zeroPoint int8) {
+	var lo, hi T
+	for _, v := range src {
+		if v < lo {
+			lo = v
+		}
+		if v > hi {
+			hi = v
+		}
+	}
+	if lo == hi {
+		return 1, 0
+	}
+	scale = (hi - lo) / 255
+	zp := clampInt8(math.Round(-128 - float64(lo/scale)))
+	for i, v := range src {
+		dst[i] = int8(clampInt8(math.Round(float64(v/scale)) + zp))
+	}
+	return scale, int8(zp)
+}
+
+func clampInt8(v float64) float64 {
+	return math.Max(math.MinInt8, math.Min(math.MaxInt8, v))
+}
+
+// get returns the real value at row r and column c.
+func (q *Quantized[T]) get(r, c int) T {
+	if r < 0 || r >= q.rows {
+		panic("mat: 'r' argument out of range")
+	}
+	if c < 0 || c >= q.cols {
+		panic("mat: 'c' argument out of range")
+	}
+	scale, zp := q.rowParams(r)
+	return scale * (T(q.data[r*q.cols+c]) - zp)
+}
+
+// set sets the real value v at row r and column c, quantizing again the
+// values which share the same quantization parameters.
+func (q *Quantized[T]) set(r, c int, v T) {
+	if r < 0 || r >= q.rows {
+		panic("mat: 'r' argument out of range")
+	}
+	if c < 0 || c >= q.cols {
+		panic("mat: 'c' argument out of range")
+	}
+	if len(q.scales) != q.rows {
+		q.inPlace(func(d *Dense[T]) { d.data[r*q.cols+c] = v })
+		return
+	}
+	scale, zp := q.rowParams(r)
+	row := q.data[r*q.cols : (r+1)*q.cols]
+	values := make([]T, len(row))
+	for i, qv := range row {
+		values[i] = scale * (T(qv) - zp)
+	}
+	values[c] = v
+	q.scales[r], q.zeroPoints[r] = quantize(values, row)
+}
+
+// inPlace calls fn with a dequantized copy of the matrix, then quantizes
+// the modified values back into the receiver.
+func (q *Quantized[T]) inPlace(fn func(d *Dense[T])) {
+	d := q.Dequantize()
+	fn(d)
+	q.rows, q.cols = d.rows, d.cols
+	q.setFromData(d.data)
+}
+
+// Rows returns the number of rows of the matrix.
+func (q *Quantized[T]) Rows() int {
+	return q.rows
+}
+
+// Columns returns the number of columns of the matrix.
+func (q *Quantized[T]) Columns() int {
+	return q.cols
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (q *Quantized[T]) Dims() (r, c int) {
+	return q.rows, q.cols
+}
+
+// The Size of the matrix (rows*columns).
+func (q *Quantized[T]) Size() int {
+	return q.rows * q.cols
+}
+
+// Data returns a copy of the dequantized values of the matrix, as a raw
+// one-dimensional slice of values in row-major order.
+func (q *Quantized[T]) Data() float.Slice {
+	return float.SliceInterface(q.Dequantize().data)
+}
+
+// SetData sets the content of the matrix, quantizing the given raw data
+// representation as one-dimensional slice.
+func (q *Quantized[T]) SetData(data float.Slice) {
+	q.setFromData(float.SliceValueOf[T](data))
+}
+
+// ZerosLike returns a new quantized matrix with the same dimensions of the
+// receiver, initialized with zeros.
+func (q *Quantized[T]) ZerosLike() Matrix {
+	return NewEmptyQuantized[T](q.rows, q.cols)
+}
+
+// OnesLike returns a new Dense matrix with the same dimensions of the
+// receiver, initialized with ones.
+func (q *Quantized[T]) OnesLike() Matrix {
+	return NewInitDense[T](q.rows, q.cols, 1)
+}
+
+// Scalar returns the scalar value.
+// It panics if the matrix does not contain exactly one element.
+func (q *Quantized[T]) Scalar() float.Float {
+	if q.Size() != 1 {
+		panic("mat: expected scalar but the matrix contains more elements")
+	}
+	return float.Interface(q.get(0, 0))
+}
+
+// Zeros sets all the values of the matrix to zero.
+func (q *Quantized[T]) Zeros() {
+	for i := range q.data {
+		q.data[i] = 0
+	}
+	for i := range q.scales {
+		q.scales[i] = 1
+		q.zeroPoints[i] = 0
+	}
+}
+
+// Set sets the scalar value from a 1×1 matrix at row r and column c.
+// It panics if the given matrix is not 1×1, or if indices are out of range.
+func (q *Quantized[T]) Set(r int, c int, m Matrix) {
+	q.set(r, c, float.ValueOf[T](m.Scalar()))
+}
+
+// At returns the value at row r and column c as a 1×1 matrix.
+// It panics if the given indices are out of range.
+func (q *Quantized[T]) At(r int, c int) Matrix {
+	return NewScalar(q.get(r, c))
+}
+
+// SetScalar sets the value v at row r and column c.
+// It panics if the given indices are out of range.
+func (q *Quantized[T]) SetScalar(r int, c int, v float.Float) {
+	q.set(r, c, float.ValueOf[T](v))
+}
+
+// ScalarAt returns the value at row r and column c.
+// It panics if the given indices are out of range.
+func (q *Quantized[T]) ScalarAt(r int, c int) float.Float {
+	return float.Interface(q.get(r, c))
+}
+
+// SetVec sets the scalar value from a 1×1 matrix at position i of a
+// vector. It panics if the receiver is not a vector, or the given matrix is
+// not 1×1, or the position is out of range.
+func (q *Quantized[T]) SetVec(i int, m Matrix) {
+	r, c := q.vecIndices(i)
+	q.set(r, c, float.ValueOf[T](m.Scalar()))
+}
+
+// AtVec returns the value at position i of a vector as a 1×1 matrix.
+// It panics if the receiver is not a vector or the position is out of range.
+func (q *Quantized[T]) AtVec(i int) Matrix {
+	return NewScalar(q.get(q.vecIndices(i)))
+}
+
+// SetVecScalar sets the value v at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (q *Quantized[T]) SetVecScalar(i int, v float.Float) {
+	r, c := q.vecIndices(i)
+	q.set(r, c, float.ValueOf[T](v))
+}
+
+// ScalarAtVec returns the value at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (q *Quantized[T]) ScalarAtVec(i int) float.Float {
+	return float.Interface(q.get(q.vecIndices(i)))
+}
+
+func (q *Quantized[T]) vecIndices(i int) (r, c int) {
+	if !IsVector(q) {
+		panic("mat: expected vector")
+	}
+	if i < 0 || i >= q.Size() {
+		panic("mat: 'i' argument out of range")
+	}
+	if q.cols == 1 {
+		return i, 0
+	}
+	return 0, i
+}
+
+// ExtractRow returns a copy of the i-th row of the matrix,
+// as a Dense row vector (1×cols).
+func (q *Quantized[T]) ExtractRow(i int) Matrix {
+	return q.Dequantize().ExtractRow(i)
+}
+
+// ExtractColumn returns a copy of the i-th column of the matrix,
+// as a Dense column vector (rows×1).
+func (q *Quantized[T]) ExtractColumn(i int) Matrix {
+	return q.Dequantize().ExtractColumn(i)
+}
+
+// View returns a Dense copy of the matrix with the given dimensions, since
+// the quantized representation cannot be shared.
+func (q *Quantized[T]) View(rows, cols int) Matrix {
+	return q.Dequantize().Reshape(rows, cols)
+}
+
+// Slice returns a new Dense matrix obtained by slicing the receiver across
+// the given positions. The parameters "fromRow" and "fromCol" are inclusive,
+// while "toRow" and "toCol" are exclusive.
+func (q *Quantized[T]) Slice(fromRow, fromCol, toRow, toCol int) Matrix {
+	return q.Dequantize().Slice(fromRow, fromCol, toRow, toCol)
+}
+
+// Reshape returns a Dense copy of the matrix with the given dimensions.
+// It panics if the dimensions are incompatible.
+func (q *Quantized[T]) Reshape(rows, cols int) Matrix {
+	return q.Dequantize().Reshape(rows, cols)
+}
+
+// ReshapeInPlace changes the dimensions of the matrix in place, quantizing
+// again its values, and returns the matrix itself.
+// It panics if the dimensions are incompatible.
+func (q *Quantized[T]) ReshapeInPlace(rows, cols int) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.ReshapeInPlace(rows, cols) })
+	return q
+}
+
+// Flatten creates a new Dense row vector (1×size) corresponding to the
+// "flattened" row-major ordered representation of the initial matrix.
+func (q *Quantized[T]) Flatten() Matrix {
+	return q.Dequantize().Flatten()
+}
+
+// FlattenInPlace transforms the matrix in place, changing its dimensions,
+// obtaining a row vector (1×size) containing the "flattened" row-major
+// ordered representation of the initial value.
+// It returns the matrix itself.
+func (q *Quantized[T]) FlattenInPlace() Matrix {
+	return q.ReshapeInPlace(1, q.Size())
+}
+
+// ResizeVector returns a resized Dense copy of the vector.
+//
+// If the new size is smaller than the input vector, the remaining tail
+// elements are removed. If it's bigger, the additional tail elements
+// are set to zero.
+func (q *Quantized[T]) ResizeVector(newSize int) Matrix {
+	return q.Dequantize().ResizeVector(newSize)
+}
+
+// T returns the transpose of the matrix, as a new Dense matrix.
+func (q *Quantized[T]) T() Matrix {
+	return q.Dequantize().T()
+}
+
+// TransposeInPlace transposes the matrix in place, quantizing again its
+// values, and returns the matrix itself.
+func (q *Quantized[T]) TransposeInPlace() Matrix {
+	q.inPlace(func(d *Dense[T]) { d.TransposeInPlace() })
+	return q
+}
+
+// Add returns the addition between the receiver and another matrix,
+// as a new Dense matrix.
+func (q *Quantized[T]) Add(other Matrix) Matrix {
+	return q.Dequantize().Add(other)
+}
+
+// AddInPlace performs the in-place addition with the other matrix.
+func (q *Quantized[T]) AddInPlace(other Matrix) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.AddInPlace(other) })
+	return q
+}
+
+// AddScalar performs the addition between the matrix and the given value,
+// returning a new Dense matrix.
+func (q *Quantized[T]) AddScalar(n float64) Matrix {
+	return q.Dequantize().AddScalar(n)
+}
+
+// AddScalarInPlace adds the scalar to all values of the matrix.
+func (q *Quantized[T]) AddScalarInPlace(n float64) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.AddScalarInPlace(n) })
+	return q
+}
+
+// Sub returns the subtraction of the other matrix from the receiver,
+// as a new Dense matrix.
+func (q *Quantized[T]) Sub(other Matrix) Matrix {
+	return q.Dequantize().Sub(other)
+}
+
+// SubInPlace performs the in-place subtraction with the other matrix.
+func (q *Quantized[T]) SubInPlace(other Matrix) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.SubInPlace(other) })
+	return q
+}
+
+// SubScalar performs a subtraction between the matrix and the given value,
+// returning a new Dense matrix.
+func (q *Quantized[T]) SubScalar(n float64) Matrix {
+	return q.Dequantize().SubScalar(n)
+}
+
+// SubScalarInPlace subtracts the scalar from the receiver's values.
+func (q *Quantized[T]) SubScalarInPlace(n float64) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.SubScalarInPlace(n) })
+	return q
+}
+
+// Prod performs the element-wise product between the receiver and the
+// other matrix, returning a new Dense matrix.
+func (q *Quantized[T]) Prod(other Matrix) Matrix {
+	return q.Dequantize().Prod(other)
+}
+
+// ProdInPlace performs the in-place element-wise product with the other matrix.
+func (q *Quantized[T]) ProdInPlace(other Matrix) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.ProdInPlace(other) })
+	return q
+}
+
+// ProdScalar returns the multiplication between the matrix and the given
+// value, as a new quantized matrix.
+func (q *Quantized[T]) ProdScalar(n float64) Matrix {
+	return q.Clone().(*Quantized[T]).ProdScalarInPlace(n)
+}
+
+// ProdScalarInPlace performs the in-place multiplication between the
+// matrix and the given value.
+func (q *Quantized[T]) ProdScalarInPlace(n float64) Matrix {
+	if n < 0 {
+		q.inPlace(func(d *Dense[T]) { d.ProdScalarInPlace(n) })
+		return q
+	}
+	if n == 0 {
+		q.Zeros()
+		return q
+	}
+	for i := range q.scales {
+		q.scales[i] *= T(n)
+	}
+	return q
+}
+
+// ProdMatrixScalarInPlace multiplies the given matrix with the value,
+// storing the result in the receiver.
+func (q *Quantized[T]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.ProdMatrixScalarInPlace(m, n) })
+	return q
+}
+
+// Div returns the result of the element-wise division of the receiver by
+// the other matrix, as a new Dense matrix.
+func (q *Quantized[T]) Div(other Matrix) Matrix {
+	return q.Dequantize().Div(other)
+}
+
+// DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
+func (q *Quantized[T]) DivInPlace(other Matrix) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.DivInPlace(other) })
+	return q
+}
+
+// Mul performs the multiplication row by column.
+// If A is an i×j Matrix, and B is j×k, then the resulting Matrix
+// C = AB will be i×k.
+//
+// The result is a new Dense matrix. The quantized values are multiplied
+// directly, applying the scale and zero-point of each row to the
+// accumulated results.
+func (q *Quantized[T]) Mul(other Matrix) Matrix {
+	if q.cols != other.Rows() {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	outCols := other.Columns()
+	out := densePool[T]().GetEmpty(q.rows, outCols)
+	oData := Data[T](other)
+
+	// The zero-point contribution of each row is zp*sum(other[:, j]).
+	colSums := make([]T, outCols)
+	for k := 0; k < q.cols; k++ {
+		axpy(1, oData[k*outCols:(k+1)*outCols], colSums)
+	}
+
+	for r := 0; r < q.rows; r++ {
+		outRow := out.data[r*outCols : (r+1)*outCols]
+		for k, v := range q.data[r*q.cols : (r+1)*q.cols] {
+			if v != 0 {
+				axpy(T(v), oData[k*outCols:(k+1)*outCols], outRow)
+			}
+		}
+		scale, zp := q.rowParams(r)
+		for j, v := range outRow {
+			outRow[j] = scale * (v - zp*colSums[j])
+		}
+	}
+	return out
+}
+
+// MulT performs the matrix multiplication row by column.
+// ATB = C, where AT is the transpose of A
+// if A is an r x c Matrix, and B is j x k, r = j the resulting
+// Matrix C will be c x k.
+//
+// The result is a new Dense matrix.
+func (q *Quantized[T]) MulT(other Matrix) Matrix {
+	if q.rows != other.Rows() {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	outCols := other.Columns()
+	out := densePool[T]().GetEmpty(q.cols, outCols)
+	oData := Data[T](other)
+	for r := 0; r < q.rows; r++ {
+		scale, zp := q.rowParams(r)
+		oRow := oData[r*outCols : (r+1)*outCols]
+		for c, v := range q.data[r*q.cols : (r+1)*q.cols] {
+			if w := scale * (T(v) - zp); w != 0 {
+				axpy(w, oRow, out.data[c*outCols:(c+1)*outCols])
+			}
+		}
+	}
+	return out
+}
+
+// denseMul returns the product d×q as a new Dense matrix.
+func (q *Quantized[T]) denseMul(d *Dense[T]) *Dense[T] {
+	out := densePool[T]().GetEmpty(d.rows, q.cols)
+	for i := 0; i < d.rows; i++ {
+		q.accRows(d.data[i*d.cols:(i+1)*d.cols], out.data[i*q.cols:(i+1)*q.cols])
+	}
+	return out
+}
+
+// denseMulT returns the product dᵀ×q as a new Dense matrix.
+func (q *Quantized[T]) denseMulT(d *Dense[T]) *Dense[T] {
+	out := densePool[T]().GetEmpty(d.cols, q.cols)
+	coeffs := make([]T, d.rows)
+	for i := 0; i < d.cols; i++ {
+		for k := range coeffs {
+			coeffs[k] = d.data[k*d.cols+i]
+		}
+		q.accRows(coeffs, out.data[i*q.cols:(i+1)*q.cols])
+	}
+	return out
+}
+
+// accRows adds to dst the linear combination of the rows of the matrix,
+// weighted by the given coefficients.
+func (q *Quantized[T]) accRows(coeffs, dst []T) {
+	var offset T
+	for k, coeff := range coeffs {
+		if coeff == 0 {
+			continue
+		}
+		scale, zp := q.rowParams(k)
+		a := coeff * scale
+		offset += a * zp
+		for j, v := range q.data[k*q.cols : (k+1)*q.cols] {
+			dst[j] += a * T(v)
+		}
+	}
+	for j := range dst {
+		dst[j] -= offset
+	}
+}
+
+// DotUnitary returns the dot product of two vectors as a scalar Matrix.
+func (q *Quantized[T]) DotUnitary(other Matrix) Matrix {
+	return q.Dequantize().DotUnitary(other)
+}
+
+// ClipInPlace clips in place each value of the matrix.
+func (q *Quantized[T]) ClipInPlace(min, max float64) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.ClipInPlace(min, max) })
+	return q
+}
+
+// Maximum returns a new Dense matrix containing the element-wise maxima.
+func (q *Quantized[T]) Maximum(other Matrix) Matrix {
+	return q.Dequantize().Maximum(other)
+}
+
+// Minimum returns a new Dense matrix containing the element-wise minima.
+func (q *Quantized[T]) Minimum(other Matrix) Matrix {
+	return q.Dequantize().Minimum(other)
+}
+
+// Abs returns a new Dense matrix applying the absolute value function to
+// all elements.
+func (q *Quantized[T]) Abs() Matrix {
+	return q.Dequantize().Abs()
+}
+
+// Pow returns a new Dense matrix, applying the power function with given
+// exponent to all elements of the matrix.
+func (q *Quantized[T]) Pow(power float64) Matrix {
+	return q.Dequantize().Pow(power)
+}
+
+// Sqrt returns a new Dense matrix applying the square root function to
+// all elements.
+func (q *Quantized[T]) Sqrt() Matrix {
+	return q.Dequantize().Sqrt()
+}
+
+// Log returns a new Dense matrix applying the natural logarithm function to
+// each element.
+func (q *Quantized[T]) Log() Matrix {
+	return q.Dequantize().Log()
+}
+
+// Exp returns a new Dense matrix applying the base-e exponential function
+// to each element.
+func (q *Quantized[T]) Exp() Matrix {
+	return q.Dequantize().Exp()
+}
+
+// Sigmoid returns a new Dense matrix applying the sigmoid function to each
+// element.
+func (q *Quantized[T]) Sigmoid() Matrix {
+	return q.Dequantize().Sigmoid()
+}
+
+// Sum returns the sum of all values of the matrix as a scalar Matrix.
+func (q *Quantized[T]) Sum() Matrix {
+	return q.Dequantize().Sum()
+}
+
+// Max returns the maximum value of the matrix as a scalar Matrix.
+func (q *Quantized[T]) Max() Matrix {
+	return q.Dequantize().Max()
+}
+
+// Min returns the minimum value of the matrix as a scalar Matrix.
+func (q *Quantized[T]) Min() Matrix {
+	return q.Dequantize().Min()
+}
+
+// ArgMax returns the index of the vector's element with the maximum value.
+func (q *Quantized[T]) ArgMax() int {
+	return q.Dequantize().ArgMax()
+}
+
+// Softmax applies the softmax function to the vector, returning the
+// result as a new Dense column vector.
+func (q *Quantized[T]) Softmax() Matrix {
+	return q.Dequantize().Softmax()
+}
+
+// CumSum computes the cumulative sum of the vector's elements, returning
+// the result as a new Dense column vector.
+func (q *Quantized[T]) CumSum() Matrix {
+	return q.Dequantize().CumSum()
+}
+
+// Range creates a new Dense vector initialized with data extracted from the
+// matrix values, from start (inclusive) to end (exclusive).
+func (q *Quantized[T]) Range(start, end int) Matrix {
+	return q.Dequantize().Range(start, end)
+}
+
+// SplitV splits the vector in N Dense chunks of given sizes,
+// so that N[i] has size sizes[i].
+func (q *Quantized[T]) SplitV(sizes ...int) []Matrix {
+	return q.Dequantize().SplitV(sizes...)
+}
+
+// Augment places the identity matrix at the end of the original matrix,
+// returning a new Dense matrix.
+func (q *Quantized[T]) Augment() Matrix {
+	return q.Dequantize().Augment()
+}
+
+// SwapInPlace swaps two rows of the matrix in place.
+func (q *Quantized[T]) SwapInPlace(r1, r2 int) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.SwapInPlace(r1, r2) })
+	return q
+}
+
+// PadRows returns a Dense copy of the matrix with n additional tail rows.
+// The additional elements are set to zero.
+func (q *Quantized[T]) PadRows(n int) Matrix {
+	return q.Dequantize().PadRows(n)
+}
+
+// PadColumns returns a Dense copy of the matrix with n additional tail
+// columns. The additional elements are set to zero.
+func (q *Quantized[T]) PadColumns(n int) Matrix {
+	return q.Dequantize().PadColumns(n)
+}
+
+// AppendRows returns a Dense copy of the matrix with len(vs) additional
+// tail rows, being each new row filled with the values of each given vector.
+//
+// It accepts row or column vectors indifferently, virtually treating all of
+// them as row vectors.
+func (q *Quantized[T]) AppendRows(vs ...Matrix) Matrix {
+	return q.Dequantize().AppendRows(vs...)
+}
+
+// Norm returns the vector's norm. Use pow = 2.0 to compute the Euclidean norm.
+// The result is a scalar Matrix.
+func (q *Quantized[T]) Norm(pow float64) Matrix {
+	return q.Dequantize().Norm(pow)
+}
+
+// Pivoting returns the partial pivots of a square matrix to reorder rows.
+// Considerate square sub-matrix from element (offset, offset).
+func (q *Quantized[T]) Pivoting(row int) (Matrix, bool, [2]int) {
+	return q.Dequantize().Pivoting(row)
+}
+
+// Normalize2 normalizes an array with the Euclidean norm, returning a new
+// Dense matrix.
+func (q *Quantized[T]) Normalize2() Matrix {
+	return q.Dequantize().Normalize2()
+}
+
+// LU performs lower–upper (LU) decomposition of a square matrix D such as
+// PLU = D, L is lower diagonal and U is upper diagonal, p are pivots.
+func (q *Quantized[T]) LU() (l, u, p Matrix) {
+	return q.Dequantize().LU()
+}
+
+// Inverse returns the inverse of the Matrix, as a new Dense matrix.
+func (q *Quantized[T]) Inverse() Matrix {
+	return q.Dequantize().Inverse()
+}
+
+// VecForEach calls fn for each element of the vector.
+// It panics if the receiver is not a vector.
+func (q *Quantized[T]) VecForEach(fn func(i int, v float64)) {
+	q.Dequantize().VecForEach(fn)
+}
+
+// Apply creates a new Dense matrix executing the unary function fn.
+func (q *Quantized[T]) Apply(fn func(r, c int, v float64) float64) Matrix {
+	return q.Dequantize().Apply(fn)
+}
+
+// ApplyInPlace executes the unary function fn over the matrix a,
+// and stores the result in the receiver, returning the receiver itself.
+func (q *Quantized[T]) ApplyInPlace(fn func(r, c int, v float64) float64, a Matrix) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.ApplyInPlace(fn, a) })
+	return q
+}
+
+// ApplyWithAlpha creates a new Dense matrix executing the unary function
+// fn, taking additional parameters alpha.
+func (q *Quantized[T]) ApplyWithAlpha(fn func(r, c int, v float64, alpha ...float64) float64, alpha ...float64) Matrix {
+	return q.Dequantize().ApplyWithAlpha(fn, alpha...)
+}
+
+// ApplyWithAlphaInPlace executes the unary function fn over the matrix a,
+// taking additional parameters alpha, and stores the result in the
+// receiver, returning the receiver itself.
+func (q *Quantized[T]) ApplyWithAlphaInPlace(fn func(r, c int, v float64, alpha ...float64) float64, a Matrix, alpha ...float64) Matrix {
+	q.inPlace(func(d *Dense[T]) { d.ApplyWithAlphaInPlace(fn, a, alpha...) })
+	return q
+}
+
+// DoNonZero calls a function for each non-zero element of the matrix.
+// The parameters of the function are the element's indices and value.
+func (q *Quantized[T]) DoNonZero(fn func(r, c int, v float64)) {
+	q.Dequantize().DoNonZero(fn)
+}
+
+// DoVecNonZero calls a function for each non-zero element of the vector.
+// The parameters of the function are the element's index and value.
+func (q *Quantized[T]) DoVecNonZero(fn func(i int, v float64)) {
+	q.Dequantize().DoVecNonZero(fn)
+}
+
+// Clone returns a new quantized matrix, copying all its values from the
+// receiver.
+func (q *Quantized[T]) Clone() Matrix {
+	return &Quantized[T]{
+		rows:       q.rows,
+		cols:       q.cols,
+		data:       append([]int8(nil), q.data...),
+		scales:     append([]T(nil), q.scales...),
+		zeroPoints: append([]int8(nil), q.zeroPoints...),
+	}
+}
+
+// Copy copies the data from the other matrix to the receiver, quantizing
+// it if necessary.
+// It panics if the matrices have different dimensions.
+func (q *Quantized[T]) Copy(other Matrix) {
+	if !SameDims(q, other) {
+		panic("mat: incompatible matrix dimensions")
+	}
+	if o, ok := other.(*Quantized[T]); ok {
+		*q = *o.Clone().(*Quantized[T])
+		return
+	}
+	q.setFromData(Data[T](other))
+}
+
+// String returns a string representation of the matrix.
+func (q *Quantized[T]) String() string {
+	return fmt.Sprintf("Matrix|Quantized[%T](%d×%d)%v", T(0), q.rows, q.cols, q.Dequantize().data)
+}
+
+// NewMatrix creates a new Dense matrix, of the same data type of the
+// receiver, of size rows×cols, initialized with a copy of raw data.
+func (q *Quantized[T]) NewMatrix(rows, cols int, data float.Slice) Matrix {
+	return NewDense[T](rows, cols, float.SliceValueOf[T](data))
+}
+
+// NewVec creates a new Dense column vector (len(data)×1), of the same data
+// type of the receiver, initialized with a copy of raw data.
+func (q *Quantized[T]) NewVec(data float.Slice) Matrix {
+	return NewVecDense[T](float.SliceValueOf[T](data))
+}
+
+// NewScalar creates a new 1×1 matrix, of the same data type of the receiver,
+// containing the given value.
+func (q *Quantized[T]) NewScalar(v float64) Matrix {
+	return NewScalar(T(v))
+}
+
+// NewEmptyVec creates a new Dense vector, of the same data type of the
+// receiver, with dimensions size×1, initialized with zeros.
+func (q *Quantized[T]) NewEmptyVec(size int) Matrix {
+	return NewEmptyVecDense[T](size)
+}
+
+// NewEmptyMatrix creates a new rows×cols Dense matrix, of the same data
+// type of the receiver, initialized with zeros.
+func (q *Quantized[T]) NewEmptyMatrix(rows, cols int) Matrix {
+	return NewEmptyDense[T](rows, cols)
+}
+
+// NewInitMatrix creates a new rows×cols dense matrix, of the same data type
+// of the receiver, initialized with a constant value.
+func (q *Quantized[T]) NewInitMatrix(rows, cols int, v float64) Matrix {
+	return NewInitDense(rows, cols, T(v))
+}
+
+// NewInitFuncMatrix creates a new rows×cols dense matrix, of the same data
+// type of the receiver, initialized with the values returned from the
+// callback function.
+func (q *Quantized[T]) NewInitFuncMatrix(rows, cols int, fn func(r, c int) float64) Matrix {
+	return NewInitFuncDense(rows, cols, func(r, c int) T {
+		return T(fn(r, c))
+	})
+}
+
+// NewInitVec creates a new Dense column vector (size×1), of the same data
+// type of the receiver, initialized with a constant value.
+func (q *Quantized[T]) NewInitVec(size int, v float64) Matrix {
+	return NewInitVecDense(size, T(v))
+}
+
+// NewIdentityMatrix creates a new square Dense identity matrix (size×size),
+// of the same data type of the receiver.
+func (q *Quantized[T]) NewIdentityMatrix(size int) Matrix {
+	return NewIdentityDense[T](size)
+}
+
+// NewOneHotVec creates a new Dense one-hot column vector (size×1), of the
+// same data type of the receiver.
+func (q *Quantized[T]) NewOneHotVec(size int, oneAt int) Matrix {
+	return NewOneHotVecDense[T](size, oneAt)
+}
+
+// NewConcatV creates a new Dense column vector, of the same data type of
+// the receiver, concatenating two or more vectors "vertically".
+func (q *Quantized[T]) NewConcatV(vs ...Matrix) Matrix {
+	return ConcatV[T](vs...)
+}
+
+// NewStack creates a new Dense matrix, of the same data type of the
+// receiver, stacking two or more vectors of the same size on top of each
+// other.
+func (q *Quantized[T]) NewStack(vs ...Matrix) Matrix {
+	return Stack[T](vs...)
+}
@@ -0,0 +1,120 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+)
+
+func init() {
+	gob.Register(&Quantized[float32]{})
+	gob.Register(&Quantized[float64]{})
+}
+
+const (
+	binaryQuantizedFloat32 byte = iota
+	binaryQuantizedFloat64
+)
+
+// Quantized matrix marshaling:
+// - 1 byte - identifier binaryQuantizedFloat32 or binaryQuantizedFloat64
+// - 8 bytes - rows (uint64)
+// - 8 bytes - cols (uint64)
+// - 8 bytes - number of quantization groups (uint64)
+// - groups * (4 or 8) bytes - scales (float32 or float64 bits)
+// - groups bytes - zero-points (int8)
+// - size bytes - data (int8)
+
+// MarshalBinary marshals a Quantized matrix into binary form.
+func (q Quantized[T]) MarshalBinary() ([]byte, error) {
+	var identifier byte
+	var scaleSize int
+	switch any(T(0)).(type) {
+	case float32:
+		identifier, scaleSize = binaryQuantizedFloat32, 4
+	case float64:
+		identifier, scaleSize = binaryQuantizedFloat64, 8
+	default:
+		panic(fmt.Sprintf("mat: unexpected quantized matrix type %T", T(0)))
+	}
+
+	groups := len(q.scales)
+	data := make([]byte, 25+groups*(scaleSize+1)+len(q.data))
+	data[0] = identifier
+	binary.LittleEndian.PutUint64(data[1:], uint64(q.rows))
+	binary.LittleEndian.PutUint64(data[9:], uint64(q.cols))
+	binary.LittleEndian.PutUint64(data[17:], uint64(groups))
+
+	s := data[25:]
+	for _, v := range q.scales {
+		if scaleSize == 4 {
+			binary.LittleEndian.PutUint32(s, math.Float32bits(float32(v)))
+		} else {
+			binary.LittleEndian.PutUint64(s, math.Float64bits(float64(v)))
+		}
+		s = s[scaleSize:]
+	}
+	for i, v := range q.zeroPoints {
+		s[i] = byte(v)
+	}
+	s = s[groups:]
+	for i, v := range q.data {
+		s[i] = byte(v)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of a Quantized matrix.
+// The representation can only be unmarshaled into a matrix of the same
+// data type.
+func (q *Quantized[T]) UnmarshalBinary(data []byte) error {
+	var identifier byte
+	var scaleSize int
+	switch any(T(0)).(type) {
+	case float32:
+		identifier, scaleSize = binaryQuantizedFloat32, 4
+	case float64:
+		identifier, scaleSize = binaryQuantizedFloat64, 8
+	default:
+		panic(fmt.Sprintf("mat: unexpected quantized matrix type %T", T(0)))
+	}
+
+	if len(data) < 25 || data[0] != identifier {
+		return fmt.Errorf("mat: cannot unmarshal Quantized[%T]: invalid identifier", T(0))
+	}
+	rows := int(binary.LittleEndian.Uint64(data[1:]))
+	cols := int(binary.LittleEndian.Uint64(data[9:]))
+	groups := int(binary.LittleEndian.Uint64(data[17:]))
+	if len(data) != 25+groups*(scaleSize+1)+rows*cols {
+		return errors.New("mat: cannot unmarshal Quantized: invalid data length")
+	}
+
+	q.rows, q.cols = rows, cols
+	q.scales = make([]T, groups)
+	q.zeroPoints = make([]int8, groups)
+	q.data = make([]int8, rows*cols)
+
+	s := data[25:]
+	for i := range q.scales {
+		if scaleSize == 4 {
+			q.scales[i] = T(math.Float32frombits(binary.LittleEndian.Uint32(s)))
+		} else {
+			q.scales[i] = T(math.Float64frombits(binary.LittleEndian.Uint64(s)))
+		}
+		s = s[scaleSize:]
+	}
+	for i := range q.zeroPoints {
+		q.zeroPoints[i] = int8(s[i])
+	}
+	s = s[groups:]
+	for i := range q.data {
+		q.data[i] = int8(s[i])
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// NewQuantized returns a new rows×cols quantized matrix, quantizing the
+// given values, interpreted in row-major order.
+// It panics if the length of data is not rows*cols.
+func NewQuantized[T float.DType](rows, cols int, data []T) *Quantized[T] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if len(data) != rows*cols {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", rows*cols))
+	}
+	q := &Quantized[T]{rows: rows, cols: cols}
+	q.setFromData(data)
+	return q
+}
+
+// NewEmptyQuantized returns a new rows×cols quantized matrix,
+// initialized with zeros.
+func NewEmptyQuantized[T float.DType](rows, cols int) *Quantized[T] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	q := &Quantized[T]{rows: rows, cols: cols}
+	q.setFromData(make([]T, rows*cols))
+	return q
+}
+
+// NewQuantizedFromMatrix returns a new quantized matrix with the same
+// dimensions and the quantized values of m.
+func NewQuantizedFromMatrix[T float.DType](m Matrix) *Quantized[T] {
+	if q, ok := m.(*Quantized[T]); ok {
+		return q.Clone().(*Quantized[T])
+	}
+	return NewQuantized[T](m.Rows(), m.Columns(), Data[T](m))
+}
+
+// Quantize returns a new Quantized matrix, of the same data type of m,
+// with the quantized values of m.
+func Quantize(m Matrix) Matrix {
+	switch m.Data().BitSize() {
+	case 32:
+		return NewQuantizedFromMatrix[float32](m)
+	case 64:
+		return NewQuantizedFromMatrix[float64](m)
+	default:
+		panic(fmt.Sprintf("mat: unexpected matrix type %T", m))
+	}
+}
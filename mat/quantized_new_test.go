@@ -0,0 +1,55 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQuantized(t *testing.T) {
+	t.Run("float32", testNewQuantized[float32])
+	t.Run("float64", testNewQuantized[float64])
+}
+
+func testNewQuantized[T float.DType](t *testing.T) {
+	q := NewQuantized[T](2, 3, []T{1, 2, 3, 4, 5, 6})
+	assert.Equal(t, 2, q.Rows())
+	assert.Equal(t, 3, q.Columns())
+	assert.InDeltaSlice(t, []T{1, 2, 3, 4, 5, 6}, Data[T](q), 2.0e-2)
+
+	require.Panics(t, func() { NewQuantized[T](-1, 2, nil) })
+	require.Panics(t, func() { NewQuantized[T](2, 2, []T{1}) })
+}
+
+func TestNewEmptyQuantized(t *testing.T) {
+	t.Run("float32", testNewEmptyQuantized[float32])
+	t.Run("float64", testNewEmptyQuantized[float64])
+}
+
+func testNewEmptyQuantized[T float.DType](t *testing.T) {
+	q := NewEmptyQuantized[T](2, 3)
+	assert.Equal(t, []T{0, 0, 0, 0, 0, 0}, Data[T](q))
+	require.Panics(t, func() { NewEmptyQuantized[T](2, -1) })
+}
+
+func TestQuantize(t *testing.T) {
+	t.Run("float32", testQuantize[float32])
+	t.Run("float64", testQuantize[float64])
+}
+
+func testQuantize[T float.DType](t *testing.T) {
+	d := NewDense[T](2, 2, []T{-1, 0, 1, 2})
+	q, ok := Quantize(d).(*Quantized[T])
+	require.True(t, ok)
+	assert.InDeltaSlice(t, Data[T](d), Data[T](q), 1.0e-2)
+
+	c := NewQuantizedFromMatrix[T](q)
+	assert.NotSame(t, q, c)
+	assert.Equal(t, q, c)
+}
@@ -0,0 +1,192 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Matrix = &Quantized[float32]{}
+var _ Matrix = &Quantized[float64]{}
+
+func newTestQuantizedData[T float.DType]() []T {
+	return []T{
+		0.5, -1.2, 0.0, 2.4,
+		0.0, 0.0, 0.0, 0.0,
+		3.0, 3.0, 3.0, 3.0,
+	}
+}
+
+func TestQuantized_Quantization(t *testing.T) {
+	t.Run("float32", testQuantizedQuantization[float32])
+	t.Run("float64", testQuantizedQuantization[float64])
+}
+
+func testQuantizedQuantization[T float.DType](t *testing.T) {
+	t.Run("per-row parameters", func(t *testing.T) {
+		data := newTestQuantizedData[T]()
+		q := NewQuantized[T](3, 4, data)
+		qData, scales, zeroPoints := q.Quantization()
+		assert.Len(t, qData, 12)
+		assert.Len(t, scales, 3)
+		assert.Len(t, zeroPoints, 3)
+
+		d := q.Dequantize()
+		assertDenseDims(t, 3, 4, d)
+		for r := 0; r < 3; r++ {
+			for c := 0; c < 4; c++ {
+				i := r*4 + c
+				assert.InDelta(t, data[i], d.data[i], float64(scales[r])/2+1.0e-6)
+			}
+		}
+		assert.Equal(t, T(0), d.data[2], "zero must be represented exactly")
+		assert.Equal(t, []T{0, 0, 0, 0}, d.data[4:8])
+	})
+
+	t.Run("column vector", func(t *testing.T) {
+		q := NewQuantized[T](4, 1, []T{-1, 0, 0.5, 1})
+		_, scales, zeroPoints := q.Quantization()
+		assert.Len(t, scales, 1)
+		assert.Len(t, zeroPoints, 1)
+		assert.InDeltaSlice(t, []T{-1, 0, 0.5, 1}, q.Dequantize().data, 1.0e-2)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		q := NewQuantized[T](3, 4, newTestQuantizedData[T]())
+		q.SetScalar(1, 2, float.Interface(T(-2)))
+		assert.InDelta(t, -2.0, q.ScalarAt(1, 2).F64(), 1.0e-6)
+		assert.Equal(t, T(0), float.ValueOf[T](q.ScalarAt(1, 1)))
+
+		v := NewQuantized[T](3, 1, []T{1, 2, 3})
+		v.SetVecScalar(0, float.Interface(T(-3)))
+		assert.InDeltaSlice(t, []T{-3, 2, 3}, Data[T](v), 2.0e-2)
+
+		require.Panics(t, func() { q.ScalarAt(3, 0) })
+	})
+}
+
+func TestQuantized_Mul(t *testing.T) {
+	t.Run("float32", testQuantizedMul[float32])
+	t.Run("float64", testQuantizedMul[float64])
+}
+
+func testQuantizedMul[T float.DType](t *testing.T) {
+	q := NewQuantized[T](3, 4, newTestQuantizedData[T]())
+	d := q.Dequantize()
+
+	t.Run("quantized × dense", func(t *testing.T) {
+		b := NewDense[T](4, 2, []T{
+			1, 2,
+			3, 4,
+			5, 6,
+			7, 8,
+		})
+		y := q.Mul(b)
+		assertDenseDims(t, 3, 2, y.(*Dense[T]))
+		assert.InDeltaSlice(t, Data[T](d.Mul(b)), Data[T](y), 1.0e-4)
+
+		x := NewVecDense([]T{1, -1, 0.5, 2})
+		assert.InDeltaSlice(t, Data[T](d.Mul(x)), Data[T](q.Mul(x)), 1.0e-4)
+	})
+
+	t.Run("quantized transposed × dense", func(t *testing.T) {
+		b := NewDense[T](3, 2, []T{
+			1, 2,
+			3, 4,
+			5, 6,
+		})
+		y := q.MulT(b)
+		assertDenseDims(t, 4, 2, y.(*Dense[T]))
+		dt := d.T()
+		assert.InDeltaSlice(t, Data[T](dt.Mul(b)), Data[T](y), 1.0e-4)
+	})
+
+	t.Run("dense × quantized", func(t *testing.T) {
+		a := NewDense[T](2, 3, []T{
+			1, 2, 3,
+			4, 5, 6,
+		})
+		y := a.Mul(q)
+		assertDenseDims(t, 2, 4, y.(*Dense[T]))
+		assert.InDeltaSlice(t, Data[T](a.Mul(d)), Data[T](y), 1.0e-4)
+	})
+
+	t.Run("dense transposed × quantized", func(t *testing.T) {
+		a := NewDense[T](3, 2, []T{
+			1, 2,
+			3, 4,
+			5, 6,
+		})
+		y := a.MulT(q)
+		assertDenseDims(t, 2, 4, y.(*Dense[T]))
+		at := a.T()
+		assert.InDeltaSlice(t, Data[T](at.Mul(d)), Data[T](y), 1.0e-4)
+	})
+
+	t.Run("incompatible dimensions", func(t *testing.T) {
+		require.Panics(t, func() { q.Mul(NewEmptyDense[T](3, 1)) })
+		require.Panics(t, func() { NewEmptyDense[T](2, 2).Mul(q) })
+	})
+}
+
+func TestQuantized_InPlace(t *testing.T) {
+	t.Run("float32", testQuantizedInPlace[float32])
+	t.Run("float64", testQuantizedInPlace[float64])
+}
+
+func testQuantizedInPlace[T float.DType](t *testing.T) {
+	q := NewQuantized[T](2, 2, []T{1, 2, 3, 4})
+
+	y := q.Clone().(*Quantized[T])
+	y.AddInPlace(NewInitDense[T](2, 2, 1))
+	assert.InDeltaSlice(t, []T{2, 3, 4, 5}, Data[T](y), 2.0e-2)
+
+	y = q.ProdScalar(2).(*Quantized[T])
+	assert.InDeltaSlice(t, []T{2, 4, 6, 8}, Data[T](y), 2.0e-2)
+	assert.InDeltaSlice(t, []T{1, 2, 3, 4}, Data[T](q), 2.0e-2)
+
+	y = q.Clone().(*Quantized[T])
+	y.SubInPlace(q.Dequantize())
+	assert.Equal(t, []T{0, 0, 0, 0}, Data[T](y))
+
+	z := q.Add(q)
+	assertDenseDims(t, 2, 2, z.(*Dense[T]))
+
+	require.NotPanics(t, func() { ReleaseMatrix(q) })
+}
+
+func TestQuantized_Marshaling(t *testing.T) {
+	t.Run("float32", testQuantizedMarshaling[float32])
+	t.Run("float64", testQuantizedMarshaling[float64])
+}
+
+func testQuantizedMarshaling[T float.DType](t *testing.T) {
+	q := NewQuantized[T](3, 4, newTestQuantizedData[T]())
+
+	t.Run("binary", func(t *testing.T) {
+		data, err := q.MarshalBinary()
+		require.NoError(t, err)
+
+		y := new(Quantized[T])
+		require.NoError(t, y.UnmarshalBinary(data))
+		assert.Equal(t, q, y)
+
+		assert.Error(t, y.UnmarshalBinary(data[:len(data)-1]))
+	})
+
+	t.Run("matrix", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, MarshalBinaryMatrix(q, &buf))
+
+		y, err := UnmarshalBinaryMatrix(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, q, y)
+	})
+}
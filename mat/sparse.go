@@ -0,0 +1,1073 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// Sparse is a sparse matrix, storing only its non-zero values in
+// compressed sparse row (CSR) format.
+//
+// Sparse implements the Matrix interface, so it can be used in place of a
+// Dense matrix, for example as the value of an input node. Matrix products
+// involving a Sparse matrix and a Dense one, in any order, only visit the
+// non-zero values, without converting the sparse operand to dense storage.
+// The operations that do not preserve sparsity return a Dense matrix.
+//
+// Sparse matrices are not pooled: ReleaseMatrix has no effect on them.
+type Sparse[T float.DType] struct {
+	rows int
+	cols int
+	// indptr has length rows+1: the column indices and values of the i-th
+	// row are stored from indptr[i] (inclusive) to indptr[i+1] (exclusive).
+	indptr []int
+	// indices contains the column index of each value, sorted within
+	// each row.
+	indices []int
+	values  []T
+}
+
+// NNZ returns the number of non-zero values stored in the matrix.
+func (s *Sparse[T]) NNZ() int {
+	return len(s.values)
+}
+
+// CSR returns the compressed sparse row representation of the matrix.
+// The returned slices ARE NOT copies and must not be modified.
+func (s *Sparse[T]) CSR() (indptr, indices []int, values []T) {
+	return s.indptr, s.indices, s.values
+}
+
+// COO returns the coordinate representation of the matrix: the k-th
+// non-zero value is located at row rowIndices[k] and column colIndices[k].
+// The values are sorted by row, then by column.
+func (s *Sparse[T]) COO() (rowIndices, colIndices []int, values []T) {
+	rowIndices = make([]int, len(s.values))
+	for r := 0; r < s.rows; r++ {
+		for p := s.indptr[r]; p < s.indptr[r+1]; p++ {
+			rowIndices[p] = r
+		}
+	}
+	colIndices = append([]int(nil), s.indices...)
+	values = append([]T(nil), s.values...)
+	return
+}
+
+// doNonZero calls fn for each stored value, in row-major order.
+func (s *Sparse[T]) doNonZero(fn func(r, c int, v T)) {
+	for r := 0; r < s.rows; r++ {
+		for p := s.indptr[r]; p < s.indptr[r+1]; p++ {
+			fn(r, s.indices[p], s.values[p])
+		}
+	}
+}
+
+// find returns the position of the value at row r and column c in the
+// compressed representation, and whether it is actually stored.
+func (s *Sparse[T]) find(r, c int) (int, bool) {
+	if r < 0 || r >= s.rows {
+		panic("mat: 'r' argument out of range")
+	}
+	if c < 0 || c >= s.cols {
+		panic("mat: 'c' argument out of range")
+	}
+	from, to := s.indptr[r], s.indptr[r+1]
+	p := from + sort.SearchInts(s.indices[from:to], c)
+	return p, p < to && s.indices[p] == c
+}
+
+func (s *Sparse[T]) get(r, c int) T {
+	if p, ok := s.find(r, c); ok {
+		return s.values[p]
+	}
+	return 0
+}
+
+// set stores the value v at row r and column c, inserting or removing
+// it from the compressed representation as needed.
+func (s *Sparse[T]) set(r, c int, v T) {
+	p, ok := s.find(r, c)
+	switch {
+	case ok && v != 0:
+		s.values[p] = v
+		return
+	case ok:
+		s.indices = append(s.indices[:p], s.indices[p+1:]...)
+		s.values = append(s.values[:p], s.values[p+1:]...)
+		for i := r + 1; i <= s.rows; i++ {
+			s.indptr[i]--
+		}
+	case v != 0:
+		s.indices = append(s.indices, 0)
+		copy(s.indices[p+1:], s.indices[p:])
+		s.indices[p] = c
+		s.values = append(s.values, 0)
+		copy(s.values[p+1:], s.values[p:])
+		s.values[p] = v
+		for i := r + 1; i <= s.rows; i++ {
+			s.indptr[i]++
+		}
+	}
+}
+
+// prune removes any explicitly stored zero value.
+func (s *Sparse[T]) prune() {
+	n := 0
+	from := 0
+	for r := 0; r < s.rows; r++ {
+		to := s.indptr[r+1]
+		for p := from; p < to; p++ {
+			if s.values[p] == 0 {
+				continue
+			}
+			s.indices[n] = s.indices[p]
+			s.values[n] = s.values[p]
+			n++
+		}
+		from = to
+		s.indptr[r+1] = n
+	}
+	s.indices = s.indices[:n]
+	s.values = s.values[:n]
+}
+
+// setFromData replaces the content of the matrix with the non-zero values
+// of data, interpreted in row-major order.
+func (s *Sparse[T]) setFromData(data []T) {
+	if len(data) != s.rows*s.cols {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Elements size must be: %d", s.rows*s.cols))
+	}
+	s.indptr = make([]int, s.rows+1)
+	s.indices = s.indices[:0]
+	s.values = s.values[:0]
+	for r, i := 0, 0; r < s.rows; r++ {
+		for c := 0; c < s.cols; c, i = c+1, i+1 {
+			if v := data[i]; v != 0 {
+				s.indices = append(s.indices, c)
+				s.values = append(s.values, v)
+			}
+		}
+		s.indptr[r+1] = len(s.values)
+	}
+}
+
+// dense returns a new Dense matrix with the same values of the receiver.
+// The result is not taken from the pool, so it can be safely discarded.
+func (s *Sparse[T]) dense() *Dense[T] {
+	d := &Dense[T]{rows: s.rows, cols: s.cols, data: make([]T, s.rows*s.cols)}
+	s.doNonZero(func(r, c int, v T) {
+		d.data[r*s.cols+c] = v
+	})
+	return d
+}
+
+// inPlace calls fn with a Dense copy of the matrix, then stores back the
+// modified values in the receiver.
+func (s *Sparse[T]) inPlace(fn func(d *Dense[T])) {
+	d := s.dense()
+	fn(d)
+	s.rows, s.cols = d.rows, d.cols
+	s.setFromData(d.data)
+}
+
+// withValues returns a new sparse matrix with the same structure of the
+// receiver and the values obtained applying fn to each stored value.
+func (s *Sparse[T]) withValues(fn func(v T) T) *Sparse[T] {
+	out := s.Clone().(*Sparse[T])
+	for i, v := range out.values {
+		out.values[i] = fn(v)
+	}
+	out.prune()
+	return out
+}
+
+// Rows returns the number of rows of the matrix.
+func (s *Sparse[T]) Rows() int {
+	return s.rows
+}
+
+// Columns returns the number of columns of the matrix.
+func (s *Sparse[T]) Columns() int {
+	return s.cols
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (s *Sparse[T]) Dims() (r, c int) {
+	return s.rows, s.cols
+}
+
+// The Size of the matrix (rows*columns).
+func (s *Sparse[T]) Size() int {
+	return s.rows * s.cols
+}
+
+// Data returns a copy of all the values of the matrix, zeros included,
+// as a raw one-dimensional slice of values in row-major order.
+func (s *Sparse[T]) Data() float.Slice {
+	return float.SliceInterface(s.dense().data)
+}
+
+// SetData sets the content of the matrix, storing the non-zero values of
+// the given raw data representation as one-dimensional slice.
+func (s *Sparse[T]) SetData(data float.Slice) {
+	s.setFromData(float.SliceValueOf[T](data))
+}
+
+// ZerosLike returns a new sparse matrix with the same dimensions of the
+// receiver, with no non-zero values.
+func (s *Sparse[T]) ZerosLike() Matrix {
+	return NewEmptySparse[T](s.rows, s.cols)
+}
+
+// OnesLike returns a new Dense matrix with the same dimensions of the
+// receiver, initialized with ones.
+func (s *Sparse[T]) OnesLike() Matrix {
+	return NewInitDense[T](s.rows, s.cols, 1)
+}
+
+// Scalar returns the scalar value.
+// It panics if the matrix does not contain exactly one element.
+func (s *Sparse[T]) Scalar() float.Float {
+	if s.Size() != 1 {
+		panic("mat: expected scalar but the matrix contains more elements")
+	}
+	return float.Interface(s.get(0, 0))
+}
+
+// Zeros sets all the values of the matrix to zero.
+func (s *Sparse[T]) Zeros() {
+	s.indptr = make([]int, s.rows+1)
+	s.indices = s.indices[:0]
+	s.values = s.values[:0]
+}
+
+// Set sets the scalar value from a 1×1 matrix at row r and column c.
+// It panics if the given matrix is not 1×1, or if indices are out of range.
+func (s *Sparse[T]) Set(r int, c int, m Matrix) {
+	s.set(r, c, float.ValueOf[T](m.Scalar()))
+}
+
+// At returns the value at row r and column c as a 1×1 matrix.
+// It panics if the given indices are out of range.
+func (s *Sparse[T]) At(r int, c int) Matrix {
+	return NewScalar(s.get(r, c))
+}
+
+// SetScalar sets the value v at row r and column c.
+// It panics if the given indices are out of range.
+func (s *Sparse[T]) SetScalar(r int, c int, v float.Float) {
+	s.set(r, c, float.ValueOf[T](v))
+}
+
+// ScalarAt returns the value at row r and column c.
+// It panics if the given indices are out of range.
+func (s *Sparse[T]) ScalarAt(r int, c int) float.Float {
+	return float.Interface(s.get(r, c))
+}
+
+// SetVec sets the scalar value from a 1×1 matrix at position i of a
+// vector. It panics if the receiver is not a vector, or the given matrix is
+// not 1×1, or the position is out of range.
+func (s *Sparse[T]) SetVec(i int, m Matrix) {
+	r, c := s.vecIndices(i)
+	s.set(r, c, float.ValueOf[T](m.Scalar()))
+}
+
+// AtVec returns the value at position i of a vector as a 1×1 matrix.
+// It panics if the receiver is not a vector or the position is out of range.
+func (s *Sparse[T]) AtVec(i int) Matrix {
+	return NewScalar(s.get(s.vecIndices(i)))
+}
+
+// SetVecScalar sets the value v at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (s *Sparse[T]) SetVecScalar(i int, v float.Float) {
+	r, c := s.vecIndices(i)
+	s.set(r, c, float.ValueOf[T](v))
+}
+
+// ScalarAtVec returns the value at position i of a vector.
+// It panics if the receiver is not a vector or the position is out of range.
+func (s *Sparse[T]) ScalarAtVec(i int) float.Float {
+	return float.Interface(s.get(s.vecIndices(i)))
+}
+
+func (s *Sparse[T]) vecIndices(i int) (r, c int) {
+	if !IsVector(s) {
+		panic("mat: expected vector")
+	}
+	if i < 0 || i >= s.Size() {
+		panic("mat: 'i' argument out of range")
+	}
+	if s.cols == 1 {
+		return i, 0
+	}
+	return 0, i
+}
+
+// ExtractRow returns a copy of the i-th row of the matrix,
+// as a sparse row vector (1×cols).
+func (s *Sparse[T]) ExtractRow(i int) Matrix {
+	return s.Slice(i, 0, i+1, s.cols)
+}
+
+// ExtractColumn returns a copy of the i-th column of the matrix,
+// as a sparse column vector (rows×1).
+func (s *Sparse[T]) ExtractColumn(i int) Matrix {
+	return s.Slice(0, i, s.rows, i+1)
+}
+
+// View returns a copy of the matrix with the given dimensions, since the
+// compressed representation cannot be shared.
+func (s *Sparse[T]) View(rows, cols int) Matrix {
+	return s.Reshape(rows, cols)
+}
+
+// Slice returns a new sparse matrix obtained by slicing the receiver across
+// the given positions. The parameters "fromRow" and "fromCol" are inclusive,
+// while "toRow" and "toCol" are exclusive.
+func (s *Sparse[T]) Slice(fromRow, fromCol, toRow, toCol int) Matrix {
+	if fromRow < 0 || fromCol < 0 {
+		panic("mat: starting row/column indices must not be negative")
+	}
+	if toRow < fromRow || toCol < fromCol {
+		panic("mat: ending row/column indices must be greater or equal to starting indices")
+	}
+	if toRow > s.rows || toCol > s.cols {
+		panic("mat: ending row/column indices must not be greater than the matrix dimensions")
+	}
+	out := NewEmptySparse[T](toRow-fromRow, toCol-fromCol)
+	for r := fromRow; r < toRow; r++ {
+		for p := s.indptr[r]; p < s.indptr[r+1]; p++ {
+			if c := s.indices[p]; c >= fromCol && c < toCol {
+				out.indices = append(out.indices, c-fromCol)
+				out.values = append(out.values, s.values[p])
+			}
+		}
+		out.indptr[r-fromRow+1] = len(out.values)
+	}
+	return out
+}
+
+// Reshape returns a copy of the matrix with the given dimensions.
+// It panics if the dimensions are incompatible.
+func (s *Sparse[T]) Reshape(rows, cols int) Matrix {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if rows*cols != s.Size() {
+		panic(fmt.Sprintf("mat: wrong matrix dimensions. Size (rows*cols) must be: %d", s.Size()))
+	}
+	out := NewEmptySparse[T](rows, cols)
+	out.indices = make([]int, 0, len(s.values))
+	out.values = make([]T, 0, len(s.values))
+	s.doNonZero(func(r, c int, v T) {
+		i := r*s.cols + c
+		out.indices = append(out.indices, i%cols)
+		out.values = append(out.values, v)
+		out.indptr[i/cols+1]++
+	})
+	for r := 0; r < rows; r++ {
+		out.indptr[r+1] += out.indptr[r]
+	}
+	return out
+}
+
+// ReshapeInPlace changes the dimensions of the matrix in place and returns the
+// matrix itself.
+// It panics if the dimensions are incompatible.
+func (s *Sparse[T]) ReshapeInPlace(rows, cols int) Matrix {
+	*s = *s.Reshape(rows, cols).(*Sparse[T])
+	return s
+}
+
+// Flatten creates a new sparse row vector (1×size) corresponding to the
+// "flattened" row-major ordered representation of the initial matrix.
+func (s *Sparse[T]) Flatten() Matrix {
+	return s.Reshape(1, s.Size())
+}
+
+// FlattenInPlace transforms the matrix in place, changing its dimensions,
+// obtaining a row vector (1×size) containing the "flattened" row-major
+// ordered representation of the initial value.
+// It returns the matrix itself.
+func (s *Sparse[T]) FlattenInPlace() Matrix {
+	return s.ReshapeInPlace(1, s.Size())
+}
+
+// ResizeVector returns a resized copy of the vector.
+//
+// If the new size is smaller than the input vector, the remaining tail
+// elements are removed. If it's bigger, the additional tail elements
+// are set to zero.
+func (s *Sparse[T]) ResizeVector(newSize int) Matrix {
+	if !IsVector(s) {
+		panic("mat: expected vector")
+	}
+	if newSize < 0 {
+		panic("mat: a negative size is not allowed")
+	}
+	out := NewEmptySparse[T](newSize, 1)
+	for i, p := 0, 0; i < newSize && i < s.Size(); i++ {
+		r, c := s.vecIndices(i)
+		if v := s.get(r, c); v != 0 {
+			out.indices = append(out.indices, 0)
+			out.values = append(out.values, v)
+			p++
+		}
+		out.indptr[i+1] = p
+	}
+	for i := s.Size(); i < newSize; i++ {
+		out.indptr[i+1] = out.indptr[i]
+	}
+	return out
+}
+
+// T returns the transpose of the matrix, as a new sparse matrix.
+func (s *Sparse[T]) T() Matrix {
+	out := NewEmptySparse[T](s.cols, s.rows)
+	out.indices = make([]int, len(s.values))
+	out.values = make([]T, len(s.values))
+	for _, c := range s.indices {
+		out.indptr[c+1]++
+	}
+	for c := 0; c < s.cols; c++ {
+		out.indptr[c+1] += out.indptr[c]
+	}
+	next := append([]int(nil), out.indptr[:s.cols]...)
+	s.doNonZero(func(r, c int, v T) {
+		p := next[c]
+		out.indices[p] = r
+		out.values[p] = v
+		next[c]++
+	})
+	return out
+}
+
+// TransposeInPlace transposes the matrix in place, and returns the
+// matrix itself.
+func (s *Sparse[T]) TransposeInPlace() Matrix {
+	*s = *s.T().(*Sparse[T])
+	return s
+}
+
+// Add returns the addition between the receiver and another matrix.
+// The result is sparse if the other matrix is sparse and has the same
+// dimensions, otherwise it is Dense.
+func (s *Sparse[T]) Add(other Matrix) Matrix {
+	if o, ok := other.(*Sparse[T]); ok && SameDims(s, o) {
+		return s.merge(o, addOp[T])
+	}
+	return s.dense().Add(other)
+}
+
+// AddInPlace performs the in-place addition with the other matrix.
+func (s *Sparse[T]) AddInPlace(other Matrix) Matrix {
+	if o, ok := other.(*Sparse[T]); ok && SameDims(s, o) {
+		*s = *s.merge(o, addOp[T])
+		return s
+	}
+	s.inPlace(func(d *Dense[T]) { d.AddInPlace(other) })
+	return s
+}
+
+// AddScalar performs the addition between the matrix and the given value,
+// returning a new Dense matrix.
+func (s *Sparse[T]) AddScalar(n float64) Matrix {
+	return s.dense().AddScalar(n)
+}
+
+// AddScalarInPlace adds the scalar to all values of the matrix.
+func (s *Sparse[T]) AddScalarInPlace(n float64) Matrix {
+	s.inPlace(func(d *Dense[T]) { d.AddScalarInPlace(n) })
+	return s
+}
+
+// Sub returns the subtraction of the other matrix from the receiver.
+// The result is sparse if the other matrix is sparse and has the same
+// dimensions, otherwise it is Dense.
+func (s *Sparse[T]) Sub(other Matrix) Matrix {
+	if o, ok := other.(*Sparse[T]); ok && SameDims(s, o) {
+		return s.merge(o, subOp[T])
+	}
+	return s.dense().Sub(other)
+}
+
+// SubInPlace performs the in-place subtraction with the other matrix.
+func (s *Sparse[T]) SubInPlace(other Matrix) Matrix {
+	if o, ok := other.(*Sparse[T]); ok && SameDims(s, o) {
+		*s = *s.merge(o, subOp[T])
+		return s
+	}
+	s.inPlace(func(d *Dense[T]) { d.SubInPlace(other) })
+	return s
+}
+
+// SubScalar performs a subtraction between the matrix and the given value,
+// returning a new Dense matrix.
+func (s *Sparse[T]) SubScalar(n float64) Matrix {
+	return s.dense().SubScalar(n)
+}
+
+// SubScalarInPlace subtracts the scalar from the receiver's values.
+func (s *Sparse[T]) SubScalarInPlace(n float64) Matrix {
+	s.inPlace(func(d *Dense[T]) { d.SubScalarInPlace(n) })
+	return s
+}
+
+// merge combines the values of two sparse matrices with the same
+// dimensions, applying op to each pair of values (missing values are zero).
+func (s *Sparse[T]) merge(o *Sparse[T], op func(x, y T) T) *Sparse[T] {
+	out := NewEmptySparse[T](s.rows, s.cols)
+	for r := 0; r < s.rows; r++ {
+		p, pEnd := s.indptr[r], s.indptr[r+1]
+		q, qEnd := o.indptr[r], o.indptr[r+1]
+		for p < pEnd || q < qEnd {
+			var c int
+			var v T
+			switch {
+			case q == qEnd || (p < pEnd && s.indices[p] < o.indices[q]):
+				c, v = s.indices[p], op(s.values[p], 0)
+				p++
+			case p == pEnd || o.indices[q] < s.indices[p]:
+				c, v = o.indices[q], op(0, o.values[q])
+				q++
+			default:
+				c, v = s.indices[p], op(s.values[p], o.values[q])
+				p++
+				q++
+			}
+			if v != 0 {
+				out.indices = append(out.indices, c)
+				out.values = append(out.values, v)
+			}
+		}
+		out.indptr[r+1] = len(out.values)
+	}
+	return out
+}
+
+// Prod performs the element-wise product between the receiver and the
+// other matrix. The result is sparse if the matrices have the same
+// dimensions, otherwise it is Dense.
+func (s *Sparse[T]) Prod(other Matrix) Matrix {
+	if !SameDims(s, other) {
+		return s.dense().Prod(other)
+	}
+	out := s.Clone().(*Sparse[T])
+	out.prodInPlace(other)
+	return out
+}
+
+// ProdInPlace performs the in-place element-wise product with the other matrix.
+func (s *Sparse[T]) ProdInPlace(other Matrix) Matrix {
+	if !SameDims(s, other) {
+		s.inPlace(func(d *Dense[T]) { d.ProdInPlace(other) })
+		return s
+	}
+	s.prodInPlace(other)
+	return s
+}
+
+func (s *Sparse[T]) prodInPlace(other Matrix) {
+	if o, ok := other.(*Sparse[T]); ok {
+		for r := 0; r < s.rows; r++ {
+			for p := s.indptr[r]; p < s.indptr[r+1]; p++ {
+				s.values[p] *= o.get(r, s.indices[p])
+			}
+		}
+	} else {
+		oData := Data[T](other)
+		for r := 0; r < s.rows; r++ {
+			for p := s.indptr[r]; p < s.indptr[r+1]; p++ {
+				s.values[p] *= oData[r*s.cols+s.indices[p]]
+			}
+		}
+	}
+	s.prune()
+}
+
+// ProdScalar returns the multiplication between the matrix and the given
+// value, as a new sparse matrix.
+func (s *Sparse[T]) ProdScalar(n float64) Matrix {
+	return s.withValues(func(v T) T { return v * T(n) })
+}
+
+// ProdScalarInPlace performs the in-place multiplication between the
+// matrix and the given value.
+func (s *Sparse[T]) ProdScalarInPlace(n float64) Matrix {
+	for i := range s.values {
+		s.values[i] *= T(n)
+	}
+	s.prune()
+	return s
+}
+
+// ProdMatrixScalarInPlace multiplies the given matrix with the value,
+// storing the result in the receiver.
+func (s *Sparse[T]) ProdMatrixScalarInPlace(m Matrix, n float64) Matrix {
+	if !SameDims(s, m) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	s.Copy(m)
+	return s.ProdScalarInPlace(n)
+}
+
+// Div returns the result of the element-wise division of the receiver by
+// the other matrix, as a new Dense matrix.
+func (s *Sparse[T]) Div(other Matrix) Matrix {
+	return s.dense().Div(other)
+}
+
+// DivInPlace performs the in-place element-wise division of the receiver by the other matrix.
+func (s *Sparse[T]) DivInPlace(other Matrix) Matrix {
+	s.inPlace(func(d *Dense[T]) { d.DivInPlace(other) })
+	return s
+}
+
+// Mul performs the multiplication row by column.
+// If A is an i×j Matrix, and B is j×k, then the resulting Matrix
+// C = AB will be i×k.
+//
+// The result is a new Dense matrix. Only the non-zero values of the
+// receiver (and of the other matrix, if it is sparse too) are visited.
+func (s *Sparse[T]) Mul(other Matrix) Matrix {
+	if s.cols != other.Rows() {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	outCols := other.Columns()
+	out := densePool[T]().GetEmpty(s.rows, outCols)
+	if o, ok := other.(*Sparse[T]); ok {
+		s.doNonZero(func(r, k int, v T) {
+			outRow := out.data[r*outCols : (r+1)*outCols]
+			for q := o.indptr[k]; q < o.indptr[k+1]; q++ {
+				outRow[o.indices[q]] += v * o.values[q]
+			}
+		})
+		return out
+	}
+	oData := Data[T](other)
+	s.doNonZero(func(r, k int, v T) {
+		axpy(v, oData[k*outCols:(k+1)*outCols], out.data[r*outCols:(r+1)*outCols])
+	})
+	return out
+}
+
+// MulT performs the matrix multiplication row by column.
+// ATB = C, where AT is the transpose of A
+// if A is an r x c Matrix, and B is j x k, r = j the resulting
+// Matrix C will be c x k.
+//
+// The result is a new Dense matrix.
+func (s *Sparse[T]) MulT(other Matrix) Matrix {
+	if s.rows != other.Rows() {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	outCols := other.Columns()
+	out := densePool[T]().GetEmpty(s.cols, outCols)
+	oData := Data[T](other)
+	s.doNonZero(func(r, c int, v T) {
+		axpy(v, oData[r*outCols:(r+1)*outCols], out.data[c*outCols:(c+1)*outCols])
+	})
+	return out
+}
+
+// denseMul returns the product d×s as a new Dense matrix.
+func (s *Sparse[T]) denseMul(d *Dense[T]) *Dense[T] {
+	out := densePool[T]().GetEmpty(d.rows, s.cols)
+	s.doNonZero(func(k, c int, v T) {
+		for i, oi := k, c; i < len(d.data); i, oi = i+d.cols, oi+s.cols {
+			out.data[oi] += d.data[i] * v
+		}
+	})
+	return out
+}
+
+// denseMulT returns the product dᵀ×s as a new Dense matrix.
+func (s *Sparse[T]) denseMulT(d *Dense[T]) *Dense[T] {
+	out := densePool[T]().GetEmpty(d.cols, s.cols)
+	s.doNonZero(func(r, c int, v T) {
+		dRow := d.data[r*d.cols : (r+1)*d.cols]
+		for i, dv := range dRow {
+			out.data[i*s.cols+c] += dv * v
+		}
+	})
+	return out
+}
+
+// axpy computes y += alpha * x.
+func axpy[T float.DType](alpha T, x, y []T) {
+	if len(x) == 0 {
+		return
+	}
+	_ = y[len(x)-1]
+	for i, v := range x {
+		y[i] += alpha * v
+	}
+}
+
+// DotUnitary returns the dot product of two vectors as a scalar Matrix.
+func (s *Sparse[T]) DotUnitary(other Matrix) Matrix {
+	if !SameDims(s, other) {
+		panic("mat: matrices have incompatible dimensions")
+	}
+	oData := Data[T](other)
+	var sum T
+	s.doNonZero(func(r, c int, v T) {
+		sum += v * oData[r*s.cols+c]
+	})
+	return NewScalar(sum)
+}
+
+// ClipInPlace clips in place each value of the matrix.
+func (s *Sparse[T]) ClipInPlace(min, max float64) Matrix {
+	s.inPlace(func(d *Dense[T]) { d.ClipInPlace(min, max) })
+	return s
+}
+
+// Maximum returns a new Dense matrix containing the element-wise maxima.
+func (s *Sparse[T]) Maximum(other Matrix) Matrix {
+	return s.dense().Maximum(other)
+}
+
+// Minimum returns a new Dense matrix containing the element-wise minima.
+func (s *Sparse[T]) Minimum(other Matrix) Matrix {
+	return s.dense().Minimum(other)
+}
+
+// Abs returns a new sparse matrix applying the absolute value function to
+// all elements.
+func (s *Sparse[T]) Abs() Matrix {
+	return s.withValues(func(v T) T { return T(math.Abs(float64(v))) })
+}
+
+// Pow returns a new Dense matrix, applying the power function with given
+// exponent to all elements of the matrix.
+func (s *Sparse[T]) Pow(power float64) Matrix {
+	return s.dense().Pow(power)
+}
+
+// Sqrt returns a new sparse matrix applying the square root function to
+// all elements.
+func (s *Sparse[T]) Sqrt() Matrix {
+	return s.withValues(func(v T) T { return T(math.Sqrt(float64(v))) })
+}
+
+// Log returns a new Dense matrix applying the natural logarithm function to
+// each element.
+func (s *Sparse[T]) Log() Matrix {
+	return s.dense().Log()
+}
+
+// Exp returns a new Dense matrix applying the base-e exponential function
+// to each element.
+func (s *Sparse[T]) Exp() Matrix {
+	return s.dense().Exp()
+}
+
+// Sigmoid returns a new Dense matrix applying the sigmoid function to each
+// element.
+func (s *Sparse[T]) Sigmoid() Matrix {
+	return s.dense().Sigmoid()
+}
+
+// Sum returns the sum of all values of the matrix as a scalar Matrix.
+func (s *Sparse[T]) Sum() Matrix {
+	var sum T
+	for _, v := range s.values {
+		sum += v
+	}
+	return NewScalar(sum)
+}
+
+// Max returns the maximum value of the matrix as a scalar Matrix.
+func (s *Sparse[T]) Max() Matrix {
+	return s.dense().Max()
+}
+
+// Min returns the minimum value of the matrix as a scalar Matrix.
+func (s *Sparse[T]) Min() Matrix {
+	return s.dense().Min()
+}
+
+// ArgMax returns the index of the vector's element with the maximum value.
+func (s *Sparse[T]) ArgMax() int {
+	return s.dense().ArgMax()
+}
+
+// Softmax applies the softmax function to the vector, returning the
+// result as a new Dense column vector.
+func (s *Sparse[T]) Softmax() Matrix {
+	return s.dense().Softmax()
+}
+
+// CumSum computes the cumulative sum of the vector's elements, returning
+// the result as a new Dense column vector.
+func (s *Sparse[T]) CumSum() Matrix {
+	return s.dense().CumSum()
+}
+
+// Range creates a new Dense vector initialized with data extracted from the
+// matrix values, from start (inclusive) to end (exclusive).
+func (s *Sparse[T]) Range(start, end int) Matrix {
+	return s.dense().Range(start, end)
+}
+
+// SplitV splits the vector in N Dense chunks of given sizes,
+// so that N[i] has size sizes[i].
+func (s *Sparse[T]) SplitV(sizes ...int) []Matrix {
+	return s.dense().SplitV(sizes...)
+}
+
+// Augment places the identity matrix at the end of the original matrix,
+// returning a new Dense matrix.
+func (s *Sparse[T]) Augment() Matrix {
+	return s.dense().Augment()
+}
+
+// SwapInPlace swaps two rows of the matrix in place.
+func (s *Sparse[T]) SwapInPlace(r1, r2 int) Matrix {
+	s.inPlace(func(d *Dense[T]) { d.SwapInPlace(r1, r2) })
+	return s
+}
+
+// PadRows returns a sparse copy of the matrix with n additional tail rows.
+// The additional elements are set to zero.
+func (s *Sparse[T]) PadRows(n int) Matrix {
+	if n < 0 {
+		panic("mat: a negative number of rows is not allowed")
+	}
+	out := s.Clone().(*Sparse[T])
+	for i := 0; i < n; i++ {
+		out.indptr = append(out.indptr, len(out.values))
+	}
+	out.rows += n
+	return out
+}
+
+// PadColumns returns a sparse copy of the matrix with n additional tail
+// columns. The additional elements are set to zero.
+func (s *Sparse[T]) PadColumns(n int) Matrix {
+	if n < 0 {
+		panic("mat: a negative number of columns is not allowed")
+	}
+	out := s.Clone().(*Sparse[T])
+	out.cols += n
+	return out
+}
+
+// AppendRows returns a Dense copy of the matrix with len(vs) additional
+// tail rows, being each new row filled with the values of each given vector.
+//
+// It accepts row or column vectors indifferently, virtually treating all of
+// them as row vectors.
+func (s *Sparse[T]) AppendRows(vs ...Matrix) Matrix {
+	return s.dense().AppendRows(vs...)
+}
+
+// Norm returns the vector's norm. Use pow = 2.0 to compute the Euclidean norm.
+// The result is a scalar Matrix.
+func (s *Sparse[T]) Norm(pow float64) Matrix {
+	if !IsVector(s) {
+		panic("mat: expected vector")
+	}
+	var sum float64
+	for _, v := range s.values {
+		sum += math.Pow(float64(v), pow)
+	}
+	return NewScalar(T(math.Pow(sum, 1/pow)))
+}
+
+// Pivoting returns the partial pivots of a square matrix to reorder rows.
+// Considerate square sub-matrix from element (offset, offset).
+func (s *Sparse[T]) Pivoting(row int) (Matrix, bool, [2]int) {
+	return s.dense().Pivoting(row)
+}
+
+// Normalize2 normalizes an array with the Euclidean norm, returning a new
+// sparse matrix.
+func (s *Sparse[T]) Normalize2() Matrix {
+	norm2 := s.Norm(2)
+	defer ReleaseMatrix(norm2)
+	n := float.ValueOf[T](norm2.Scalar())
+	if n == 0 {
+		return s.Clone()
+	}
+	return s.withValues(func(v T) T { return v / n })
+}
+
+// LU performs lower–upper (LU) decomposition of a square matrix D such as
+// PLU = D, L is lower diagonal and U is upper diagonal, p are pivots.
+func (s *Sparse[T]) LU() (l, u, p Matrix) {
+	return s.dense().LU()
+}
+
+// Inverse returns the inverse of the Matrix, as a new Dense matrix.
+func (s *Sparse[T]) Inverse() Matrix {
+	return s.dense().Inverse()
+}
+
+// VecForEach calls fn for each element of the vector, zeros included.
+// It panics if the receiver is not a vector.
+func (s *Sparse[T]) VecForEach(fn func(i int, v float64)) {
+	s.dense().VecForEach(fn)
+}
+
+// Apply creates a new Dense matrix executing the unary function fn.
+func (s *Sparse[T]) Apply(fn func(r, c int, v float64) float64) Matrix {
+	return s.dense().Apply(fn)
+}
+
+// ApplyInPlace executes the unary function fn over the matrix a,
+// and stores the result in the receiver, returning the receiver itself.
+func (s *Sparse[T]) ApplyInPlace(fn func(r, c int, v float64) float64, a Matrix) Matrix {
+	s.inPlace(func(d *Dense[T]) { d.ApplyInPlace(fn, a) })
+	return s
+}
+
+// ApplyWithAlpha creates a new Dense matrix executing the unary function
+// fn, taking additional parameters alpha.
+func (s *Sparse[T]) ApplyWithAlpha(fn func(r, c int, v float64, alpha ...float64) float64, alpha ...float64) Matrix {
+	return s.dense().ApplyWithAlpha(fn, alpha...)
+}
+
+// ApplyWithAlphaInPlace executes the unary function fn over the matrix a,
+// taking additional parameters alpha, and stores the result in the
+// receiver, returning the receiver itself.
+func (s *Sparse[T]) ApplyWithAlphaInPlace(fn func(r, c int, v float64, alpha ...float64) float64, a Matrix, alpha ...float64) Matrix {
+	s.inPlace(func(d *Dense[T]) { d.ApplyWithAlphaInPlace(fn, a, alpha...) })
+	return s
+}
+
+// DoNonZero calls a function for each non-zero element of the matrix.
+// The parameters of the function are the element's indices and value.
+// Only the stored values are visited.
+func (s *Sparse[T]) DoNonZero(fn func(r, c int, v float64)) {
+	s.doNonZero(func(r, c int, v T) {
+		fn(r, c, float64(v))
+	})
+}
+
+// DoVecNonZero calls a function for each non-zero element of the vector.
+// The parameters of the function are the element's index and value.
+// Only the stored values are visited.
+func (s *Sparse[T]) DoVecNonZero(fn func(i int, v float64)) {
+	if !IsVector(s) {
+		panic("mat: expected vector")
+	}
+	s.doNonZero(func(r, c int, v T) {
+		fn(r+c, float64(v))
+	})
+}
+
+// Clone returns a new sparse matrix, copying all its values from the receiver.
+func (s *Sparse[T]) Clone() Matrix {
+	return &Sparse[T]{
+		rows:    s.rows,
+		cols:    s.cols,
+		indptr:  append([]int(nil), s.indptr...),
+		indices: append([]int(nil), s.indices...),
+		values:  append([]T(nil), s.values...),
+	}
+}
+
+// Copy copies the data from the other matrix to the receiver.
+// It panics if the matrices have different dimensions.
+func (s *Sparse[T]) Copy(other Matrix) {
+	if !SameDims(s, other) {
+		panic("mat: incompatible matrix dimensions")
+	}
+	if o, ok := other.(*Sparse[T]); ok {
+		*s = *o.Clone().(*Sparse[T])
+		return
+	}
+	s.setFromData(Data[T](other))
+}
+
+// String returns a string representation of the matrix.
+func (s *Sparse[T]) String() string {
+	return fmt.Sprintf("Matrix|Sparse[%T](%d×%d, nnz=%d)%v", T(0), s.rows, s.cols, len(s.values), s.dense().data)
+}
+
+// NewMatrix creates a new Dense matrix, of the same data type of the
+// receiver, of size rows×cols, initialized with a copy of raw data.
+func (s *Sparse[T]) NewMatrix(rows, cols int, data float.Slice) Matrix {
+	return NewDense[T](rows, cols, float.SliceValueOf[T](data))
+}
+
+// NewVec creates a new Dense column vector (len(data)×1), of the same data
+// type of the receiver, initialized with a copy of raw data.
+func (s *Sparse[T]) NewVec(data float.Slice) Matrix {
+	return NewVecDense[T](float.SliceValueOf[T](data))
+}
+
+// NewScalar creates a new 1×1 matrix, of the same data type of the receiver,
+// containing the given value.
+func (s *Sparse[T]) NewScalar(v float64) Matrix {
+	return NewScalar(T(v))
+}
+
+// NewEmptyVec creates a new Dense vector, of the same data type of the
+// receiver, with dimensions size×1, initialized with zeros.
+func (s *Sparse[T]) NewEmptyVec(size int) Matrix {
+	return NewEmptyVecDense[T](size)
+}
+
+// NewEmptyMatrix creates a new rows×cols Dense matrix, of the same data
+// type of the receiver, initialized with zeros.
+func (s *Sparse[T]) NewEmptyMatrix(rows, cols int) Matrix {
+	return NewEmptyDense[T](rows, cols)
+}
+
+// NewInitMatrix creates a new rows×cols dense matrix, of the same data type
+// of the receiver, initialized with a constant value.
+func (s *Sparse[T]) NewInitMatrix(rows, cols int, v float64) Matrix {
+	return NewInitDense(rows, cols, T(v))
+}
+
+// NewInitFuncMatrix creates a new rows×cols dense matrix, of the same data
+// type of the receiver, initialized with the values returned from the
+// callback function.
+func (s *Sparse[T]) NewInitFuncMatrix(rows, cols int, fn func(r, c int) float64) Matrix {
+	return NewInitFuncDense(rows, cols, func(r, c int) T {
+		return T(fn(r, c))
+	})
+}
+
+// NewInitVec creates a new Dense column vector (size×1), of the same data
+// type of the receiver, initialized with a constant value.
+func (s *Sparse[T]) NewInitVec(size int, v float64) Matrix {
+	return NewInitVecDense(size, T(v))
+}
+
+// NewIdentityMatrix creates a new square Dense identity matrix (size×size),
+// of the same data type of the receiver.
+func (s *Sparse[T]) NewIdentityMatrix(size int) Matrix {
+	return NewIdentityDense[T](size)
+}
+
+// NewOneHotVec creates a new sparse one-hot column vector (size×1), of the
+// same data type of the receiver.
+func (s *Sparse[T]) NewOneHotVec(size int, oneAt int) Matrix {
+	return NewOneHotVecSparse[T](size, oneAt)
+}
+
+// NewConcatV creates a new Dense column vector, of the same data type of
+// the receiver, concatenating two or more vectors "vertically".
+func (s *Sparse[T]) NewConcatV(vs ...Matrix) Matrix {
+	return ConcatV[T](vs...)
+}
+
+// NewStack creates a new Dense matrix, of the same data type of the
+// receiver, stacking two or more vectors of the same size on top of each
+// other.
+func (s *Sparse[T]) NewStack(vs ...Matrix) Matrix {
+	return Stack[T](vs...)
+}
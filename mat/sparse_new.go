@@ -0,0 +1,150 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// NewSparse returns a new rows×cols sparse matrix, built from a list of
+// values in coordinate (COO) format: the k-th value is placed at row
+// rowIndices[k] and column colIndices[k].
+//
+// Duplicate coordinates are summed together, and zero values are not stored.
+// It panics if the slices have different lengths, or if any index is out
+// of range.
+func NewSparse[T float.DType](rows, cols int, rowIndices, colIndices []int, values []T) *Sparse[T] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if len(rowIndices) != len(values) || len(colIndices) != len(values) {
+		panic("mat: COO indices and values must have the same length")
+	}
+
+	order := make([]int, len(values))
+	for k := range order {
+		r, c := rowIndices[k], colIndices[k]
+		if r < 0 || r >= rows {
+			panic("mat: 'r' argument out of range")
+		}
+		if c < 0 || c >= cols {
+			panic("mat: 'c' argument out of range")
+		}
+		order[k] = k
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if rowIndices[a] != rowIndices[b] {
+			return rowIndices[a] < rowIndices[b]
+		}
+		return colIndices[a] < colIndices[b]
+	})
+
+	s := NewEmptySparse[T](rows, cols)
+	last := -1
+	for _, k := range order {
+		r, c := rowIndices[k], colIndices[k]
+		if last >= 0 && rowIndices[last] == r && colIndices[last] == c {
+			s.values[len(s.values)-1] += values[k]
+			continue
+		}
+		s.indices = append(s.indices, c)
+		s.values = append(s.values, values[k])
+		s.indptr[r+1]++
+		last = k
+	}
+	for r := 0; r < rows; r++ {
+		s.indptr[r+1] += s.indptr[r]
+	}
+	s.prune()
+	return s
+}
+
+// NewSparseCSR returns a new rows×cols sparse matrix, initialized with a
+// copy of the given compressed sparse row (CSR) representation: the column
+// indices and values of the i-th row are stored in indices and values, from
+// position indptr[i] (inclusive) to indptr[i+1] (exclusive). Column indices
+// must be sorted within each row.
+//
+// It panics if the representation is not valid.
+func NewSparseCSR[T float.DType](rows, cols int, indptr, indices []int, values []T) *Sparse[T] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	if len(indptr) != rows+1 || indptr[0] != 0 || indptr[rows] != len(indices) || len(indices) != len(values) {
+		panic("mat: invalid CSR representation")
+	}
+	for r := 0; r < rows; r++ {
+		if indptr[r] > indptr[r+1] {
+			panic("mat: invalid CSR representation")
+		}
+		for p := indptr[r]; p < indptr[r+1]; p++ {
+			if c := indices[p]; c < 0 || c >= cols || (p > indptr[r] && c <= indices[p-1]) {
+				panic(fmt.Sprintf("mat: invalid CSR column index at position %d", p))
+			}
+		}
+	}
+	s := &Sparse[T]{
+		rows:    rows,
+		cols:    cols,
+		indptr:  append([]int(nil), indptr...),
+		indices: append([]int(nil), indices...),
+		values:  append([]T(nil), values...),
+	}
+	s.prune()
+	return s
+}
+
+// NewEmptySparse returns a new rows×cols sparse matrix, with no
+// non-zero values.
+func NewEmptySparse[T float.DType](rows, cols int) *Sparse[T] {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative values for rows and cols are not allowed")
+	}
+	return &Sparse[T]{
+		rows:   rows,
+		cols:   cols,
+		indptr: make([]int, rows+1),
+	}
+}
+
+// NewVecSparse returns a new sparse column vector (len(data)×1),
+// storing the non-zero values of data.
+func NewVecSparse[T float.DType](data []T) *Sparse[T] {
+	s := NewEmptySparse[T](len(data), 1)
+	s.setFromData(data)
+	return s
+}
+
+// NewOneHotVecSparse returns a new sparse one-hot column vector (size×1).
+func NewOneHotVecSparse[T float.DType](size int, oneAt int) *Sparse[T] {
+	if size <= 0 {
+		panic("mat: the vector size must be a positive number")
+	}
+	if oneAt < 0 || oneAt >= size {
+		panic(fmt.Sprintf("mat: impossible to set the one at index %d. The size is: %d", oneAt, size))
+	}
+	s := NewEmptySparse[T](size, 1)
+	s.indices = []int{0}
+	s.values = []T{1}
+	for r := oneAt + 1; r <= size; r++ {
+		s.indptr[r] = 1
+	}
+	return s
+}
+
+// NewSparseFromMatrix returns a new sparse matrix storing the non-zero
+// values of m.
+func NewSparseFromMatrix[T float.DType](m Matrix) *Sparse[T] {
+	if s, ok := m.(*Sparse[T]); ok {
+		return s.Clone().(*Sparse[T])
+	}
+	s := NewEmptySparse[T](m.Rows(), m.Columns())
+	s.setFromData(Data[T](m))
+	return s
+}
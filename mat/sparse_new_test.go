@@ -0,0 +1,110 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSparse(t *testing.T) {
+	t.Run("float32", testNewSparse[float32])
+	t.Run("float64", testNewSparse[float64])
+}
+
+func testNewSparse[T float.DType](t *testing.T) {
+	t.Run("sorts, sums duplicates and drops zeros", func(t *testing.T) {
+		s := NewSparse[T](3, 4,
+			[]int{2, 0, 2, 1, 0},
+			[]int{1, 3, 1, 0, 0},
+			[]T{1, 2, 3, 0, 4},
+		)
+		assert.Equal(t, 3, s.Rows())
+		assert.Equal(t, 4, s.Columns())
+		assert.Equal(t, 3, s.NNZ())
+
+		indptr, indices, values := s.CSR()
+		assert.Equal(t, []int{0, 2, 2, 3}, indptr)
+		assert.Equal(t, []int{0, 3, 1}, indices)
+		assert.Equal(t, []T{4, 2, 4}, values)
+
+		rows, cols, values := s.COO()
+		assert.Equal(t, []int{0, 0, 2}, rows)
+		assert.Equal(t, []int{0, 3, 1}, cols)
+		assert.Equal(t, []T{4, 2, 4}, values)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		require.Panics(t, func() { NewSparse[T](-1, 2, nil, nil, nil) })
+		require.Panics(t, func() { NewSparse[T](2, 2, []int{0}, []int{0, 1}, []T{1}) })
+		require.Panics(t, func() { NewSparse[T](2, 2, []int{2}, []int{0}, []T{1}) })
+		require.Panics(t, func() { NewSparse[T](2, 2, []int{0}, []int{2}, []T{1}) })
+	})
+}
+
+func TestNewSparseCSR(t *testing.T) {
+	t.Run("float32", testNewSparseCSR[float32])
+	t.Run("float64", testNewSparseCSR[float64])
+}
+
+func testNewSparseCSR[T float.DType](t *testing.T) {
+	s := NewSparseCSR[T](2, 3, []int{0, 1, 3}, []int{2, 0, 1}, []T{1, 2, 3})
+	assert.Equal(t, []T{
+		0, 0, 1,
+		2, 3, 0,
+	}, Data[T](s))
+
+	require.Panics(t, func() {
+		NewSparseCSR[T](2, 3, []int{0, 1}, []int{2}, []T{1})
+	})
+	require.Panics(t, func() {
+		NewSparseCSR[T](2, 3, []int{0, 2, 2}, []int{1, 0}, []T{1, 2})
+	})
+	require.Panics(t, func() {
+		NewSparseCSR[T](2, 3, []int{0, 1, 1}, []int{3}, []T{1})
+	})
+}
+
+func TestNewOneHotVecSparse(t *testing.T) {
+	t.Run("float32", testNewOneHotVecSparse[float32])
+	t.Run("float64", testNewOneHotVecSparse[float64])
+}
+
+func testNewOneHotVecSparse[T float.DType](t *testing.T) {
+	s := NewOneHotVecSparse[T](5, 2)
+	assert.Equal(t, 5, s.Rows())
+	assert.Equal(t, 1, s.Columns())
+	assert.Equal(t, 1, s.NNZ())
+	assert.Equal(t, []T{0, 0, 1, 0, 0}, Data[T](s))
+
+	require.Panics(t, func() { NewOneHotVecSparse[T](0, 0) })
+	require.Panics(t, func() { NewOneHotVecSparse[T](3, 3) })
+}
+
+func TestNewSparseFromMatrix(t *testing.T) {
+	t.Run("float32", testNewSparseFromMatrix[float32])
+	t.Run("float64", testNewSparseFromMatrix[float64])
+}
+
+func testNewSparseFromMatrix[T float.DType](t *testing.T) {
+	d := NewDense[T](2, 3, []T{
+		0, 1, 0,
+		2, 0, 3,
+	})
+	s := NewSparseFromMatrix[T](d)
+	assert.Equal(t, 3, s.NNZ())
+	assert.Equal(t, Data[T](d), Data[T](s))
+
+	v := NewVecSparse([]T{0, 5, 0, 6})
+	assert.Equal(t, 4, v.Rows())
+	assert.Equal(t, 2, v.NNZ())
+
+	c := NewSparseFromMatrix[T](v)
+	assert.NotSame(t, v, c)
+	assert.Equal(t, Data[T](v), Data[T](c))
+}
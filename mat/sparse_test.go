@@ -0,0 +1,308 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ Matrix = &Sparse[float32]{}
+var _ Matrix = &Sparse[float64]{}
+
+func newTestSparse[T float.DType]() *Sparse[T] {
+	return NewSparseFromMatrix[T](NewDense[T](3, 4, []T{
+		1, 0, 0, 2,
+		0, 0, 0, 0,
+		0, 3, 4, 0,
+	}))
+}
+
+func TestSparse_SetAndGet(t *testing.T) {
+	t.Run("float32", testSparseSetAndGet[float32])
+	t.Run("float64", testSparseSetAndGet[float64])
+}
+
+func testSparseSetAndGet[T float.DType](t *testing.T) {
+	s := newTestSparse[T]()
+	assert.Equal(t, T(2), float.ValueOf[T](s.ScalarAt(0, 3)))
+	assert.Equal(t, T(0), float.ValueOf[T](s.ScalarAt(1, 1)))
+
+	s.SetScalar(1, 1, float.Interface(T(5))) // insert
+	s.SetScalar(0, 3, float.Interface(T(0))) // remove
+	s.Set(2, 2, NewScalar[T](6))             // update
+	assert.Equal(t, 4, s.NNZ())
+	assert.Equal(t, []T{
+		1, 0, 0, 0,
+		0, 5, 0, 0,
+		0, 3, 6, 0,
+	}, Data[T](s))
+
+	require.Panics(t, func() { s.ScalarAt(3, 0) })
+	require.Panics(t, func() { s.SetScalar(0, 4, float.Interface(T(1))) })
+
+	v := NewVecSparse([]T{0, 1, 0})
+	v.SetVecScalar(2, float.Interface(T(3)))
+	assert.Equal(t, T(3), float.ValueOf[T](v.ScalarAtVec(2)))
+	assert.Equal(t, []T{0, 1, 3}, Data[T](v))
+}
+
+func TestSparse_T(t *testing.T) {
+	t.Run("float32", testSparseT[float32])
+	t.Run("float64", testSparseT[float64])
+}
+
+func testSparseT[T float.DType](t *testing.T) {
+	s := newTestSparse[T]()
+	st := s.T().(*Sparse[T])
+	assert.Equal(t, 4, st.Rows())
+	assert.Equal(t, 3, st.Columns())
+	assert.Equal(t, []T{
+		1, 0, 0,
+		0, 0, 3,
+		0, 0, 4,
+		2, 0, 0,
+	}, Data[T](st))
+
+	s.TransposeInPlace()
+	assert.Equal(t, Data[T](st), Data[T](s))
+}
+
+func TestSparse_Mul(t *testing.T) {
+	t.Run("float32", testSparseMul[float32])
+	t.Run("float64", testSparseMul[float64])
+}
+
+func testSparseMul[T float.DType](t *testing.T) {
+	s := newTestSparse[T]()
+	d := s.dense()
+	b := NewDense[T](4, 2, []T{
+		1, 2,
+		3, 4,
+		5, 6,
+		7, 8,
+	})
+
+	t.Run("sparse × dense", func(t *testing.T) {
+		y := s.Mul(b)
+		assertDenseDims(t, 3, 2, y.(*Dense[T]))
+		assert.Equal(t, Data[T](d.Mul(b)), Data[T](y))
+	})
+
+	t.Run("sparse × sparse", func(t *testing.T) {
+		y := s.Mul(NewSparseFromMatrix[T](b))
+		assertDenseDims(t, 3, 2, y.(*Dense[T]))
+		assert.Equal(t, Data[T](d.Mul(b)), Data[T](y))
+	})
+
+	t.Run("dense × sparse", func(t *testing.T) {
+		a := NewDense[T](2, 3, []T{
+			1, 2, 3,
+			4, 5, 6,
+		})
+		y := a.Mul(s)
+		assertDenseDims(t, 2, 4, y.(*Dense[T]))
+		assert.Equal(t, Data[T](a.Mul(d)), Data[T](y))
+	})
+
+	t.Run("sparse vector", func(t *testing.T) {
+		w := NewDense[T](2, 4, []T{
+			1, 2, 3, 4,
+			5, 6, 7, 8,
+		})
+		x := NewOneHotVecSparse[T](4, 2)
+		y := w.Mul(x)
+		assertDenseDims(t, 2, 1, y.(*Dense[T]))
+		assert.Equal(t, []T{3, 7}, Data[T](y))
+	})
+
+	t.Run("incompatible dimensions", func(t *testing.T) {
+		require.Panics(t, func() { s.Mul(NewEmptyDense[T](3, 1)) })
+		require.Panics(t, func() { NewEmptyDense[T](2, 2).Mul(s) })
+	})
+}
+
+func TestSparse_MulT(t *testing.T) {
+	t.Run("float32", testSparseMulT[float32])
+	t.Run("float64", testSparseMulT[float64])
+}
+
+func testSparseMulT[T float.DType](t *testing.T) {
+	s := newTestSparse[T]()
+	d := s.dense()
+
+	t.Run("sparse transposed × dense", func(t *testing.T) {
+		b := NewDense[T](3, 2, []T{
+			1, 2,
+			3, 4,
+			5, 6,
+		})
+		y := s.MulT(b)
+		assertDenseDims(t, 4, 2, y.(*Dense[T]))
+		dt := d.T()
+		assert.Equal(t, Data[T](dt.Mul(b)), Data[T](y))
+	})
+
+	t.Run("dense transposed × sparse", func(t *testing.T) {
+		a := NewDense[T](3, 2, []T{
+			1, 2,
+			3, 4,
+			5, 6,
+		})
+		y := a.MulT(s)
+		assertDenseDims(t, 2, 4, y.(*Dense[T]))
+		at := a.T()
+		assert.Equal(t, Data[T](at.Mul(d)), Data[T](y))
+	})
+}
+
+func TestSparse_ElementWise(t *testing.T) {
+	t.Run("float32", testSparseElementWise[float32])
+	t.Run("float64", testSparseElementWise[float64])
+}
+
+func testSparseElementWise[T float.DType](t *testing.T) {
+	s := newTestSparse[T]()
+	o := NewSparse[T](3, 4, []int{0, 1}, []int{0, 1}, []T{-1, 2})
+
+	t.Run("Add sparse", func(t *testing.T) {
+		y := s.Add(o).(*Sparse[T])
+		assert.Equal(t, 4, y.NNZ())
+		assert.Equal(t, []T{
+			0, 0, 0, 2,
+			0, 2, 0, 0,
+			0, 3, 4, 0,
+		}, Data[T](y))
+	})
+
+	t.Run("Sub dense", func(t *testing.T) {
+		y := s.Sub(NewInitDense[T](3, 4, 1))
+		assertDenseDims(t, 3, 4, y.(*Dense[T]))
+		assert.Equal(t, []T{
+			0, -1, -1, 1,
+			-1, -1, -1, -1,
+			-1, 2, 3, -1,
+		}, Data[T](y))
+	})
+
+	t.Run("Prod", func(t *testing.T) {
+		y := s.Prod(NewInitDense[T](3, 4, 2)).(*Sparse[T])
+		assert.Equal(t, 4, y.NNZ())
+		assert.Equal(t, []T{
+			2, 0, 0, 4,
+			0, 0, 0, 0,
+			0, 6, 8, 0,
+		}, Data[T](y))
+
+		y = s.Prod(o).(*Sparse[T])
+		assert.Equal(t, 1, y.NNZ())
+		assert.Equal(t, T(-1), float.ValueOf[T](y.ScalarAt(0, 0)))
+	})
+
+	t.Run("ProdScalar", func(t *testing.T) {
+		y := s.ProdScalar(0.5).(*Sparse[T])
+		assert.Equal(t, []T{0.5, 1, 1.5, 2}, y.values)
+	})
+
+	t.Run("in place fallback", func(t *testing.T) {
+		x := s.Clone().(*Sparse[T])
+		x.AddScalarInPlace(1)
+		assert.Equal(t, 12, x.NNZ())
+		x.SubScalarInPlace(1)
+		assert.Equal(t, 4, x.NNZ())
+		assert.Equal(t, Data[T](s), Data[T](x))
+	})
+
+	t.Run("reductions", func(t *testing.T) {
+		assert.Equal(t, T(10), float.ValueOf[T](s.Sum().Scalar()))
+		assert.Equal(t, T(4), float.ValueOf[T](s.Max().Scalar()))
+		assert.Equal(t, T(0), float.ValueOf[T](s.Min().Scalar()))
+		assert.InDelta(t, 5.0, NewVecSparse([]T{0, 3, 0, 4}).Norm(2).Scalar().F64(), 1.0e-6)
+	})
+}
+
+func TestSparse_Structure(t *testing.T) {
+	t.Run("float32", testSparseStructure[float32])
+	t.Run("float64", testSparseStructure[float64])
+}
+
+func testSparseStructure[T float.DType](t *testing.T) {
+	s := newTestSparse[T]()
+
+	t.Run("Slice", func(t *testing.T) {
+		y := s.Slice(0, 1, 3, 4).(*Sparse[T])
+		assert.Equal(t, 3, y.Rows())
+		assert.Equal(t, 3, y.Columns())
+		assert.Equal(t, []T{
+			0, 0, 2,
+			0, 0, 0,
+			3, 4, 0,
+		}, Data[T](y))
+
+		assert.Equal(t, []T{0, 3, 4, 0}, Data[T](s.ExtractRow(2)))
+		assert.Equal(t, []T{0, 0, 4}, Data[T](s.ExtractColumn(2)))
+	})
+
+	t.Run("Reshape", func(t *testing.T) {
+		y := s.Reshape(2, 6).(*Sparse[T])
+		assert.Equal(t, 2, y.Rows())
+		assert.Equal(t, 6, y.Columns())
+		assert.Equal(t, Data[T](s), Data[T](y))
+
+		f := s.Flatten()
+		assert.Equal(t, 1, f.Rows())
+		assert.Equal(t, Data[T](s), Data[T](f))
+	})
+
+	t.Run("Pad", func(t *testing.T) {
+		y := s.PadRows(1).PadColumns(2)
+		assert.Equal(t, 4, y.Rows())
+		assert.Equal(t, 6, y.Columns())
+		assert.Equal(t, T(4), float.ValueOf[T](y.ScalarAt(2, 2)))
+		assert.Equal(t, T(0), float.ValueOf[T](y.ScalarAt(3, 5)))
+	})
+
+	t.Run("ResizeVector", func(t *testing.T) {
+		v := NewVecSparse([]T{0, 1, 2})
+		assert.Equal(t, []T{0, 1}, Data[T](v.ResizeVector(2)))
+		assert.Equal(t, []T{0, 1, 2, 0, 0}, Data[T](v.ResizeVector(5)))
+	})
+
+	t.Run("DoNonZero", func(t *testing.T) {
+		var visited [][3]float64
+		s.DoNonZero(func(r, c int, v float64) {
+			visited = append(visited, [3]float64{float64(r), float64(c), v})
+		})
+		assert.Equal(t, [][3]float64{{0, 0, 1}, {0, 3, 2}, {2, 1, 3}, {2, 2, 4}}, visited)
+	})
+
+	t.Run("DoVecNonZero", func(t *testing.T) {
+		var visited [][2]float64
+		NewVecSparse([]T{0, 5, 0, 6}).T().DoVecNonZero(func(i int, v float64) {
+			visited = append(visited, [2]float64{float64(i), v})
+		})
+		assert.Equal(t, [][2]float64{{1, 5}, {3, 6}}, visited)
+
+		require.Panics(t, func() {
+			s.DoVecNonZero(func(int, float64) {})
+		})
+	})
+
+	t.Run("Copy", func(t *testing.T) {
+		y := NewEmptySparse[T](3, 4)
+		y.Copy(s.dense())
+		assert.Equal(t, 4, y.NNZ())
+		assert.Equal(t, Data[T](s), Data[T](y))
+	})
+
+	t.Run("ReleaseMatrix", func(t *testing.T) {
+		require.NotPanics(t, func() {
+			ReleaseMatrix(s.Clone())
+		})
+	})
+}
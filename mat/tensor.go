@@ -0,0 +1,218 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mat
+
+import (
+	"fmt"
+
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// The Tensor interface extends Matrix with N-dimensional shape and stride
+// information, views and axis-wise operations.
+//
+// A Tensor is still a Matrix: all Matrix methods operate on its
+// two-dimensional projection, which is defined as follows, depending on the
+// rank (number of dimensions):
+//   - rank 0: 1×1
+//   - rank 1: N×1 (column vector)
+//   - rank 2: R×C
+//   - rank N: shape[0] × (shape[1] * ... * shape[N-1])
+//
+// The elements of the projection are always visited in row-major order,
+// regardless of the strides of the Tensor.
+type Tensor interface {
+	Matrix
+	// Shape returns a copy of the size of each dimension.
+	Shape() []int
+	// Strides returns a copy of the number of elements to skip in the
+	// underlying data to move one step along each dimension.
+	Strides() []int
+	// Rank returns the number of dimensions.
+	Rank() int
+	// IsContiguous reports whether the elements are laid out in the
+	// underlying data in row-major order, without gaps.
+	IsContiguous() bool
+	// Item returns the value at the given multi-dimensional index.
+	// It panics if the index is out of range.
+	Item(index ...int) float.Float
+	// SetItem sets the value v at the given multi-dimensional index.
+	// It panics if the index is out of range.
+	SetItem(v float.Float, index ...int)
+	// ViewShape returns a Tensor with the given shape, sharing the same
+	// underlying data whenever the receiver is contiguous, or a copy
+	// otherwise. At most one dimension can be -1, in which case its size is
+	// inferred from the total number of elements.
+	ViewShape(shape ...int) Tensor
+	// Permute returns a view of the Tensor with its dimensions reordered
+	// according to the given axes.
+	Permute(axes ...int) Tensor
+	// TransposeAxes returns a view of the Tensor with the two given
+	// dimensions swapped.
+	TransposeAxes(a, b int) Tensor
+	// Narrow returns a view of the Tensor restricted to the indices from
+	// (inclusive) and to (exclusive) along the given axis.
+	Narrow(axis, from, to int) Tensor
+	// Select returns a view of the Tensor at the given index along the given
+	// axis. The resulting Tensor has one dimension less than the receiver.
+	Select(axis, index int) Tensor
+	// Unsqueeze returns a view of the Tensor with a new dimension of size 1
+	// inserted at the given position.
+	Unsqueeze(axis int) Tensor
+	// Squeeze returns a view of the Tensor with the given dimension removed.
+	// It panics if the dimension has not size 1.
+	Squeeze(axis int) Tensor
+	// BroadcastTo returns a view of the Tensor expanded to the given shape.
+	// Dimensions of size 1 can be expanded to any size, and new leading
+	// dimensions can be added; expanded dimensions have stride zero.
+	BroadcastTo(shape ...int) Tensor
+	// Contiguous returns the receiver itself if it is contiguous, otherwise
+	// a contiguous copy of it.
+	Contiguous() Tensor
+	// SumAxis returns a new Tensor containing the sum of the values along
+	// the given axis, which is removed from the resulting shape.
+	SumAxis(axis int) Tensor
+	// MeanAxis returns a new Tensor containing the mean of the values along
+	// the given axis, which is removed from the resulting shape.
+	MeanAxis(axis int) Tensor
+	// MaxAxis returns a new Tensor containing the maximum values along
+	// the given axis, which is removed from the resulting shape.
+	MaxAxis(axis int) Tensor
+	// MinAxis returns a new Tensor containing the minimum values along
+	// the given axis, which is removed from the resulting shape.
+	MinAxis(axis int) Tensor
+}
+
+// AsTensor returns the given matrix as a Tensor.
+//
+// If m already implements Tensor, it is returned as is. If it is a Dense
+// matrix, the result is a rank-2 Tensor sharing the same underlying data.
+// Any other matrix is copied into a new rank-2 Tensor.
+func AsTensor(m Matrix) Tensor {
+	switch mt := m.(type) {
+	case Tensor:
+		return mt
+	case *Dense[float32]:
+		return denseAsTensor(mt)
+	case *Dense[float64]:
+		return denseAsTensor(mt)
+	default:
+		switch m.Data().BitSize() {
+		case 32:
+			return NewDenseTensor([]int{m.Rows(), m.Columns()}, m.Data().F32())
+		default:
+			return NewDenseTensor([]int{m.Rows(), m.Columns()}, m.Data().F64())
+		}
+	}
+}
+
+// Shape returns the shape of the given matrix: if it implements Tensor,
+// the result of Tensor.Shape, otherwise its rows and columns.
+func Shape(m Matrix) []int {
+	if t, ok := m.(Tensor); ok {
+		return t.Shape()
+	}
+	return []int{m.Rows(), m.Columns()}
+}
+
+// SameShape reports whether the two matrices have the same shape.
+func SameShape(a, b Matrix) bool {
+	sa, sb := Shape(a), Shape(b)
+	if len(sa) != len(sb) {
+		return false
+	}
+	for i, v := range sa {
+		if v != sb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// WithShape returns a Tensor containing the same values of m, arranged with
+// the given shape. It panics if the number of elements does not match.
+func WithShape(m Matrix, shape ...int) Tensor {
+	return AsTensor(m).ViewShape(shape...)
+}
+
+// shapeSize returns the number of elements of a tensor with the given shape.
+func shapeSize(shape []int) int {
+	size := 1
+	for _, v := range shape {
+		if v < 0 {
+			panic("mat: negative dimensions are not allowed")
+		}
+		size *= v
+	}
+	return size
+}
+
+// contiguousStrides returns the row-major strides for the given shape.
+func contiguousStrides(shape []int) []int {
+	strides := make([]int, len(shape))
+	stride := 1
+	for i := len(shape) - 1; i >= 0; i-- {
+		strides[i] = stride
+		stride *= shape[i]
+	}
+	return strides
+}
+
+// projectedDims returns the dimensions of the two-dimensional projection
+// of a tensor with the given shape.
+func projectedDims(shape []int) (rows, cols int) {
+	switch len(shape) {
+	case 0:
+		return 1, 1
+	case 1:
+		return shape[0], 1
+	default:
+		return shape[0], shapeSize(shape[1:])
+	}
+}
+
+// normalizeAxis converts a possibly negative axis to a non-negative one,
+// panicking if it is out of range.
+func normalizeAxis(axis, rank int) int {
+	if axis < 0 {
+		axis += rank
+	}
+	if axis < 0 || axis >= rank {
+		panic(fmt.Sprintf("mat: axis %d out of range for rank %d", axis, rank))
+	}
+	return axis
+}
+
+// inferShape resolves an optional -1 dimension from shape, given the total
+// number of elements.
+func inferShape(shape []int, size int) []int {
+	out := append([]int(nil), shape...)
+	unknown := -1
+	known := 1
+	for i, v := range out {
+		switch {
+		case v == -1:
+			if unknown != -1 {
+				panic("mat: only one dimension can be inferred")
+			}
+			unknown = i
+		case v < 0:
+			panic("mat: negative dimensions are not allowed")
+		default:
+			known *= v
+		}
+	}
+	if unknown != -1 {
+		if known == 0 || size%known != 0 {
+			panic(fmt.Sprintf("mat: cannot infer dimension for size %d", size))
+		}
+		out[unknown] = size / known
+		known *= out[unknown]
+	}
+	if known != size {
+		panic(fmt.Sprintf("mat: wrong tensor shape. Size must be: %d", size))
+	}
+	return out
+}
@@ -7,6 +7,7 @@ package nn
 import (
 	"sync"
 
+	"github.com/nlpodyssey/spago/ag"
 	"github.com/nlpodyssey/spago/mat"
 	"github.com/nlpodyssey/spago/mat/float"
 )
@@ -107,6 +108,7 @@ func (p *BaseParam) AccGrad(grad mat.Matrix) {
 	if !p.requiresGrad {
 		return
 	}
+	ag.CheckGrad(grad)
 	p.gradMu.Lock()
 	defer p.gradMu.Unlock()
 	if p.grad == nil {
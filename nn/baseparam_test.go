@@ -0,0 +1,37 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nlpodyssey/spago/ag"
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseParam_AccGrad_AnomalyDetection(t *testing.T) {
+	defer ag.SetAnomalyDetection(ag.SetAnomalyDetection(true))
+
+	p := NewParam(mat.NewVecDense([]float64{4, 0}))
+	y := ag.ReduceSum(ag.Sqrt(p))
+	require.Equal(t, 2.0, y.Value().Scalar().F64())
+
+	err := ag.BackwardContext(context.Background(), y)
+	var ae *ag.AnomalyError
+	require.True(t, errors.As(err, &ae))
+	assert.Equal(t, "Sqrt", ae.Operator)
+	assert.True(t, ae.Backward)
+	assert.Nil(t, p.Grad())
+	ag.ReleaseGraph(y)
+
+	assert.NotPanics(t, func() {
+		p.AccGrad(mat.NewVecDense([]float64{1, 2}))
+	})
+	assert.Equal(t, []float64{1, 2}, p.Grad().Data().F64())
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+)
+
+// ToHalf converts the float32 values of all the parameters of a model,
+// including sub-models, to matrices storing 16-bit values of type H (see
+// mat.HalfDense), returning the number of converted parameters.
+//
+// It halves the memory footprint of the parameters, while the computations
+// are still performed in float32, at the cost of some precision. Since
+// in-place updates round the values again, it's meant for inference-only
+// deployments. Parameters not visited by ForEachParam, such as the ones of
+// an embeddings.Model store, are left unchanged.
+func ToHalf[H float.Half](m Model) int {
+	n := 0
+	ForEachParam(m, func(param Param, _ string, _ ParamsType) {
+		if ToHalfParam[H](param) {
+			n++
+		}
+	})
+	return n
+}
+
+// ToHalfParam replaces the value of the parameter with a matrix storing
+// 16-bit values of type H, reporting whether it has been converted. Only
+// float32 Dense values are converted, since the computations on the result
+// are performed in float32.
+func ToHalfParam[H float.Half](p Param) bool {
+	v, ok := p.Value().(*mat.Dense[float32])
+	if !ok {
+		return false
+	}
+	p.ReplaceValue(mat.NewHalfDenseFromMatrix[H](v))
+	return true
+}
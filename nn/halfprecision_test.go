@@ -0,0 +1,57 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/nlpodyssey/spago/mat/float"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHalf(t *testing.T) {
+	t.Run("Float16", testToHalf[float.Float16])
+	t.Run("BFloat16", testToHalf[float.BFloat16])
+}
+
+func testToHalf[H float.Half](t *testing.T) {
+	type OtherModel struct {
+		Module
+		Baz Param `spago:"type:weights"`
+	}
+
+	type Model struct {
+		Module
+		Foo   Param `spago:"type:weights"`
+		Bar   Param `spago:"type:biases"`
+		Qux   Param
+		Other *OtherModel
+	}
+
+	m := &Model{
+		Foo: NewParam(mat.NewDense[float32](2, 2, []float32{1, -2, 3, -4})),
+		Bar: NewParam(mat.NewVecDense[float32]([]float32{1, 2})),
+		Qux: NewParam(mat.NewScalar[float64](3)),
+		Other: &OtherModel{
+			Baz: NewParam(mat.NewDense[float32](1, 3, []float32{0.1, 0.2, 0.3})),
+		},
+	}
+
+	x := mat.NewVecDense[float32]([]float32{0.5, 0.25})
+	expected := m.Foo.Value().Mul(x).Add(m.Bar.Value())
+
+	assert.Equal(t, 3, ToHalf[H](m))
+	assert.IsType(t, &mat.HalfDense[H]{}, m.Foo.Value())
+	assert.IsType(t, &mat.HalfDense[H]{}, m.Bar.Value())
+	assert.IsType(t, &mat.HalfDense[H]{}, m.Other.Baz.Value())
+	assert.IsType(t, &mat.Dense[float64]{}, m.Qux.Value(), "float64 params must be skipped")
+
+	y := m.Foo.Value().Mul(x).Add(m.Bar.Value())
+	assert.Equal(t, mat.Data[float32](expected), mat.Data[float32](y))
+	assert.InDeltaSlice(t, []float32{0.1, 0.2, 0.3}, mat.Data[float32](m.Other.Baz.Value()), 1.0e-3)
+
+	assert.Equal(t, 0, ToHalf[H](m), "converted params must be skipped")
+}
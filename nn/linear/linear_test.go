@@ -67,6 +67,67 @@ func testModelForward[T float.DType](t *testing.T) {
 	}, model.B.Grad().Data(), 1.0e-05)
 }
 
+func TestModel_ForwardSparse(t *testing.T) {
+	t.Run("float32", testModelForwardSparse[float32])
+	t.Run("float64", testModelForwardSparse[float64])
+}
+
+func testModelForwardSparse[T float.DType](t *testing.T) {
+	data := []T{0, -0.9, 0, 1.0}
+
+	dense := newTestModel[T]()
+	yd := dense.Forward(ag.Var(mat.NewVecDense(data)))[0]
+	ag.Backward(ag.ReduceSum(yd))
+
+	sparse := newTestModel[T]()
+	ys := sparse.Forward(ag.Var(mat.NewVecSparse(data)))[0]
+	ag.Backward(ag.ReduceSum(ys))
+
+	assert.InDeltaSlice(t, yd.Value().Data(), ys.Value().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, dense.W.Grad().Data(), sparse.W.Grad().Data(), 1.0e-06)
+	assert.InDeltaSlice(t, dense.B.Grad().Data(), sparse.B.Grad().Data(), 1.0e-06)
+}
+
+func TestModel_ForwardQuantized(t *testing.T) {
+	t.Run("float32", testModelForwardQuantized[float32])
+	t.Run("float64", testModelForwardQuantized[float64])
+}
+
+func testModelForwardQuantized[T float.DType](t *testing.T) {
+	x := mat.NewVecDense([]T{-0.8, -0.9, -0.9, 1.0})
+
+	model := newTestModel[T]()
+	expected := model.Forward(ag.Var(x))[0].Value()
+
+	report := nn.Quantize(model, nil)
+	assert.Equal(t, 1, report.Params)
+	assert.IsType(t, &mat.Quantized[T]{}, model.W.Value())
+	assert.IsType(t, &mat.Dense[T]{}, model.B.Value())
+
+	y := model.Forward(ag.Var(x))[0]
+	assert.InDeltaSlice(t, expected.Data(), y.Value().Data(), 1.0e-02)
+}
+
+func TestModel_ForwardHalf(t *testing.T) {
+	t.Run("Float16", testModelForwardHalf[float.Float16])
+	t.Run("BFloat16", testModelForwardHalf[float.BFloat16])
+}
+
+func testModelForwardHalf[H float.Half](t *testing.T) {
+	x := mat.NewVecDense([]float32{-0.8, -0.9, -0.9, 1.0})
+
+	model := newTestModel[float32]()
+	expected := model.Forward(ag.Var(x))[0].Value()
+
+	assert.Equal(t, 2, nn.ToHalf[H](model))
+	assert.IsType(t, &mat.HalfDense[H]{}, model.W.Value())
+	assert.IsType(t, &mat.HalfDense[H]{}, model.B.Value())
+
+	y := model.Forward(ag.Var(x))[0]
+	assert.InDeltaSlice(t, expected.Data(), y.Value().Data(), 1.0e-02)
+	ag.ReleaseGraph(y)
+}
+
 func newTestModel[T float.DType]() *Model {
 	model := New[T](4, 5)
 	mat.SetData[T](model.W.Value(), []T{
@@ -79,3 +140,24 @@ func newTestModel[T float.DType]() *Model {
 	mat.SetData[T](model.B.Value(), []T{0.4, 0.0, -0.3, 0.8, -0.4})
 	return model
 }
+
+func TestModel_NoGrad(t *testing.T) {
+	t.Run("float32", testModelNoGrad[float32])
+	t.Run("float64", testModelNoGrad[float64])
+}
+
+func testModelNoGrad[T float.DType](t *testing.T) {
+	model := newTestModel[T]()
+	x := mat.NewVecDense([]T{-0.8, -0.9, -0.9, 1.0})
+
+	expected := model.Forward(ag.Var(x))[0]
+	y := model.Forward(ag.NoGrad(ag.Var(x)))[0]
+	assert.Equal(t, expected.Value().Data(), y.Value().Data())
+	assert.False(t, y.RequiresGrad())
+
+	// The parameters behave as constants.
+	ag.Backward(y)
+	assert.Nil(t, model.W.Grad())
+	assert.Nil(t, model.B.Grad())
+	ag.ReleaseGraph(expected, y)
+}
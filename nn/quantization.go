@@ -0,0 +1,82 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import "github.com/nlpodyssey/spago/mat"
+
+// ParamsQuantizer is implemented by models which handle the quantization
+// of their parameters on their own, for example because some of them are
+// not visited by ForEachParam. If a model implements this interface, it
+// takes precedence over the regular parameters visit in Quantize.
+type ParamsQuantizer interface {
+	// QuantizeParams converts the values of the model's weights to
+	// quantized form, returning the number of converted parameters.
+	QuantizeParams() int
+}
+
+// QuantizationReport summarizes the outcome of Quantize.
+type QuantizationReport struct {
+	// Params is the number of parameters converted to quantized form.
+	Params int
+	// Baseline is the score of the model before the quantization, as
+	// returned by the evaluation function.
+	Baseline float64
+	// Quantized is the score of the quantized model, as returned by the
+	// evaluation function.
+	Quantized float64
+}
+
+// Delta returns the difference between the score of the quantized model
+// and the baseline score.
+func (r QuantizationReport) Delta() float64 {
+	return r.Quantized - r.Baseline
+}
+
+// Quantize converts the values of all the weights parameters of a model,
+// including sub-models, to 8-bit quantized matrices (see mat.Quantized).
+// Biases and parameters of undefined type are left unchanged.
+//
+// Quantization is meant for inference-only deployments: it reduces the
+// memory footprint of the model, at the cost of some precision.
+//
+// If eval is not nil, it is called before and after the conversion to
+// score the model on a calibration set (for example, computing its
+// accuracy), so that the report includes the accuracy delta.
+func Quantize(m Model, eval func() float64) QuantizationReport {
+	var report QuantizationReport
+	if eval != nil {
+		report.Baseline = eval()
+	}
+
+	Apply(m, func(model Model, _ string) {
+		if q, ok := model.(ParamsQuantizer); ok {
+			report.Params += q.QuantizeParams()
+			return
+		}
+		ForEachParamStrict(model, func(param Param, _ string, pType ParamsType) {
+			if pType == Weights && QuantizeParam(param) {
+				report.Params++
+			}
+		})
+	})
+
+	if eval != nil {
+		report.Quantized = eval()
+	}
+	return report
+}
+
+// QuantizeParam replaces the value of the parameter with its quantized
+// form, reporting whether it has been converted. Parameters without a
+// value, or whose value is already quantized, are left unchanged.
+func QuantizeParam(p Param) bool {
+	switch v := p.Value().(type) {
+	case nil, *mat.Quantized[float32], *mat.Quantized[float64]:
+		return false
+	default:
+		p.ReplaceValue(mat.Quantize(v))
+		return true
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2022 spaGO Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package nn
+
+import (
+	"testing"
+
+	"github.com/nlpodyssey/spago/mat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuantize(t *testing.T) {
+	type T = float32
+
+	type OtherModel struct {
+		Module
+		Baz Param `spago:"type:weights"`
+	}
+
+	type Model struct {
+		Module
+		Foo   Param `spago:"type:weights"`
+		Bar   Param `spago:"type:biases"`
+		Qux   Param
+		Other *OtherModel
+	}
+
+	m := &Model{
+		Foo: NewParam(mat.NewDense[T](2, 2, []T{1, -2, 3, -4})),
+		Bar: NewParam(mat.NewVecDense[T]([]T{1, 2})),
+		Qux: NewParam(mat.NewScalar[T](3)),
+		Other: &OtherModel{
+			Baz: NewParam(mat.NewDense[T](1, 3, []T{0.1, 0.2, 0.3})),
+		},
+	}
+
+	x := mat.NewVecDense[T]([]T{0.5, 0.25})
+	eval := func() float64 {
+		y := m.Foo.Value().Mul(x)
+		return y.Sum().Scalar().F64()
+	}
+
+	report := Quantize(m, eval)
+	assert.Equal(t, 2, report.Params)
+	assert.Equal(t, 0.5, report.Baseline)
+	assert.InDelta(t, 0.5, report.Quantized, 1.0e-2)
+	assert.Equal(t, report.Quantized-report.Baseline, report.Delta())
+
+	assert.IsType(t, &mat.Quantized[T]{}, m.Foo.Value())
+	assert.IsType(t, &mat.Quantized[T]{}, m.Other.Baz.Value())
+	assert.IsType(t, &mat.Dense[T]{}, m.Bar.Value())
+	assert.IsType(t, &mat.Dense[T]{}, m.Qux.Value())
+
+	report = Quantize(m, nil)
+	assert.Equal(t, 0, report.Params, "quantized params must be skipped")
+}
+
+func TestQuantizeParam(t *testing.T) {
+	type T = float64
+
+	p := NewParam(mat.NewDense[T](2, 2, []T{1, 2, 3, 4}))
+	p.AccGrad(mat.NewDense[T](2, 2, []T{1, 1, 1, 1}))
+
+	assert.True(t, QuantizeParam(p))
+	assert.IsType(t, &mat.Quantized[T]{}, p.Value())
+	assert.InDeltaSlice(t, []T{1, 2, 3, 4}, p.Value().Data().F64(), 2.0e-2)
+	assert.False(t, p.HasGrad())
+
+	assert.False(t, QuantizeParam(p))
+}
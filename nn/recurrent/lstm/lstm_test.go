@@ -408,3 +408,49 @@ func newTestModel2[T float.DType]() *Model {
 	mat.SetData[T](model.BCand.Value(), []T{0.4, 0.3})
 	return model
 }
+
+func BenchmarkModel_Forward(b *testing.B) {
+	const in, out, seqLen = 32, 64, 10
+	model := New[float32](in, out)
+	xs := make([]mat.Matrix, seqLen)
+	for i := range xs {
+		xs[i] = mat.NewInitVecDense[float32](in, 0.1*float32(i))
+	}
+	forward := func(xs ...ag.Node) []ag.Node {
+		return model.Forward(xs...)
+	}
+
+	b.Run("dynamic", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			nodes := make([]ag.Node, len(xs))
+			for j, x := range xs {
+				nodes[j] = ag.Var(x)
+			}
+			ys := forward(nodes...)
+			ys[len(ys)-1].Value()
+			ag.ReleaseGraph(ys...)
+		}
+	})
+
+	b.Run("plan", func(b *testing.B) {
+		p := ag.TracePlan(forward, xs...)
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.Run(xs...)
+		}
+		p.Release()
+	})
+
+	b.Run("fused plan", func(b *testing.B) {
+		p := ag.TracePlan(forward, xs...)
+		p.Fuse()
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			p.Run(xs...)
+		}
+		p.Release()
+	})
+}
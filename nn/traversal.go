@@ -7,7 +7,10 @@ package nn
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
+
+	"github.com/nlpodyssey/spago/ag"
 )
 
 // ParamsTraversalFunc is the function called for each visited Param from
@@ -41,7 +44,9 @@ type paramsTraversal struct {
 // walk iterates through all the parameters of m.
 func (pt paramsTraversal) walk(m any) {
 	if m, ok := m.(ParamsTraverser); ok {
-		m.TraverseParams(pt.paramsFunc)
+		if pt.paramsFunc != nil {
+			m.TraverseParams(pt.paramsFunc)
+		}
 		return
 	}
 	forEachField(m, func(field any, name string, rTag reflect.StructTag) {
@@ -134,11 +139,17 @@ func (pt paramsTraversal) walkSlice(v reflect.Value, name string, tag moduleFiel
 	}
 }
 
+// walkMap visits the values of a map with string or int keys. In
+// deterministic mode (see ag.SetDeterministic), the keys are visited in
+// ascending order, rather than in the random order of the map iteration.
 func (pt paramsTraversal) walkMap(v reflect.Value, name string, tag moduleFieldTag) {
-	mapRange := v.MapRange()
-	for mapRange.Next() {
+	keys := v.MapKeys()
+	if ag.IsDeterministic() {
+		sortMapKeys(keys)
+	}
+	for _, k := range keys {
 		key := ""
-		switch k := mapRange.Key().Interface().(type) {
+		switch k := k.Interface().(type) {
 		case string:
 			key = k
 		case int:
@@ -147,10 +158,11 @@ func (pt paramsTraversal) walkMap(v reflect.Value, name string, tag moduleFieldT
 			return // skip map if the key is not a string or an int
 		}
 
+		value := v.MapIndex(k)
 		name := fmt.Sprintf("%s.%s", name, key)
-		switch mapRange.Value().Kind() {
+		switch value.Kind() {
 		case reflect.Struct, reflect.Ptr, reflect.Interface:
-			if !pt.walkStructOrPtr(mapRange.Value().Interface(), name, tag) {
+			if !pt.walkStructOrPtr(value.Interface(), name, tag) {
 				return
 			}
 		default:
@@ -159,6 +171,20 @@ func (pt paramsTraversal) walkMap(v reflect.Value, name string, tag moduleFieldT
 	}
 }
 
+// sortMapKeys sorts string or int map keys in ascending order. Keys of any
+// other kind are left as they are.
+func sortMapKeys(keys []reflect.Value) {
+	if len(keys) == 0 {
+		return
+	}
+	switch keys[0].Kind() {
+	case reflect.String:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	case reflect.Int:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	}
+}
+
 // forEachField calls the paramsFunc for each field of the struct i.
 func forEachField(i any, callback func(field any, name string, tag reflect.StructTag)) {
 	v := reflect.ValueOf(i)